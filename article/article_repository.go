@@ -84,12 +84,33 @@ func (r *ArticleRepository) Save(ctx context.Context, m ArticleModel) (nm Articl
 	return m, nil
 }
 
-func (r *ArticleRepository) Query(ctx context.Context, q string, id, limit int64) ([]ArticleModel, error) {
+func (r *ArticleRepository) Query(ctx context.Context, q string, categoryId uint64, tag string, id, limit int64) ([]ArticleModel, error) {
 	fromId := "id > $1"
 	if id != 0 {
 		fromId = "id < $1"
 	}
 
+	category := "$4::bigint = 0"
+	if categoryId != 0 {
+		category = `id IN (
+			SELECT ac.article_id
+			FROM article_categories ac
+				JOIN categories c ON c.id = ac.category_id
+			WHERE c.deleted_at IS NULL
+				AND ac.category_id = $4
+		)`
+	}
+
+	tagged := "$5::text = ''"
+	if tag != "" {
+		tagged = `id IN (
+			SELECT atg.article_id
+			FROM article_tags atg
+				JOIN tags t ON t.id = atg.tag_id
+			WHERE t.name = $5
+		)`
+	}
+
 	like := "id > $2"
 	order := "id"
 	if q != "" {
@@ -118,6 +139,8 @@ func (r *ArticleRepository) Query(ctx context.Context, q string, id, limit int64
 		WHERE deleted_at IS NULL
 			AND ` + fromId + `
 			AND ` + like + `
+			AND ` + category + `
+			AND ` + tagged + `
 		ORDER BY ` + order + ` DESC
 		LIMIT $3
 	`
@@ -128,6 +151,74 @@ func (r *ArticleRepository) Query(ctx context.Context, q string, id, limit int64
 		id,
 		q,
 		limit,
+		categoryId,
+		tag,
+	)
+	defer rows.Close()
+
+	var mps []*ArticleModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []ArticleModel{}, err
+	}
+
+	ms := make([]ArticleModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+// Articles are ranked by how well their tsvector match the most
+// significant lexemes of the source article, title and short description
+// first, then the most frequent content words. Ties, including articles
+// with no match at all, fall back to shared tags and then recency.
+func (r *ArticleRepository) QueryRelated(ctx context.Context, id uint64, limit int64) ([]ArticleModel, error) {
+	sqlQuery := `
+		WITH src AS (
+			SELECT to_tsquery('simple', COALESCE((
+				SELECT string_agg(quote_literal(u.lexeme), ' | ')
+				FROM (
+					SELECT lexeme
+					FROM unnest(textrank_index_col)
+					ORDER BY
+						'A' = ANY(weights) DESC,
+						'B' = ANY(weights) DESC,
+						array_length(positions, 1) DESC NULLS LAST
+					LIMIT 32
+				) u
+			), '')) AS query
+			FROM articles
+			WHERE id = $1
+		)
+		SELECT
+			a.id,
+			a.title,
+			a.short_desc,
+			a.thumbnail_url,
+			a.slug,
+			a.created_at
+		FROM articles a, src
+		WHERE a.deleted_at IS NULL
+			AND a.id <> $1
+		ORDER BY
+			ts_rank(a.textrank_index_col, src.query) DESC,
+			(
+				SELECT COUNT(*)
+				FROM article_tags x
+					JOIN article_tags y ON y.tag_id = x.tag_id
+				WHERE x.article_id = a.id
+					AND y.article_id = $1
+			) DESC,
+			a.created_at DESC
+		LIMIT $2
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		id,
+		limit,
 	)
 	defer rows.Close()
 
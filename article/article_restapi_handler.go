@@ -5,11 +5,18 @@ import (
 	"net/http"
 
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/httpdecode"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
 	"github.com/go-chi/chi/v5"
 )
 
 func (d *ArticleDeps) PostArticle(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateAdminClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
 	decoder := json.NewDecoder(r.Body)
 
 	var in AddArticleIn
@@ -19,14 +26,17 @@ func (d *ArticleDeps) PostArticle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	out := d.AddArticle(r.Context(), in)
+	out := d.AddArticle(r.Context(), jwtPayload.Uid, in)
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
 
 func (d *ArticleDeps) GetArticles(w http.ResponseWriter, r *http.Request) {
-	q := r.URL.Query().Get("q")
-	cursor := r.URL.Query().Get("cursor")
-	out := d.QueryArticle(r.Context(), q, cursor)
+	out := d.QueryArticle(r.Context(), QueryArticleQIn{
+		Q:          r.URL.Query().Get("q"),
+		Cursor:     r.URL.Query().Get("cursor"),
+		CategoryId: r.URL.Query().Get("category_id"),
+		Tag:        r.URL.Query().Get("tag"),
+	})
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
 
@@ -36,7 +46,20 @@ func (d *ArticleDeps) GetArticle(w http.ResponseWriter, r *http.Request) {
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
 
+func (d *ArticleDeps) GetRelatedArticles(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	limit := r.URL.Query().Get("limit")
+	out := d.QueryRelatedArticle(r.Context(), idParam, limit)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
 func (d *ArticleDeps) PutArticle(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateAdminClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
 	decoder := json.NewDecoder(r.Body)
 
 	var in EditArticleIn
@@ -47,7 +70,7 @@ func (d *ArticleDeps) PutArticle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	idParam := chi.URLParam(r, "id")
-	out := d.EditArticle(r.Context(), idParam, in)
+	out := d.EditArticle(r.Context(), jwtPayload.Uid, idParam, in)
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
 
@@ -17,6 +17,83 @@ import (
 
 var ErrArticleNotFound = errors.New("article tidak ditemukan")
 
+func (d *ArticleDeps) SaveArticleTaxonomy(ctx context.Context, articleId uint64, categoryIds []int64, tags []string) error {
+	var ids []uint64
+	seenIds := make(map[uint64]bool)
+	for _, ci := range categoryIds {
+		id, err := strconv.ParseUint(strconv.FormatInt(ci, 10), 10, 64)
+		if err != nil {
+			return ErrCategoryNotFound
+		}
+
+		if !seenIds[id] {
+			seenIds[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) != 0 {
+		categories, err := d.CategoryRepository.QueryUndeletedInId(ctx, ids)
+		if err != nil {
+			err = errors.Wrap(err, "query category in id")
+			return err
+		}
+
+		if len(categories) != len(ids) {
+			return ErrCategoryNotFound
+		}
+	}
+
+	var names []string
+	seenNames := make(map[string]bool)
+	for _, t := range tags {
+		name := strings.ToLower(strings.Trim(t, " "))
+		if name != "" && !seenNames[name] {
+			seenNames[name] = true
+			names = append(names, name)
+		}
+	}
+
+	var tagIds []uint64
+	if len(names) != 0 {
+		ntags, err := d.TagRepository.BulkSaveByName(ctx, names)
+		if err != nil {
+			err = errors.Wrap(err, "bulk save tag by name")
+			return err
+		}
+
+		for _, t := range ntags {
+			tagIds = append(tagIds, t.Id)
+		}
+	}
+
+	if err := d.CategoryRepository.DeleteArticleCategories(ctx, articleId); err != nil {
+		err = errors.Wrap(err, "delete article categories")
+		return err
+	}
+
+	if len(ids) != 0 {
+		if err := d.CategoryRepository.BulkSaveArticleCategories(ctx, articleId, ids); err != nil {
+			err = errors.Wrap(err, "bulk save article categories")
+			return err
+		}
+	}
+
+	if err := d.TagRepository.DeleteArticleTags(ctx, articleId); err != nil {
+		err = errors.Wrap(err, "delete article tags")
+		return err
+	}
+
+	if len(tagIds) != 0 {
+		if err := d.TagRepository.BulkSaveArticleTags(ctx, articleId, tagIds); err != nil {
+			err = errors.Wrap(err, "bulk save article tags")
+			return err
+		}
+	}
+
+	return nil
+}
+
 type ArticleIn struct {
 	Title        string
 	ShortDesc    string
@@ -112,12 +189,14 @@ func (d *ArticleDeps) ArticleModelBuilder(ctx context.Context, in ArticleIn) (bm
 
 type (
 	AddArticleIn struct {
-		Title        string `json:"title"`
-		ShortDesc    string `json:"short_desc"`
-		ThumbnailUrl string `json:"thumbnail_url"`
-		Content      string `json:"content"`
-		ContentText  string `json:"content_text"`
-		Slug         string `json:"slug"`
+		Title        string   `json:"title"`
+		ShortDesc    string   `json:"short_desc"`
+		ThumbnailUrl string   `json:"thumbnail_url"`
+		Content      string   `json:"content"`
+		ContentText  string   `json:"content_text"`
+		Slug         string   `json:"slug"`
+		CategoryIds  []int64  `json:"category_ids"`
+		Tags         []string `json:"tags"`
 	}
 	AddArticleRes struct {
 		Id int64 `json:"id"`
@@ -128,7 +207,7 @@ type (
 	}
 )
 
-func (d *ArticleDeps) AddArticle(ctx context.Context, in AddArticleIn) (out AddArticleOut) {
+func (d *ArticleDeps) AddArticle(ctx context.Context, uid string, in AddArticleIn) (out AddArticleOut) {
 	var err error
 	out.Response = resp.NewResponse(http.StatusCreated, "", nil)
 
@@ -137,7 +216,14 @@ func (d *ArticleDeps) AddArticle(ctx context.Context, in AddArticleIn) (out AddA
 		return
 	}
 
-	article, err := d.ArticleModelBuilder(ctx, ArticleIn(in))
+	article, err := d.ArticleModelBuilder(ctx, ArticleIn{
+		Title:        in.Title,
+		ShortDesc:    in.ShortDesc,
+		ThumbnailUrl: in.ThumbnailUrl,
+		Content:      in.Content,
+		ContentText:  in.ContentText,
+		Slug:         in.Slug,
+	})
 	if err != nil {
 		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "article model builder"))
 		return
@@ -148,6 +234,21 @@ func (d *ArticleDeps) AddArticle(ctx context.Context, in AddArticleIn) (out AddA
 		return
 	}
 
+	if _, err = d.SaveRevision(ctx, uid, article); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save revision"))
+		return
+	}
+
+	err = d.SaveArticleTaxonomy(ctx, article.Id, in.CategoryIds, in.Tags)
+	if errors.Is(err, ErrCategoryNotFound) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", err)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save article taxonomy"))
+		return
+	}
+
 	out.Res.Id = int64(article.Id)
 
 	return
@@ -171,9 +272,15 @@ type (
 		resp.Response
 		Res QueryArticleRes
 	}
+	QueryArticleQIn struct {
+		Q          string
+		Cursor     string
+		CategoryId string
+		Tag        string
+	}
 )
 
-func (d *ArticleDeps) QueryArticle(ctx context.Context, q, cursor string) (out QueryArticleOut) {
+func (d *ArticleDeps) QueryArticle(ctx context.Context, qin QueryArticleQIn) (out QueryArticleOut) {
 	var err error
 	out.Response = resp.NewResponse(http.StatusOK, "", nil)
 
@@ -183,8 +290,10 @@ func (d *ArticleDeps) QueryArticle(ctx context.Context, q, cursor string) (out Q
 		return
 	}
 
-	fromCursor, _ := strconv.ParseInt(cursor, 10, 64)
-	articles, err := d.ArticleRepository.Query(ctx, q, fromCursor, 25)
+	fromCursor, _ := strconv.ParseInt(qin.Cursor, 10, 64)
+	categoryId, _ := strconv.ParseUint(qin.CategoryId, 10, 64)
+	tag := strings.ToLower(strings.Trim(qin.Tag, " "))
+	articles, err := d.ArticleRepository.Query(ctx, qin.Q, categoryId, tag, fromCursor, 25)
 	if err != nil {
 		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query articles"))
 		return
@@ -219,14 +328,16 @@ func (d *ArticleDeps) QueryArticle(ctx context.Context, q, cursor string) (out Q
 
 type (
 	ArticleRes struct {
-		Id           int64  `json:"id"`
-		Title        string `json:"title"`
-		ShortDesc    string `json:"short_desc"`
-		ThumbnailUrl string `json:"thumbnail_url"`
-		Content      string `json:"content"`
-		ContentText  string `json:"content_text"`
-		Slug         string `json:"slug"`
-		CreatedAt    string `json:"created_at"`
+		Id           int64         `json:"id"`
+		Title        string        `json:"title"`
+		ShortDesc    string        `json:"short_desc"`
+		ThumbnailUrl string        `json:"thumbnail_url"`
+		Content      string        `json:"content"`
+		ContentText  string        `json:"content_text"`
+		Slug         string        `json:"slug"`
+		CreatedAt    string        `json:"created_at"`
+		Categories   []CategoryOut `json:"categories"`
+		Tags         []string      `json:"tags"`
 	}
 	FindArticleOut struct {
 		resp.Response
@@ -263,6 +374,31 @@ func (d *ArticleDeps) FindArticleById(ctx context.Context, pid string) (out Find
 		}
 	}
 
+	categories, err := d.CategoryRepository.QueryByArticleId(ctx, id)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query categories by article id"))
+		return
+	}
+
+	outCategories := make([]CategoryOut, len(categories))
+	for i, c := range categories {
+		outCategories[i] = CategoryOut{
+			Id:   int64(c.Id),
+			Name: c.Name,
+		}
+	}
+
+	tags, err := d.TagRepository.QueryByArticleId(ctx, id)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query tags by article id"))
+		return
+	}
+
+	outTags := make([]string, len(tags))
+	for i, t := range tags {
+		outTags[i] = t.Name
+	}
+
 	out.Res = ArticleRes{
 		Id:           int64(article.Id),
 		Title:        article.Title,
@@ -272,18 +408,80 @@ func (d *ArticleDeps) FindArticleById(ctx context.Context, pid string) (out Find
 		Slug:         article.Slug,
 		ThumbnailUrl: article.ThumbnailUrl,
 		CreatedAt:    article.CreatedAt.Format("2006-01-02"),
+		Categories:   outCategories,
+		Tags:         outTags,
+	}
+
+	return
+}
+
+type (
+	QueryRelatedArticleRes struct {
+		Articles []ArticleOut `json:"articles"`
+	}
+	QueryRelatedArticleOut struct {
+		resp.Response
+		Res QueryRelatedArticleRes
+	}
+)
+
+func (d *ArticleDeps) QueryRelatedArticle(ctx context.Context, pid, limit string) (out QueryRelatedArticleOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(pid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrArticleNotFound)
+		return
+	}
+
+	_, err = d.ArticleRepository.FindUndeletedById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrArticleNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find article by id"))
+		return
+	}
+
+	var n int64 = 5
+	if l, err := strconv.ParseInt(limit, 10, 64); err == nil && l > 0 && l <= 20 {
+		n = l
+	}
+
+	articles, err := d.ArticleRepository.QueryRelated(ctx, id, n)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query related articles"))
+		return
 	}
 
+	outArticles := make([]ArticleOut, len(articles))
+	for i, b := range articles {
+		outArticles[i] = ArticleOut{
+			Id:           int64(b.Id),
+			Title:        b.Title,
+			ShortDesc:    b.ShortDesc,
+			Slug:         b.Slug,
+			ThumbnailUrl: b.ThumbnailUrl,
+			CreatedAt:    b.CreatedAt.Format("2006-01-02"),
+		}
+	}
+
+	out.Res.Articles = outArticles
+
 	return
 }
 
 type (
 	EditArticleIn struct {
-		Title        string `json:"title"`
-		ShortDesc    string `json:"short_desc"`
-		ThumbnailUrl string `json:"thumbnail_url"`
-		Content      string `json:"content"`
-		ContentText  string `json:"content_text"`
+		Title        string   `json:"title"`
+		ShortDesc    string   `json:"short_desc"`
+		ThumbnailUrl string   `json:"thumbnail_url"`
+		Content      string   `json:"content"`
+		ContentText  string   `json:"content_text"`
+		CategoryIds  []int64  `json:"category_ids"`
+		Tags         []string `json:"tags"`
 	}
 	EditArticleRes struct {
 		Id int64 `json:"id"`
@@ -294,7 +492,7 @@ type (
 	}
 )
 
-func (d *ArticleDeps) EditArticle(ctx context.Context, pid string, in EditArticleIn) (out EditArticleOut) {
+func (d *ArticleDeps) EditArticle(ctx context.Context, uid, pid string, in EditArticleIn) (out EditArticleOut) {
 	var err error
 	out.Response = resp.NewResponse(http.StatusOK, "", nil)
 
@@ -335,12 +533,28 @@ func (d *ArticleDeps) EditArticle(ctx context.Context, pid string, in EditArticl
 	article.Title = nb.Title
 	article.ShortDesc = nb.ShortDesc
 	article.ThumbnailUrl = nb.ThumbnailUrl
+	article.ContentText = nb.ContentText
 
 	if err = d.ArticleRepository.UpdateById(ctx, id, article); err != nil {
 		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update position by id"))
 		return
 	}
 
+	if _, err = d.SaveRevision(ctx, uid, article); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save revision"))
+		return
+	}
+
+	err = d.SaveArticleTaxonomy(ctx, id, in.CategoryIds, in.Tags)
+	if errors.Is(err, ErrCategoryNotFound) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", err)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save article taxonomy"))
+		return
+	}
+
 	out.Res.Id = int64(id)
 
 	return
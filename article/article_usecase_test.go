@@ -19,6 +19,11 @@ func TestAddArticle(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	category, err := categoryRepository.Save(context.Background(), categorySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	testCases := []struct {
 		Name               string
 		ExpectedStatusCode int
@@ -149,6 +154,43 @@ func TestAddArticle(t *testing.T) {
 				ContentText:  "test",
 			},
 		},
+		{
+			Name:               "Add Article with Categories and Tags Success",
+			ExpectedStatusCode: http.StatusCreated,
+			init:               func() {},
+			In: article.AddArticleIn{
+				Title:       "Title",
+				ShortDesc:   "Short Desc",
+				Slug:        "slug",
+				Content:     `{"test": "hi"}`,
+				CategoryIds: []int64{int64(category.Id)},
+				Tags:        []string{"Wisata", "wisata ", "kuliner"},
+			},
+		},
+		{
+			Name:               "Add Article with Unknown Category Failed",
+			ExpectedStatusCode: http.StatusNotFound,
+			init:               func() {},
+			In: article.AddArticleIn{
+				Title:       "Title",
+				ShortDesc:   "Short Desc",
+				Slug:        "slug",
+				Content:     `{"test": "hi"}`,
+				CategoryIds: []int64{999},
+			},
+		},
+		{
+			Name:               "Add Article with Tag over 50 chars Failed",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			init:               func() {},
+			In: article.AddArticleIn{
+				Title:     "Title",
+				ShortDesc: "Short Desc",
+				Slug:      "slug",
+				Content:   `{"test": "hi"}`,
+				Tags:      []string{strings.Repeat("a", 51)},
+			},
+		},
 		{
 			Name:               "Add Article with Title over 200 chars Failed",
 			ExpectedStatusCode: http.StatusUnprocessableEntity,
@@ -224,7 +266,7 @@ func TestAddArticle(t *testing.T) {
 	for _, c := range testCases {
 		t.Run(c.Name, func(t *testing.T) {
 			c.init()
-			res := articleDeps.AddArticle(context.Background(), c.In)
+			res := articleDeps.AddArticle(context.Background(), authorId, c.In)
 
 			if res.StatusCode != c.ExpectedStatusCode {
 				t.Logf("%#v", res)
@@ -241,7 +283,17 @@ func TestQueryArticle(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err = articleRepository.Save(context.Background(), articleSeed)
+	a, err := articleRepository.Save(context.Background(), articleSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	category, err := categoryRepository.Save(context.Background(), categorySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = articleDeps.SaveArticleTaxonomy(context.Background(), a.Id, []int64{int64(category.Id)}, []string{"Wisata"})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -249,16 +301,48 @@ func TestQueryArticle(t *testing.T) {
 	testCases := []struct {
 		Name               string
 		ExpectedStatusCode int
+		ExpectedTotal      int
+		QIn                article.QueryArticleQIn
 	}{
 		{
 			Name:               "Query Article Success",
 			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      1,
+			QIn:                article.QueryArticleQIn{},
+		},
+		{
+			Name:               "Query Article by Category Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      1,
+			QIn: article.QueryArticleQIn{
+				CategoryId: strconv.FormatUint(category.Id, 10),
+			},
+		},
+		{
+			Name:               "Query Article by Tag Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      1,
+			QIn: article.QueryArticleQIn{
+				Tag: "wisata",
+			},
+		},
+		{
+			Name:               "Query Article by Unknown Tag Return Empty",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      0,
+			QIn: article.QueryArticleQIn{
+				Tag: "kuliner",
+			},
 		},
 	}
 
 	for _, c := range testCases {
 		t.Run(c.Name, func(t *testing.T) {
-			res := articleDeps.QueryArticle(context.Background(), "", "")
+			res := articleDeps.QueryArticle(context.Background(), c.QIn)
+
+			if len(res.Res.Articles) != c.ExpectedTotal {
+				t.Fatalf("Expected articles length %d. Got %d\n", c.ExpectedTotal, len(res.Res.Articles))
+			}
 
 			if res.StatusCode != c.ExpectedStatusCode {
 				t.Logf("%#v", res)
@@ -312,6 +396,60 @@ func TestFindArticleById(t *testing.T) {
 	}
 }
 
+func TestQueryRelatedArticle(t *testing.T) {
+	err := ClearTables(postgrePool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := articleRepository.Save(context.Background(), articleSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = articleRepository.Save(context.Background(), articleSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pid := strconv.FormatUint(a.Id, 10)
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		ExpectedTotal      int
+		Id                 string
+	}{
+		{
+			Name:               "Query Related Article Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      1,
+			Id:                 pid,
+		},
+		{
+			Name:               "Query Related Article Fail, Article Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			ExpectedTotal:      0,
+			Id:                 "999",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := articleDeps.QueryRelatedArticle(context.Background(), c.Id, "")
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if len(res.Res.Articles) != c.ExpectedTotal {
+				t.Fatalf("Expected articles length %d. Got %d\n", c.ExpectedTotal, len(res.Res.Articles))
+			}
+		})
+	}
+}
+
 func TestEditArticle(t *testing.T) {
 	err := ClearTables(postgrePool)
 	if err != nil {
@@ -515,7 +653,7 @@ func TestEditArticle(t *testing.T) {
 
 	for _, c := range testCases {
 		t.Run(c.Name, func(t *testing.T) {
-			res := articleDeps.EditArticle(context.Background(), c.Id, c.In)
+			res := articleDeps.EditArticle(context.Background(), authorId, c.Id, c.In)
 
 			if res.StatusCode != c.ExpectedStatusCode {
 				t.Logf("%#v", res)
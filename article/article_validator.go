@@ -1,6 +1,7 @@
 package article
 
 import (
+	"strings"
 	"unicode/utf8"
 
 	"github.com/pkg/errors"
@@ -11,6 +12,7 @@ var (
 	ErrMaxTitle     = errors.New("judul tidak dapat lebih dari 200 karakter")
 	ErrMaxShortDesc = errors.New("deskripsi singkat tidak dapat lebih dari 200 karakter")
 	ErrMaxSlug      = errors.New("slug tidak dapat lebih dari 200 karakter")
+	ErrMaxTag       = errors.New("tag tidak dapat lebih dari 50 karakter")
 )
 
 func ValidateAddArticleIn(i AddArticleIn) error {
@@ -33,6 +35,14 @@ func ValidateAddArticleIn(i AddArticleIn) error {
 		}
 		return nil
 	})
+	g.Go(func() error {
+		for _, t := range i.Tags {
+			if utf8.RuneCountInString(strings.Trim(t, " ")) > 50 {
+				return ErrMaxTag
+			}
+		}
+		return nil
+	})
 	if err := g.Wait(); err != nil {
 		return err
 	}
@@ -53,6 +63,14 @@ func ValidateEditArticleIn(i EditArticleIn) error {
 		}
 		return nil
 	})
+	g.Go(func() error {
+		for _, t := range i.Tags {
+			if utf8.RuneCountInString(strings.Trim(t, " ")) > 50 {
+				return ErrMaxTag
+			}
+		}
+		return nil
+	})
 	if err := g.Wait(); err != nil {
 		return err
 	}
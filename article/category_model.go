@@ -0,0 +1,14 @@
+package article
+
+import (
+	"database/sql"
+	"time"
+)
+
+type CategoryModel struct {
+	Id        uint64
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt sql.NullTime
+}
@@ -0,0 +1,366 @@
+package article
+
+import (
+	"context"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type CategoryRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewCategoryRepository(postgreDb *pgxpool.Pool) *CategoryRepository {
+	return &CategoryRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+type (
+	CategoryExecutor   func(ctx context.Context, sql string, arguments ...interface{}) (commandTag pgconn.CommandTag, err error)
+	CategoryQuerierRow func(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	CategoryQuerier    func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	CategoryCopierFrom func(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+)
+
+func (r *CategoryRepository) Save(ctx context.Context, m CategoryModel) (nm CategoryModel, err error) {
+	sqlQuery := `
+		INSERT INTO categories (
+			name,
+			created_at,
+			updated_at,
+			deleted_at
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	var queryRow CategoryQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	var lastInsertId uint64
+	t := time.Now()
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		m.Name,
+		t,
+		t,
+		nil,
+	).Scan(&lastInsertId)
+	if err != nil {
+		return CategoryModel{}, err
+	}
+
+	m.Id = lastInsertId
+	m.CreatedAt = t
+	m.UpdatedAt = t
+
+	return m, nil
+}
+
+func (r *CategoryRepository) UpdateById(ctx context.Context, id uint64, m CategoryModel) error {
+	sqlQuery := `
+		UPDATE categories SET (
+			name,
+			updated_at
+		) = ($1, $2)
+		WHERE id = $3
+	`
+
+	var exec CategoryExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	var err error
+	t := time.Now()
+
+	_, err = exec(
+		context.Background(),
+		sqlQuery,
+		m.Name,
+		t,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *CategoryRepository) FindUndeletedById(ctx context.Context, id uint64) (m CategoryModel, err error) {
+	querystr := `
+		SELECT
+			id,
+			name,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM categories
+		WHERE deleted_at IS NULL
+		AND id = $1
+	`
+
+	var query CategoryQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+	rows, err = query(
+		context.Background(),
+		querystr,
+		id,
+	)
+	if err != nil {
+		return CategoryModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return CategoryModel{}, err
+	}
+
+	return m, nil
+}
+
+func (r *CategoryRepository) DeleteById(ctx context.Context, id uint64) error {
+	sqlQuery := `
+		UPDATE categories
+		SET deleted_at = $1
+		WHERE id = $2
+	`
+
+	var exec CategoryExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	var err error
+	t := time.Now()
+
+	_, err = exec(
+		context.Background(),
+		sqlQuery,
+		t,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *CategoryRepository) Query(ctx context.Context) ([]CategoryModel, error) {
+	sqlQuery := `
+		SELECT
+			id,
+			name,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM categories
+		WHERE deleted_at IS NULL
+		ORDER BY name ASC
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+	)
+	defer rows.Close()
+
+	var mps []*CategoryModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []CategoryModel{}, err
+	}
+
+	ms := make([]CategoryModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *CategoryRepository) QueryUndeletedInId(ctx context.Context, ids []uint64) ([]CategoryModel, error) {
+	sqlQuery := `
+		SELECT
+			id,
+			name,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM categories
+		WHERE deleted_at IS NULL
+		AND id = ANY($1)
+	`
+
+	var query CategoryQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	rows, _ := query(
+		context.Background(),
+		sqlQuery,
+		ids,
+	)
+	defer rows.Close()
+
+	var mps []*CategoryModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []CategoryModel{}, err
+	}
+
+	ms := make([]CategoryModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *CategoryRepository) QueryByArticleId(ctx context.Context, articleId uint64) ([]CategoryModel, error) {
+	sqlQuery := `
+		SELECT
+			c.id,
+			c.name,
+			c.created_at,
+			c.updated_at,
+			c.deleted_at
+		FROM article_categories ac
+			JOIN categories c ON c.id = ac.category_id
+		WHERE c.deleted_at IS NULL
+			AND ac.article_id = $1
+		ORDER BY c.name ASC
+	`
+
+	var query CategoryQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	rows, _ := query(
+		context.Background(),
+		sqlQuery,
+		articleId,
+	)
+	defer rows.Close()
+
+	var mps []*CategoryModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []CategoryModel{}, err
+	}
+
+	ms := make([]CategoryModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *CategoryRepository) DeleteArticleCategories(ctx context.Context, articleId uint64) error {
+	sqlQuery := `
+		DELETE FROM article_categories
+		WHERE article_id = $1
+	`
+
+	var exec CategoryExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		articleId,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *CategoryRepository) DeleteCategoryArticles(ctx context.Context, categoryId uint64) error {
+	sqlQuery := `
+		DELETE FROM article_categories
+		WHERE category_id = $1
+	`
+
+	var exec CategoryExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		categoryId,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *CategoryRepository) BulkSaveArticleCategories(ctx context.Context, articleId uint64, categoryIds []uint64) error {
+	var copyFrom CategoryCopierFrom
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		copyFrom = tx.CopyFrom
+	} else {
+		copyFrom = r.PostgreDb.CopyFrom
+	}
+
+	_, err := copyFrom(
+		context.Background(),
+		pgx.Identifier{"article_categories"},
+		[]string{"article_id", "category_id"},
+		pgx.CopyFromSlice(len(categoryIds), func(i int) ([]interface{}, error) {
+			return []interface{}{articleId, categoryIds[i]}, nil
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
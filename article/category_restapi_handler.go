@@ -0,0 +1,49 @@
+package article
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/go-chi/chi/v5"
+)
+
+func (d *ArticleDeps) PostCategory(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+
+	var in AddCategoryIn
+	err := decoder.Decode(&in)
+	if err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.AddCategory(r.Context(), in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *ArticleDeps) GetCategories(w http.ResponseWriter, r *http.Request) {
+	out := d.QueryCategory(r.Context())
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *ArticleDeps) PutCategory(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+
+	var in EditCategoryIn
+	err := decoder.Decode(&in)
+	if err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	idParam := chi.URLParam(r, "id")
+	out := d.EditCategory(r.Context(), idParam, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *ArticleDeps) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	out := d.RemoveCategory(r.Context(), idParam)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
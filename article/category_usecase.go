@@ -0,0 +1,184 @@
+package article
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+)
+
+var ErrCategoryNotFound = errors.New("kategori tidak ditemukan")
+
+type (
+	AddCategoryIn struct {
+		Name string `json:"name"`
+	}
+	AddCategoryRes struct {
+		Id int64 `json:"id"`
+	}
+	AddCategoryOut struct {
+		resp.Response
+		Res AddCategoryRes
+	}
+)
+
+func (d *ArticleDeps) AddCategory(ctx context.Context, in AddCategoryIn) (out AddCategoryOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusCreated, "", nil)
+
+	if err = ValidateAddCategoryIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	category := CategoryModel{
+		Name: in.Name,
+	}
+	if category, err = d.CategoryRepository.Save(ctx, category); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save category"))
+		return
+	}
+
+	out.Res.Id = int64(category.Id)
+
+	return
+}
+
+type (
+	CategoryOut struct {
+		Id   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	QueryCategoryRes struct {
+		Total      int64         `json:"total"`
+		Categories []CategoryOut `json:"categories"`
+	}
+	QueryCategoryOut struct {
+		resp.Response
+		Res QueryCategoryRes
+	}
+)
+
+func (d *ArticleDeps) QueryCategory(ctx context.Context) (out QueryCategoryOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	categories, err := d.CategoryRepository.Query(ctx)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query categories"))
+		return
+	}
+
+	outCategories := make([]CategoryOut, len(categories))
+	for i, c := range categories {
+		outCategories[i] = CategoryOut{
+			Id:   int64(c.Id),
+			Name: c.Name,
+		}
+	}
+
+	out.Res = QueryCategoryRes{
+		Total:      int64(len(outCategories)),
+		Categories: outCategories,
+	}
+
+	return
+}
+
+type (
+	EditCategoryIn struct {
+		Name string `json:"name"`
+	}
+	EditCategoryRes struct {
+		Id int64 `json:"id"`
+	}
+	EditCategoryOut struct {
+		resp.Response
+		Res EditCategoryRes
+	}
+)
+
+func (d *ArticleDeps) EditCategory(ctx context.Context, pid string, in EditCategoryIn) (out EditCategoryOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateEditCategoryIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	id, err := strconv.ParseUint(pid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrCategoryNotFound)
+		return
+	}
+
+	category, err := d.CategoryRepository.FindUndeletedById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrCategoryNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find category by id"))
+		return
+	}
+
+	category.Name = in.Name
+
+	if err = d.CategoryRepository.UpdateById(ctx, id, category); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update category by id"))
+		return
+	}
+
+	out.Res.Id = int64(id)
+
+	return
+}
+
+type (
+	RemoveCategoryRes struct {
+		Id int64 `json:"id"`
+	}
+	RemoveCategoryOut struct {
+		resp.Response
+		Res RemoveCategoryRes
+	}
+)
+
+func (d *ArticleDeps) RemoveCategory(ctx context.Context, pid string) (out RemoveCategoryOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(pid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrCategoryNotFound)
+		return
+	}
+
+	_, err = d.CategoryRepository.FindUndeletedById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrCategoryNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find category by id"))
+		return
+	}
+
+	if err = d.CategoryRepository.DeleteCategoryArticles(ctx, id); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "delete category articles"))
+		return
+	}
+
+	if err = d.CategoryRepository.DeleteById(ctx, id); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "delete category by id"))
+		return
+	}
+
+	out.Res.Id = int64(id)
+
+	return
+}
@@ -0,0 +1,187 @@
+package article_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/article"
+)
+
+func TestAddCategory(t *testing.T) {
+	err := ClearTables(postgrePool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		In                 article.AddCategoryIn
+	}{
+		{
+			Name:               "Add Category Success",
+			ExpectedStatusCode: http.StatusCreated,
+			In: article.AddCategoryIn{
+				Name: "Berita",
+			},
+		},
+		{
+			Name:               "Add Category with Empty Name Failed",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: article.AddCategoryIn{
+				Name: "",
+			},
+		},
+		{
+			Name:               "Add Category with Name over 100 chars Failed",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: article.AddCategoryIn{
+				Name: strings.Repeat("a", 101),
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := articleDeps.AddCategory(context.Background(), c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestQueryCategory(t *testing.T) {
+	err := ClearTables(postgrePool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = categoryRepository.Save(context.Background(), categorySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+	}{
+		{
+			Name:               "Query Category Success",
+			ExpectedStatusCode: http.StatusOK,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := articleDeps.QueryCategory(context.Background())
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestEditCategory(t *testing.T) {
+	err := ClearTables(postgrePool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	category, err := categoryRepository.Save(context.Background(), categorySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pid := strconv.FormatUint(category.Id, 10)
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Id                 string
+		In                 article.EditCategoryIn
+	}{
+		{
+			Name:               "Edit Category Success",
+			ExpectedStatusCode: http.StatusOK,
+			Id:                 pid,
+			In: article.EditCategoryIn{
+				Name: "Kegiatan",
+			},
+		},
+		{
+			Name:               "Edit Category Fail, Category Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Id:                 "999",
+			In: article.EditCategoryIn{
+				Name: "Kegiatan",
+			},
+		},
+		{
+			Name:               "Edit Category with Empty Name Failed",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Id:                 pid,
+			In: article.EditCategoryIn{
+				Name: "",
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := articleDeps.EditCategory(context.Background(), c.Id, c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestRemoveCategory(t *testing.T) {
+	err := ClearTables(postgrePool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	category, err := categoryRepository.Save(context.Background(), categorySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Id                 string
+	}{
+		{
+			Name:               "Remove Category Success",
+			ExpectedStatusCode: http.StatusOK,
+			Id:                 strconv.FormatUint(category.Id, 10),
+		},
+		{
+			Name:               "Remove Category Fail, Category Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Id:                 "999",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := articleDeps.RemoveCategory(context.Background(), c.Id)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+}
@@ -13,11 +13,14 @@ type (
 )
 
 type ArticleDeps struct {
-	ImgCldTmpFolder   string
-	ImgClgFolder      string
-	MoveFile          FileMover
-	Upload            FileUploader
-	ArticleRepository *ArticleRepository
+	ImgCldTmpFolder    string
+	ImgClgFolder       string
+	MoveFile           FileMover
+	Upload             FileUploader
+	ArticleRepository  *ArticleRepository
+	CategoryRepository *CategoryRepository
+	TagRepository      *TagRepository
+	RevisionRepository *RevisionRepository
 }
 
 func NewDeps(
@@ -26,13 +29,19 @@ func NewDeps(
 	moveFile FileMover,
 	upload FileUploader,
 	articleRepository *ArticleRepository,
+	categoryRepository *CategoryRepository,
+	tagRepository *TagRepository,
+	revisionRepository *RevisionRepository,
 ) *ArticleDeps {
 	return &ArticleDeps{
-		ImgClgFolder:      imgClgFolder,
-		ImgCldTmpFolder:   imgCldTmpFolder,
-		MoveFile:          moveFile,
-		Upload:            upload,
-		ArticleRepository: articleRepository,
+		ImgClgFolder:       imgClgFolder,
+		ImgCldTmpFolder:    imgCldTmpFolder,
+		MoveFile:           moveFile,
+		Upload:             upload,
+		ArticleRepository:  articleRepository,
+		CategoryRepository: categoryRepository,
+		TagRepository:      tagRepository,
+		RevisionRepository: revisionRepository,
 	}
 }
 
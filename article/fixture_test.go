@@ -16,14 +16,17 @@ import (
 )
 
 var (
-	postgrePool       *pgxpool.Pool
-	articleRepository *article.ArticleRepository
-	articleDeps       *article.ArticleDeps
-	fileName          = "images.jpeg"
-	fileDir           = "./fixture/" + fileName
-	imgTmpFolder      = "blabla"
-	imgFolder         = "blublu"
-	articleSeed       = article.ArticleModel{
+	postgrePool        *pgxpool.Pool
+	articleRepository  *article.ArticleRepository
+	categoryRepository *article.CategoryRepository
+	tagRepository      *article.TagRepository
+	revisionRepository *article.RevisionRepository
+	articleDeps        *article.ArticleDeps
+	fileName           = "images.jpeg"
+	fileDir            = "./fixture/" + fileName
+	imgTmpFolder       = "blabla"
+	imgFolder          = "blublu"
+	articleSeed        = article.ArticleModel{
 		Title:        "title",
 		ShortDesc:    "Short desc",
 		Slug:         "slug",
@@ -35,6 +38,10 @@ var (
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
+	authorId     = "a8b9e6e4-2d0f-4c8b-9c5e-2a4c0b1f7e3d"
+	categorySeed = article.CategoryModel{
+		Name: "Berita",
+	}
 )
 
 var (
@@ -84,6 +91,11 @@ func ClearTables(conn *pgxpool.Pool) error {
 
 	// This should be in order of which table truncate first before the other
 	queries := []string{
+		`TRUNCATE article_categories CASCADE`,
+		`TRUNCATE article_revisions CASCADE`,
+		`TRUNCATE article_tags CASCADE`,
+		`TRUNCATE categories CASCADE`,
+		`TRUNCATE tags CASCADE`,
 		`TRUNCATE articles CASCADE`,
 		`TRUNCATE image_caches CASCADE`,
 	}
@@ -160,12 +172,18 @@ func TestMain(m *testing.M) {
 	}
 
 	articleRepository = article.NewRepository("imgchc", postgrePool)
+	categoryRepository = article.NewCategoryRepository(postgrePool)
+	tagRepository = article.NewTagRepository(postgrePool)
+	revisionRepository = article.NewRevisionRepository(postgrePool)
 	articleDeps = article.NewDeps(
 		imgFolder,
 		imgTmpFolder,
 		moveFile,
 		upload,
 		articleRepository,
+		categoryRepository,
+		tagRepository,
+		revisionRepository,
 	)
 
 	LoadTables(postgrePool)
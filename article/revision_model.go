@@ -0,0 +1,17 @@
+package article
+
+import (
+	"time"
+)
+
+type RevisionModel struct {
+	Id           uint64
+	ArticleId    uint64
+	Title        string
+	ShortDesc    string
+	ThumbnailUrl string
+	ContentText  string
+	AuthorId     string
+	Content      map[string]interface{}
+	CreatedAt    time.Time
+}
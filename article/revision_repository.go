@@ -0,0 +1,163 @@
+package article
+
+import (
+	"context"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type RevisionRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewRevisionRepository(postgreDb *pgxpool.Pool) *RevisionRepository {
+	return &RevisionRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+type (
+	RevisionQuerierRow func(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	RevisionQuerier    func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+)
+
+func (r *RevisionRepository) Save(ctx context.Context, m RevisionModel) (nm RevisionModel, err error) {
+	sqlQuery := `
+		INSERT INTO article_revisions (
+			article_id,
+			title,
+			short_desc,
+			thumbnail_url,
+			content,
+			content_text,
+			author_id,
+			created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	var queryRow RevisionQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	var lastInsertId uint64
+	t := time.Now()
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		m.ArticleId,
+		m.Title,
+		m.ShortDesc,
+		m.ThumbnailUrl,
+		m.Content,
+		m.ContentText,
+		m.AuthorId,
+		t,
+	).Scan(&lastInsertId)
+	if err != nil {
+		return RevisionModel{}, err
+	}
+
+	m.Id = lastInsertId
+	m.CreatedAt = t
+
+	return m, nil
+}
+
+func (r *RevisionRepository) QueryByArticleId(ctx context.Context, articleId uint64) ([]RevisionModel, error) {
+	sqlQuery := `
+		SELECT
+			id,
+			article_id,
+			title,
+			short_desc,
+			thumbnail_url,
+			content,
+			content_text,
+			author_id,
+			created_at
+		FROM article_revisions
+		WHERE article_id = $1
+		ORDER BY id DESC
+	`
+
+	var query RevisionQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	rows, _ := query(
+		context.Background(),
+		sqlQuery,
+		articleId,
+	)
+	defer rows.Close()
+
+	var mps []*RevisionModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []RevisionModel{}, err
+	}
+
+	ms := make([]RevisionModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *RevisionRepository) FindByIdAndArticleId(ctx context.Context, id, articleId uint64) (m RevisionModel, err error) {
+	sqlQuery := `
+		SELECT
+			id,
+			article_id,
+			title,
+			short_desc,
+			thumbnail_url,
+			content,
+			content_text,
+			author_id,
+			created_at
+		FROM article_revisions
+		WHERE id = $1
+			AND article_id = $2
+	`
+
+	var query RevisionQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+	rows, err = query(
+		context.Background(),
+		sqlQuery,
+		id,
+		articleId,
+	)
+	if err != nil {
+		return RevisionModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return RevisionModel{}, err
+	}
+
+	return m, nil
+}
@@ -0,0 +1,43 @@
+package article
+
+import (
+	"net/http"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/go-chi/chi/v5"
+)
+
+func (d *ArticleDeps) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	out := d.QueryRevision(r.Context(), idParam)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *ArticleDeps) GetRevision(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	ridParam := chi.URLParam(r, "rid")
+	out := d.FindRevision(r.Context(), idParam, ridParam)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *ArticleDeps) GetRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+	out := d.DiffRevision(r.Context(), idParam, from, to)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *ArticleDeps) PostRevisionRestore(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateAdminClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	idParam := chi.URLParam(r, "id")
+	ridParam := chi.URLParam(r, "rid")
+	out := d.RestoreRevision(r.Context(), jwtPayload.Uid, idParam, ridParam)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
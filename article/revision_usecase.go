@@ -0,0 +1,403 @@
+package article
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+)
+
+var ErrRevisionNotFound = errors.New("revisi artikel tidak ditemukan")
+
+const (
+	BlockEqual   = "equal"
+	BlockAdded   = "added"
+	BlockRemoved = "removed"
+	BlockChanged = "changed"
+)
+
+func (d *ArticleDeps) SaveRevision(ctx context.Context, uid string, article ArticleModel) (RevisionModel, error) {
+	revision := RevisionModel{
+		ArticleId:    article.Id,
+		Title:        article.Title,
+		ShortDesc:    article.ShortDesc,
+		ThumbnailUrl: article.ThumbnailUrl,
+		ContentText:  article.ContentText,
+		AuthorId:     uid,
+		Content:      article.Content,
+	}
+
+	revision, err := d.RevisionRepository.Save(ctx, revision)
+	if err != nil {
+		err = errors.Wrap(err, "save revision")
+		return RevisionModel{}, err
+	}
+
+	return revision, nil
+}
+
+type (
+	RevisionOut struct {
+		Id        int64  `json:"id"`
+		Title     string `json:"title"`
+		AuthorId  string `json:"author_id"`
+		CreatedAt string `json:"created_at"`
+	}
+	QueryRevisionRes struct {
+		Total     int64         `json:"total"`
+		Revisions []RevisionOut `json:"revisions"`
+	}
+	QueryRevisionOut struct {
+		resp.Response
+		Res QueryRevisionRes
+	}
+)
+
+func (d *ArticleDeps) QueryRevision(ctx context.Context, pid string) (out QueryRevisionOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(pid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrArticleNotFound)
+		return
+	}
+
+	_, err = d.ArticleRepository.FindUndeletedById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrArticleNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find article by id"))
+		return
+	}
+
+	revisions, err := d.RevisionRepository.QueryByArticleId(ctx, id)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query revisions by article id"))
+		return
+	}
+
+	outRevisions := make([]RevisionOut, len(revisions))
+	for i, r := range revisions {
+		outRevisions[i] = RevisionOut{
+			Id:        int64(r.Id),
+			Title:     r.Title,
+			AuthorId:  r.AuthorId,
+			CreatedAt: r.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+
+	out.Res = QueryRevisionRes{
+		Total:     int64(len(outRevisions)),
+		Revisions: outRevisions,
+	}
+
+	return
+}
+
+func (d *ArticleDeps) findRevision(ctx context.Context, pid, rid string) (RevisionModel, resp.Response) {
+	id, err := strconv.ParseUint(pid, 10, 64)
+	if err != nil {
+		return RevisionModel{}, resp.NewResponse(http.StatusNotFound, "", ErrArticleNotFound)
+	}
+
+	revisionId, err := strconv.ParseUint(rid, 10, 64)
+	if err != nil {
+		return RevisionModel{}, resp.NewResponse(http.StatusNotFound, "", ErrRevisionNotFound)
+	}
+
+	revision, err := d.RevisionRepository.FindByIdAndArticleId(ctx, revisionId, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return RevisionModel{}, resp.NewResponse(http.StatusNotFound, "", ErrRevisionNotFound)
+	}
+	if err != nil {
+		return RevisionModel{}, resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find revision by id and article id"))
+	}
+
+	return revision, resp.NewResponse(http.StatusOK, "", nil)
+}
+
+type (
+	RevisionRes struct {
+		Id           int64  `json:"id"`
+		ArticleId    int64  `json:"article_id"`
+		Title        string `json:"title"`
+		ShortDesc    string `json:"short_desc"`
+		ThumbnailUrl string `json:"thumbnail_url"`
+		Content      string `json:"content"`
+		ContentText  string `json:"content_text"`
+		AuthorId     string `json:"author_id"`
+		CreatedAt    string `json:"created_at"`
+	}
+	FindRevisionOut struct {
+		resp.Response
+		Res RevisionRes
+	}
+)
+
+func (d *ArticleDeps) FindRevision(ctx context.Context, pid, rid string) (out FindRevisionOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	revision, r := d.findRevision(ctx, pid, rid)
+	if r.Error != nil {
+		out.Response = r
+		return
+	}
+
+	b := []byte("")
+	if len(revision.Content) != 0 {
+		b, err = json.Marshal(revision.Content)
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "json marshal"))
+			return
+		}
+	}
+
+	out.Res = RevisionRes{
+		Id:           int64(revision.Id),
+		ArticleId:    int64(revision.ArticleId),
+		Title:        revision.Title,
+		ShortDesc:    revision.ShortDesc,
+		ThumbnailUrl: revision.ThumbnailUrl,
+		Content:      string(b),
+		ContentText:  revision.ContentText,
+		AuthorId:     revision.AuthorId,
+		CreatedAt:    revision.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	return
+}
+
+type editorBlock struct {
+	Id   string
+	Type string
+	Data interface{}
+	key  string
+}
+
+// Editor.js keeps the document in a "blocks" array, every block
+// carries its type and data and, on newer versions, a stable id.
+func editorBlocks(content map[string]interface{}) []editorBlock {
+	rawBlocks, ok := content["blocks"].([]interface{})
+	if !ok {
+		return []editorBlock{}
+	}
+
+	blocks := make([]editorBlock, 0, len(rawBlocks))
+	for _, rb := range rawBlocks {
+		b, ok := rb.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		id, _ := b["id"].(string)
+		t, _ := b["type"].(string)
+
+		// Map keys are sorted by json.Marshal, so the same block
+		// always produce the same key.
+		k, _ := json.Marshal([]interface{}{t, b["data"]})
+
+		blocks = append(blocks, editorBlock{
+			Id:   id,
+			Type: t,
+			Data: b["data"],
+			key:  string(k),
+		})
+	}
+
+	return blocks
+}
+
+type BlockDiffOut struct {
+	Op     string      `json:"op"`
+	Id     string      `json:"id"`
+	Type   string      `json:"type"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Blocks are compared with the longest common subsequence, then
+// a removed and an added block sharing the same id are merged
+// into a single changed block.
+func DiffBlocks(from, to map[string]interface{}) []BlockDiffOut {
+	a := editorBlocks(from)
+	b := editorBlocks(to)
+
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i].key == b[j].key {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var diffs []BlockDiffOut
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i].key == b[j].key:
+			diffs = append(diffs, BlockDiffOut{
+				Op:     BlockEqual,
+				Id:     b[j].Id,
+				Type:   b[j].Type,
+				Before: a[i].Data,
+				After:  b[j].Data,
+			})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			diffs = append(diffs, BlockDiffOut{
+				Op:    BlockAdded,
+				Id:    b[j].Id,
+				Type:  b[j].Type,
+				After: b[j].Data,
+			})
+			j++
+		default:
+			diffs = append(diffs, BlockDiffOut{
+				Op:     BlockRemoved,
+				Id:     a[i].Id,
+				Type:   a[i].Type,
+				Before: a[i].Data,
+			})
+			i++
+		}
+	}
+
+	removed := make(map[string]int)
+	for k, d := range diffs {
+		if d.Op == BlockRemoved && d.Id != "" {
+			removed[d.Id] = k
+		}
+	}
+
+	merged := make(map[int]bool)
+	for k, d := range diffs {
+		if d.Op != BlockAdded || d.Id == "" {
+			continue
+		}
+
+		if r, ok := removed[d.Id]; ok && !merged[r] {
+			diffs[k].Op = BlockChanged
+			diffs[k].Before = diffs[r].Before
+			merged[r] = true
+		}
+	}
+
+	res := make([]BlockDiffOut, 0, len(diffs))
+	for k, d := range diffs {
+		if !merged[k] {
+			res = append(res, d)
+		}
+	}
+
+	return res
+}
+
+type (
+	DiffRevisionRes struct {
+		FromId int64          `json:"from_id"`
+		ToId   int64          `json:"to_id"`
+		Blocks []BlockDiffOut `json:"blocks"`
+	}
+	DiffRevisionOut struct {
+		resp.Response
+		Res DiffRevisionRes
+	}
+)
+
+func (d *ArticleDeps) DiffRevision(ctx context.Context, pid, fromRid, toRid string) (out DiffRevisionOut) {
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	from, r := d.findRevision(ctx, pid, fromRid)
+	if r.Error != nil {
+		out.Response = r
+		return
+	}
+
+	to, r := d.findRevision(ctx, pid, toRid)
+	if r.Error != nil {
+		out.Response = r
+		return
+	}
+
+	out.Res = DiffRevisionRes{
+		FromId: int64(from.Id),
+		ToId:   int64(to.Id),
+		Blocks: DiffBlocks(from.Content, to.Content),
+	}
+
+	return
+}
+
+type (
+	RestoreRevisionRes struct {
+		Id         int64 `json:"id"`
+		RevisionId int64 `json:"revision_id"`
+	}
+	RestoreRevisionOut struct {
+		resp.Response
+		Res RestoreRevisionRes
+	}
+)
+
+func (d *ArticleDeps) RestoreRevision(ctx context.Context, uid, pid, rid string) (out RestoreRevisionOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	revision, r := d.findRevision(ctx, pid, rid)
+	if r.Error != nil {
+		out.Response = r
+		return
+	}
+
+	article, err := d.ArticleRepository.FindUndeletedById(ctx, revision.ArticleId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrArticleNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find article by id"))
+		return
+	}
+
+	article.Title = revision.Title
+	article.ShortDesc = revision.ShortDesc
+	article.ThumbnailUrl = revision.ThumbnailUrl
+	article.Content = revision.Content
+	article.ContentText = revision.ContentText
+
+	if err = d.ArticleRepository.UpdateById(ctx, article.Id, article); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update article by id"))
+		return
+	}
+
+	nrevision, err := d.SaveRevision(ctx, uid, article)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save revision"))
+		return
+	}
+
+	out.Res = RestoreRevisionRes{
+		Id:         int64(article.Id),
+		RevisionId: int64(nrevision.Id),
+	}
+
+	return
+}
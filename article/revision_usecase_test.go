@@ -0,0 +1,238 @@
+package article_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/article"
+)
+
+func TestQueryRevision(t *testing.T) {
+	err := ClearTables(postgrePool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := articleRepository.Save(context.Background(), articleSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = articleDeps.SaveRevision(context.Background(), authorId, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Id                 string
+	}{
+		{
+			Name:               "Query Revision Success",
+			ExpectedStatusCode: http.StatusOK,
+			Id:                 strconv.FormatUint(a.Id, 10),
+		},
+		{
+			Name:               "Query Revision Fail, Article Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Id:                 "999",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := articleDeps.QueryRevision(context.Background(), c.Id)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestFindRevision(t *testing.T) {
+	err := ClearTables(postgrePool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := articleRepository.Save(context.Background(), articleSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := articleDeps.SaveRevision(context.Background(), authorId, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pid := strconv.FormatUint(a.Id, 10)
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Id                 string
+		RevisionId         string
+	}{
+		{
+			Name:               "Find Revision Success",
+			ExpectedStatusCode: http.StatusOK,
+			Id:                 pid,
+			RevisionId:         strconv.FormatUint(r.Id, 10),
+		},
+		{
+			Name:               "Find Revision Fail, Revision Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Id:                 pid,
+			RevisionId:         "999",
+		},
+		{
+			Name:               "Find Revision Fail, Revision of Other Article",
+			ExpectedStatusCode: http.StatusNotFound,
+			Id:                 "999",
+			RevisionId:         strconv.FormatUint(r.Id, 10),
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := articleDeps.FindRevision(context.Background(), c.Id, c.RevisionId)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestDiffRevision(t *testing.T) {
+	err := ClearTables(postgrePool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := articleRepository.Save(context.Background(), articleSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.Content = map[string]interface{}{
+		"blocks": []interface{}{
+			map[string]interface{}{"id": "a", "type": "paragraph", "data": map[string]interface{}{"text": "satu"}},
+			map[string]interface{}{"id": "b", "type": "paragraph", "data": map[string]interface{}{"text": "dua"}},
+		},
+	}
+	r1, err := articleDeps.SaveRevision(context.Background(), authorId, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.Content = map[string]interface{}{
+		"blocks": []interface{}{
+			map[string]interface{}{"id": "a", "type": "paragraph", "data": map[string]interface{}{"text": "satu"}},
+			map[string]interface{}{"id": "b", "type": "paragraph", "data": map[string]interface{}{"text": "dua!"}},
+			map[string]interface{}{"id": "c", "type": "header", "data": map[string]interface{}{"text": "tiga"}},
+		},
+	}
+	r2, err := articleDeps.SaveRevision(context.Background(), authorId, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pid := strconv.FormatUint(a.Id, 10)
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		From               string
+		To                 string
+		ExpectedOps        []string
+	}{
+		{
+			Name:               "Diff Revision Success",
+			ExpectedStatusCode: http.StatusOK,
+			From:               strconv.FormatUint(r1.Id, 10),
+			To:                 strconv.FormatUint(r2.Id, 10),
+			ExpectedOps:        []string{article.BlockEqual, article.BlockChanged, article.BlockAdded},
+		},
+		{
+			Name:               "Diff Revision Fail, Revision Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			From:               strconv.FormatUint(r1.Id, 10),
+			To:                 "999",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := articleDeps.DiffRevision(context.Background(), pid, c.From, c.To)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if len(res.Res.Blocks) != len(c.ExpectedOps) {
+				t.Fatalf("Expected blocks length %d. Got %d\n", len(c.ExpectedOps), len(res.Res.Blocks))
+			}
+
+			for i, b := range res.Res.Blocks {
+				if b.Op != c.ExpectedOps[i] {
+					t.Fatalf("Expected block %d op %s. Got %s\n", i, c.ExpectedOps[i], b.Op)
+				}
+			}
+		})
+	}
+}
+
+func TestRestoreRevision(t *testing.T) {
+	err := ClearTables(postgrePool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := articleRepository.Save(context.Background(), articleSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := articleDeps.SaveRevision(context.Background(), authorId, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pid := strconv.FormatUint(a.Id, 10)
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		RevisionId         string
+	}{
+		{
+			Name:               "Restore Revision Success",
+			ExpectedStatusCode: http.StatusOK,
+			RevisionId:         strconv.FormatUint(r.Id, 10),
+		},
+		{
+			Name:               "Restore Revision Fail, Revision Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			RevisionId:         "999",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := articleDeps.RestoreRevision(context.Background(), authorId, pid, c.RevisionId)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package article
+
+import (
+	"time"
+)
+
+type TagModel struct {
+	Id        uint64
+	Name      string
+	CreatedAt time.Time
+}
+
+type TagCountModel struct {
+	Id   uint64
+	Name string
+	N    int64
+}
@@ -0,0 +1,197 @@
+package article
+
+import (
+	"context"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type TagRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewTagRepository(postgreDb *pgxpool.Pool) *TagRepository {
+	return &TagRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+type (
+	TagExecutor   func(ctx context.Context, sql string, arguments ...interface{}) (commandTag pgconn.CommandTag, err error)
+	TagQuerier    func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	TagCopierFrom func(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+)
+
+// Tags are free-form, so saving a name that already exists
+// returns the existing row instead of failing on the unique key.
+func (r *TagRepository) BulkSaveByName(ctx context.Context, names []string) ([]TagModel, error) {
+	sqlQuery := `
+		INSERT INTO tags (
+			name,
+			created_at
+		)
+		SELECT UNNEST($1::text[]), $2
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id, name, created_at
+	`
+
+	var query TagQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	t := time.Now()
+
+	rows, _ := query(
+		context.Background(),
+		sqlQuery,
+		names,
+		t,
+	)
+	defer rows.Close()
+
+	var mps []*TagModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []TagModel{}, err
+	}
+
+	ms := make([]TagModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *TagRepository) QueryByArticleId(ctx context.Context, articleId uint64) ([]TagModel, error) {
+	sqlQuery := `
+		SELECT
+			t.id,
+			t.name,
+			t.created_at
+		FROM article_tags atg
+			JOIN tags t ON t.id = atg.tag_id
+		WHERE atg.article_id = $1
+		ORDER BY t.name ASC
+	`
+
+	var query TagQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	rows, _ := query(
+		context.Background(),
+		sqlQuery,
+		articleId,
+	)
+	defer rows.Close()
+
+	var mps []*TagModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []TagModel{}, err
+	}
+
+	ms := make([]TagModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *TagRepository) QueryCount(ctx context.Context, limit int64) ([]TagCountModel, error) {
+	sqlQuery := `
+		SELECT
+			t.id,
+			t.name,
+			COUNT(a.id) AS n
+		FROM tags t
+			JOIN article_tags atg ON atg.tag_id = t.id
+			JOIN articles a ON a.id = atg.article_id
+		WHERE a.deleted_at IS NULL
+		GROUP BY t.id, t.name
+		ORDER BY n DESC, t.name ASC
+		LIMIT $1
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		limit,
+	)
+	defer rows.Close()
+
+	var mps []*TagCountModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []TagCountModel{}, err
+	}
+
+	ms := make([]TagCountModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *TagRepository) DeleteArticleTags(ctx context.Context, articleId uint64) error {
+	sqlQuery := `
+		DELETE FROM article_tags
+		WHERE article_id = $1
+	`
+
+	var exec TagExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		articleId,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TagRepository) BulkSaveArticleTags(ctx context.Context, articleId uint64, tagIds []uint64) error {
+	var copyFrom TagCopierFrom
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		copyFrom = tx.CopyFrom
+	} else {
+		copyFrom = r.PostgreDb.CopyFrom
+	}
+
+	_, err := copyFrom(
+		context.Background(),
+		pgx.Identifier{"article_tags"},
+		[]string{"article_id", "tag_id"},
+		pgx.CopyFromSlice(len(tagIds), func(i int) ([]interface{}, error) {
+			return []interface{}{articleId, tagIds[i]}, nil
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package article
+
+import (
+	"net/http"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+)
+
+func (d *ArticleDeps) GetTags(w http.ResponseWriter, r *http.Request) {
+	limit := r.URL.Query().Get("limit")
+	out := d.QueryTag(r.Context(), limit)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
@@ -0,0 +1,54 @@
+package article
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/pkg/errors"
+)
+
+type (
+	TagOut struct {
+		Id    int64  `json:"id"`
+		Name  string `json:"name"`
+		Total int64  `json:"total"`
+	}
+	QueryTagRes struct {
+		Tags []TagOut `json:"tags"`
+	}
+	QueryTagOut struct {
+		resp.Response
+		Res QueryTagRes
+	}
+)
+
+func (d *ArticleDeps) QueryTag(ctx context.Context, limit string) (out QueryTagOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	var n int64 = 50
+	if l, err := strconv.ParseInt(limit, 10, 64); err == nil && l > 0 {
+		n = l
+	}
+
+	tags, err := d.TagRepository.QueryCount(ctx, n)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query tag count"))
+		return
+	}
+
+	outTags := make([]TagOut, len(tags))
+	for i, t := range tags {
+		outTags[i] = TagOut{
+			Id:    int64(t.Id),
+			Name:  t.Name,
+			Total: t.N,
+		}
+	}
+
+	out.Res.Tags = outTags
+
+	return
+}
@@ -0,0 +1,108 @@
+package card
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/qrcode"
+)
+
+// The ID-1 card size, 85.6 x 54 mm, at 300 dpi
+const (
+	Width  = 1012
+	Height = 638
+)
+
+var (
+	headerColor  = color.RGBA{R: 0x1b, G: 0x5e, B: 0x20, A: 0xff}
+	textColor    = color.RGBA{R: 0x21, G: 0x21, B: 0x21, A: 0xff}
+	mutedColor   = color.RGBA{R: 0x61, G: 0x61, B: 0x61, A: 0xff}
+	photoBgColor = color.RGBA{R: 0xe0, G: 0xe0, B: 0xe0, A: 0xff}
+)
+
+type Card struct {
+	Title      string
+	Name       string
+	Position   string
+	Period     string
+	ValidUntil string
+	// A card without photo show a blank frame
+	Photo image.Image
+	Qr    *qrcode.Code
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+// Scale the photo to cover the frame, cropping the center of the longer
+// side, using the nearest pixel
+func drawPhoto(img *image.RGBA, frame image.Rectangle, photo image.Image) {
+	fillRect(img, frame, photoBgColor)
+	if photo == nil {
+		return
+	}
+
+	src := photo.Bounds()
+	if src.Empty() {
+		return
+	}
+
+	fw, fh := frame.Dx(), frame.Dy()
+	sw, sh := src.Dx(), src.Dy()
+	// Compare fw/fh with sw/sh without the floating point
+	cropW, cropH := sw, sh
+	if sw*fh > sh*fw {
+		cropW = sh * fw / fh
+	} else {
+		cropH = sw * fh / fw
+	}
+	offX := src.Min.X + (sw-cropW)/2
+	offY := src.Min.Y + (sh-cropH)/2
+
+	for y := 0; y < fh; y++ {
+		for x := 0; x < fw; x++ {
+			img.Set(frame.Min.X+x, frame.Min.Y+y, photo.At(offX+x*cropW/fw, offY+y*cropH/fh))
+		}
+	}
+}
+
+func Render(c Card) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	fillRect(img, img.Bounds(), color.White)
+
+	fillRect(img, image.Rect(0, 0, Width, 120), headerColor)
+	drawText(img, 40, 39, 6, fitText(c.Title, Width-80, 6), color.White)
+
+	drawPhoto(img, image.Rect(40, 160, 280, 460), c.Photo)
+
+	textX := 320
+	nameScale := 5
+	if textWidth(c.Name, nameScale) > Width-40-textX {
+		nameScale = 3
+	}
+	drawText(img, textX, 170, nameScale, fitText(c.Name, Width-40-textX, nameScale), textColor)
+	drawText(img, textX, 250, 3, fitText(c.Position, Width-40-textX, 3), mutedColor)
+	drawText(img, textX, 290, 3, fitText(c.Period, Width-40-textX, 3), mutedColor)
+
+	qrRight := Width - 40
+	if c.Qr != nil {
+		scale := 240 / (c.Qr.Size + 4)
+		qr := c.Qr.Image(scale, 2)
+		at := image.Pt(Width-40-qr.Bounds().Dx(), Height-40-qr.Bounds().Dy())
+		draw.Draw(img, qr.Bounds().Add(at), qr, image.Point{}, draw.Src)
+		qrRight = at.X - 20
+	}
+
+	drawText(img, textX, 480, 3, fitText("Berlaku hingga", qrRight-textX, 3), mutedColor)
+	drawText(img, textX, 520, 4, fitText(c.ValidUntil, qrRight-textX, 4), textColor)
+
+	return img
+}
+
+func EncodePNG(w io.Writer, c Card) error {
+	return png.Encode(w, Render(c))
+}
@@ -0,0 +1,234 @@
+package card_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/card"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/qrcode"
+)
+
+func newCard(t *testing.T) card.Card {
+	qr, err := qrcode.Encode([]byte("uhomestay"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	photo := image.NewRGBA(image.Rect(0, 0, 300, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 300; x++ {
+			photo.Set(x, y, color.RGBA{R: 0xff, A: 0xff})
+		}
+	}
+
+	return card.Card{
+		Title:      "Kartu Anggota",
+		Name:       "Fikry Fahrezy",
+		Position:   "Ketua",
+		Period:     "Periode 2022 - 2023",
+		ValidUntil: "19-10-2027",
+		Photo:      photo,
+		Qr:         qr,
+	}
+}
+
+func TestRender(t *testing.T) {
+	testCases := []struct {
+		name  string
+		init  func(c card.Card) card.Card
+		photo color.RGBA
+	}{
+		{
+			name: "Render Card Success",
+			init: func(c card.Card) card.Card {
+				return c
+			},
+			photo: color.RGBA{R: 0xff, A: 0xff},
+		},
+		{
+			name: "Render Card without Photo and QR Success",
+			init: func(c card.Card) card.Card {
+				c.Photo = nil
+				c.Qr = nil
+				return c
+			},
+			photo: color.RGBA{R: 0xe0, G: 0xe0, B: 0xe0, A: 0xff},
+		},
+		{
+			name: "Render Card with Long Name Success",
+			init: func(c card.Card) card.Card {
+				c.Name = strings.Repeat("Nama Panjang ", 10)
+				return c
+			},
+			photo: color.RGBA{R: 0xff, A: 0xff},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			img := card.Render(c.init(newCard(t)))
+			if img.Bounds().Dx() != card.Width || img.Bounds().Dy() != card.Height {
+				t.Fatalf("Expected size %dx%d. Got %v", card.Width, card.Height, img.Bounds())
+			}
+
+			if got := img.RGBAAt(160, 310); got != c.photo {
+				t.Fatalf("Expected photo color %v. Got %v", c.photo, got)
+			}
+		})
+	}
+}
+
+func TestRenderQr(t *testing.T) {
+	c := newCard(t)
+	img := card.Render(c)
+
+	// The code is drawn on the bottom right with a quiet zone of 2 modules
+	scale := 240 / (c.Qr.Size + 4)
+	side := (c.Qr.Size + 4) * scale
+	at := image.Pt(card.Width-40-side, card.Height-40-side)
+	for y := -2; y < c.Qr.Size+2; y++ {
+		for x := -2; x < c.Qr.Size+2; x++ {
+			px := img.RGBAAt(at.X+(x+2)*scale+scale/2, at.Y+(y+2)*scale+scale/2)
+			isDark := px.R < 128
+			if isDark != c.Qr.Dark(x, y) {
+				t.Fatalf("Expected module (%d, %d) dark %t", x, y, c.Qr.Dark(x, y))
+			}
+		}
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	c := newCard(t)
+	img := card.Render(c)
+
+	// The validity is printed at 320, 520 with 4 pixels per dot, each
+	// character read back as its 5x7 dots
+	cells := make([]string, len(c.ValidUntil))
+	for i := range cells {
+		var dots strings.Builder
+		for y := 0; y < 7; y++ {
+			for x := 0; x < 6; x++ {
+				px := img.RGBAAt(320+(i*6+x)*4+2, 520+y*4+2)
+				if px.R < 128 {
+					dots.WriteByte('#')
+				} else {
+					dots.WriteByte('.')
+				}
+			}
+		}
+		cells[i] = dots.String()
+	}
+
+	for i := range cells {
+		if !strings.Contains(cells[i], "#") {
+			t.Fatalf("Expected character %q printed", c.ValidUntil[i])
+		}
+
+		for j := range cells {
+			if (c.ValidUntil[i] == c.ValidUntil[j]) != (cells[i] == cells[j]) {
+				t.Fatalf("Expected characters %q and %q printed distinctly", c.ValidUntil[i], c.ValidUntil[j])
+			}
+		}
+	}
+
+	if cells[2] != "......"+"......"+"......"+"#####."+"......"+"......"+"......" {
+		t.Fatalf("Expected a dash. Got %s", cells[2])
+	}
+}
+
+func TestEncodePNG(t *testing.T) {
+	var b bytes.Buffer
+	if err := card.EncodePNG(&b, newCard(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if img.Bounds().Dx() != card.Width {
+		t.Fatalf("Expected width %d. Got %d", card.Width, img.Bounds().Dx())
+	}
+}
+
+func TestEncodePDF(t *testing.T) {
+	var b bytes.Buffer
+	if err := card.EncodePDF(&b, newCard(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	pdf := b.String()
+	if !strings.HasPrefix(pdf, "%PDF-1.4") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Fatal("Expected a PDF document")
+	}
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(pdf)
+	if m == nil {
+		t.Fatal("Expected startxref")
+	}
+
+	xref, _ := strconv.Atoi(m[1])
+	if !strings.HasPrefix(pdf[xref:], "xref\n0 6\n") {
+		t.Fatalf("Expected xref table at %d", xref)
+	}
+
+	// Every entry point to its object
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(pdf[xref:], -1)
+	if len(entries) != 5 {
+		t.Fatalf("Expected 5 objects. Got %d", len(entries))
+	}
+
+	for i, e := range entries {
+		offset, _ := strconv.Atoi(e[1])
+		if !strings.HasPrefix(pdf[offset:], strconv.Itoa(i+1)+" 0 obj") {
+			t.Fatalf("Expected object %d at %d", i+1, offset)
+		}
+	}
+
+	// The image stream hold the pixels of the rendered card
+	loc := regexp.MustCompile(`/Width (\d+) /Height (\d+) /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length (\d+) >>\nstream\n`).FindStringSubmatchIndex(pdf)
+	if loc == nil {
+		t.Fatal("Expected image stream")
+	}
+
+	width, _ := strconv.Atoi(pdf[loc[2]:loc[3]])
+	height, _ := strconv.Atoi(pdf[loc[4]:loc[5]])
+	length, _ := strconv.Atoi(pdf[loc[6]:loc[7]])
+	if width != card.Width || height != card.Height {
+		t.Fatalf("Expected image size %dx%d. Got %dx%d", card.Width, card.Height, width, height)
+	}
+
+	zr, err := zlib.NewReader(strings.NewReader(pdf[loc[1] : loc[1]+length]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pixels, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := card.Render(newCard(t))
+	if len(pixels) != card.Width*card.Height*3 {
+		t.Fatalf("Expected %d bytes of pixels. Got %d", card.Width*card.Height*3, len(pixels))
+	}
+
+	for y := 0; y < card.Height; y++ {
+		for x := 0; x < card.Width; x++ {
+			px := img.RGBAAt(x, y)
+			i := (y*card.Width + x) * 3
+			if pixels[i] != px.R || pixels[i+1] != px.G || pixels[i+2] != px.B {
+				t.Fatalf("Expected pixel (%d, %d) %v", x, y, px)
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package card
+
+import (
+	"image"
+	"image/color"
+	"strings"
+)
+
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+// Uppercase 5x7 bitmap font, the card only print names and dates so the
+// lowercase letters are printed as the uppercase ones
+var glyphs = map[rune][glyphHeight]string{
+	' ':  {".....", ".....", ".....", ".....", ".....", ".....", "....."},
+	'A':  {".###.", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'B':  {"####.", "#...#", "#...#", "####.", "#...#", "#...#", "####."},
+	'C':  {".###.", "#...#", "#....", "#....", "#....", "#...#", ".###."},
+	'D':  {"###..", "#..#.", "#...#", "#...#", "#...#", "#..#.", "###.."},
+	'E':  {"#####", "#....", "#....", "####.", "#....", "#....", "#####"},
+	'F':  {"#####", "#....", "#....", "####.", "#....", "#....", "#...."},
+	'G':  {".###.", "#...#", "#....", "#.###", "#...#", "#...#", ".####"},
+	'H':  {"#...#", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'I':  {".###.", "..#..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'J':  {"..###", "...#.", "...#.", "...#.", "...#.", "#..#.", ".##.."},
+	'K':  {"#...#", "#..#.", "#.#..", "##...", "#.#..", "#..#.", "#...#"},
+	'L':  {"#....", "#....", "#....", "#....", "#....", "#....", "#####"},
+	'M':  {"#...#", "##.##", "#.#.#", "#.#.#", "#...#", "#...#", "#...#"},
+	'N':  {"#...#", "#...#", "##..#", "#.#.#", "#..##", "#...#", "#...#"},
+	'O':  {".###.", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'P':  {"####.", "#...#", "#...#", "####.", "#....", "#....", "#...."},
+	'Q':  {".###.", "#...#", "#...#", "#...#", "#.#.#", "#..#.", ".##.#"},
+	'R':  {"####.", "#...#", "#...#", "####.", "#.#..", "#..#.", "#...#"},
+	'S':  {".####", "#....", "#....", ".###.", "....#", "....#", "####."},
+	'T':  {"#####", "..#..", "..#..", "..#..", "..#..", "..#..", "..#.."},
+	'U':  {"#...#", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'V':  {"#...#", "#...#", "#...#", "#...#", "#...#", ".#.#.", "..#.."},
+	'W':  {"#...#", "#...#", "#...#", "#.#.#", "#.#.#", "#.#.#", ".#.#."},
+	'X':  {"#...#", "#...#", ".#.#.", "..#..", ".#.#.", "#...#", "#...#"},
+	'Y':  {"#...#", "#...#", ".#.#.", "..#..", "..#..", "..#..", "..#.."},
+	'Z':  {"#####", "....#", "...#.", "..#..", ".#...", "#....", "#####"},
+	'0':  {".###.", "#...#", "#..##", "#.#.#", "##..#", "#...#", ".###."},
+	'1':  {"..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'2':  {".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"},
+	'3':  {"#####", "...#.", "..#..", "...#.", "....#", "#...#", ".###."},
+	'4':  {"...#.", "..##.", ".#.#.", "#..#.", "#####", "...#.", "...#."},
+	'5':  {"#####", "#....", "####.", "....#", "....#", "#...#", ".###."},
+	'6':  {"..##.", ".#...", "#....", "####.", "#...#", "#...#", ".###."},
+	'7':  {"#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#..."},
+	'8':  {".###.", "#...#", "#...#", ".###.", "#...#", "#...#", ".###."},
+	'9':  {".###.", "#...#", "#...#", ".####", "....#", "...#.", ".##.."},
+	'.':  {".....", ".....", ".....", ".....", ".....", ".##..", ".##.."},
+	',':  {".....", ".....", ".....", ".....", ".##..", "..#..", ".#..."},
+	'-':  {".....", ".....", ".....", "#####", ".....", ".....", "....."},
+	':':  {".....", ".##..", ".##..", ".....", ".##..", ".##..", "....."},
+	'/':  {".....", "....#", "...#.", "..#..", ".#...", "#....", "....."},
+	'\'': {"..#..", "..#..", ".#...", ".....", ".....", ".....", "....."},
+	'(':  {"...#.", "..#..", ".#...", ".#...", ".#...", "..#..", "...#."},
+	')':  {".#...", "..#..", "...#.", "...#.", "...#.", "..#..", ".#..."},
+	'&':  {".##..", "#..#.", "#.#..", ".#...", "#.#.#", "#..#.", ".##.#"},
+	'+':  {".....", "..#..", "..#..", "#####", "..#..", "..#..", "....."},
+	'?':  {".###.", "#...#", "....#", "...#.", "..#..", ".....", "..#.."},
+}
+
+// Width of the text in pixel, a glyph is followed by a one column gap
+func textWidth(text string, scale int) int {
+	return len([]rune(text)) * (glyphWidth + 1) * scale
+}
+
+// Cut the text so it fit the width, the cut text end with two dots
+func fitText(text string, width, scale int) string {
+	text = strings.ToUpper(text)
+	if textWidth(text, scale) <= width {
+		return text
+	}
+
+	r := []rune(text)
+	for len(r) > 0 && textWidth(string(r)+"..", scale) > width {
+		r = r[:len(r)-1]
+	}
+
+	return strings.TrimRight(string(r), " ") + ".."
+}
+
+// Draw the text with its top left corner at x, y, a character without a
+// glyph is drawn as a question mark
+func drawText(img *image.RGBA, x, y, scale int, text string, c color.Color) {
+	for _, ch := range strings.ToUpper(text) {
+		g, ok := glyphs[ch]
+		if !ok {
+			g = glyphs['?']
+		}
+
+		for gy, row := range g {
+			for gx, px := range row {
+				if px != '#' {
+					continue
+				}
+				fillRect(img, image.Rect(x+gx*scale, y+gy*scale, x+(gx+1)*scale, y+(gy+1)*scale), c)
+			}
+		}
+
+		x += (glyphWidth + 1) * scale
+	}
+}
@@ -0,0 +1,76 @@
+package card
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// The ID-1 card size in point
+const (
+	pageWidth  = 242.65
+	pageHeight = 153.07
+)
+
+// A one page PDF with the rendered card as its only content, so the
+// printed card look the same as the PNG
+// Ref: https://opensource.adobe.com/dc-acrobat-sdk-docs/pdfstandards/PDF32000_2008.pdf
+func EncodePDF(wr io.Writer, c Card) error {
+	img := Render(c)
+	b := img.Bounds()
+
+	var pixels bytes.Buffer
+	zw := zlib.NewWriter(&pixels)
+	row := make([]byte, b.Dx()*3)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			i := img.PixOffset(x, y)
+			copy(row[(x-b.Min.X)*3:], img.Pix[i:i+3])
+		}
+		if _, err := zw.Write(row); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	content := fmt.Sprintf("q %.2f 0 0 %.2f 0 0 cm /Card Do Q", pageWidth, pageHeight)
+
+	w := bufio.NewWriter(wr)
+	var offsets []int
+	written := 0
+	write := func(s string) {
+		n, _ := w.WriteString(s)
+		written += n
+	}
+	object := func(body string, stream []byte) {
+		offsets = append(offsets, written)
+		write(fmt.Sprintf("%d 0 obj\n%s\n", len(offsets), body))
+		if stream != nil {
+			write("stream\n")
+			n, _ := w.Write(stream)
+			written += n
+			write("\nendstream\n")
+		}
+		write("endobj\n")
+	}
+
+	write("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>", nil)
+	object("<< /Type /Pages /Kids [3 0 R] /Count 1 >>", nil)
+	object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /XObject << /Card 4 0 R >> >> /Contents 5 0 R >>", pageWidth, pageHeight), nil)
+	object(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>", b.Dx(), b.Dy(), pixels.Len()), pixels.Bytes())
+	object(fmt.Sprintf("<< /Length %d >>", len(content)), []byte(content))
+
+	xref := written
+	write(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1))
+	for _, o := range offsets {
+		write(fmt.Sprintf("%010d 00000 n \n", o))
+	}
+	write(fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref))
+
+	return w.Flush()
+}
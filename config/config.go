@@ -3,19 +3,27 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
-	JwtKey          []byte
-	CloudinaryUrl   string
-	Port            string
-	Argon2Salt      string
-	JwtAudiencesStr string
-	JwtKeyStr       string
-	JwtIssuerUrl    string
-	PostgreUrl      string
-	JwtAudiences    []string
+	JwtKey                    []byte
+	CloudinaryUrl             string
+	Port                      string
+	Argon2Salt                string
+	JwtAudiencesStr           string
+	JwtKeyStr                 string
+	JwtIssuerUrl              string
+	PostgreUrl                string
+	JwtAudiences              []string
+	IcalSyncInterval          time.Duration
+	RegistrationPurgeInterval time.Duration
+	RejectedMemberRetention   time.Duration
+	FileRemovalRetryInterval  time.Duration
+	TrustedProxies            int
+	PublicUrl                 string
 }
 
 func LoadConfig() Config {
@@ -65,5 +73,58 @@ func LoadConfig() Config {
 	}
 	c.PostgreUrl = postgreUrl
 
+	c.IcalSyncInterval = 30 * time.Minute
+	if icalSyncInterval := os.Getenv("HOMESTAY_ICAL_SYNC_INTERVAL"); icalSyncInterval != "" {
+		d, err := time.ParseDuration(icalSyncInterval)
+		if err != nil || d <= 0 {
+			log.Fatal("$HOMESTAY_ICAL_SYNC_INTERVAL must be a positive duration, e.g. 30m")
+		}
+		c.IcalSyncInterval = d
+	}
+
+	c.RegistrationPurgeInterval = 24 * time.Hour
+	if registrationPurgeInterval := os.Getenv("HOMESTAY_REGISTRATION_PURGE_INTERVAL"); registrationPurgeInterval != "" {
+		d, err := time.ParseDuration(registrationPurgeInterval)
+		if err != nil || d <= 0 {
+			log.Fatal("$HOMESTAY_REGISTRATION_PURGE_INTERVAL must be a positive duration, e.g. 24h")
+		}
+		c.RegistrationPurgeInterval = d
+	}
+
+	c.RejectedMemberRetention = 30 * 24 * time.Hour
+	if rejectedMemberRetention := os.Getenv("HOMESTAY_REJECTED_MEMBER_RETENTION"); rejectedMemberRetention != "" {
+		d, err := time.ParseDuration(rejectedMemberRetention)
+		if err != nil || d <= 0 {
+			log.Fatal("$HOMESTAY_REJECTED_MEMBER_RETENTION must be a positive duration, e.g. 720h")
+		}
+		c.RejectedMemberRetention = d
+	}
+
+	c.FileRemovalRetryInterval = time.Hour
+	if fileRemovalRetryInterval := os.Getenv("HOMESTAY_FILE_REMOVAL_RETRY_INTERVAL"); fileRemovalRetryInterval != "" {
+		d, err := time.ParseDuration(fileRemovalRetryInterval)
+		if err != nil || d <= 0 {
+			log.Fatal("$HOMESTAY_FILE_REMOVAL_RETRY_INTERVAL must be a positive duration, e.g. 1h")
+		}
+		c.FileRemovalRetryInterval = d
+	}
+
+	// Number of proxies in front of the app appending the client IP to
+	// X-Forwarded-For, e.g. 1 behind the Heroku router
+	if trustedProxies := os.Getenv("HOMESTAY_TRUSTED_PROXIES"); trustedProxies != "" {
+		n, err := strconv.Atoi(trustedProxies)
+		if err != nil || n < 0 {
+			log.Fatal("$HOMESTAY_TRUSTED_PROXIES must be a non negative number, e.g. 1")
+		}
+		c.TrustedProxies = n
+	}
+
+	// Url the app is reached at, the member card qr code point to it
+	publicUrl := os.Getenv("HOMESTAY_PUBLIC_URL")
+	if publicUrl == "" {
+		publicUrl = "http://localhost:" + port
+	}
+	c.PublicUrl = strings.TrimRight(publicUrl, "/")
+
 	return c
 }
@@ -35,14 +35,16 @@ type (
 		Url       string `json:"url"`
 	}
 	MemberOut struct {
-		Id            string `json:"id"`
-		Username      string `json:"username"`
-		Name          string `json:"name"`
-		WaPhone       string `json:"wa_phone"`
-		OtherPhone    string `json:"other_phone"`
-		ProfilePicUrl string `json:"profile_pic_url"`
-		IsAdmin       bool   `json:"is_admin"`
-		IsApproved    bool   `json:"is_approved"`
+		Id             string `json:"id"`
+		Username       string `json:"username"`
+		Name           string `json:"name"`
+		WaPhone        string `json:"wa_phone"`
+		OtherPhone     string `json:"other_phone"`
+		ProfilePicUrl  string `json:"profile_pic_url"`
+		IsAdmin        bool   `json:"is_admin"`
+		IsApproved     bool   `json:"is_approved"`
+		ApprovalStatus string `json:"approval_status"`
+		Status         string `json:"status"`
 	}
 	DuesOut struct {
 		Id        int64  `json:"id"`
@@ -113,4 +115,14 @@ type (
 		Url         string `json:"url"`
 		Description string `json:"description"`
 	}
+	HomestayMonthlyStatsOut struct {
+		Month              string  `json:"month"`
+		ListingTotal       int64   `json:"listing_total"`
+		RoomTotal          int64   `json:"room_total"`
+		RoomNights         int64   `json:"room_nights"`
+		BookedNights       int64   `json:"booked_nights"`
+		OccupancyRate      float64 `json:"occupancy_rate"`
+		IdrAvgNightlyPrice string  `json:"idr_avg_nightly_price"`
+		IdrRevenue         string  `json:"idr_revenue"`
+	}
 )
@@ -3,11 +3,18 @@ package dashboard
 import (
 	"net/http"
 
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
 )
 
 func (d *DashboardDeps) GetPrivateDashboard(w http.ResponseWriter, r *http.Request) {
-	out := d.GetPrivate(r.Context())
+	var jwtPayload jwt.JwtPrivateAdminClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.GetPrivate(r.Context(), jwtPayload.Uid)
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
 
@@ -4,29 +4,31 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/article"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/dues"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
 )
 
 type (
 	PrivateRes struct {
-		MemberTotal     int64                `json:"member_total"`
-		DocumentTotal   int64                `json:"document_total"`
-		ArticleTotal    int64                `json:"article_total"`
-		PositionTotal   int64                `json:"position_total"`
-		MemberDuesTotal int64                `json:"member_dues_total"`
-		ImageTotal      int64                `json:"image_total"`
-		Documents       []DocumentOut        `json:"documents"`
-		Members         []MemberOut          `json:"members"`
-		Cashflows       CashflowRes          `json:"cashflow"`
-		Dues            DuesOut              `json:"dues"`
-		Articles        []ArticleOut         `json:"articles"`
-		Positions       []PositionOut        `json:"positions"`
-		LatestHistory   LatestHistoryRes     `json:"latest_history"`
-		MemberDues      []MembersDuesOut     `json:"member_dues"`
-		OrgPeriodGoal   FindOrgPeriodGoalRes `json:"org_period_goal"`
-		ActivePeriod    PeriodRes            `json:"active_period"`
-		Images          []ImageOut           `json:"images"`
+		MemberTotal     int64                     `json:"member_total"`
+		DocumentTotal   int64                     `json:"document_total"`
+		ArticleTotal    int64                     `json:"article_total"`
+		PositionTotal   int64                     `json:"position_total"`
+		MemberDuesTotal int64                     `json:"member_dues_total"`
+		ImageTotal      int64                     `json:"image_total"`
+		Documents       []DocumentOut             `json:"documents"`
+		Members         []MemberOut               `json:"members"`
+		Cashflows       CashflowRes               `json:"cashflow"`
+		Dues            DuesOut                   `json:"dues"`
+		Articles        []ArticleOut              `json:"articles"`
+		Positions       []PositionOut             `json:"positions"`
+		LatestHistory   LatestHistoryRes          `json:"latest_history"`
+		MemberDues      []MembersDuesOut          `json:"member_dues"`
+		OrgPeriodGoal   FindOrgPeriodGoalRes      `json:"org_period_goal"`
+		ActivePeriod    PeriodRes                 `json:"active_period"`
+		Images          []ImageOut                `json:"images"`
+		HomestayStats   []HomestayMonthlyStatsOut `json:"homestay_stats"`
 	}
 	PrivateOut struct {
 		resp.Response
@@ -34,7 +36,7 @@ type (
 	}
 )
 
-func (d *DashboardDeps) GetPrivate(ctx context.Context) (out PrivateOut) {
+func (d *DashboardDeps) GetPrivate(ctx context.Context, uid string) (out PrivateOut) {
 	out.Response = resp.NewResponse(http.StatusOK, "", nil)
 
 	c := make(chan CashflowRes)
@@ -56,7 +58,7 @@ func (d *DashboardDeps) GetPrivate(ctx context.Context) (out PrivateOut) {
 	mt := make(chan int64)
 	mr := make(chan resp.Response)
 	go func(ctx context.Context, m chan []MemberOut, mt chan int64, res chan resp.Response) {
-		out := d.QueryMember(ctx, "", "", "5")
+		out := d.QueryMember(ctx, uid, "", "", "5")
 
 		l := len(out.Res.Members)
 		if l > 5 {
@@ -108,7 +110,7 @@ func (d *DashboardDeps) GetPrivate(ctx context.Context) (out PrivateOut) {
 	bt := make(chan int64)
 	br := make(chan resp.Response)
 	go func(ctx context.Context, b chan []ArticleOut, bt chan int64, res chan resp.Response) {
-		out := d.QueryArticle(ctx, "", "")
+		out := d.QueryArticle(ctx, article.QueryArticleQIn{})
 
 		l := len(out.Res.Articles)
 		if l > 5 {
@@ -206,6 +208,20 @@ func (d *DashboardDeps) GetPrivate(ctx context.Context) (out PrivateOut) {
 		res <- out.Response
 	}(ctx, pe, per)
 
+	hs := make(chan []HomestayMonthlyStatsOut)
+	hsr := make(chan resp.Response)
+	go func(ctx context.Context, hs chan []HomestayMonthlyStatsOut, res chan resp.Response) {
+		out := d.QueryHomestayStats(ctx, "", "", "")
+
+		ms := make([]HomestayMonthlyStatsOut, len(out.Res.Months))
+		for i, v := range out.Res.Months {
+			ms[i] = HomestayMonthlyStatsOut(v)
+		}
+
+		hs <- ms
+		res <- out.Response
+	}(ctx, hs, hsr)
+
 	imgs, imgT, _ := func(ctx context.Context) (imgs []ImageOut, imgT int64, res resp.Response) {
 		out := d.QueryImage(ctx, "", "5")
 
@@ -250,6 +266,8 @@ func (d *DashboardDeps) GetPrivate(ctx context.Context) (out PrivateOut) {
 	<-opr
 	peV := <-pe
 	<-per
+	hsV := <-hs
+	<-hsr
 
 	out.Res = PrivateRes{
 		MemberTotal:     mtV,
@@ -269,6 +287,7 @@ func (d *DashboardDeps) GetPrivate(ctx context.Context) (out PrivateOut) {
 		OrgPeriodGoal:   opV,
 		ActivePeriod:    peV,
 		Images:          imgs,
+		HomestayStats:   hsV,
 	}
 
 	return
@@ -314,7 +333,7 @@ func (d *DashboardDeps) GetPublic(ctx context.Context) (out PublicOut) {
 	b := make(chan []ArticleOut)
 	br := make(chan resp.Response)
 	go func(ctx context.Context, b chan []ArticleOut, res chan resp.Response) {
-		out := d.QueryArticle(ctx, "", "")
+		out := d.QueryArticle(ctx, article.QueryArticleQIn{})
 
 		l := len(out.Res.Articles)
 		if l > 4 {
@@ -1,6 +1,11 @@
 package dashboard
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/article"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/cashflow"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/document"
@@ -11,7 +16,11 @@ import (
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
 )
 
+type FileFetcher func(ctx context.Context, url string) (io.ReadCloser, error)
+
 type DashboardDeps struct {
+	PublicUrl string
+	FetchFile FileFetcher
 	*history.HistoryDeps
 	*image.ImageDeps
 	*homestay.HomestayDeps
@@ -23,6 +32,8 @@ type DashboardDeps struct {
 }
 
 func NewDeps(
+	publicUrl string,
+	fetchFile FileFetcher,
 	historyDeps *history.HistoryDeps,
 	imageDeps *image.ImageDeps,
 	homestayDeps *homestay.HomestayDeps,
@@ -33,6 +44,8 @@ func NewDeps(
 	userDeps *user.UserDeps,
 ) *DashboardDeps {
 	return &DashboardDeps{
+		PublicUrl:    publicUrl,
+		FetchFile:    fetchFile,
 		HistoryDeps:  historyDeps,
 		ImageDeps:    imageDeps,
 		HomestayDeps: homestayDeps,
@@ -43,3 +56,24 @@ func NewDeps(
 		UserDeps:     userDeps,
 	}
 }
+
+func HttpFileFetch(client *http.Client) FileFetcher {
+	return func(ctx context.Context, url string) (io.ReadCloser, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+		}
+
+		return resp.Body, nil
+	}
+}
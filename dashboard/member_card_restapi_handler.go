@@ -0,0 +1,29 @@
+package dashboard
+
+import (
+	"net/http"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+)
+
+func (d *DashboardDeps) GetMemberCard(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	out := d.RenderMemberCard(r.Context(), jwtPayload.Uid, format)
+	if out.Error != nil {
+		out.HttpJSON(w, nil)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", out.Res.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="`+out.Res.Filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(out.Res.Card)
+}
@@ -0,0 +1,138 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/card"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/qrcode"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidCardFormat = errors.New("format kartu harus berupa png atau pdf")
+
+const (
+	CardFormatPng = "png"
+	CardFormatPdf = "pdf"
+)
+
+type (
+	RenderMemberCardRes struct {
+		Filename    string
+		ContentType string
+		Card        []byte
+	}
+	RenderMemberCardOut struct {
+		resp.Response
+		Res RenderMemberCardRes
+	}
+)
+
+// A photo that can not be fetched or decoded leave the photo frame blank
+// instead of failing the card
+func (d *DashboardDeps) fetchCardPhoto(ctx context.Context, url string) image.Image {
+	if url == "" {
+		return nil
+	}
+
+	body, err := d.FetchFile(ctx, url)
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+
+	photo, _, err := image.Decode(body)
+	if err != nil {
+		return nil
+	}
+
+	return photo
+}
+
+// The card of the member with the name, the photo, the current position,
+// and the validity, the qr code carry the url of VerifyMemberCard with the
+// token so any scanner open the verification
+func (d *DashboardDeps) RenderMemberCard(ctx context.Context, uid, format string) (out RenderMemberCardOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if format == "" {
+		format = CardFormatPng
+	}
+	if format != CardFormatPng && format != CardFormatPdf {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidCardFormat)
+		return
+	}
+
+	issued := d.IssueMemberCard(ctx, uid)
+	if issued.Error != nil {
+		out.Response = issued.Response
+		return
+	}
+
+	detail := d.FindMemberDetail(ctx, uid, uid)
+	if detail.Error != nil {
+		out.Response = detail.Response
+		return
+	}
+
+	verifyUrl := d.PublicUrl + "/api/v1/cards/verify?" + url.Values{"token": {issued.Res.Token}}.Encode()
+	qr, err := qrcode.Encode([]byte(verifyUrl))
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "encode card qr code"))
+		return
+	}
+
+	position := "Anggota"
+	if len(detail.Res.Positions) != 0 {
+		names := make([]string, len(detail.Res.Positions))
+		for i, p := range detail.Res.Positions {
+			names[i] = p.Name
+		}
+		position = strings.Join(names, ", ")
+	}
+
+	period := ""
+	if detail.Res.PeriodId != 0 {
+		period = "Periode " + detail.Res.Period
+	}
+
+	c := card.Card{
+		Title:      "Kartu Anggota",
+		Name:       detail.Res.Name,
+		Position:   position,
+		Period:     period,
+		ValidUntil: issued.Res.ValidUntil.Format("02-01-2006"),
+		Photo:      d.fetchCardPhoto(ctx, detail.Res.ProfilePicUrl),
+		Qr:         qr,
+	}
+
+	var b bytes.Buffer
+	contentType := "image/png"
+	if format == CardFormatPdf {
+		contentType = "application/pdf"
+		err = card.EncodePDF(&b, c)
+	} else {
+		err = card.EncodePNG(&b, c)
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "encode card"))
+		return
+	}
+
+	out.Res = RenderMemberCardRes{
+		Filename:    "uhomestay-card-" + detail.Res.Username + "." + format,
+		ContentType: contentType,
+		Card:        b.Bytes(),
+	}
+
+	return
+}
@@ -0,0 +1,28 @@
+package dashboard
+
+import (
+	"net/http"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+)
+
+func (d *DashboardDeps) GetMemberExport(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.ExportMemberData(r.Context(), jwtPayload.Uid)
+	if out.Error != nil {
+		out.HttpJSON(w, nil)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+out.Res.Filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(out.Res.Archive)
+}
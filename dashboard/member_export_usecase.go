@@ -0,0 +1,310 @@
+package dashboard
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+var ErrMemberNotFound = errors.New("anggota tidak ditemukan")
+
+type (
+	MemberExportProfile struct {
+		Id                   string    `json:"id"`
+		Name                 string    `json:"name"`
+		Username             string    `json:"username"`
+		WaPhone              string    `json:"wa_phone"`
+		OtherPhone           string    `json:"other_phone"`
+		UsernameVisibility   string    `json:"username_visibility"`
+		WaPhoneVisibility    string    `json:"wa_phone_visibility"`
+		OtherPhoneVisibility string    `json:"other_phone_visibility"`
+		IsAdmin              bool      `json:"is_admin"`
+		ApprovalStatus       string    `json:"approval_status"`
+		Status               string    `json:"status"`
+		SuspendedUntil       null.Time `json:"suspended_until"`
+		ProfilePicUrl        string    `json:"profile_pic_url"`
+		ProfilePicFile       string    `json:"profile_pic_file"`
+		IdCardUrl            string    `json:"id_card_url"`
+		IdCardFile           string    `json:"id_card_file"`
+		CreatedAt            time.Time `json:"created_at"`
+	}
+	MemberExportPosition struct {
+		PositionName    string `json:"position_name"`
+		PositionLevel   int64  `json:"position_level"`
+		PeriodId        int64  `json:"period_id"`
+		PeriodStartDate string `json:"period_start_date"`
+		PeriodEndDate   string `json:"period_end_date"`
+	}
+	MemberExportDues struct {
+		Id           int64     `json:"id"`
+		DuesId       int64     `json:"dues_id"`
+		Date         string    `json:"date"`
+		IdrAmount    string    `json:"idr_amount"`
+		Status       string    `json:"status"`
+		PayDate      null.Time `json:"pay_date"`
+		ProveFileUrl string    `json:"prove_file_url"`
+		ProveFile    string    `json:"prove_file"`
+	}
+	MemberExportHomestayImage struct {
+		Id       int64  `json:"id"`
+		Caption  string `json:"caption"`
+		Position int64  `json:"position"`
+		Url      string `json:"url"`
+		File     string `json:"file"`
+	}
+	MemberExportHomestay struct {
+		Id           int64                       `json:"id"`
+		Name         string                      `json:"name"`
+		Address      string                      `json:"address"`
+		Latitude     float64                     `json:"latitude"`
+		Longitude    float64                     `json:"longitude"`
+		Status       string                      `json:"status"`
+		StatusReason string                      `json:"status_reason"`
+		ThumbnailUrl string                      `json:"thumbnail_url"`
+		CreatedAt    time.Time                   `json:"created_at"`
+		Images       []MemberExportHomestayImage `json:"images"`
+	}
+	MemberExport struct {
+		ExportedAt time.Time              `json:"exported_at"`
+		Profile    MemberExportProfile    `json:"profile"`
+		Positions  []MemberExportPosition `json:"positions"`
+		Dues       []MemberExportDues     `json:"dues"`
+		Homestays  []MemberExportHomestay `json:"homestays"`
+	}
+	ExportMemberDataRes struct {
+		Filename string
+		Archive  []byte
+	}
+	ExportMemberDataOut struct {
+		resp.Response
+		Res ExportMemberDataRes
+	}
+)
+
+// Copy an uploaded file into the archive and return its path in the
+// archive. A file that can not be fetched only keep its url in the data,
+// so one broken file does not block the whole export.
+func (d *DashboardDeps) archiveFile(ctx context.Context, zw *zip.Writer, name, url string) (string, error) {
+	if url == "" {
+		return "", nil
+	}
+
+	body, err := d.FetchFile(ctx, url)
+	if err != nil {
+		return "", nil
+	}
+	defer body.Close()
+
+	filePath := "files/" + name + path.Ext(url)
+	w, err := zw.Create(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = io.Copy(w, body); err != nil {
+		return "", err
+	}
+
+	return filePath, nil
+}
+
+// Everything the association holds about a member in a zip archive, the
+// data.json describe the profile, the positions, the dues, and the
+// homestays while the uploaded files are copied under the files directory
+func (d *DashboardDeps) ExportMemberData(ctx context.Context, uid string) (out ExportMemberDataOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	_, err = uuid.FromString(uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	member, err := d.UserDeps.MemberRepository.FindById(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by id"))
+		return
+	}
+
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+
+	export := MemberExport{
+		ExportedAt: time.Now(),
+		Profile: MemberExportProfile{
+			Id:                   uid,
+			Name:                 member.Name,
+			Username:             member.Username,
+			WaPhone:              member.WaPhone,
+			OtherPhone:           member.OtherPhone,
+			UsernameVisibility:   member.UsernameVisibility.String,
+			WaPhoneVisibility:    member.WaPhoneVisibility.String,
+			OtherPhoneVisibility: member.OtherPhoneVisibility.String,
+			IsAdmin:              member.IsAdmin,
+			ApprovalStatus:       member.ApprovalStatus.String,
+			Status:               user.EffectiveMemberStatus(member).String,
+			SuspendedUntil:       null.NewTime(member.SuspendedUntil.Time, member.SuspendedUntil.Valid),
+			ProfilePicUrl:        member.ProfilePicUrl,
+			IdCardUrl:            member.IdCardUrl,
+			CreatedAt:            member.CreatedAt,
+		},
+		Positions: []MemberExportPosition{},
+		Dues:      []MemberExportDues{},
+		Homestays: []MemberExportHomestay{},
+	}
+
+	export.Profile.ProfilePicFile, err = d.archiveFile(ctx, zw, "profile", member.ProfilePicUrl)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "archive profile picture"))
+		return
+	}
+
+	export.Profile.IdCardFile, err = d.archiveFile(ctx, zw, "id-card", member.IdCardUrl)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "archive id card"))
+		return
+	}
+
+	structures, err := d.OrgStructureRepository.FindByMemberId(ctx, uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find org structures by member id"))
+		return
+	}
+
+	periods := make(map[uint64]user.OrgPeriodModel)
+	for _, s := range structures {
+		period, ok := periods[s.OrgPeriodId]
+		if !ok {
+			period, err = d.OrgPeriodRepository.FindById(ctx, s.OrgPeriodId)
+			if err != nil {
+				out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find org period by id"))
+				return
+			}
+			periods[s.OrgPeriodId] = period
+		}
+
+		export.Positions = append(export.Positions, MemberExportPosition{
+			PositionName:    s.PositionName,
+			PositionLevel:   int64(s.PositionLevel),
+			PeriodId:        int64(s.OrgPeriodId),
+			PeriodStartDate: period.StartDate.Format("2006-01-02"),
+			PeriodEndDate:   period.EndDate.Format("2006-01-02"),
+		})
+	}
+
+	// Every row of the member is part of the export
+	memberDues, _, err := d.MemberDuesRepository.QueryMDVByUid(ctx, uid, 0, math.MaxInt64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query member dues by member id"))
+		return
+	}
+
+	for _, m := range memberDues {
+		proveFile, err := d.archiveFile(ctx, zw, "dues/"+strconv.FormatUint(m.Id, 10), m.ProveFileUrl)
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "archive dues prove"))
+			return
+		}
+
+		export.Dues = append(export.Dues, MemberExportDues{
+			Id:           int64(m.Id),
+			DuesId:       int64(m.DuesId),
+			Date:         m.Date.Format("2006-01"),
+			IdrAmount:    m.IdrAmount,
+			Status:       m.Status.String,
+			PayDate:      null.NewTime(m.PayDate.Time, m.PayDate.Valid),
+			ProveFileUrl: m.ProveFileUrl,
+			ProveFile:    proveFile,
+		})
+	}
+
+	memberHomestays, err := d.MemberHomestayRepository.Query(ctx, uid, []uint64{}, false, 0, math.MaxInt64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query member homestays"))
+		return
+	}
+
+	for _, h := range memberHomestays {
+		images, err := d.HomestayImageRepository.FindByMemberHomestayId(ctx, h.Id)
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find homestay images by member homestay id"))
+			return
+		}
+
+		homestay := MemberExportHomestay{
+			Id:           int64(h.Id),
+			Name:         h.Name,
+			Address:      h.Address,
+			Latitude:     h.Latitude,
+			Longitude:    h.Longitude,
+			Status:       h.Status.String,
+			StatusReason: h.StatusReason,
+			ThumbnailUrl: h.ThumbnailUrl,
+			CreatedAt:    h.CreatedAt,
+			Images:       make([]MemberExportHomestayImage, len(images)),
+		}
+
+		for i, img := range images {
+			name := "homestays/" + strconv.FormatUint(h.Id, 10) + "/" + strconv.FormatUint(img.Id, 10)
+			file, err := d.archiveFile(ctx, zw, name, img.Url)
+			if err != nil {
+				out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "archive homestay image"))
+				return
+			}
+
+			homestay.Images[i] = MemberExportHomestayImage{
+				Id:       int64(img.Id),
+				Caption:  img.Caption,
+				Position: img.Position,
+				Url:      img.Url,
+				File:     file,
+			}
+		}
+
+		export.Homestays = append(export.Homestays, homestay)
+	}
+
+	w, err := zw.Create("data.json")
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "create archive data"))
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(export); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "encode archive data"))
+		return
+	}
+
+	if err = zw.Close(); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "close archive"))
+		return
+	}
+
+	out.Res = ExportMemberDataRes{
+		Filename: "uhomestay-" + member.Username + "-" + export.ExportedAt.Format("20060102") + ".zip",
+		Archive:  b.Bytes(),
+	}
+
+	return
+}
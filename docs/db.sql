@@ -1,3 +1,11 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist WITH SCHEMA public;
+
+CREATE TYPE public.bookingstatus AS ENUM (
+    'pending',
+    'accepted',
+    'declined'
+);
+
 CREATE TYPE public.cashflowtype AS ENUM (
     'income',
     'outcome'
@@ -14,6 +22,92 @@ CREATE TYPE public.duesstatus AS ENUM (
     'paid'
 );
 
+CREATE TYPE public.fieldvisibility AS ENUM (
+    'public',
+    'members',
+    'admins'
+);
+
+CREATE TYPE public.homestaystatus AS ENUM (
+    'pending',
+    'approved',
+    'rejected',
+    'suspended'
+);
+
+CREATE TYPE public.loginlockaction AS ENUM (
+    'locked',
+    'unlocked'
+);
+
+CREATE TYPE public.loginthrottlekind AS ENUM (
+    'username',
+    'ip'
+);
+
+CREATE TYPE public.memberapprovalstatus AS ENUM (
+    'pending',
+    'approved',
+    'rejected',
+    'info_requested'
+);
+
+CREATE TYPE public.membererasurestatus AS ENUM (
+    'pending',
+    'confirmed',
+    'rejected'
+);
+
+CREATE TYPE public.memberstatus AS ENUM (
+    'pending',
+    'active',
+    'suspended',
+    'resigned',
+    'deceased'
+);
+
+CREATE TYPE public.profilechangefield AS ENUM (
+    'name',
+    'id_card'
+);
+
+CREATE TYPE public.profilechangestatus AS ENUM (
+    'pending',
+    'approved',
+    'rejected'
+);
+
+CREATE TABLE public.article_categories (
+    article_id bigint NOT NULL,
+    category_id bigint NOT NULL
+);
+
+CREATE TABLE public.article_revisions (
+    id bigint NOT NULL,
+    article_id bigint NOT NULL,
+    title character varying(200) DEFAULT ''::character varying NOT NULL,
+    short_desc character varying(200) DEFAULT ''::character varying NOT NULL,
+    thumbnail_url text DEFAULT ''::text NOT NULL,
+    content jsonb DEFAULT '{}'::jsonb NOT NULL,
+    content_text text DEFAULT ''::text NOT NULL,
+    author_id uuid NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE SEQUENCE public.article_revisions_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.article_revisions_id_seq OWNED BY public.article_revisions.id;
+
+CREATE TABLE public.article_tags (
+    article_id bigint NOT NULL,
+    tag_id bigint NOT NULL
+);
+
 CREATE TABLE public.articles (
     id bigint NOT NULL,
     title character varying(200) DEFAULT ''::character varying NOT NULL,
@@ -59,6 +153,23 @@ CREATE SEQUENCE public.cashflows_id_seq
 
 ALTER SEQUENCE public.cashflows_id_seq OWNED BY public.cashflows.id;
 
+CREATE TABLE public.categories (
+    id bigint NOT NULL,
+    name character varying(100) DEFAULT ''::character varying NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at timestamp without time zone
+);
+
+CREATE SEQUENCE public.categories_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.categories_id_seq OWNED BY public.categories.id;
+
 CREATE TABLE public.documents (
     id bigint NOT NULL,
     name character varying(200) DEFAULT ''::character varying NOT NULL,
@@ -101,6 +212,24 @@ CREATE SEQUENCE public.dues_id_seq
 
 ALTER SEQUENCE public.dues_id_seq OWNED BY public.dues.id;
 
+CREATE TABLE public.file_removals (
+    id bigint NOT NULL,
+    url text NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    last_error text DEFAULT ''::text NOT NULL,
+    last_attempted_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE SEQUENCE public.file_removals_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.file_removals_id_seq OWNED BY public.file_removals.id;
+
 CREATE TABLE public.goals (
     id bigint NOT NULL,
     vision jsonb DEFAULT '{}'::jsonb NOT NULL,
@@ -136,11 +265,30 @@ CREATE SEQUENCE public.histories_id_seq
 
 ALTER SEQUENCE public.histories_id_seq OWNED BY public.histories.id;
 
+CREATE TABLE public.homestay_amenities (
+    id bigint NOT NULL,
+    name character varying(100) DEFAULT ''::character varying NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at timestamp without time zone
+);
+
+CREATE SEQUENCE public.homestay_amenities_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.homestay_amenities_id_seq OWNED BY public.homestay_amenities.id;
+
 CREATE TABLE public.homestay_images (
     id bigint NOT NULL,
     name character varying(200) DEFAULT ''::character varying NOT NULL,
     alphnum_name character varying(200) DEFAULT ''::character varying NOT NULL,
     url text DEFAULT ''::text NOT NULL,
+    caption character varying(200) DEFAULT ''::character varying NOT NULL,
+    "position" integer DEFAULT 0 NOT NULL,
     member_homestay_id bigint,
     created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
     deleted_at timestamp without time zone
@@ -155,6 +303,130 @@ CREATE SEQUENCE public.homestay_images_id_seq
 
 ALTER SEQUENCE public.homestay_images_id_seq OWNED BY public.homestay_images.id;
 
+CREATE TABLE public.homestay_bookings (
+    id bigint NOT NULL,
+    member_homestay_id bigint NOT NULL,
+    homestay_room_id bigint NOT NULL,
+    guest_name character varying(100) DEFAULT ''::character varying NOT NULL,
+    guest_phone character varying(50) DEFAULT ''::character varying NOT NULL,
+    guest_email character varying(100) DEFAULT ''::character varying NOT NULL,
+    guest_count integer DEFAULT 1 NOT NULL,
+    check_in date NOT NULL,
+    check_out date NOT NULL,
+    note character varying(500) DEFAULT ''::character varying NOT NULL,
+    idr_nightly_price bigint DEFAULT 0 NOT NULL,
+    status public.bookingstatus DEFAULT 'pending'::public.bookingstatus NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    decided_at timestamp without time zone,
+    CONSTRAINT homestay_bookings_guest_count_check CHECK ((guest_count > 0)),
+    CONSTRAINT homestay_bookings_stay_check CHECK ((check_out > check_in))
+);
+
+CREATE SEQUENCE public.homestay_bookings_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.homestay_bookings_id_seq OWNED BY public.homestay_bookings.id;
+
+CREATE TABLE public.homestay_room_blocks (
+    homestay_room_id bigint NOT NULL,
+    date date NOT NULL,
+    note character varying(200) DEFAULT ''::character varying NOT NULL,
+    homestay_room_ical_feed_id bigint,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE public.homestay_room_ical_feeds (
+    id bigint NOT NULL,
+    homestay_room_id bigint NOT NULL,
+    name character varying(100) DEFAULT ''::character varying NOT NULL,
+    url text DEFAULT ''::text NOT NULL,
+    last_synced_at timestamp without time zone,
+    last_error text DEFAULT ''::text NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE SEQUENCE public.homestay_room_ical_feeds_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.homestay_room_ical_feeds_id_seq OWNED BY public.homestay_room_ical_feeds.id;
+
+CREATE TABLE public.homestay_review_codes (
+    id bigint NOT NULL,
+    member_homestay_id bigint NOT NULL,
+    code character varying(16) NOT NULL,
+    expired_at timestamp without time zone NOT NULL,
+    used_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE SEQUENCE public.homestay_review_codes_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.homestay_review_codes_id_seq OWNED BY public.homestay_review_codes.id;
+
+CREATE TABLE public.homestay_reviews (
+    id bigint NOT NULL,
+    member_homestay_id bigint NOT NULL,
+    homestay_booking_id bigint,
+    homestay_review_code_id bigint,
+    reviewer_name character varying(100) DEFAULT ''::character varying NOT NULL,
+    rating smallint NOT NULL,
+    review character varying(1000) DEFAULT ''::character varying NOT NULL,
+    owner_reply character varying(1000) DEFAULT ''::character varying NOT NULL,
+    replied_at timestamp without time zone,
+    is_hidden boolean DEFAULT false NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT homestay_reviews_rating_check CHECK (((rating >= 1) AND (rating <= 5))),
+    CONSTRAINT homestay_reviews_proof_check CHECK (((homestay_booking_id IS NOT NULL) OR (homestay_review_code_id IS NOT NULL)))
+);
+
+CREATE SEQUENCE public.homestay_reviews_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.homestay_reviews_id_seq OWNED BY public.homestay_reviews.id;
+
+CREATE TABLE public.homestay_rooms (
+    id bigint NOT NULL,
+    type character varying(100) DEFAULT ''::character varying NOT NULL,
+    capacity integer DEFAULT 1 NOT NULL,
+    idr_nightly_price bigint DEFAULT 0 NOT NULL,
+    member_homestay_id bigint NOT NULL,
+    ical_token character varying(64) DEFAULT ''::character varying NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at timestamp without time zone,
+    CONSTRAINT homestay_rooms_capacity_check CHECK ((capacity > 0)),
+    CONSTRAINT homestay_rooms_idr_nightly_price_check CHECK ((idr_nightly_price >= 0))
+);
+
+CREATE SEQUENCE public.homestay_rooms_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.homestay_rooms_id_seq OWNED BY public.homestay_rooms.id;
+
 CREATE TABLE public.image_caches (
     name character varying DEFAULT ''::character varying NOT NULL,
     image_id character varying DEFAULT ''::character varying NOT NULL,
@@ -180,6 +452,36 @@ CREATE SEQUENCE public.images_id_seq
 
 ALTER SEQUENCE public.images_id_seq OWNED BY public.images.id;
 
+CREATE TABLE public.login_lock_histories (
+    id bigint NOT NULL,
+    kind public.loginthrottlekind NOT NULL,
+    identifier text NOT NULL,
+    action public.loginlockaction NOT NULL,
+    failures integer DEFAULT 0 NOT NULL,
+    actor_id uuid,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE SEQUENCE public.login_lock_histories_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.login_lock_histories_id_seq OWNED BY public.login_lock_histories.id;
+
+CREATE TABLE public.login_throttles (
+    kind public.loginthrottlekind NOT NULL,
+    identifier text NOT NULL,
+    failures integer DEFAULT 0 NOT NULL,
+    blocked_until timestamp without time zone,
+    locked_at timestamp without time zone,
+    last_failed_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
 CREATE TABLE public.member_dues (
     id bigint NOT NULL,
     member_id uuid NOT NULL,
@@ -201,17 +503,51 @@ CREATE SEQUENCE public.member_dues_id_seq
 
 ALTER SEQUENCE public.member_dues_id_seq OWNED BY public.member_dues.id;
 
+CREATE TABLE public.member_erasure_requests (
+    id bigint NOT NULL,
+    member_id uuid NOT NULL,
+    reason character varying(500) DEFAULT ''::character varying NOT NULL,
+    status public.membererasurestatus DEFAULT 'pending'::public.membererasurestatus NOT NULL,
+    review_reason character varying(500) DEFAULT ''::character varying NOT NULL,
+    reviewer_id uuid,
+    reviewed_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE SEQUENCE public.member_erasure_requests_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.member_erasure_requests_id_seq OWNED BY public.member_erasure_requests.id;
+
+CREATE TABLE public.member_homestay_amenities (
+    member_homestay_id bigint NOT NULL,
+    homestay_amenity_id bigint NOT NULL
+);
+
 CREATE TABLE public.member_homestays (
     id bigint NOT NULL,
     name character varying(200) DEFAULT ''::character varying NOT NULL,
     address character varying(200) DEFAULT ''::character varying NOT NULL,
-    latitude character varying(50) DEFAULT ''::character varying NOT NULL,
-    longitude character varying(50) DEFAULT ''::character varying NOT NULL,
+    latitude double precision DEFAULT 0 NOT NULL,
+    longitude double precision DEFAULT 0 NOT NULL,
+    is_coordinate_invalid boolean DEFAULT false NOT NULL,
     thumbnail_url text DEFAULT ''::text NOT NULL,
     member_id uuid NOT NULL,
+    rating_avg double precision DEFAULT 0 NOT NULL,
+    rating_count integer DEFAULT 0 NOT NULL,
+    status public.homestaystatus DEFAULT 'pending'::public.homestaystatus NOT NULL,
+    status_reason character varying(500) DEFAULT ''::character varying NOT NULL,
+    reviewed_at timestamp without time zone,
     created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
     updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
-    deleted_at timestamp without time zone
+    deleted_at timestamp without time zone,
+    textsearchable_index_col tsvector GENERATED ALWAYS AS (to_tsvector('simple'::regconfig, (((COALESCE(name, ''::character varying))::text || ' '::text) || (COALESCE(address, ''::character varying))::text))) STORED,
+    CONSTRAINT member_homestays_latitude_check CHECK (((latitude >= ('-90'::integer)::double precision) AND (latitude <= (90)::double precision))),
+    CONSTRAINT member_homestays_longitude_check CHECK (((longitude >= ('-180'::integer)::double precision) AND (longitude <= (180)::double precision)))
 );
 
 CREATE SEQUENCE public.member_homestays_id_seq
@@ -223,6 +559,124 @@ CREATE SEQUENCE public.member_homestays_id_seq
 
 ALTER SEQUENCE public.member_homestays_id_seq OWNED BY public.member_homestays.id;
 
+CREATE TABLE public.member_invitations (
+    id bigint NOT NULL,
+    token character varying(64) NOT NULL,
+    name character varying(100) DEFAULT ''::character varying NOT NULL,
+    wa_phone character varying(50) DEFAULT ''::character varying NOT NULL,
+    other_phone character varying(50) DEFAULT ''::character varying NOT NULL,
+    is_admin boolean DEFAULT false NOT NULL,
+    org_period_id bigint,
+    position_ids bigint[] DEFAULT '{}'::bigint[] NOT NULL,
+    inviter_id uuid NOT NULL,
+    member_id uuid,
+    expired_at timestamp without time zone NOT NULL,
+    used_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE SEQUENCE public.member_invitations_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.member_invitations_id_seq OWNED BY public.member_invitations.id;
+
+CREATE TABLE public.member_profile_changes (
+    id bigint NOT NULL,
+    member_id uuid NOT NULL,
+    field public.profilechangefield NOT NULL,
+    old_value text DEFAULT ''::text NOT NULL,
+    new_value text DEFAULT ''::text NOT NULL,
+    status public.profilechangestatus DEFAULT 'pending'::public.profilechangestatus NOT NULL,
+    review_reason character varying(500) DEFAULT ''::character varying NOT NULL,
+    reviewer_id uuid,
+    reviewed_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE SEQUENCE public.member_profile_changes_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.member_profile_changes_id_seq OWNED BY public.member_profile_changes.id;
+
+CREATE TABLE public.member_recovery_codes (
+    id bigint NOT NULL,
+    member_id uuid NOT NULL,
+    code_hash character varying(64) NOT NULL,
+    used_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE SEQUENCE public.member_recovery_codes_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.member_recovery_codes_id_seq OWNED BY public.member_recovery_codes.id;
+
+CREATE TABLE public.member_sessions (
+    id bigint NOT NULL,
+    token_id uuid NOT NULL,
+    member_id uuid NOT NULL,
+    is_admin boolean DEFAULT false NOT NULL,
+    device character varying(100) DEFAULT ''::character varying NOT NULL,
+    ip character varying(64) DEFAULT ''::character varying NOT NULL,
+    user_agent text DEFAULT ''::text NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    last_seen_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    expires_at timestamp without time zone NOT NULL,
+    revoked_at timestamp without time zone
+);
+
+CREATE SEQUENCE public.member_sessions_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.member_sessions_id_seq OWNED BY public.member_sessions.id;
+
+CREATE TABLE public.member_status_histories (
+    id bigint NOT NULL,
+    member_id uuid NOT NULL,
+    from_status public.memberstatus NOT NULL,
+    to_status public.memberstatus NOT NULL,
+    reason character varying(500) DEFAULT ''::character varying NOT NULL,
+    suspended_until timestamp without time zone,
+    actor_id uuid,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE SEQUENCE public.member_status_histories_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.member_status_histories_id_seq OWNED BY public.member_status_histories.id;
+
+CREATE TABLE public.member_totps (
+    member_id uuid NOT NULL,
+    secret character varying(64) NOT NULL,
+    is_enabled boolean DEFAULT false NOT NULL,
+    last_used_step bigint DEFAULT 0 NOT NULL,
+    enabled_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
 CREATE TABLE public.members (
     id uuid NOT NULL,
     name character varying(100) DEFAULT ''::character varying NOT NULL,
@@ -234,6 +688,14 @@ CREATE TABLE public.members (
     password character varying(200) DEFAULT ''::character varying NOT NULL,
     is_admin boolean DEFAULT false NOT NULL,
     is_approved boolean DEFAULT false NOT NULL,
+    approval_status public.memberapprovalstatus DEFAULT 'pending'::public.memberapprovalstatus NOT NULL,
+    approval_reason character varying(500) DEFAULT ''::character varying NOT NULL,
+    reviewed_at timestamp without time zone,
+    status public.memberstatus DEFAULT 'pending'::public.memberstatus NOT NULL,
+    suspended_until timestamp without time zone,
+    username_visibility public.fieldvisibility DEFAULT 'members'::public.fieldvisibility NOT NULL,
+    wa_phone_visibility public.fieldvisibility DEFAULT 'members'::public.fieldvisibility NOT NULL,
+    other_phone_visibility public.fieldvisibility DEFAULT 'members'::public.fieldvisibility NOT NULL,
     created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
     updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
     deleted_at timestamp without time zone,
@@ -299,62 +761,189 @@ CREATE SEQUENCE public.positions_id_seq
 
 ALTER SEQUENCE public.positions_id_seq OWNED BY public.positions.id;
 
+CREATE TABLE public.tags (
+    id bigint NOT NULL,
+    name character varying(50) DEFAULT ''::character varying NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE SEQUENCE public.tags_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.tags_id_seq OWNED BY public.tags.id;
+
+ALTER TABLE ONLY public.article_revisions ALTER COLUMN id SET DEFAULT nextval('public.article_revisions_id_seq'::regclass);
+
 ALTER TABLE ONLY public.articles ALTER COLUMN id SET DEFAULT nextval('public.articles_id_seq'::regclass);
 
 ALTER TABLE ONLY public.cashflows ALTER COLUMN id SET DEFAULT nextval('public.cashflows_id_seq'::regclass);
 
+ALTER TABLE ONLY public.categories ALTER COLUMN id SET DEFAULT nextval('public.categories_id_seq'::regclass);
+
 ALTER TABLE ONLY public.documents ALTER COLUMN id SET DEFAULT nextval('public.documents_id_seq'::regclass);
 
 ALTER TABLE ONLY public.dues ALTER COLUMN id SET DEFAULT nextval('public.dues_id_seq'::regclass);
 
+ALTER TABLE ONLY public.file_removals ALTER COLUMN id SET DEFAULT nextval('public.file_removals_id_seq'::regclass);
+
 ALTER TABLE ONLY public.goals ALTER COLUMN id SET DEFAULT nextval('public.goals_id_seq'::regclass);
 
 ALTER TABLE ONLY public.histories ALTER COLUMN id SET DEFAULT nextval('public.histories_id_seq'::regclass);
 
+ALTER TABLE ONLY public.homestay_amenities ALTER COLUMN id SET DEFAULT nextval('public.homestay_amenities_id_seq'::regclass);
+
 ALTER TABLE ONLY public.homestay_images ALTER COLUMN id SET DEFAULT nextval('public.homestay_images_id_seq'::regclass);
 
+ALTER TABLE ONLY public.homestay_bookings ALTER COLUMN id SET DEFAULT nextval('public.homestay_bookings_id_seq'::regclass);
+
+ALTER TABLE ONLY public.homestay_room_ical_feeds ALTER COLUMN id SET DEFAULT nextval('public.homestay_room_ical_feeds_id_seq'::regclass);
+
+ALTER TABLE ONLY public.homestay_review_codes ALTER COLUMN id SET DEFAULT nextval('public.homestay_review_codes_id_seq'::regclass);
+
+ALTER TABLE ONLY public.homestay_reviews ALTER COLUMN id SET DEFAULT nextval('public.homestay_reviews_id_seq'::regclass);
+
+ALTER TABLE ONLY public.homestay_rooms ALTER COLUMN id SET DEFAULT nextval('public.homestay_rooms_id_seq'::regclass);
+
 ALTER TABLE ONLY public.images ALTER COLUMN id SET DEFAULT nextval('public.images_id_seq'::regclass);
 
+ALTER TABLE ONLY public.login_lock_histories ALTER COLUMN id SET DEFAULT nextval('public.login_lock_histories_id_seq'::regclass);
+
 ALTER TABLE ONLY public.member_dues ALTER COLUMN id SET DEFAULT nextval('public.member_dues_id_seq'::regclass);
 
+ALTER TABLE ONLY public.member_erasure_requests ALTER COLUMN id SET DEFAULT nextval('public.member_erasure_requests_id_seq'::regclass);
+
 ALTER TABLE ONLY public.member_homestays ALTER COLUMN id SET DEFAULT nextval('public.member_homestays_id_seq'::regclass);
 
+ALTER TABLE ONLY public.member_invitations ALTER COLUMN id SET DEFAULT nextval('public.member_invitations_id_seq'::regclass);
+
+ALTER TABLE ONLY public.member_profile_changes ALTER COLUMN id SET DEFAULT nextval('public.member_profile_changes_id_seq'::regclass);
+
+ALTER TABLE ONLY public.member_recovery_codes ALTER COLUMN id SET DEFAULT nextval('public.member_recovery_codes_id_seq'::regclass);
+
+ALTER TABLE ONLY public.member_sessions ALTER COLUMN id SET DEFAULT nextval('public.member_sessions_id_seq'::regclass);
+
+ALTER TABLE ONLY public.member_status_histories ALTER COLUMN id SET DEFAULT nextval('public.member_status_histories_id_seq'::regclass);
+
 ALTER TABLE ONLY public.org_periods ALTER COLUMN id SET DEFAULT nextval('public.org_periods_id_seq'::regclass);
 
 ALTER TABLE ONLY public.org_structures ALTER COLUMN id SET DEFAULT nextval('public.org_structures_id_seq'::regclass);
 
 ALTER TABLE ONLY public.positions ALTER COLUMN id SET DEFAULT nextval('public.positions_id_seq'::regclass);
 
+ALTER TABLE ONLY public.tags ALTER COLUMN id SET DEFAULT nextval('public.tags_id_seq'::regclass);
+
+ALTER TABLE ONLY public.article_categories
+    ADD CONSTRAINT article_categories_pkey PRIMARY KEY (article_id, category_id);
+
+ALTER TABLE ONLY public.article_revisions
+    ADD CONSTRAINT article_revisions_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.article_tags
+    ADD CONSTRAINT article_tags_pkey PRIMARY KEY (article_id, tag_id);
+
 ALTER TABLE ONLY public.articles
     ADD CONSTRAINT articles_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY public.cashflows
     ADD CONSTRAINT cashflows_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY public.categories
+    ADD CONSTRAINT categories_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY public.documents
     ADD CONSTRAINT documents_x_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY public.dues
     ADD CONSTRAINT dues_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY public.file_removals
+    ADD CONSTRAINT file_removals_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY public.goals
     ADD CONSTRAINT goals_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY public.histories
     ADD CONSTRAINT histories_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY public.homestay_amenities
+    ADD CONSTRAINT homestay_amenities_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY public.homestay_images
     ADD CONSTRAINT homestay_images_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY public.homestay_bookings
+    ADD CONSTRAINT homestay_bookings_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.homestay_bookings
+    ADD CONSTRAINT homestay_bookings_no_overlap EXCLUDE USING gist (homestay_room_id WITH =, daterange(check_in, check_out) WITH &&) WHERE ((status = 'accepted'::public.bookingstatus));
+
+ALTER TABLE ONLY public.homestay_room_blocks
+    ADD CONSTRAINT homestay_room_blocks_pkey PRIMARY KEY (homestay_room_id, date);
+
+ALTER TABLE ONLY public.homestay_room_ical_feeds
+    ADD CONSTRAINT homestay_room_ical_feeds_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.homestay_review_codes
+    ADD CONSTRAINT homestay_review_codes_member_homestay_id_code_key UNIQUE (member_homestay_id, code);
+
+ALTER TABLE ONLY public.homestay_review_codes
+    ADD CONSTRAINT homestay_review_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.homestay_reviews
+    ADD CONSTRAINT homestay_reviews_homestay_booking_id_key UNIQUE (homestay_booking_id);
+
+ALTER TABLE ONLY public.homestay_reviews
+    ADD CONSTRAINT homestay_reviews_homestay_review_code_id_key UNIQUE (homestay_review_code_id);
+
+ALTER TABLE ONLY public.homestay_reviews
+    ADD CONSTRAINT homestay_reviews_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.homestay_rooms
+    ADD CONSTRAINT homestay_rooms_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY public.images
     ADD CONSTRAINT images_x_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY public.login_lock_histories
+    ADD CONSTRAINT login_lock_histories_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.login_throttles
+    ADD CONSTRAINT login_throttles_pkey PRIMARY KEY (kind, identifier);
+
 ALTER TABLE ONLY public.member_dues
     ADD CONSTRAINT member_dues_x_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY public.member_erasure_requests
+    ADD CONSTRAINT member_erasure_requests_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.member_homestay_amenities
+    ADD CONSTRAINT member_homestay_amenities_pkey PRIMARY KEY (member_homestay_id, homestay_amenity_id);
+
 ALTER TABLE ONLY public.member_homestays
     ADD CONSTRAINT member_homestays_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY public.member_invitations
+    ADD CONSTRAINT member_invitations_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.member_profile_changes
+    ADD CONSTRAINT member_profile_changes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.member_recovery_codes
+    ADD CONSTRAINT member_recovery_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.member_sessions
+    ADD CONSTRAINT member_sessions_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.member_status_histories
+    ADD CONSTRAINT member_status_histories_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.member_totps
+    ADD CONSTRAINT member_totps_pkey PRIMARY KEY (member_id);
+
 ALTER TABLE ONLY public.members
     ADD CONSTRAINT members_x_other_phone_key UNIQUE (other_phone);
 
@@ -376,25 +965,167 @@ ALTER TABLE ONLY public.org_structures
 ALTER TABLE ONLY public.positions
     ADD CONSTRAINT positions_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY public.tags
+    ADD CONSTRAINT tags_name_key UNIQUE (name);
+
+ALTER TABLE ONLY public.tags
+    ADD CONSTRAINT tags_pkey PRIMARY KEY (id);
+
+CREATE INDEX article_revisions_article_id_idx ON public.article_revisions USING btree (article_id);
+
 CREATE INDEX articles_textrank_idx ON public.articles USING gin (textrank_index_col);
 
 CREATE INDEX articles_textsearch_idx ON public.articles USING gin (textsearchable_index_col);
 
+CREATE INDEX homestay_bookings_member_homestay_id_idx ON public.homestay_bookings USING btree (member_homestay_id);
+
+CREATE INDEX homestay_room_blocks_homestay_room_ical_feed_id_idx ON public.homestay_room_blocks USING btree (homestay_room_ical_feed_id);
+
+CREATE INDEX homestay_room_ical_feeds_homestay_room_id_idx ON public.homestay_room_ical_feeds USING btree (homestay_room_id);
+
+CREATE INDEX homestay_images_member_homestay_id_position_idx ON public.homestay_images USING btree (member_homestay_id, "position");
+
+CREATE INDEX homestay_reviews_member_homestay_id_idx ON public.homestay_reviews USING btree (member_homestay_id);
+
+CREATE UNIQUE INDEX homestay_rooms_ical_token_idx ON public.homestay_rooms USING btree (ical_token) WHERE ((ical_token)::text <> ''::text);
+
+CREATE INDEX homestay_rooms_member_homestay_id_idx ON public.homestay_rooms USING btree (member_homestay_id);
+
+CREATE INDEX login_lock_histories_kind_identifier_idx ON public.login_lock_histories USING btree (kind, identifier);
+
+CREATE INDEX login_throttles_locked_at_idx ON public.login_throttles USING btree (locked_at) WHERE (locked_at IS NOT NULL);
+
+CREATE INDEX member_dues_member_id_status_idx ON public.member_dues USING btree (member_id, status);
+
+CREATE INDEX member_erasure_requests_member_id_idx ON public.member_erasure_requests USING btree (member_id);
+
+CREATE INDEX member_homestay_amenities_homestay_amenity_id_idx ON public.member_homestay_amenities USING btree (homestay_amenity_id);
+
+CREATE INDEX member_homestays_coordinate_idx ON public.member_homestays USING btree (latitude, longitude);
+
+CREATE INDEX member_homestays_status_idx ON public.member_homestays USING btree (status);
+
+CREATE INDEX member_homestays_textsearch_idx ON public.member_homestays USING gin (textsearchable_index_col);
+
+CREATE UNIQUE INDEX member_invitations_token_idx ON public.member_invitations USING btree (token);
+
+CREATE INDEX member_profile_changes_member_id_idx ON public.member_profile_changes USING btree (member_id);
+
+CREATE INDEX member_recovery_codes_member_id_idx ON public.member_recovery_codes USING btree (member_id);
+
+CREATE INDEX member_sessions_member_id_idx ON public.member_sessions USING btree (member_id);
+
+CREATE UNIQUE INDEX member_sessions_token_id_idx ON public.member_sessions USING btree (token_id);
+
+CREATE INDEX member_status_histories_member_id_idx ON public.member_status_histories USING btree (member_id);
+
+CREATE INDEX org_structures_member_id_idx ON public.org_structures USING btree (member_id);
+
+ALTER TABLE ONLY public.article_categories
+    ADD CONSTRAINT article_categories_article_id_fkey FOREIGN KEY (article_id) REFERENCES public.articles(id);
+
+ALTER TABLE ONLY public.article_categories
+    ADD CONSTRAINT article_categories_category_id_fkey FOREIGN KEY (category_id) REFERENCES public.categories(id);
+
+ALTER TABLE ONLY public.article_revisions
+    ADD CONSTRAINT article_revisions_article_id_fkey FOREIGN KEY (article_id) REFERENCES public.articles(id);
+
+ALTER TABLE ONLY public.article_tags
+    ADD CONSTRAINT article_tags_article_id_fkey FOREIGN KEY (article_id) REFERENCES public.articles(id);
+
+ALTER TABLE ONLY public.article_tags
+    ADD CONSTRAINT article_tags_tag_id_fkey FOREIGN KEY (tag_id) REFERENCES public.tags(id);
+
 ALTER TABLE ONLY public.goals
     ADD CONSTRAINT goals_org_period_id_fkey FOREIGN KEY (org_period_id) REFERENCES public.org_periods(id);
 
 ALTER TABLE ONLY public.homestay_images
     ADD CONSTRAINT homestay_images_member_homestay_id_fkey FOREIGN KEY (member_homestay_id) REFERENCES public.member_homestays(id);
 
+ALTER TABLE ONLY public.homestay_bookings
+    ADD CONSTRAINT homestay_bookings_homestay_room_id_fkey FOREIGN KEY (homestay_room_id) REFERENCES public.homestay_rooms(id);
+
+ALTER TABLE ONLY public.homestay_bookings
+    ADD CONSTRAINT homestay_bookings_member_homestay_id_fkey FOREIGN KEY (member_homestay_id) REFERENCES public.member_homestays(id);
+
+ALTER TABLE ONLY public.homestay_room_blocks
+    ADD CONSTRAINT homestay_room_blocks_homestay_room_id_fkey FOREIGN KEY (homestay_room_id) REFERENCES public.homestay_rooms(id);
+
+ALTER TABLE ONLY public.homestay_room_blocks
+    ADD CONSTRAINT homestay_room_blocks_homestay_room_ical_feed_id_fkey FOREIGN KEY (homestay_room_ical_feed_id) REFERENCES public.homestay_room_ical_feeds(id);
+
+ALTER TABLE ONLY public.homestay_room_ical_feeds
+    ADD CONSTRAINT homestay_room_ical_feeds_homestay_room_id_fkey FOREIGN KEY (homestay_room_id) REFERENCES public.homestay_rooms(id);
+
+ALTER TABLE ONLY public.homestay_review_codes
+    ADD CONSTRAINT homestay_review_codes_member_homestay_id_fkey FOREIGN KEY (member_homestay_id) REFERENCES public.member_homestays(id);
+
+ALTER TABLE ONLY public.homestay_reviews
+    ADD CONSTRAINT homestay_reviews_homestay_booking_id_fkey FOREIGN KEY (homestay_booking_id) REFERENCES public.homestay_bookings(id);
+
+ALTER TABLE ONLY public.homestay_reviews
+    ADD CONSTRAINT homestay_reviews_homestay_review_code_id_fkey FOREIGN KEY (homestay_review_code_id) REFERENCES public.homestay_review_codes(id);
+
+ALTER TABLE ONLY public.homestay_reviews
+    ADD CONSTRAINT homestay_reviews_member_homestay_id_fkey FOREIGN KEY (member_homestay_id) REFERENCES public.member_homestays(id);
+
+ALTER TABLE ONLY public.homestay_rooms
+    ADD CONSTRAINT homestay_rooms_member_homestay_id_fkey FOREIGN KEY (member_homestay_id) REFERENCES public.member_homestays(id);
+
+ALTER TABLE ONLY public.login_lock_histories
+    ADD CONSTRAINT login_lock_histories_actor_id_fkey FOREIGN KEY (actor_id) REFERENCES public.members(id);
+
 ALTER TABLE ONLY public.member_dues
     ADD CONSTRAINT member_dues_x_dues_id_fkey FOREIGN KEY (dues_id) REFERENCES public.dues(id);
 
 ALTER TABLE ONLY public.member_dues
     ADD CONSTRAINT member_dues_x_member_id_fkey FOREIGN KEY (member_id) REFERENCES public.members(id);
 
+ALTER TABLE ONLY public.member_erasure_requests
+    ADD CONSTRAINT member_erasure_requests_member_id_fkey FOREIGN KEY (member_id) REFERENCES public.members(id);
+
+ALTER TABLE ONLY public.member_erasure_requests
+    ADD CONSTRAINT member_erasure_requests_reviewer_id_fkey FOREIGN KEY (reviewer_id) REFERENCES public.members(id);
+
+ALTER TABLE ONLY public.member_homestay_amenities
+    ADD CONSTRAINT member_homestay_amenities_homestay_amenity_id_fkey FOREIGN KEY (homestay_amenity_id) REFERENCES public.homestay_amenities(id);
+
+ALTER TABLE ONLY public.member_homestay_amenities
+    ADD CONSTRAINT member_homestay_amenities_member_homestay_id_fkey FOREIGN KEY (member_homestay_id) REFERENCES public.member_homestays(id);
+
 ALTER TABLE ONLY public.member_homestays
     ADD CONSTRAINT member_homestays_member_id_fkey FOREIGN KEY (member_id) REFERENCES public.members(id);
 
+ALTER TABLE ONLY public.member_invitations
+    ADD CONSTRAINT member_invitations_inviter_id_fkey FOREIGN KEY (inviter_id) REFERENCES public.members(id);
+
+ALTER TABLE ONLY public.member_invitations
+    ADD CONSTRAINT member_invitations_member_id_fkey FOREIGN KEY (member_id) REFERENCES public.members(id);
+
+ALTER TABLE ONLY public.member_invitations
+    ADD CONSTRAINT member_invitations_org_period_id_fkey FOREIGN KEY (org_period_id) REFERENCES public.org_periods(id);
+
+ALTER TABLE ONLY public.member_profile_changes
+    ADD CONSTRAINT member_profile_changes_member_id_fkey FOREIGN KEY (member_id) REFERENCES public.members(id);
+
+ALTER TABLE ONLY public.member_profile_changes
+    ADD CONSTRAINT member_profile_changes_reviewer_id_fkey FOREIGN KEY (reviewer_id) REFERENCES public.members(id);
+
+ALTER TABLE ONLY public.member_recovery_codes
+    ADD CONSTRAINT member_recovery_codes_member_id_fkey FOREIGN KEY (member_id) REFERENCES public.members(id);
+
+ALTER TABLE ONLY public.member_sessions
+    ADD CONSTRAINT member_sessions_member_id_fkey FOREIGN KEY (member_id) REFERENCES public.members(id);
+
+ALTER TABLE ONLY public.member_status_histories
+    ADD CONSTRAINT member_status_histories_actor_id_fkey FOREIGN KEY (actor_id) REFERENCES public.members(id);
+
+ALTER TABLE ONLY public.member_status_histories
+    ADD CONSTRAINT member_status_histories_member_id_fkey FOREIGN KEY (member_id) REFERENCES public.members(id);
+
+ALTER TABLE ONLY public.member_totps
+    ADD CONSTRAINT member_totps_member_id_fkey FOREIGN KEY (member_id) REFERENCES public.members(id);
+
 ALTER TABLE ONLY public.org_structures
     ADD CONSTRAINT org_structures_x_member_id_fkey1 FOREIGN KEY (member_id) REFERENCES public.members(id);
 
@@ -0,0 +1,59 @@
+-- Convert the free text homestay coordinates of an existing database to
+-- double precision. A coordinate that is not a number or is out of range
+-- is stored as 0 and its homestay is marked for correction by its owner,
+-- the mark is cleared once the owner update the homestay.
+--
+-- Safe to run more than once, the conversion only happen while the
+-- coordinates are still stored as text.
+
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1
+        FROM information_schema.columns
+        WHERE table_schema = 'public'
+            AND table_name = 'member_homestays'
+            AND column_name = 'latitude'
+            AND data_type = 'character varying'
+    ) THEN
+        ALTER TABLE public.member_homestays
+            ADD COLUMN is_coordinate_invalid boolean DEFAULT false NOT NULL;
+
+        UPDATE public.member_homestays SET is_coordinate_invalid = true
+        WHERE CASE
+            WHEN trim(latitude) ~ '^[-+]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$'
+                AND trim(longitude) ~ '^[-+]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$'
+            THEN NOT (
+                trim(latitude)::double precision BETWEEN -90 AND 90
+                AND trim(longitude)::double precision BETWEEN -180 AND 180
+            )
+            ELSE true
+        END;
+
+        ALTER TABLE public.member_homestays
+            ALTER COLUMN latitude DROP DEFAULT,
+            ALTER COLUMN longitude DROP DEFAULT;
+
+        ALTER TABLE public.member_homestays
+            ALTER COLUMN latitude TYPE double precision USING (
+                CASE WHEN is_coordinate_invalid THEN 0 ELSE trim(latitude)::double precision END
+            ),
+            ALTER COLUMN longitude TYPE double precision USING (
+                CASE WHEN is_coordinate_invalid THEN 0 ELSE trim(longitude)::double precision END
+            );
+
+        ALTER TABLE public.member_homestays
+            ALTER COLUMN latitude SET DEFAULT 0,
+            ALTER COLUMN longitude SET DEFAULT 0,
+            ADD CONSTRAINT member_homestays_latitude_check CHECK (((latitude >= ('-90'::integer)::double precision) AND (latitude <= (90)::double precision))),
+            ADD CONSTRAINT member_homestays_longitude_check CHECK (((longitude >= ('-180'::integer)::double precision) AND (longitude <= (180)::double precision)));
+    END IF;
+END $$;
+
+ALTER TABLE public.member_homestays
+    ADD COLUMN IF NOT EXISTS is_coordinate_invalid boolean DEFAULT false NOT NULL,
+    ADD COLUMN IF NOT EXISTS textsearchable_index_col tsvector GENERATED ALWAYS AS (to_tsvector('simple'::regconfig, (((COALESCE(name, ''::character varying))::text || ' '::text) || (COALESCE(address, ''::character varying))::text))) STORED;
+
+CREATE INDEX IF NOT EXISTS member_homestays_coordinate_idx ON public.member_homestays USING btree (latitude, longitude);
+
+CREATE INDEX IF NOT EXISTS member_homestays_textsearch_idx ON public.member_homestays USING gin (textsearchable_index_col);
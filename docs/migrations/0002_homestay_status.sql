@@ -0,0 +1,36 @@
+-- Add the review status of the homestay listings to an existing database.
+-- Every listing published before the review workflow existed was already
+-- public, so they are approved instead of waiting for a review.
+--
+-- Safe to run more than once, the backfill only happen while the status
+-- column is added.
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'homestaystatus') THEN
+        CREATE TYPE public.homestaystatus AS ENUM (
+            'pending',
+            'approved',
+            'rejected',
+            'suspended'
+        );
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1
+        FROM information_schema.columns
+        WHERE table_schema = 'public'
+            AND table_name = 'member_homestays'
+            AND column_name = 'status'
+    ) THEN
+        ALTER TABLE public.member_homestays
+            ADD COLUMN status public.homestaystatus DEFAULT 'pending'::public.homestaystatus NOT NULL,
+            ADD COLUMN status_reason character varying(500) DEFAULT ''::character varying NOT NULL,
+            ADD COLUMN reviewed_at timestamp without time zone;
+
+        UPDATE public.member_homestays
+        SET status = 'approved';
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS member_homestays_status_idx ON public.member_homestays USING btree (status);
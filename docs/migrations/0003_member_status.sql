@@ -0,0 +1,46 @@
+-- Add the lifecycle status of the members to an existing database. The
+-- approved members are active, the rest are still waiting for their
+-- registration to be reviewed.
+--
+-- Safe to run more than once, the backfill only happen while the status
+-- column is added.
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'memberstatus') THEN
+        CREATE TYPE public.memberstatus AS ENUM (
+            'pending',
+            'active',
+            'suspended',
+            'resigned',
+            'deceased'
+        );
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1
+        FROM information_schema.columns
+        WHERE table_schema = 'public'
+            AND table_name = 'members'
+            AND column_name = 'status'
+    ) THEN
+        ALTER TABLE public.members
+            ADD COLUMN status public.memberstatus DEFAULT 'pending'::public.memberstatus NOT NULL,
+            ADD COLUMN suspended_until timestamp without time zone;
+
+        UPDATE public.members SET status = 'active' WHERE is_approved = true;
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS public.member_status_histories (
+    id bigserial PRIMARY KEY,
+    member_id uuid NOT NULL REFERENCES public.members(id),
+    from_status public.memberstatus NOT NULL,
+    to_status public.memberstatus NOT NULL,
+    reason character varying(500) DEFAULT ''::character varying NOT NULL,
+    suspended_until timestamp without time zone,
+    actor_id uuid REFERENCES public.members(id),
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS member_status_histories_member_id_idx ON public.member_status_histories USING btree (member_id);
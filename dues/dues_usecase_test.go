@@ -3,6 +3,7 @@ package dues_test
 import (
 	"context"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"testing"
@@ -106,6 +107,67 @@ func TestAddDues(t *testing.T) {
 	}
 }
 
+// An approved member registered before the members had a status is
+// given the dues once the status migration is run
+func TestAddDuesMemberBeforeStatus(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, _, err := createMemberNDues(duesDeps, memberSeed, duesSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec(context.Background(), `ALTER TABLE members DROP COLUMN status, DROP COLUMN suspended_until`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.ReadFile("../docs/migrations/0003_member_status.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = db.Exec(context.Background(), string(f)); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), arbitary.TrxX{}, tx)
+	res := duesDeps.AddDues(ctx, dues.AddDuesIn{
+		Date:      time.Now().Add(time.Hour * 24 * 300).Format("2006-01-02"),
+		IdrAmount: "100000",
+	})
+	tx.Commit(context.Background())
+	tx.Rollback(context.Background())
+
+	if res.StatusCode != http.StatusCreated {
+		t.Logf("%#v", res)
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusCreated, res.StatusCode)
+	}
+
+	var n int64
+	err = db.QueryRow(
+		context.Background(),
+		`SELECT COUNT(id) FROM member_dues WHERE member_id = $1 AND dues_id = $2`,
+		muid,
+		res.Res.Id,
+	).Scan(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 1 {
+		t.Fatalf("Expected member dues %d. Got %d\n", 1, n)
+	}
+}
+
 func TestQueryDues(t *testing.T) {
 	err := ClearTables(db)
 	if err != nil {
@@ -335,6 +335,8 @@ func (r *MemberDuesRepository) UpdateById(ctx context.Context, id uint64, m Memb
 	return nil
 }
 
+// Dues are only given to active members, a member with a suspension
+// already over is active
 func (r *MemberDuesRepository) GenerateDues(ctx context.Context, duesId uint64) (err error) {
 	// Ref: PostgreSQL: insert from another table
 	// https://stackoverflow.com/a/6898775/12976234
@@ -359,6 +361,10 @@ func (r *MemberDuesRepository) GenerateDues(ctx context.Context, duesId uint64)
 		FROM members 
 		WHERE deleted_at IS NULL 
 			AND is_approved = true
+			AND (
+				status = 'active'
+				OR (status = 'suspended' AND suspended_until <= $2)
+			)
 	`
 
 	var exec MemberDuesExecutor
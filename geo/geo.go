@@ -0,0 +1,48 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidLatitude  = errors.New("latitude must be a number between -90 and 90")
+	ErrInvalidLongitude = errors.New("longitude must be a number between -180 and 180")
+)
+
+func parseCoordinate(s string, limit float64) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.Trim(s, " "), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+
+	if f < -limit || f > limit {
+		return 0, false
+	}
+
+	return f, true
+}
+
+// Parse a decimal degree latitude, return error if it is not a number
+// or fall outside -90 to 90
+func ParseLatitude(s string) (float64, error) {
+	f, ok := parseCoordinate(s, 90)
+	if !ok {
+		return 0, ErrInvalidLatitude
+	}
+
+	return f, nil
+}
+
+// Parse a decimal degree longitude, return error if it is not a number
+// or fall outside -180 to 180
+func ParseLongitude(s string) (float64, error) {
+	f, ok := parseCoordinate(s, 180)
+	if !ok {
+		return 0, ErrInvalidLongitude
+	}
+
+	return f, nil
+}
@@ -0,0 +1,93 @@
+package geo_test
+
+import (
+	"testing"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/geo"
+)
+
+func TestParseLatitude(t *testing.T) {
+	testCases := []struct {
+		name     string
+		in       string
+		expected float64
+		isErr    bool
+	}{
+		{
+			name:     "Parse Latitude Success",
+			in:       "-6.914744",
+			expected: -6.914744,
+		},
+		{
+			name:     "Parse Latitude with Spaces Success",
+			in:       " 90 ",
+			expected: 90,
+		},
+		{
+			name:  "Parse Latitude Fail, Out of Range",
+			in:    "120.12312312",
+			isErr: true,
+		},
+		{
+			name:  "Parse Latitude Fail, Not a Number",
+			in:    "abc",
+			isErr: true,
+		},
+		{
+			name:  "Parse Latitude Fail, NaN",
+			in:    "NaN",
+			isErr: true,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := geo.ParseLatitude(c.in)
+			if (err != nil) != c.isErr {
+				t.Fatalf("Expected error %t. Got %v", c.isErr, err)
+			}
+
+			if f != c.expected {
+				t.Fatalf("Expected %f. Got %f", c.expected, f)
+			}
+		})
+	}
+}
+
+func TestParseLongitude(t *testing.T) {
+	testCases := []struct {
+		name     string
+		in       string
+		expected float64
+		isErr    bool
+	}{
+		{
+			name:     "Parse Longitude Success",
+			in:       "107.609810",
+			expected: 107.609810,
+		},
+		{
+			name:  "Parse Longitude Fail, Out of Range",
+			in:    "-180.5",
+			isErr: true,
+		},
+		{
+			name:  "Parse Longitude Fail, Empty",
+			in:    "",
+			isErr: true,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := geo.ParseLongitude(c.in)
+			if (err != nil) != c.isErr {
+				t.Fatalf("Expected error %t. Got %v", c.isErr, err)
+			}
+
+			if f != c.expected {
+				t.Fatalf("Expected %f. Got %f", c.expected, f)
+			}
+		})
+	}
+}
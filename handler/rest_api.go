@@ -47,8 +47,9 @@ func NewRestApi(
 }
 
 func (p *RestApiConf) RestApiHandler() {
-	jwtMidd := jwt.NewMiddleware(p.Conf.JwtKey, p.Conf.JwtIssuerUrl, p.Conf.JwtAudiences, &jwt.JwtPrivateClaim{})
-	adminJwtMidd := jwt.NewMiddleware(p.Conf.JwtKey, p.Conf.JwtIssuerUrl, p.Conf.JwtAudiences, &jwt.JwtPrivateAdminClaim{})
+	jwtMidd := jwt.NewMiddleware(p.Conf.JwtKey, p.Conf.JwtIssuerUrl, p.Conf.JwtAudiences, &jwt.JwtPrivateClaim{}, p.DashboardDeps.IsSessionAlive)
+	adminJwtMidd := jwt.NewMiddleware(p.Conf.JwtKey, p.Conf.JwtIssuerUrl, p.Conf.JwtAudiences, &jwt.JwtPrivateAdminClaim{}, p.DashboardDeps.IsSessionAlive)
+	optJwtMidd := jwt.NewOptionalMiddleware(p.Conf.JwtKey, p.Conf.JwtIssuerUrl, p.Conf.JwtAudiences, &jwt.JwtPrivateClaim{}, p.DashboardDeps.IsSessionAlive)
 	trxMidd := mw.NewTrxMiddleware(p.PosgrePool)
 
 	// Basic CORS
@@ -75,6 +76,7 @@ func (p *RestApiConf) RestApiHandler() {
 	rateLMidd := httprate.LimitByIP(100, 1*time.Minute)
 
 	r := chi.NewRouter()
+	r.Use(mw.NewRealIpMiddleware(p.Conf.TrustedProxies))
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(rateLMidd)
@@ -110,15 +112,52 @@ func (p *RestApiConf) RestApiHandler() {
 	r.With(trxMidd).Post("/api/v1/register", p.DashboardDeps.PostRegisterMember)
 	r.Post("/api/v1/login/members", p.DashboardDeps.PostLoginMember)
 	r.Post("/api/v1/login/admins", p.DashboardDeps.PostLoginAdmin)
+	r.With(trxMidd).Post("/api/v1/login/totp", p.DashboardDeps.PostLoginTotp)
+	r.Post("/api/v1/login/totp/enrollment", p.DashboardDeps.PostLoginTotpEnrollment)
+	r.Post("/api/v1/register/status", p.DashboardDeps.PostRegistrationStatus)
+	r.With(trxMidd).Post("/api/v1/register/resubmission", p.DashboardDeps.PostRegistrationResubmission)
+	r.Get("/api/v1/register/invitation", p.DashboardDeps.GetRegisterInvitation)
+	r.With(trxMidd).Post("/api/v1/register/invitation", p.DashboardDeps.PostRegisterInvitation)
 
-	r.Get("/api/v1/members", p.DashboardDeps.GetMembers)
-	r.Get("/api/v1/members/{id}", p.DashboardDeps.GetMember)
+	r.With(optJwtMidd).Get("/api/v1/members", p.DashboardDeps.GetMembers)
+	r.With(adminJwtMidd).Get("/api/v1/members/search", p.DashboardDeps.GetMembersSearch)
+	r.With(optJwtMidd).Get("/api/v1/members/{id}", p.DashboardDeps.GetMember)
 	r.With(jwtMidd).Get("/api/v1/profile", p.DashboardDeps.GetProfileMember)
+	r.With(jwtMidd).Get("/api/v1/profile/privacy", p.DashboardDeps.GetMemberPrivacy)
+	r.With(jwtMidd).Put("/api/v1/profile/privacy", p.DashboardDeps.PutMemberPrivacy)
+	r.With(jwtMidd).Get("/api/v1/profile/export", p.DashboardDeps.GetMemberExport)
+	r.With(jwtMidd).Get("/api/v1/profile/erasure", p.DashboardDeps.GetMemberErasure)
+	r.With(jwtMidd).Post("/api/v1/profile/erasure", p.DashboardDeps.PostMemberErasure)
+	r.With(jwtMidd).Get("/api/v1/profile/changes", p.DashboardDeps.GetMemberProfileChanges)
+	r.With(jwtMidd).Get("/api/v1/profile/card", p.DashboardDeps.GetMemberCard)
+	r.With(jwtMidd).Get("/api/v1/profile/sessions", p.DashboardDeps.GetMemberSessions)
+	r.With(jwtMidd).Delete("/api/v1/profile/sessions/{id}", p.DashboardDeps.DeleteMemberSession)
+	r.With(jwtMidd).Get("/api/v1/profile/totp", p.DashboardDeps.GetMemberTotp)
+	r.With(jwtMidd).Post("/api/v1/profile/totp", p.DashboardDeps.PostMemberTotp)
+	r.With(jwtMidd).With(trxMidd).Post("/api/v1/profile/totp/confirm", p.DashboardDeps.PostMemberTotpConfirm)
+	r.With(jwtMidd).Post("/api/v1/profile/totp/disable", p.DashboardDeps.PostMemberTotpDisable)
+	r.With(jwtMidd).With(trxMidd).Post("/api/v1/profile/totp/recovery-codes", p.DashboardDeps.PostMemberRecoveryCodes)
+	r.Get("/api/v1/cards/verify", p.DashboardDeps.GetMemberCardVerification)
 	r.With(adminJwtMidd).With(trxMidd).Post("/api/v1/members", p.DashboardDeps.PostMember)
+	r.With(adminJwtMidd).With(trxMidd).Post("/api/v1/members/import", p.DashboardDeps.PostMembersImport)
 	r.With(jwtMidd).With(trxMidd).Put("/api/v1/members", p.DashboardDeps.PutMemberProfile)
 	r.With(adminJwtMidd).With(trxMidd).Put("/api/v1/members/{id}", p.DashboardDeps.PutMember)
 	r.With(adminJwtMidd).With(trxMidd).Delete("/api/v1/members/{id}", p.DashboardDeps.DeleteMember)
 	r.With(adminJwtMidd).With(trxMidd).Patch("/api/v1/members/{id}", p.DashboardDeps.PatchMemberApproval)
+	r.With(adminJwtMidd).With(trxMidd).Patch("/api/v1/members/{id}/status", p.DashboardDeps.PatchMemberStatus)
+	r.With(adminJwtMidd).Get("/api/v1/members/{id}/status", p.DashboardDeps.GetMemberStatusHistory)
+	r.With(adminJwtMidd).Delete("/api/v1/members/{id}/totp", p.DashboardDeps.DeleteMemberTotp)
+	r.With(adminJwtMidd).Delete("/api/v1/members/{id}/lock", p.DashboardDeps.DeleteMemberLoginLock)
+	r.With(adminJwtMidd).Delete("/api/v1/members/{id}/sessions", p.DashboardDeps.DeleteMemberSessions)
+	r.With(adminJwtMidd).Get("/api/v1/login-locks", p.DashboardDeps.GetLoginLocks)
+	r.With(adminJwtMidd).Get("/api/v1/file-removals", p.DashboardDeps.GetFileRemovals)
+	r.With(adminJwtMidd).Get("/api/v1/erasures", p.DashboardDeps.GetMemberErasures)
+	r.With(adminJwtMidd).With(trxMidd).Patch("/api/v1/erasures/{id}", p.DashboardDeps.PatchMemberErasure)
+	r.With(adminJwtMidd).Get("/api/v1/profile-changes", p.DashboardDeps.GetProfileChanges)
+	r.With(adminJwtMidd).With(trxMidd).Patch("/api/v1/profile-changes/{id}", p.DashboardDeps.PatchProfileChange)
+	r.With(adminJwtMidd).Get("/api/v1/invitations", p.DashboardDeps.GetMemberInvitations)
+	r.With(adminJwtMidd).Post("/api/v1/invitations", p.DashboardDeps.PostMemberInvitation)
+	r.With(adminJwtMidd).Delete("/api/v1/invitations/{id}", p.DashboardDeps.DeleteMemberInvitation)
 
 	r.Get("/api/v1/periods", p.DashboardDeps.GetPeriods)
 	r.Get("/api/v1/periods/active", p.DashboardDeps.GetActivePeriod)
@@ -147,11 +186,22 @@ func (p *RestApiConf) RestApiHandler() {
 	r.With(adminJwtMidd).Post("/api/v1/histories", p.DashboardDeps.PostHistory)
 	r.Get("/api/v1/histories", p.DashboardDeps.GetHistory)
 
+	r.Get("/api/v1/articles/categories", p.DashboardDeps.GetCategories)
+	r.With(adminJwtMidd).Post("/api/v1/articles/categories", p.DashboardDeps.PostCategory)
+	r.With(adminJwtMidd).Put("/api/v1/articles/categories/{id}", p.DashboardDeps.PutCategory)
+	r.With(adminJwtMidd).With(trxMidd).Delete("/api/v1/articles/categories/{id}", p.DashboardDeps.DeleteCategory)
+	r.Get("/api/v1/articles/tags", p.DashboardDeps.GetTags)
+
 	r.Get("/api/v1/articles", p.DashboardDeps.GetArticles)
 	r.Get("/api/v1/articles/{id}", p.DashboardDeps.GetArticle)
-	r.With(adminJwtMidd).Post("/api/v1/articles", p.DashboardDeps.PostArticle)
-	r.With(adminJwtMidd).Put("/api/v1/articles/{id}", p.DashboardDeps.PutArticle)
+	r.Get("/api/v1/articles/{id}/related", p.DashboardDeps.GetRelatedArticles)
+	r.With(adminJwtMidd).With(trxMidd).Post("/api/v1/articles", p.DashboardDeps.PostArticle)
+	r.With(adminJwtMidd).With(trxMidd).Put("/api/v1/articles/{id}", p.DashboardDeps.PutArticle)
 	r.With(adminJwtMidd).Delete("/api/v1/articles/{id}", p.DashboardDeps.DeleteArticle)
+	r.With(adminJwtMidd).Get("/api/v1/articles/{id}/revisions", p.DashboardDeps.GetRevisions)
+	r.With(adminJwtMidd).Get("/api/v1/articles/{id}/revisions/diff", p.DashboardDeps.GetRevisionDiff)
+	r.With(adminJwtMidd).Get("/api/v1/articles/{id}/revisions/{rid}", p.DashboardDeps.GetRevision)
+	r.With(adminJwtMidd).With(trxMidd).Post("/api/v1/articles/{id}/revisions/{rid}/restore", p.DashboardDeps.PostRevisionRestore)
 	r.With(adminJwtMidd).Post("/api/v1/articles/image", p.DashboardDeps.PostImage)
 
 	r.Get("/api/v1/cashflows", p.DashboardDeps.GetCashflows)
@@ -180,13 +230,47 @@ func (p *RestApiConf) RestApiHandler() {
 	r.With(adminJwtMidd).Delete("/api/v1/images/{id}", p.DashboardDeps.DeleteImage)
 
 	r.With(jwtMidd).Post("/api/v1/homestays/images", p.DashboardDeps.PostHomestayImage)
-	r.With(jwtMidd).Delete("/api/v1/homestays/images/{id}", p.DashboardDeps.DeleteHomestayImage)
+	r.With(jwtMidd).With(trxMidd).Delete("/api/v1/homestays/images/{id}", p.DashboardDeps.DeleteHomestayImage)
+
+	r.Get("/api/v1/calendars/{token}.ics", p.DashboardDeps.GetHomestayRoomCalendar)
 
-	r.Get("/api/v1/homestays/{uid}/list", p.DashboardDeps.GetMemberHomestays)
-	r.Get("/api/v1/homestays/{id}/{uid}", p.DashboardDeps.GetMemberHomestay)
-	r.With(jwtMidd).Post("/api/v1/homestays/{uid}", p.DashboardDeps.PostMemberHomestay)
+	r.Get("/api/v1/homestays", p.DashboardDeps.GetHomestayDirectory)
+	r.With(adminJwtMidd).Get("/api/v1/homestays/reviews", p.DashboardDeps.GetAllHomestayReviews)
+	r.With(adminJwtMidd).With(trxMidd).Patch("/api/v1/homestays/reviews/{vid}", p.DashboardDeps.PatchHomestayReview)
+	r.With(adminJwtMidd).Get("/api/v1/homestays/listings", p.DashboardDeps.GetHomestayListings)
+	r.With(adminJwtMidd).Patch("/api/v1/homestays/listings/{hid}", p.DashboardDeps.PatchHomestayListing)
+	r.With(adminJwtMidd).Get("/api/v1/homestays/stats", p.DashboardDeps.GetHomestayStats)
+	r.Get("/api/v1/homestays/geojson", p.DashboardDeps.GetHomestayGeoJSON)
+	r.Get("/api/v1/homestays/amenities", p.DashboardDeps.GetHomestayAmenities)
+	r.With(adminJwtMidd).Post("/api/v1/homestays/amenities", p.DashboardDeps.PostHomestayAmenity)
+	r.With(adminJwtMidd).Put("/api/v1/homestays/amenities/{aid}", p.DashboardDeps.PutHomestayAmenity)
+	r.With(adminJwtMidd).With(trxMidd).Delete("/api/v1/homestays/amenities/{aid}", p.DashboardDeps.DeleteHomestayAmenity)
+	r.With(optJwtMidd).Get("/api/v1/homestays/{uid}/list", p.DashboardDeps.GetMemberHomestays)
+	r.Get("/api/v1/homestays/{id}/rooms", p.DashboardDeps.GetHomestayRooms)
+	r.Get("/api/v1/homestays/{id}/availability", p.DashboardDeps.GetHomestayAvailability)
+	r.With(jwtMidd).Post("/api/v1/homestays/{id}/rooms", p.DashboardDeps.PostHomestayRoom)
+	r.With(jwtMidd).Put("/api/v1/homestays/{id}/rooms/{rid}", p.DashboardDeps.PutHomestayRoom)
+	r.With(jwtMidd).Delete("/api/v1/homestays/{id}/rooms/{rid}", p.DashboardDeps.DeleteHomestayRoom)
+	r.With(jwtMidd).Get("/api/v1/homestays/{id}/rooms/{rid}/ical", p.DashboardDeps.GetHomestayRoomIcal)
+	r.With(jwtMidd).Post("/api/v1/homestays/{id}/rooms/{rid}/ical/token", p.DashboardDeps.PostHomestayRoomIcalToken)
+	r.With(jwtMidd).Post("/api/v1/homestays/{id}/rooms/{rid}/ical/feeds", p.DashboardDeps.PostHomestayRoomIcalFeed)
+	r.With(jwtMidd).Delete("/api/v1/homestays/{id}/rooms/{rid}/ical/feeds/{fid}", p.DashboardDeps.DeleteHomestayRoomIcalFeed)
+	r.With(jwtMidd).Put("/api/v1/homestays/{id}/images/order", p.DashboardDeps.PutHomestayImagesOrder)
+	r.With(jwtMidd).Patch("/api/v1/homestays/{id}/images/{iid}", p.DashboardDeps.PatchHomestayImage)
+	r.With(jwtMidd).Post("/api/v1/homestays/{id}/images/{iid}/cover", p.DashboardDeps.PostHomestayCover)
+	r.With(jwtMidd).Post("/api/v1/homestays/{id}/blocks", p.DashboardDeps.PostHomestayBlocks)
+	r.With(jwtMidd).Delete("/api/v1/homestays/{id}/blocks", p.DashboardDeps.DeleteHomestayBlocks)
+	r.Post("/api/v1/homestays/{id}/bookings", p.DashboardDeps.PostHomestayBooking)
+	r.With(jwtMidd).Get("/api/v1/homestays/{id}/bookings", p.DashboardDeps.GetHomestayBookings)
+	r.With(jwtMidd).Patch("/api/v1/homestays/{id}/bookings/{bid}", p.DashboardDeps.PatchHomestayBooking)
+	r.Get("/api/v1/homestays/{id}/reviews", p.DashboardDeps.GetHomestayReviews)
+	r.With(trxMidd).Post("/api/v1/homestays/{id}/reviews", p.DashboardDeps.PostHomestayReview)
+	r.With(jwtMidd).Post("/api/v1/homestays/{id}/reviews/codes", p.DashboardDeps.PostHomestayReviewCode)
+	r.With(jwtMidd).Post("/api/v1/homestays/{id}/reviews/{vid}/reply", p.DashboardDeps.PostHomestayReviewReply)
+	r.With(optJwtMidd).Get("/api/v1/homestays/{id}/{uid}", p.DashboardDeps.GetMemberHomestay)
+	r.With(jwtMidd).With(trxMidd).Post("/api/v1/homestays/{uid}", p.DashboardDeps.PostMemberHomestay)
 	r.With(jwtMidd).Delete("/api/v1/homestays/{id}/{uid}", p.DashboardDeps.DeleteMemberHomestay)
-	r.With(jwtMidd).Put("/api/v1/homestays/{id}/{uid}", p.DashboardDeps.PutMemberHomestay)
+	r.With(jwtMidd).With(trxMidd).Put("/api/v1/homestays/{id}/{uid}", p.DashboardDeps.PutMemberHomestay)
 
 	workDir, _ := os.Getwd()
 	filesDir := http.Dir(filepath.Join(workDir, "docs"))
@@ -25,6 +25,13 @@ import (
 
 var db *pgxpool.Pool
 
+const aliveSessionId = "8f2a7a8e-7a51-4d0f-9a3c-4f1b2d3c4e5f"
+
+// Only the session of aliveSessionId is not revoked
+func checkSession(ctx context.Context, sessionId string) (bool, error) {
+	return sessionId == aliveSessionId, nil
+}
+
 func TestMain(m *testing.M) {
 	// uses a sensible default on windows (tcp/http) and linux/osx (socket)
 	pool, err := dockertest.NewPool("")
@@ -91,7 +98,7 @@ func TestPrivateJWTRoute(t *testing.T) {
 	jwtIssuerUrl := "http://localhost:8080"
 	jwtAudiences := []string{"test"}
 
-	jwtMidd := jwt.NewMiddleware(jwtKey, jwtIssuerUrl, jwtAudiences, &jwt.JwtPrivateClaim{})
+	jwtMidd := jwt.NewMiddleware(jwtKey, jwtIssuerUrl, jwtAudiences, &jwt.JwtPrivateClaim{}, checkSession)
 
 	testCases := []struct {
 		name               string
@@ -102,7 +109,7 @@ func TestPrivateJWTRoute(t *testing.T) {
 			name: "Access Private Route Success",
 			setHeader: func(r *http.Request) {
 				jwtToken, _ := jwt.Sign(
-					"",
+					aliveSessionId,
 					"token",
 					jwtIssuerUrl,
 					jwtKey,
@@ -121,7 +128,7 @@ func TestPrivateJWTRoute(t *testing.T) {
 			name: "Access Private Route Fail, Wrong JWT Key",
 			setHeader: func(r *http.Request) {
 				jwtToken, _ := jwt.Sign(
-					"",
+					aliveSessionId,
 					"token",
 					jwtIssuerUrl,
 					[]byte("wrong-key"),
@@ -140,7 +147,7 @@ func TestPrivateJWTRoute(t *testing.T) {
 			name: "Access Private Route Fail, Not Audience",
 			setHeader: func(r *http.Request) {
 				jwtToken, _ := jwt.Sign(
-					"",
+					aliveSessionId,
 					"token",
 					jwtIssuerUrl,
 					jwtKey,
@@ -159,7 +166,7 @@ func TestPrivateJWTRoute(t *testing.T) {
 			name: "Access Private Route Fail, Token Expired",
 			setHeader: func(r *http.Request) {
 				jwtToken, _ := jwt.Sign(
-					"",
+					aliveSessionId,
 					"token",
 					jwtIssuerUrl,
 					jwtKey,
@@ -174,6 +181,44 @@ func TestPrivateJWTRoute(t *testing.T) {
 			},
 			expectedStatusCode: http.StatusUnauthorized,
 		},
+		{
+			name: "Access Private Route Fail, No Expiry",
+			setHeader: func(r *http.Request) {
+				jwtToken, _ := jwt.Sign(
+					aliveSessionId,
+					"token",
+					jwtIssuerUrl,
+					jwtKey,
+					jwtAudiences,
+					time.Time{},
+					time.Time{},
+					time.Time{},
+					jwt.JwtPrivateClaim{
+						Uid: "12345678-1234-1234-1234-123456789012",
+					})
+				r.Header.Set("Authorization", "Bearer "+jwtToken)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name: "Access Private Route Fail, Session Revoked",
+			setHeader: func(r *http.Request) {
+				jwtToken, _ := jwt.Sign(
+					"0c5e1f7a-2b3d-4e6f-8a9b-1c2d3e4f5a6b",
+					"token",
+					jwtIssuerUrl,
+					jwtKey,
+					jwtAudiences,
+					time.Time{},
+					time.Now().Add(time.Hour),
+					time.Time{},
+					jwt.JwtPrivateClaim{
+						Uid: "12345678-1234-1234-1234-123456789012",
+					})
+				r.Header.Set("Authorization", "Bearer "+jwtToken)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
 		{
 			name: "Access Private Route Fail, Not JWT Token",
 			setHeader: func(r *http.Request) {
@@ -227,7 +272,7 @@ func TestPrivateJWTAdminRoute(t *testing.T) {
 	jwtIssuerUrl := "http://localhost:8080"
 	jwtAudiences := []string{"test"}
 
-	jwtMidd := jwt.NewMiddleware(jwtKey, jwtIssuerUrl, jwtAudiences, &jwt.JwtPrivateAdminClaim{})
+	jwtMidd := jwt.NewMiddleware(jwtKey, jwtIssuerUrl, jwtAudiences, &jwt.JwtPrivateAdminClaim{}, checkSession)
 
 	testCases := []struct {
 		name               string
@@ -238,7 +283,7 @@ func TestPrivateJWTAdminRoute(t *testing.T) {
 			name: "Access Private Route Success",
 			setHeader: func(r *http.Request) {
 				jwtToken, _ := jwt.Sign(
-					"",
+					aliveSessionId,
 					"token",
 					jwtIssuerUrl,
 					jwtKey,
@@ -258,7 +303,7 @@ func TestPrivateJWTAdminRoute(t *testing.T) {
 			name: "Access Private Route Fail, JWT is not Admin JWT",
 			setHeader: func(r *http.Request) {
 				jwtToken, _ := jwt.Sign(
-					"",
+					aliveSessionId,
 					"token",
 					jwtIssuerUrl,
 					[]byte("wrong-key"),
@@ -278,7 +323,7 @@ func TestPrivateJWTAdminRoute(t *testing.T) {
 			name: "Access Private Route Fail, Wrong JWT Key",
 			setHeader: func(r *http.Request) {
 				jwtToken, _ := jwt.Sign(
-					"",
+					aliveSessionId,
 					"token",
 					jwtIssuerUrl,
 					[]byte("wrong-key"),
@@ -298,7 +343,7 @@ func TestPrivateJWTAdminRoute(t *testing.T) {
 			name: "Access Private Route Fail, Not Audience",
 			setHeader: func(r *http.Request) {
 				jwtToken, _ := jwt.Sign(
-					"",
+					aliveSessionId,
 					"token",
 					jwtIssuerUrl,
 					jwtKey,
@@ -318,7 +363,7 @@ func TestPrivateJWTAdminRoute(t *testing.T) {
 			name: "Access Private Route Fail, Token Expired",
 			setHeader: func(r *http.Request) {
 				jwtToken, _ := jwt.Sign(
-					"",
+					aliveSessionId,
 					"token",
 					jwtIssuerUrl,
 					jwtKey,
@@ -334,6 +379,26 @@ func TestPrivateJWTAdminRoute(t *testing.T) {
 			},
 			expectedStatusCode: http.StatusUnauthorized,
 		},
+		{
+			name: "Access Private Route Fail, Session Revoked",
+			setHeader: func(r *http.Request) {
+				jwtToken, _ := jwt.Sign(
+					"0c5e1f7a-2b3d-4e6f-8a9b-1c2d3e4f5a6b",
+					"token",
+					jwtIssuerUrl,
+					jwtKey,
+					jwtAudiences,
+					time.Time{},
+					time.Now().Add(time.Hour),
+					time.Time{},
+					jwt.JwtPrivateAdminClaim{
+						Uid:     "12345678-1234-1234-1234-123456789012",
+						IsAdmin: true,
+					})
+				r.Header.Set("Authorization", "Bearer "+jwtToken)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
 		{
 			name: "Access Private Route Fail, Not JWT Token",
 			setHeader: func(r *http.Request) {
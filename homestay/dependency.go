@@ -2,7 +2,12 @@ package homestay
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
 
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
 	"github.com/cloudinary/cloudinary-go/api/uploader"
@@ -10,26 +15,45 @@ import (
 
 type (
 	FileUploader func(filename string, file io.Reader) (string, error)
+	IcalFetcher  func(ctx context.Context, url string) (io.ReadCloser, error)
 )
 
 type HomestayDeps struct {
-	Upload                   FileUploader
-	HomestayImageRepository  *HomestayImageRepository
-	MemberHomestayRepository *MemberHomestayRepository
-	MemberRepository         *user.MemberRepository
+	Upload                         FileUploader
+	FetchIcal                      IcalFetcher
+	HomestayImageRepository        *HomestayImageRepository
+	MemberHomestayRepository       *MemberHomestayRepository
+	HomestayRoomRepository         *HomestayRoomRepository
+	HomestayBookingRepository      *HomestayBookingRepository
+	HomestayRoomIcalFeedRepository *HomestayRoomIcalFeedRepository
+	HomestayReviewRepository       *HomestayReviewRepository
+	HomestayAmenityRepository      *HomestayAmenityRepository
+	MemberRepository               *user.MemberRepository
 }
 
 func NewDeps(
 	upload FileUploader,
+	fetchIcal IcalFetcher,
 	homestayImageRepository *HomestayImageRepository,
 	memberHomestayRepository *MemberHomestayRepository,
+	homestayRoomRepository *HomestayRoomRepository,
+	homestayBookingRepository *HomestayBookingRepository,
+	homestayRoomIcalFeedRepository *HomestayRoomIcalFeedRepository,
+	homestayReviewRepository *HomestayReviewRepository,
+	homestayAmenityRepository *HomestayAmenityRepository,
 	memberRepository *user.MemberRepository,
 ) *HomestayDeps {
 	return &HomestayDeps{
-		Upload:                   upload,
-		HomestayImageRepository:  homestayImageRepository,
-		MemberHomestayRepository: memberHomestayRepository,
-		MemberRepository:         memberRepository,
+		Upload:                         upload,
+		FetchIcal:                      fetchIcal,
+		HomestayImageRepository:        homestayImageRepository,
+		MemberHomestayRepository:       memberHomestayRepository,
+		HomestayRoomRepository:         homestayRoomRepository,
+		HomestayBookingRepository:      homestayBookingRepository,
+		HomestayRoomIcalFeedRepository: homestayRoomIcalFeedRepository,
+		HomestayReviewRepository:       homestayReviewRepository,
+		HomestayAmenityRepository:      homestayAmenityRepository,
+		MemberRepository:               memberRepository,
 	}
 }
 
@@ -46,3 +70,77 @@ func FileUpload(uploadParams uploader.UploadParams, upload func(ctx context.Cont
 		return resp.SecureURL, nil
 	}
 }
+
+// Refuse to connect to an address of the server itself or its private
+// network. The check runs on the resolved address of every dial, so a host
+// resolving to such an address and a redirect to one are refused as well.
+func icalDialControl(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() {
+		return ErrIcalFeedAddressNotAllowed
+	}
+
+	return nil
+}
+
+// Client to fetch the external iCal feeds, the feed url is given by the
+// homestay owner so only public addresses are reachable
+func NewIcalHttpClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: icalDialControl,
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// A proxy would be dialed instead of the feed host
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return fmt.Errorf("stopped after %d redirects", len(via))
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return ErrInvalidIcalFeedUrl
+			}
+			return nil
+		},
+	}
+}
+
+func HttpIcalFetch(client *http.Client) IcalFetcher {
+	return func(ctx context.Context, url string) (io.ReadCloser, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "text/calendar")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+		}
+
+		return resp.Body, nil
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/http"
 	"os"
 	"testing"
 	"time"
@@ -21,23 +22,34 @@ import (
 )
 
 var (
-	db                       *pgxpool.Pool
-	memberRepository         *user.MemberRepository
-	homestayImageRepository  *homestay.HomestayImageRepository
-	memberHomestayRepository *homestay.MemberHomestayRepository
-	homestayDeps             *homestay.HomestayDeps
-	fileName                 = "images.jpeg"
-	fileDir                  = "./fixture/" + fileName
-	fileSeed                 = homestay.HomestayImageModel{
+	db                        *pgxpool.Pool
+	memberRepository          *user.MemberRepository
+	homestayImageRepository   *homestay.HomestayImageRepository
+	memberHomestayRepository  *homestay.MemberHomestayRepository
+	homestayRoomRepository    *homestay.HomestayRoomRepository
+	homestayBookingRepository *homestay.HomestayBookingRepository
+	icalFeedRepository        *homestay.HomestayRoomIcalFeedRepository
+	homestayReviewRepository  *homestay.HomestayReviewRepository
+	homestayAmenityRepository *homestay.HomestayAmenityRepository
+	homestayDeps              *homestay.HomestayDeps
+	fileName                  = "images.jpeg"
+	fileDir                   = "./fixture/" + fileName
+	fileSeed                  = homestay.HomestayImageModel{
 		Name: "file.jpg",
 		Url:  "http://localhost:5000/file.jpg",
 	}
 	homestaySeed = homestay.MemberHomestayModel{
 		Name:         "Name",
 		Address:      "Address",
-		Latitude:     "120",
-		Longitude:    "90",
+		Latitude:     -6.9,
+		Longitude:    107.6,
 		ThumbnailUrl: "http://localhost:5000/file.jpg",
+		Status:       homestay.HomestayApproved,
+	}
+	roomSeed = homestay.HomestayRoomModel{
+		Type:            "Deluxe",
+		Capacity:        2,
+		IdrNightlyPrice: 250000,
 	}
 	memberSeed = user.MemberModel{
 		Name:       "Name",
@@ -92,6 +104,14 @@ func ClearTables(conn *pgxpool.Pool) error {
 
 	// This should be in order of which table truncate first before the other
 	queries := []string{
+		`TRUNCATE member_homestay_amenities CASCADE`,
+		`TRUNCATE homestay_amenities CASCADE`,
+		`TRUNCATE homestay_reviews CASCADE`,
+		`TRUNCATE homestay_review_codes CASCADE`,
+		`TRUNCATE homestay_bookings CASCADE`,
+		`TRUNCATE homestay_room_blocks CASCADE`,
+		`TRUNCATE homestay_room_ical_feeds CASCADE`,
+		`TRUNCATE homestay_rooms CASCADE`,
 		`TRUNCATE homestay_images CASCADE`,
 		`TRUNCATE member_homestays CASCADE`,
 		`TRUNCATE members CASCADE`,
@@ -150,6 +170,15 @@ func createMemberHomestay(r *homestay.MemberHomestayRepository, memberId string,
 	return int64(homestay.Id), nil
 }
 
+func createHomestayRoom(r *homestay.HomestayRoomRepository, homestayId int64, room homestay.HomestayRoomModel) (id int64, err error) {
+	room.MemberHomestayId = uint64(homestayId)
+	if room, err = r.Save(context.Background(), room); err != nil {
+		return 0, err
+	}
+
+	return int64(room.Id), nil
+}
+
 func generateFile(fileDir, fileName string) httpdecode.FileHeader {
 	f, err := os.OpenFile(fileDir, os.O_RDONLY, 0o444)
 	if err != nil {
@@ -216,10 +245,21 @@ func TestMain(m *testing.M) {
 	memberRepository = user.NewMemberRepository(db)
 	homestayImageRepository = homestay.NewHomestayImageRepository(db)
 	memberHomestayRepository = homestay.NewMemberHomestayRepository(db)
+	homestayRoomRepository = homestay.NewHomestayRoomRepository(db)
+	homestayBookingRepository = homestay.NewHomestayBookingRepository(db)
+	icalFeedRepository = homestay.NewHomestayRoomIcalFeedRepository(db)
+	homestayReviewRepository = homestay.NewHomestayReviewRepository(db)
+	homestayAmenityRepository = homestay.NewHomestayAmenityRepository(db)
 	homestayDeps = homestay.NewDeps(
 		upload,
+		homestay.HttpIcalFetch(http.DefaultClient),
 		homestayImageRepository,
 		memberHomestayRepository,
+		homestayRoomRepository,
+		homestayBookingRepository,
+		icalFeedRepository,
+		homestayReviewRepository,
+		homestayAmenityRepository,
 		memberRepository,
 	)
 
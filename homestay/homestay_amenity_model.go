@@ -0,0 +1,14 @@
+package homestay
+
+import (
+	"database/sql"
+	"time"
+)
+
+type HomestayAmenityModel struct {
+	Id        uint64
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt sql.NullTime
+}
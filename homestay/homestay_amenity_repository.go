@@ -0,0 +1,363 @@
+package homestay
+
+import (
+	"context"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type HomestayAmenityRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewHomestayAmenityRepository(postgreDb *pgxpool.Pool) *HomestayAmenityRepository {
+	return &HomestayAmenityRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+type (
+	HomestayAmenityExecutor   func(ctx context.Context, sql string, arguments ...interface{}) (commandTag pgconn.CommandTag, err error)
+	HomestayAmenityQuerierRow func(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	HomestayAmenityQuerier    func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	HomestayAmenityCopierFrom func(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+)
+
+func (r *HomestayAmenityRepository) Save(ctx context.Context, m HomestayAmenityModel) (nm HomestayAmenityModel, err error) {
+	sqlQuery := `
+		INSERT INTO homestay_amenities (
+			name,
+			created_at,
+			updated_at,
+			deleted_at
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	var queryRow HomestayAmenityQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	var lastInsertId uint64
+	t := time.Now()
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		m.Name,
+		t,
+		t,
+		nil,
+	).Scan(&lastInsertId)
+
+	if err != nil {
+		return HomestayAmenityModel{}, err
+	}
+
+	m.Id = lastInsertId
+	m.CreatedAt = t
+	m.UpdatedAt = t
+
+	return m, nil
+}
+
+func (r *HomestayAmenityRepository) UpdateById(ctx context.Context, id uint64, m HomestayAmenityModel) error {
+	sqlQuery := `
+		UPDATE homestay_amenities SET (
+			name,
+			updated_at
+		) = ($1, $2)
+		WHERE id = $3
+	`
+
+	var exec HomestayAmenityExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		m.Name,
+		time.Now(),
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *HomestayAmenityRepository) FindUndeletedById(ctx context.Context, id uint64) (m HomestayAmenityModel, err error) {
+	querystr := `
+		SELECT
+			id,
+			name,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM homestay_amenities
+		WHERE deleted_at IS NULL
+		AND id = $1
+	`
+
+	var query HomestayAmenityQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+	rows, err = query(
+		context.Background(),
+		querystr,
+		id,
+	)
+
+	if err != nil {
+		return HomestayAmenityModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return HomestayAmenityModel{}, err
+	}
+
+	return m, nil
+}
+
+func (r *HomestayAmenityRepository) DeleteById(ctx context.Context, id uint64) error {
+	sqlQuery := `
+		UPDATE homestay_amenities
+		SET deleted_at = $1
+		WHERE id = $2
+	`
+
+	var exec HomestayAmenityExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		time.Now(),
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *HomestayAmenityRepository) Query(ctx context.Context) ([]HomestayAmenityModel, error) {
+	sqlQuery := `
+		SELECT
+			id,
+			name,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM homestay_amenities
+		WHERE deleted_at IS NULL
+		ORDER BY name ASC
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+	)
+	defer rows.Close()
+
+	var mps []*HomestayAmenityModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []HomestayAmenityModel{}, err
+	}
+
+	ms := make([]HomestayAmenityModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *HomestayAmenityRepository) QueryUndeletedInId(ctx context.Context, ids []uint64) ([]HomestayAmenityModel, error) {
+	sqlQuery := `
+		SELECT
+			id,
+			name,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM homestay_amenities
+		WHERE deleted_at IS NULL
+		AND id = ANY($1)
+	`
+
+	var query HomestayAmenityQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	rows, _ := query(
+		context.Background(),
+		sqlQuery,
+		ids,
+	)
+	defer rows.Close()
+
+	var mps []*HomestayAmenityModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []HomestayAmenityModel{}, err
+	}
+
+	ms := make([]HomestayAmenityModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *HomestayAmenityRepository) QueryByHomestayId(ctx context.Context, homestayId uint64) ([]HomestayAmenityModel, error) {
+	sqlQuery := `
+		SELECT
+			a.id,
+			a.name,
+			a.created_at,
+			a.updated_at,
+			a.deleted_at
+		FROM member_homestay_amenities mha
+			JOIN homestay_amenities a ON a.id = mha.homestay_amenity_id
+		WHERE a.deleted_at IS NULL
+			AND mha.member_homestay_id = $1
+		ORDER BY a.name ASC
+	`
+
+	var query HomestayAmenityQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	rows, _ := query(
+		context.Background(),
+		sqlQuery,
+		homestayId,
+	)
+	defer rows.Close()
+
+	var mps []*HomestayAmenityModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []HomestayAmenityModel{}, err
+	}
+
+	ms := make([]HomestayAmenityModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *HomestayAmenityRepository) DeleteHomestayAmenities(ctx context.Context, homestayId uint64) error {
+	sqlQuery := `
+		DELETE FROM member_homestay_amenities
+		WHERE member_homestay_id = $1
+	`
+
+	var exec HomestayAmenityExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		homestayId,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *HomestayAmenityRepository) DeleteAmenityHomestays(ctx context.Context, amenityId uint64) error {
+	sqlQuery := `
+		DELETE FROM member_homestay_amenities
+		WHERE homestay_amenity_id = $1
+	`
+
+	var exec HomestayAmenityExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		amenityId,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *HomestayAmenityRepository) BulkSaveHomestayAmenities(ctx context.Context, homestayId uint64, amenityIds []uint64) error {
+	var copyFrom HomestayAmenityCopierFrom
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		copyFrom = tx.CopyFrom
+	} else {
+		copyFrom = r.PostgreDb.CopyFrom
+	}
+
+	_, err := copyFrom(
+		context.Background(),
+		pgx.Identifier{"member_homestay_amenities"},
+		[]string{"member_homestay_id", "homestay_amenity_id"},
+		pgx.CopyFromSlice(len(amenityIds), func(i int) ([]interface{}, error) {
+			return []interface{}{homestayId, amenityIds[i]}, nil
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
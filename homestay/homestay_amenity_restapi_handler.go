@@ -0,0 +1,49 @@
+package homestay
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/go-chi/chi/v5"
+)
+
+func (d *HomestayDeps) PostHomestayAmenity(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+
+	var in AddHomestayAmenityIn
+	err := decoder.Decode(&in)
+	if err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.AddHomestayAmenity(r.Context(), in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) GetHomestayAmenities(w http.ResponseWriter, r *http.Request) {
+	out := d.QueryHomestayAmenities(r.Context())
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) PutHomestayAmenity(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+
+	var in EditHomestayAmenityIn
+	err := decoder.Decode(&in)
+	if err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	aid := chi.URLParam(r, "aid")
+	out := d.EditHomestayAmenity(r.Context(), aid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) DeleteHomestayAmenity(w http.ResponseWriter, r *http.Request) {
+	aid := chi.URLParam(r, "aid")
+	out := d.RemoveHomestayAmenity(r.Context(), aid)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
@@ -0,0 +1,264 @@
+package homestay
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrAmenityNotFound   = errors.New("fasilitas tidak ditemukan")
+	ErrInvalidAmenityIds = errors.New("fasilitas harus berupa daftar id yang dipisahkan koma")
+)
+
+// Parse the comma separated amenity ids of a listing filter, duplicated
+// ids are dropped so the filter can count the matched amenities
+func ParseAmenityIds(amenities string) ([]uint64, error) {
+	ids := []uint64{}
+	if strings.Trim(amenities, " ") == "" {
+		return ids, nil
+	}
+
+	seenIds := make(map[uint64]bool)
+	for _, s := range strings.Split(amenities, ",") {
+		id, err := strconv.ParseUint(strings.Trim(s, " "), 10, 64)
+		if err != nil || id == 0 {
+			return nil, ErrInvalidAmenityIds
+		}
+
+		if !seenIds[id] {
+			seenIds[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// Check that every given amenity exist in the catalogue, it return the
+// deduplicated ids to be linked to the homestay
+func (d *HomestayDeps) findHomestayAmenityIds(ctx context.Context, amenityIds []int64) ([]uint64, error) {
+	var ids []uint64
+	seenIds := make(map[uint64]bool)
+	for _, ai := range amenityIds {
+		if ai < 1 {
+			return nil, ErrAmenityNotFound
+		}
+
+		id := uint64(ai)
+		if !seenIds[id] {
+			seenIds[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	amenities, err := d.HomestayAmenityRepository.QueryUndeletedInId(ctx, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "query amenity in id")
+	}
+
+	if len(amenities) != len(ids) {
+		return nil, ErrAmenityNotFound
+	}
+
+	return ids, nil
+}
+
+func (d *HomestayDeps) saveHomestayAmenities(ctx context.Context, homestayId uint64, amenityIds []uint64) error {
+	if err := d.HomestayAmenityRepository.DeleteHomestayAmenities(ctx, homestayId); err != nil {
+		return errors.Wrap(err, "delete homestay amenities")
+	}
+
+	if len(amenityIds) != 0 {
+		if err := d.HomestayAmenityRepository.BulkSaveHomestayAmenities(ctx, homestayId, amenityIds); err != nil {
+			return errors.Wrap(err, "bulk save homestay amenities")
+		}
+	}
+
+	return nil
+}
+
+type (
+	AddHomestayAmenityIn struct {
+		Name string `json:"name"`
+	}
+	AddHomestayAmenityRes struct {
+		Id int64 `json:"id"`
+	}
+	AddHomestayAmenityOut struct {
+		resp.Response
+		Res AddHomestayAmenityRes
+	}
+)
+
+func (d *HomestayDeps) AddHomestayAmenity(ctx context.Context, in AddHomestayAmenityIn) (out AddHomestayAmenityOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusCreated, "", nil)
+
+	if err = ValidateAddHomestayAmenityIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	amenity := HomestayAmenityModel{
+		Name: strings.Trim(in.Name, " "),
+	}
+	if amenity, err = d.HomestayAmenityRepository.Save(ctx, amenity); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save amenity"))
+		return
+	}
+
+	out.Res.Id = int64(amenity.Id)
+
+	return
+}
+
+type (
+	HomestayAmenityOut struct {
+		Id   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	QueryHomestayAmenitiesRes struct {
+		Total     int64                `json:"total"`
+		Amenities []HomestayAmenityOut `json:"amenities"`
+	}
+	QueryHomestayAmenitiesOut struct {
+		resp.Response
+		Res QueryHomestayAmenitiesRes
+	}
+)
+
+func toHomestayAmenitiesOut(amenities []HomestayAmenityModel) []HomestayAmenityOut {
+	outAmenities := make([]HomestayAmenityOut, len(amenities))
+	for i, a := range amenities {
+		outAmenities[i] = HomestayAmenityOut{
+			Id:   int64(a.Id),
+			Name: a.Name,
+		}
+	}
+
+	return outAmenities
+}
+
+func (d *HomestayDeps) QueryHomestayAmenities(ctx context.Context) (out QueryHomestayAmenitiesOut) {
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	amenities, err := d.HomestayAmenityRepository.Query(ctx)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query amenities"))
+		return
+	}
+
+	outAmenities := toHomestayAmenitiesOut(amenities)
+
+	out.Res = QueryHomestayAmenitiesRes{
+		Total:     int64(len(outAmenities)),
+		Amenities: outAmenities,
+	}
+
+	return
+}
+
+type (
+	EditHomestayAmenityIn struct {
+		Name string `json:"name"`
+	}
+	EditHomestayAmenityRes struct {
+		Id int64 `json:"id"`
+	}
+	EditHomestayAmenityOut struct {
+		resp.Response
+		Res EditHomestayAmenityRes
+	}
+)
+
+func (d *HomestayDeps) EditHomestayAmenity(ctx context.Context, aid string, in EditHomestayAmenityIn) (out EditHomestayAmenityOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateEditHomestayAmenityIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	id, err := strconv.ParseUint(aid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrAmenityNotFound)
+		return
+	}
+
+	amenity, err := d.HomestayAmenityRepository.FindUndeletedById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrAmenityNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find amenity by id"))
+		return
+	}
+
+	amenity.Name = strings.Trim(in.Name, " ")
+
+	if err = d.HomestayAmenityRepository.UpdateById(ctx, id, amenity); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update amenity by id"))
+		return
+	}
+
+	out.Res.Id = int64(id)
+
+	return
+}
+
+type (
+	RemoveHomestayAmenityRes struct {
+		Id int64 `json:"id"`
+	}
+	RemoveHomestayAmenityOut struct {
+		resp.Response
+		Res RemoveHomestayAmenityRes
+	}
+)
+
+func (d *HomestayDeps) RemoveHomestayAmenity(ctx context.Context, aid string) (out RemoveHomestayAmenityOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(aid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrAmenityNotFound)
+		return
+	}
+
+	_, err = d.HomestayAmenityRepository.FindUndeletedById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrAmenityNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find amenity by id"))
+		return
+	}
+
+	if err = d.HomestayAmenityRepository.DeleteAmenityHomestays(ctx, id); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "delete amenity homestays"))
+		return
+	}
+
+	if err = d.HomestayAmenityRepository.DeleteById(ctx, id); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "delete amenity by id"))
+		return
+	}
+
+	out.Res.Id = int64(id)
+
+	return
+}
@@ -0,0 +1,218 @@
+package homestay_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/homestay"
+)
+
+func TestAddHomestayAmenity(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		In                 homestay.AddHomestayAmenityIn
+	}{
+		{
+			Name:               "Add Homestay Amenity Success",
+			ExpectedStatusCode: http.StatusCreated,
+			In: homestay.AddHomestayAmenityIn{
+				Name: "Wi-Fi",
+			},
+		},
+		{
+			Name:               "Add Homestay Amenity Fail, Name Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: homestay.AddHomestayAmenityIn{
+				Name: " ",
+			},
+		},
+		{
+			Name:               "Add Homestay Amenity Fail, Name over 100 characters",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: homestay.AddHomestayAmenityIn{
+				Name: strings.Repeat("a", 101),
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := homestayDeps.AddHomestayAmenity(context.Background(), c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestQueryHomestaysByAmenities(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var amenityIds []int64
+	for _, name := range []string{"Wi-Fi", "Kamar mandi dalam", "Dekat pantai"} {
+		a := homestayDeps.AddHomestayAmenity(context.Background(), homestay.AddHomestayAmenityIn{
+			Name: name,
+		})
+		if a.Error != nil {
+			t.Fatal(a.Error)
+		}
+
+		amenityIds = append(amenityIds, a.Res.Id)
+	}
+	wifi := strconv.FormatInt(amenityIds[0], 10)
+	bathroom := strconv.FormatInt(amenityIds[1], 10)
+	beach := strconv.FormatInt(amenityIds[2], 10)
+
+	// The first homestay has Wi-Fi and private bathroom, the second only
+	// has Wi-Fi
+	for _, ids := range [][]int64{amenityIds[:2], amenityIds[:1]} {
+		imageId, err := createHomestayImage(homestayImageRepository, fileSeed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		h := homestayDeps.AddMemberHomestay(context.Background(), muid, homestay.AddMemberHomestayIn{
+			Name:       "Homestay Name",
+			Address:    "Homestay Address",
+			Latitude:   "-6.9",
+			Longitude:  "107.6",
+			ImageIds:   []int64{imageId},
+			AmenityIds: ids,
+		})
+		if h.Error != nil {
+			t.Fatal(h.Error)
+		}
+
+		approval := homestayDeps.ReviewHomestayListing(context.Background(), strconv.FormatInt(h.Res.Id, 10), homestay.ReviewHomestayListingIn{
+			Status: homestay.HomestayApproved.String,
+		})
+		if approval.Error != nil {
+			t.Fatal(approval.Error)
+		}
+	}
+
+	t.Run("Add Member Homestay Fail, Amenity not Found", func(t *testing.T) {
+		imageId, err := createHomestayImage(homestayImageRepository, fileSeed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res := homestayDeps.AddMemberHomestay(context.Background(), muid, homestay.AddMemberHomestayIn{
+			Name:       "Homestay Name",
+			Address:    "Homestay Address",
+			Latitude:   "-6.9",
+			Longitude:  "107.6",
+			ImageIds:   []int64{imageId},
+			AmenityIds: []int64{amenityIds[2] + 1},
+		})
+		if res.StatusCode != http.StatusNotFound {
+			t.Fatalf("Expected response code %d. Got %d\n", http.StatusNotFound, res.StatusCode)
+		}
+	})
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		ExpectedTotal      int
+		Amenities          string
+	}{
+		{
+			Name:               "Query Homestays Success, No Amenity Filter",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      2,
+			Amenities:          "",
+		},
+		{
+			Name:               "Query Homestays Success, Single Amenity",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      2,
+			Amenities:          wifi,
+		},
+		{
+			Name:               "Query Homestays Success, All Amenities Required",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      1,
+			Amenities:          wifi + "," + bathroom + "," + wifi,
+		},
+		{
+			Name:               "Query Homestays Success, No Homestay with Amenity",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      0,
+			Amenities:          wifi + "," + beach,
+		},
+		{
+			Name:               "Query Homestays Fail, Invalid Amenities",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			ExpectedTotal:      0,
+			Amenities:          "wifi",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			directory := homestayDeps.QueryHomestayDirectory(context.Background(), homestay.QueryHomestayDirectoryQIn{
+				Amenities: c.Amenities,
+			})
+			if directory.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", directory)
+				t.Fatalf("Expected directory response code %d. Got %d\n", c.ExpectedStatusCode, directory.StatusCode)
+			}
+			if len(directory.Res.Homestays) != c.ExpectedTotal {
+				t.Fatalf("Expected directory homestays length %d. Got %d\n", c.ExpectedTotal, len(directory.Res.Homestays))
+			}
+
+			geojson := homestayDeps.QueryHomestayGeoJSON(context.Background(), "", c.Amenities)
+			if geojson.StatusCode != c.ExpectedStatusCode {
+				t.Fatalf("Expected geojson response code %d. Got %d\n", c.ExpectedStatusCode, geojson.StatusCode)
+			}
+			if len(geojson.Res.Features) != c.ExpectedTotal {
+				t.Fatalf("Expected features length %d. Got %d\n", c.ExpectedTotal, len(geojson.Res.Features))
+			}
+
+			list := homestayDeps.QueryMemberHomestays(context.Background(), muid, "", c.Amenities, "", "")
+			if list.StatusCode != c.ExpectedStatusCode {
+				t.Fatalf("Expected list response code %d. Got %d\n", c.ExpectedStatusCode, list.StatusCode)
+			}
+			if int(list.Res.Total) != c.ExpectedTotal || len(list.Res.MemberHomestays) != c.ExpectedTotal {
+				t.Fatalf("Expected member homestays total %d. Got %d\n", c.ExpectedTotal, list.Res.Total)
+			}
+		})
+	}
+
+	t.Run("Remove Homestay Amenity Success, Unlinked from Homestays", func(t *testing.T) {
+		res := homestayDeps.RemoveHomestayAmenity(context.Background(), bathroom)
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		directory := homestayDeps.QueryHomestayDirectory(context.Background(), homestay.QueryHomestayDirectoryQIn{
+			Amenities: wifi + "," + bathroom,
+		})
+		if directory.Error != nil {
+			t.Fatal(directory.Error)
+		}
+
+		if len(directory.Res.Homestays) != 0 {
+			t.Fatalf("Expected directory homestays length 0. Got %d\n", len(directory.Res.Homestays))
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package homestay
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrAmenityNameRequired = errors.New("nama fasilitas tidak boleh kosong")
+	ErrMaxAmenityName      = errors.New("nama fasilitas tidak dapat lebih dari 100 karakter")
+)
+
+func ValidateAddHomestayAmenityIn(i AddHomestayAmenityIn) error {
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		if strings.Trim(i.Name, " ") == "" {
+			return ErrAmenityNameRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Name) > 100 {
+			return ErrMaxAmenityName
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ValidateEditHomestayAmenityIn(i EditHomestayAmenityIn) error {
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		if strings.Trim(i.Name, " ") == "" {
+			return ErrAmenityNameRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Name) > 100 {
+			return ErrMaxAmenityName
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return nil
+}
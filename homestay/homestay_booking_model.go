@@ -0,0 +1,79 @@
+package homestay
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Ref: Saving enumerated values to a database
+// https://stackoverflow.com/a/25374979/12976234
+type BookingStatus struct {
+	String string
+}
+
+var (
+	BookingUnknown  = BookingStatus{""}
+	BookingPending  = BookingStatus{"pending"}
+	BookingAccepted = BookingStatus{"accepted"}
+	BookingDeclined = BookingStatus{"declined"}
+)
+
+func bookingStatusFromString(s string) (BookingStatus, error) {
+	switch s {
+	case BookingPending.String:
+		return BookingPending, nil
+	case BookingAccepted.String:
+		return BookingAccepted, nil
+	case BookingDeclined.String:
+		return BookingDeclined, nil
+	}
+
+	return BookingUnknown, errors.New("unknown type: " + s)
+}
+
+func (u *BookingStatus) Scan(src interface{}) error {
+	if src == nil {
+		u.String = ""
+		return nil
+	}
+
+	s, ok := src.(string)
+	if !ok {
+		u.String = ""
+		return nil
+	}
+
+	bs, _ := bookingStatusFromString(s)
+	u.String = bs.String
+	return nil
+}
+
+func (u BookingStatus) Value() (driver.Value, error) {
+	bs, err := bookingStatusFromString(u.String)
+	if err != nil {
+		bs = BookingPending
+	}
+
+	return bs.String, nil
+}
+
+type HomestayBookingModel struct {
+	Id               uint64
+	MemberHomestayId uint64
+	HomestayRoomId   uint64
+	GuestName        string
+	GuestPhone       string
+	GuestEmail       string
+	GuestCount       int64
+	CheckIn          time.Time
+	CheckOut         time.Time
+	Note             string
+	IdrNightlyPrice  int64
+	Status           BookingStatus
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DecidedAt        sql.NullTime
+}
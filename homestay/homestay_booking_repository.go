@@ -0,0 +1,319 @@
+package homestay
+
+import (
+	"context"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type HomestayBookingRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewHomestayBookingRepository(postgreDb *pgxpool.Pool) *HomestayBookingRepository {
+	return &HomestayBookingRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+type (
+	HomestayBookingExecutor   func(ctx context.Context, sql string, arguments ...interface{}) (commandTag pgconn.CommandTag, err error)
+	HomestayBookingQuerierRow func(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	HomestayBookingQuerier    func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+)
+
+func (r *HomestayBookingRepository) Save(ctx context.Context, m HomestayBookingModel) (nm HomestayBookingModel, err error) {
+	sqlQuery := `
+		INSERT INTO homestay_bookings (
+			member_homestay_id,
+			homestay_room_id,
+			guest_name,
+			guest_phone,
+			guest_email,
+			guest_count,
+			check_in,
+			check_out,
+			note,
+			idr_nightly_price,
+			status,
+			created_at,
+			updated_at,
+			decided_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id
+	`
+
+	var queryRow HomestayBookingQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	var lastInsertId uint64
+	t := time.Now()
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		m.MemberHomestayId,
+		m.HomestayRoomId,
+		m.GuestName,
+		m.GuestPhone,
+		m.GuestEmail,
+		m.GuestCount,
+		m.CheckIn,
+		m.CheckOut,
+		m.Note,
+		m.IdrNightlyPrice,
+		m.Status,
+		t,
+		t,
+		nil,
+	).Scan(&lastInsertId)
+
+	if err != nil {
+		return HomestayBookingModel{}, err
+	}
+
+	m.Id = lastInsertId
+	m.CreatedAt = t
+	m.UpdatedAt = t
+
+	return m, nil
+}
+
+func (r *HomestayBookingRepository) FindById(ctx context.Context, homestayId, id uint64) (m HomestayBookingModel, err error) {
+	querystr := `
+		SELECT
+			id,
+			member_homestay_id,
+			homestay_room_id,
+			guest_name,
+			guest_phone,
+			guest_email,
+			guest_count,
+			check_in,
+			check_out,
+			note,
+			idr_nightly_price,
+			status,
+			created_at,
+			updated_at,
+			decided_at
+		FROM homestay_bookings
+		WHERE member_homestay_id = $1 AND id = $2
+	`
+
+	var query HomestayBookingQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+	rows, err = query(
+		context.Background(),
+		querystr,
+		homestayId,
+		id,
+	)
+
+	if err != nil {
+		return HomestayBookingModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return HomestayBookingModel{}, err
+	}
+
+	return m, nil
+}
+
+// Empty status query bookings of every status
+func (r *HomestayBookingRepository) Query(ctx context.Context, homestayId uint64, status string, id, limit int64) ([]HomestayBookingModel, error) {
+	fromId := "id > $1"
+	if id != 0 {
+		fromId = "id < $1"
+	}
+
+	sqlQuery := `
+		SELECT
+			id,
+			member_homestay_id,
+			homestay_room_id,
+			guest_name,
+			guest_phone,
+			guest_email,
+			guest_count,
+			check_in,
+			check_out,
+			note,
+			idr_nightly_price,
+			status,
+			created_at,
+			updated_at,
+			decided_at
+		FROM homestay_bookings
+		WHERE ` + fromId + `
+			AND member_homestay_id = $2
+			AND ($3 = '' OR status::text = $3)
+		ORDER BY id DESC
+		LIMIT $4
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		id,
+		homestayId,
+		status,
+		limit,
+	)
+	defer rows.Close()
+
+	var mps []*HomestayBookingModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []HomestayBookingModel{}, err
+	}
+
+	ms := make([]HomestayBookingModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *HomestayBookingRepository) Count(ctx context.Context, homestayId uint64, status string) (n int64, err error) {
+	sqlQuery := `
+		SELECT COUNT(id) AS n
+		FROM homestay_bookings
+		WHERE member_homestay_id = $1
+		AND ($2 = '' OR status::text = $2)
+	`
+
+	var queryRow HomestayBookingQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		homestayId,
+		status,
+	).Scan(&n)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Accepted bookings with at least one night between start and end, both
+// inclusive. The check out date itself is not a booked night.
+func (r *HomestayBookingRepository) QueryAccepted(ctx context.Context, homestayId uint64, start, end time.Time) ([]HomestayBookingModel, error) {
+	sqlQuery := `
+		SELECT
+			id,
+			member_homestay_id,
+			homestay_room_id,
+			guest_name,
+			guest_phone,
+			guest_email,
+			guest_count,
+			check_in,
+			check_out,
+			note,
+			idr_nightly_price,
+			status,
+			created_at,
+			updated_at,
+			decided_at
+		FROM homestay_bookings
+		WHERE member_homestay_id = $1
+			AND status = 'accepted'
+			AND daterange(check_in, check_out) && daterange($2::date, $3::date, '[]')
+		ORDER BY check_in ASC, id ASC
+	`
+
+	var query HomestayBookingQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	rows, _ := query(
+		context.Background(),
+		sqlQuery,
+		homestayId,
+		start,
+		end,
+	)
+	defer rows.Close()
+
+	var mps []*HomestayBookingModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []HomestayBookingModel{}, err
+	}
+
+	ms := make([]HomestayBookingModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *HomestayBookingRepository) UpdateStatusById(ctx context.Context, homestayId, id uint64, status BookingStatus) error {
+	sqlQuery := `
+		UPDATE homestay_bookings SET (
+			status,
+			updated_at,
+			decided_at
+		) = ($1, $2, $3)
+		WHERE member_homestay_id = $4 AND id = $5
+	`
+
+	var exec HomestayBookingExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	t := time.Now()
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		status,
+		t,
+		t,
+		homestayId,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package homestay
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/go-chi/chi/v5"
+)
+
+func (d *HomestayDeps) PostHomestayBooking(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	decoder := json.NewDecoder(r.Body)
+
+	var in AddHomestayBookingIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.AddHomestayBooking(r.Context(), id, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) GetHomestayBookings(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	status := r.URL.Query().Get("status")
+	cursor := r.URL.Query().Get("cursor")
+	limit := r.URL.Query().Get("limit")
+	out := d.QueryHomestayBookings(r.Context(), jwtPayload.Uid, id, status, cursor, limit)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) PatchHomestayBooking(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	bid := chi.URLParam(r, "bid")
+	decoder := json.NewDecoder(r.Body)
+
+	var in DecideHomestayBookingIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.DecideHomestayBooking(r.Context(), jwtPayload.Uid, id, bid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
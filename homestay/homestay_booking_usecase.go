@@ -0,0 +1,370 @@
+package homestay
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrHomestayBookingNotFound = errors.New("pemesanan homestay tidak ditemukan")
+	ErrCheckOutBeforeCheckIn   = errors.New("tanggal check out harus setelah tanggal check in")
+	ErrCheckInInThePast        = errors.New("tanggal check in tidak boleh di masa lalu")
+	ErrMaxBookingNights        = errors.New("lama menginap tidak dapat lebih dari 30 malam")
+	ErrGuestCountOverCapacity  = errors.New("jumlah tamu melebihi kapasitas kamar")
+	ErrRoomUnavailable         = errors.New("kamar tidak tersedia pada tanggal yang dipilih")
+	ErrBookingAlreadyDecided   = errors.New("pemesanan sudah diterima atau ditolak")
+	ErrInvalidBookingStatus    = errors.New("status pemesanan harus berupa pending, accepted, atau declined")
+)
+
+// Maximum number of nights of one booking request
+const maxBookingNights = 30
+
+// Postgres error code of an exclusion constraint violation
+const exclusionViolation = "23P01"
+
+// Check in and check out follow the lodging convention, the check out
+// date is the day the guest leave and is not a booked night
+func ParseStayDates(checkIn, checkOut string) (in, out time.Time, err error) {
+	if in, err = time.Parse("2006-01-02", checkIn); err != nil {
+		return time.Time{}, time.Time{}, ErrDateFormat
+	}
+	if out, err = time.Parse("2006-01-02", checkOut); err != nil {
+		return time.Time{}, time.Time{}, ErrDateFormat
+	}
+
+	if !out.After(in) {
+		return time.Time{}, time.Time{}, ErrCheckOutBeforeCheckIn
+	}
+	if out.Sub(in) > maxBookingNights*24*time.Hour {
+		return time.Time{}, time.Time{}, ErrMaxBookingNights
+	}
+
+	return in, out, nil
+}
+
+// Report whether the room has a blocked date or an accepted booking on
+// any night from check in until the night before check out
+func (d *HomestayDeps) isRoomUnavailable(ctx context.Context, homestayId, roomId uint64, checkIn, checkOut time.Time) (bool, error) {
+	lastNight := checkOut.AddDate(0, 0, -1)
+
+	blocks, err := d.HomestayRoomRepository.QueryBlocks(ctx, homestayId, checkIn, lastNight)
+	if err != nil {
+		return false, errors.Wrap(err, "query homestay room blocks")
+	}
+	for _, b := range blocks {
+		if b.HomestayRoomId == roomId {
+			return true, nil
+		}
+	}
+
+	bookings, err := d.HomestayBookingRepository.QueryAccepted(ctx, homestayId, checkIn, lastNight)
+	if err != nil {
+		return false, errors.Wrap(err, "query accepted homestay bookings")
+	}
+	for _, b := range bookings {
+		if b.HomestayRoomId == roomId {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+type (
+	AddHomestayBookingIn struct {
+		RoomId     int64  `json:"room_id"`
+		GuestName  string `json:"guest_name"`
+		GuestPhone string `json:"guest_phone"`
+		GuestEmail string `json:"guest_email"`
+		GuestCount int64  `json:"guest_count"`
+		CheckIn    string `json:"check_in"`
+		CheckOut   string `json:"check_out"`
+		Note       string `json:"note"`
+	}
+	AddHomestayBookingRes struct {
+		Id     int64  `json:"id"`
+		Status string `json:"status"`
+	}
+	AddHomestayBookingOut struct {
+		resp.Response
+		Res AddHomestayBookingRes
+	}
+)
+
+func (d *HomestayDeps) AddHomestayBooking(ctx context.Context, hid string, in AddHomestayBookingIn) (out AddHomestayBookingOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusCreated, "", nil)
+
+	id, err := strconv.ParseUint(hid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+
+	if err = ValidateAddHomestayBookingIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	checkIn, checkOut, err := ParseStayDates(in.CheckIn, in.CheckOut)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	if checkIn.Before(time.Now().UTC().Truncate(24 * time.Hour)) {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrCheckInInThePast)
+		return
+	}
+
+	_, err = d.MemberHomestayRepository.FindApprovedById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member homestay by id"))
+		return
+	}
+
+	room, err := d.HomestayRoomRepository.FindUndeletedById(ctx, id, uint64(in.RoomId))
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayRoomNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find homestay room by id"))
+		return
+	}
+
+	if in.GuestCount > room.Capacity {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrGuestCountOverCapacity)
+		return
+	}
+
+	unavailable, err := d.isRoomUnavailable(ctx, id, room.Id, checkIn, checkOut)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", err)
+		return
+	}
+	if unavailable {
+		out.Response = resp.NewResponse(http.StatusConflict, "", ErrRoomUnavailable)
+		return
+	}
+
+	booking := HomestayBookingModel{
+		MemberHomestayId: id,
+		HomestayRoomId:   room.Id,
+		GuestName:        in.GuestName,
+		GuestPhone:       in.GuestPhone,
+		GuestEmail:       in.GuestEmail,
+		GuestCount:       in.GuestCount,
+		CheckIn:          checkIn,
+		CheckOut:         checkOut,
+		Note:             in.Note,
+		IdrNightlyPrice:  room.IdrNightlyPrice,
+		Status:           BookingPending,
+	}
+	if booking, err = d.HomestayBookingRepository.Save(ctx, booking); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save homestay booking"))
+		return
+	}
+
+	out.Res = AddHomestayBookingRes{
+		Id:     int64(booking.Id),
+		Status: booking.Status.String,
+	}
+
+	return
+}
+
+type (
+	HomestayBookingOut struct {
+		Id              int64     `json:"id"`
+		RoomId          int64     `json:"room_id"`
+		GuestName       string    `json:"guest_name"`
+		GuestPhone      string    `json:"guest_phone"`
+		GuestEmail      string    `json:"guest_email"`
+		GuestCount      int64     `json:"guest_count"`
+		CheckIn         string    `json:"check_in"`
+		CheckOut        string    `json:"check_out"`
+		Note            string    `json:"note"`
+		IdrNightlyPrice string    `json:"idr_nightly_price"`
+		Status          string    `json:"status"`
+		CreatedAt       time.Time `json:"created_at"`
+		DecidedAt       null.Time `json:"decided_at"`
+	}
+	QueryHomestayBookingsRes struct {
+		Cursor   int64                `json:"cursor"`
+		Total    int64                `json:"total"`
+		Bookings []HomestayBookingOut `json:"bookings"`
+	}
+	QueryHomestayBookingsOut struct {
+		resp.Response
+		Res QueryHomestayBookingsRes
+	}
+)
+
+func (d *HomestayDeps) QueryHomestayBookings(ctx context.Context, uid, hid, status, cursor, limit string) (out QueryHomestayBookingsOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if status != "" {
+		if _, err = bookingStatusFromString(status); err != nil {
+			out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidBookingStatus)
+			return
+		}
+	}
+
+	memberHomestay, res := d.findOwnedHomestay(ctx, uid, hid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	fromCursor, _ := strconv.ParseInt(cursor, 10, 64)
+	nlimit, _ := strconv.ParseInt(limit, 10, 64)
+	if nlimit == 0 {
+		nlimit = 25
+	}
+
+	total, err := d.HomestayBookingRepository.Count(ctx, memberHomestay.Id, status)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "count homestay bookings"))
+		return
+	}
+
+	bookings, err := d.HomestayBookingRepository.Query(ctx, memberHomestay.Id, status, fromCursor, nlimit)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query homestay bookings"))
+		return
+	}
+
+	bookingLen := len(bookings)
+
+	var nextCursor int64
+	if bookingLen != 0 {
+		nextCursor = int64(bookings[bookingLen-1].Id)
+	}
+
+	outBookings := make([]HomestayBookingOut, bookingLen)
+	for i, b := range bookings {
+		outBookings[i] = HomestayBookingOut{
+			Id:              int64(b.Id),
+			RoomId:          int64(b.HomestayRoomId),
+			GuestName:       b.GuestName,
+			GuestPhone:      b.GuestPhone,
+			GuestEmail:      b.GuestEmail,
+			GuestCount:      b.GuestCount,
+			CheckIn:         b.CheckIn.Format("2006-01-02"),
+			CheckOut:        b.CheckOut.Format("2006-01-02"),
+			Note:            b.Note,
+			IdrNightlyPrice: strconv.FormatInt(b.IdrNightlyPrice, 10),
+			Status:          b.Status.String,
+			CreatedAt:       b.CreatedAt,
+			DecidedAt:       null.NewTime(b.DecidedAt.Time, b.DecidedAt.Valid),
+		}
+	}
+
+	out.Res = QueryHomestayBookingsRes{
+		Cursor:   nextCursor,
+		Total:    total,
+		Bookings: outBookings,
+	}
+
+	return
+}
+
+type (
+	DecideHomestayBookingIn struct {
+		IsAccepted null.Bool `json:"is_accepted"`
+	}
+	DecideHomestayBookingRes struct {
+		Id     int64  `json:"id"`
+		Status string `json:"status"`
+	}
+	DecideHomestayBookingOut struct {
+		resp.Response
+		Res DecideHomestayBookingRes
+	}
+)
+
+func (d *HomestayDeps) DecideHomestayBooking(ctx context.Context, uid, hid, bid string, in DecideHomestayBookingIn) (out DecideHomestayBookingOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(bid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayBookingNotFound)
+		return
+	}
+
+	if err = ValidateDecideHomestayBookingIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	memberHomestay, res := d.findOwnedHomestay(ctx, uid, hid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	booking, err := d.HomestayBookingRepository.FindById(ctx, memberHomestay.Id, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayBookingNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find homestay booking by id"))
+		return
+	}
+
+	if booking.Status != BookingPending {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrBookingAlreadyDecided)
+		return
+	}
+
+	status := BookingDeclined
+	if in.IsAccepted.Bool {
+		status = BookingAccepted
+
+		unavailable, err := d.isRoomUnavailable(ctx, memberHomestay.Id, booking.HomestayRoomId, booking.CheckIn, booking.CheckOut)
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", err)
+			return
+		}
+		if unavailable {
+			out.Response = resp.NewResponse(http.StatusConflict, "", ErrRoomUnavailable)
+			return
+		}
+	}
+
+	// The overlap check above is racy, the exclusion constraint on the
+	// table is what guarantee no two accepted bookings share a night
+	err = d.HomestayBookingRepository.UpdateStatusById(ctx, memberHomestay.Id, id, status)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == exclusionViolation {
+		out.Response = resp.NewResponse(http.StatusConflict, "", ErrRoomUnavailable)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update homestay booking status"))
+		return
+	}
+
+	out.Res = DecideHomestayBookingRes{
+		Id:     int64(booking.Id),
+		Status: status.String,
+	}
+
+	return
+}
@@ -0,0 +1,282 @@
+package homestay_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/homestay"
+	"gopkg.in/guregu/null.v4"
+)
+
+func dateFromNow(days int) string {
+	return time.Now().AddDate(0, 0, days).Format("2006-01-02")
+}
+
+func TestAddHomestayBooking(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hid, err := createMemberHomestay(memberHomestayRepository, muid, homestaySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rid, err := createHomestayRoom(homestayRoomRepository, hid, roomSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := homestayDeps.BlockHomestayDates(context.Background(), muid, strconv.FormatInt(hid, 10), homestay.BlockHomestayDatesIn{
+		StartDate: dateFromNow(20),
+		EndDate:   dateFromNow(20),
+	})
+	if res.Error != nil {
+		t.Fatal(res.Error)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Hid                string
+		In                 homestay.AddHomestayBookingIn
+	}{
+		{
+			Name:               "Add Homestay Booking Success",
+			ExpectedStatusCode: http.StatusCreated,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.AddHomestayBookingIn{
+				RoomId:     rid,
+				GuestName:  "Guest",
+				GuestPhone: "+62 821-2222-0000",
+				GuestEmail: "guest@example.com",
+				GuestCount: 2,
+				CheckIn:    dateFromNow(10),
+				CheckOut:   dateFromNow(12),
+			},
+		},
+		{
+			Name:               "Add Homestay Booking Fail, Over Capacity",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.AddHomestayBookingIn{
+				RoomId:     rid,
+				GuestName:  "Guest",
+				GuestPhone: "+62 821-2222-0000",
+				GuestCount: 3,
+				CheckIn:    dateFromNow(10),
+				CheckOut:   dateFromNow(12),
+			},
+		},
+		{
+			Name:               "Add Homestay Booking Fail, Blocked Date",
+			ExpectedStatusCode: http.StatusConflict,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.AddHomestayBookingIn{
+				RoomId:     rid,
+				GuestName:  "Guest",
+				GuestPhone: "+62 821-2222-0000",
+				GuestCount: 1,
+				CheckIn:    dateFromNow(19),
+				CheckOut:   dateFromNow(21),
+			},
+		},
+		{
+			Name:               "Add Homestay Booking Fail, Check Out Before Check In",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.AddHomestayBookingIn{
+				RoomId:     rid,
+				GuestName:  "Guest",
+				GuestPhone: "+62 821-2222-0000",
+				GuestCount: 1,
+				CheckIn:    dateFromNow(12),
+				CheckOut:   dateFromNow(12),
+			},
+		},
+		{
+			Name:               "Add Homestay Booking Fail, Check In in the Past",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.AddHomestayBookingIn{
+				RoomId:     rid,
+				GuestName:  "Guest",
+				GuestPhone: "+62 821-2222-0000",
+				GuestCount: 1,
+				CheckIn:    dateFromNow(-2),
+				CheckOut:   dateFromNow(1),
+			},
+		},
+		{
+			Name:               "Add Homestay Booking Fail, Invalid Email",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.AddHomestayBookingIn{
+				RoomId:     rid,
+				GuestName:  "Guest",
+				GuestPhone: "+62 821-2222-0000",
+				GuestEmail: "guest",
+				GuestCount: 1,
+				CheckIn:    dateFromNow(10),
+				CheckOut:   dateFromNow(12),
+			},
+		},
+		{
+			Name:               "Add Homestay Booking Fail, Room not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.AddHomestayBookingIn{
+				RoomId:     rid + 1,
+				GuestName:  "Guest",
+				GuestPhone: "+62 821-2222-0000",
+				GuestCount: 1,
+				CheckIn:    dateFromNow(10),
+				CheckOut:   dateFromNow(12),
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := homestayDeps.AddHomestayBooking(context.Background(), c.Hid, c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestDecideHomestayBooking(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hid, err := createMemberHomestay(memberHomestayRepository, muid, homestaySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rid, err := createHomestayRoom(homestayRoomRepository, hid, roomSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stays of [10, 13), [12, 15) and [13, 16) days from now
+	var bids []string
+	for _, checkIn := range []int{10, 12, 13} {
+		res := homestayDeps.AddHomestayBooking(context.Background(), strconv.FormatInt(hid, 10), homestay.AddHomestayBookingIn{
+			RoomId:     rid,
+			GuestName:  "Guest",
+			GuestPhone: "+62 821-2222-0000",
+			GuestCount: 1,
+			CheckIn:    dateFromNow(checkIn),
+			CheckOut:   dateFromNow(checkIn + 3),
+		})
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		bids = append(bids, strconv.FormatInt(res.Res.Id, 10))
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Bid                string
+		In                 homestay.DecideHomestayBookingIn
+	}{
+		{
+			Name:               "Accept Homestay Booking Success",
+			ExpectedStatusCode: http.StatusOK,
+			Bid:                bids[0],
+			In: homestay.DecideHomestayBookingIn{
+				IsAccepted: null.BoolFrom(true),
+			},
+		},
+		{
+			Name:               "Accept Homestay Booking Fail, Overlap Accepted Booking",
+			ExpectedStatusCode: http.StatusConflict,
+			Bid:                bids[1],
+			In: homestay.DecideHomestayBookingIn{
+				IsAccepted: null.BoolFrom(true),
+			},
+		},
+		{
+			Name:               "Accept Homestay Booking Success, Check In on Previous Check Out",
+			ExpectedStatusCode: http.StatusOK,
+			Bid:                bids[2],
+			In: homestay.DecideHomestayBookingIn{
+				IsAccepted: null.BoolFrom(true),
+			},
+		},
+		{
+			Name:               "Decline Homestay Booking Success",
+			ExpectedStatusCode: http.StatusOK,
+			Bid:                bids[1],
+			In: homestay.DecideHomestayBookingIn{
+				IsAccepted: null.BoolFrom(false),
+			},
+		},
+		{
+			Name:               "Decide Homestay Booking Fail, Already Decided",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Bid:                bids[1],
+			In: homestay.DecideHomestayBookingIn{
+				IsAccepted: null.BoolFrom(true),
+			},
+		},
+		{
+			Name:               "Decide Homestay Booking Fail, Decision Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Bid:                bids[1],
+			In:                 homestay.DecideHomestayBookingIn{},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := homestayDeps.DecideHomestayBooking(context.Background(), muid, strconv.FormatInt(hid, 10), c.Bid, c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+
+	t.Run("Insert Overlapping Accepted Booking Fail, Exclusion Constraint", func(t *testing.T) {
+		checkIn, _ := time.Parse("2006-01-02", dateFromNow(11))
+		checkOut, _ := time.Parse("2006-01-02", dateFromNow(12))
+
+		_, err := homestayBookingRepository.Save(context.Background(), homestay.HomestayBookingModel{
+			MemberHomestayId: uint64(hid),
+			HomestayRoomId:   uint64(rid),
+			GuestName:        "Guest",
+			GuestPhone:       "+62 821-2222-0000",
+			GuestCount:       1,
+			CheckIn:          checkIn,
+			CheckOut:         checkOut,
+			Status:           homestay.BookingAccepted,
+		})
+		if err == nil {
+			t.Fatal("Expected exclusion constraint error. Got nil")
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package homestay
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrGuestNameRequired    = errors.New("nama tamu tidak boleh kosong")
+	ErrMaxGuestName         = errors.New("nama tamu tidak dapat lebih dari 100 karakter")
+	ErrGuestPhoneRequired   = errors.New("nomor telepon tamu tidak boleh kosong")
+	ErrMaxGuestPhone        = errors.New("nomor telepon tamu tidak dapat lebih dari 50 karakter")
+	ErrMaxGuestEmail        = errors.New("email tamu tidak dapat lebih dari 100 karakter")
+	ErrInvalidGuestEmail    = errors.New("email tamu tidak valid")
+	ErrInvalidGuestCount    = errors.New("jumlah tamu harus lebih dari 0")
+	ErrBookingRoomRequired  = errors.New("kamar yang dipesan tidak boleh kosong")
+	ErrCheckInRequired      = errors.New("tanggal check in tidak boleh kosong")
+	ErrCheckOutRequired     = errors.New("tanggal check out tidak boleh kosong")
+	ErrMaxBookingNote       = errors.New("catatan pemesanan tidak dapat lebih dari 500 karakter")
+	ErrBookingDecisionEmpty = errors.New("keputusan pemesanan tidak boleh kosong")
+)
+
+func ValidateAddHomestayBookingIn(i AddHomestayBookingIn) error {
+	g := new(errgroup.Group)
+
+	g.Go(func() error {
+		if strings.Trim(i.GuestName, " ") == "" {
+			return ErrGuestNameRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.GuestName) > 100 {
+			return ErrMaxGuestName
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.GuestPhone, " ") == "" {
+			return ErrGuestPhoneRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.GuestPhone) > 50 {
+			return ErrMaxGuestPhone
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.GuestEmail) > 100 {
+			return ErrMaxGuestEmail
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.GuestEmail, " ") == "" {
+			return nil
+		}
+		if _, err := mail.ParseAddress(i.GuestEmail); err != nil {
+			return ErrInvalidGuestEmail
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if i.GuestCount < 1 {
+			return ErrInvalidGuestCount
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if i.RoomId < 1 {
+			return ErrBookingRoomRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.CheckIn, " ") == "" {
+			return ErrCheckInRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.CheckOut, " ") == "" {
+			return ErrCheckOutRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Note) > 500 {
+			return ErrMaxBookingNote
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ValidateDecideHomestayBookingIn(i DecideHomestayBookingIn) error {
+	if !i.IsAccepted.Valid {
+		return ErrBookingDecisionEmpty
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package homestay
+
+import (
+	"net/http"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+)
+
+func (d *HomestayDeps) GetHomestayDirectory(w http.ResponseWriter, r *http.Request) {
+	out := d.QueryHomestayDirectory(r.Context(), QueryHomestayDirectoryQIn{
+		Q:         r.URL.Query().Get("q"),
+		Lat:       r.URL.Query().Get("lat"),
+		Lng:       r.URL.Query().Get("lng"),
+		Radius:    r.URL.Query().Get("radius"),
+		Bbox:      r.URL.Query().Get("bbox"),
+		Amenities: r.URL.Query().Get("amenities"),
+		Sort:      r.URL.Query().Get("sort"),
+		Cursor:    r.URL.Query().Get("cursor"),
+		Limit:     r.URL.Query().Get("limit"),
+	})
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
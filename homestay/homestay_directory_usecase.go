@@ -0,0 +1,184 @@
+package homestay
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/geo"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrInvalidCenter  = errors.New("titik pusat pencarian harus berupa lintang dan bujur yang valid")
+	ErrInvalidRadius  = errors.New("radius pencarian harus berupa angka tidak negatif")
+	ErrRadiusNoCenter = errors.New("radius pencarian membutuhkan titik pusat lintang dan bujur")
+	ErrInvalidBbox    = errors.New("bbox harus berupa <bujur barat>,<lintang selatan>,<bujur timur>,<lintang utara>")
+)
+
+// Parse bbox with the same order as GeoJSON and most map libraries,
+// west, south, east and north
+func ParseBbox(bbox string) (minLat, minLng, maxLat, maxLng float64, err error) {
+	s := strings.Split(bbox, ",")
+	if len(s) != 4 {
+		return 0, 0, 0, 0, ErrInvalidBbox
+	}
+
+	if minLng, err = geo.ParseLongitude(s[0]); err != nil {
+		return 0, 0, 0, 0, ErrInvalidBbox
+	}
+	if minLat, err = geo.ParseLatitude(s[1]); err != nil {
+		return 0, 0, 0, 0, ErrInvalidBbox
+	}
+	if maxLng, err = geo.ParseLongitude(s[2]); err != nil {
+		return 0, 0, 0, 0, ErrInvalidBbox
+	}
+	if maxLat, err = geo.ParseLatitude(s[3]); err != nil {
+		return 0, 0, 0, 0, ErrInvalidBbox
+	}
+
+	if minLat > maxLat {
+		return 0, 0, 0, 0, ErrInvalidBbox
+	}
+
+	return minLat, minLng, maxLat, maxLng, nil
+}
+
+type (
+	QueryHomestayDirectoryQIn struct {
+		Q         string
+		Lat       string
+		Lng       string
+		Radius    string
+		Bbox      string
+		Amenities string
+		Sort      string
+		Cursor    string
+		Limit     string
+	}
+	HomestayDirectoryOut struct {
+		Id           int64      `json:"id"`
+		Name         string     `json:"name"`
+		Address      string     `json:"address"`
+		Latitude     float64    `json:"latitude"`
+		Longitude    float64    `json:"longitude"`
+		ThumbnailUrl string     `json:"thumbnail_url"`
+		MemberId     string     `json:"member_id"`
+		OwnerName    string     `json:"owner_name"`
+		DistanceKm   null.Float `json:"distance_km"`
+	}
+	QueryHomestayDirectoryRes struct {
+		Total     int64                  `json:"total"`
+		Cursor    int64                  `json:"cursor"`
+		Homestays []HomestayDirectoryOut `json:"homestays"`
+	}
+	QueryHomestayDirectoryOut struct {
+		resp.Response
+		Res QueryHomestayDirectoryRes
+	}
+)
+
+func (d *HomestayDeps) QueryHomestayDirectory(ctx context.Context, qin QueryHomestayDirectoryQIn) (out QueryHomestayDirectoryOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	f := DirectoryFilter{
+		Q:      strings.Trim(qin.Q, " "),
+		SortBy: qin.Sort,
+	}
+
+	if qin.Lat != "" || qin.Lng != "" {
+		lat, latErr := geo.ParseLatitude(qin.Lat)
+		lng, lngErr := geo.ParseLongitude(qin.Lng)
+		if latErr != nil || lngErr != nil {
+			out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidCenter)
+			return
+		}
+
+		f.HasCenter = true
+		f.Lat = lat
+		f.Lng = lng
+	}
+
+	if qin.Radius != "" {
+		if !f.HasCenter {
+			out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrRadiusNoCenter)
+			return
+		}
+
+		radius, err := strconv.ParseFloat(qin.Radius, 64)
+		if err != nil || radius < 0 {
+			out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidRadius)
+			return
+		}
+
+		f.RadiusKm = radius
+	}
+
+	if qin.Bbox != "" {
+		f.MinLat, f.MinLng, f.MaxLat, f.MaxLng, err = ParseBbox(qin.Bbox)
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+
+		f.HasBox = true
+	}
+
+	if f.AmenityIds, err = ParseAmenityIds(qin.Amenities); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	f.Offset, _ = strconv.ParseInt(qin.Cursor, 10, 64)
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+
+	f.Limit, _ = strconv.ParseInt(qin.Limit, 10, 64)
+	if f.Limit <= 0 || f.Limit > 100 {
+		f.Limit = 25
+	}
+
+	homestays, err := d.MemberHomestayRepository.QueryDirectory(ctx, f)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query homestay directory"))
+		return
+	}
+
+	hLen := len(homestays)
+
+	var total, nextCursor int64
+	if hLen != 0 {
+		total = homestays[0].Total
+	}
+	if f.Offset+int64(hLen) < total {
+		nextCursor = f.Offset + int64(hLen)
+	}
+
+	outHomestays := make([]HomestayDirectoryOut, hLen)
+	for i, h := range homestays {
+		outHomestays[i] = HomestayDirectoryOut{
+			Id:           int64(h.Id),
+			Name:         h.Name,
+			Address:      h.Address,
+			Latitude:     h.Latitude,
+			Longitude:    h.Longitude,
+			ThumbnailUrl: h.ThumbnailUrl,
+			MemberId:     h.MemberId,
+			OwnerName:    h.OwnerName,
+			DistanceKm:   null.NewFloat(h.Distance.Float64, h.Distance.Valid),
+		}
+	}
+
+	out.Res = QueryHomestayDirectoryRes{
+		Total:     total,
+		Cursor:    nextCursor,
+		Homestays: outHomestays,
+	}
+
+	return
+}
@@ -0,0 +1,140 @@
+package homestay_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/homestay"
+)
+
+func TestQueryHomestayDirectory(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unapprovedMember := memberSeed
+	unapprovedMember.Username = "unapproved"
+	unapprovedMember.WaPhone = "+62 821-1111-0001"
+	unapprovedMember.OtherPhone = "+62 821-1111-0001"
+	unapprovedMember.IsApproved = false
+	unapprovedUid, err := createUser(memberRepository, unapprovedMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	near := homestaySeed
+	near.Name = "Homestay Dekat"
+	near.Latitude = -6.9175
+	near.Longitude = 107.6191
+	if _, err = createMemberHomestay(memberHomestayRepository, muid, near); err != nil {
+		t.Fatal(err)
+	}
+
+	far := homestaySeed
+	far.Name = "Homestay Jauh"
+	far.Latitude = -8.4095
+	far.Longitude = 115.1889
+	if _, err = createMemberHomestay(memberHomestayRepository, muid, far); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = createMemberHomestay(memberHomestayRepository, unapprovedUid, near); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := near
+	invalid.Name = "Homestay Koordinat Salah"
+	invalidId, err := createMemberHomestay(memberHomestayRepository, muid, invalid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = db.Exec(context.Background(), `UPDATE member_homestays SET is_coordinate_invalid = true WHERE id = $1`, invalidId); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		ExpectedTotal      int
+		QIn                homestay.QueryHomestayDirectoryQIn
+	}{
+		{
+			Name:               "Query Homestay Directory Success, Only Approved Members",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      2,
+			QIn:                homestay.QueryHomestayDirectoryQIn{},
+		},
+		{
+			Name:               "Query Homestay Directory by Radius Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      1,
+			QIn: homestay.QueryHomestayDirectoryQIn{
+				Lat:    "-6.9",
+				Lng:    "107.6",
+				Radius: "10",
+				Sort:   "distance",
+			},
+		},
+		{
+			Name:               "Query Homestay Directory by Bbox Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      1,
+			QIn: homestay.QueryHomestayDirectoryQIn{
+				Bbox: "114,-9,116,-8",
+			},
+		},
+		{
+			Name:               "Query Homestay Directory by Text Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      1,
+			QIn: homestay.QueryHomestayDirectoryQIn{
+				Q: "jauh",
+			},
+		},
+		{
+			Name:               "Query Homestay Directory Fail, Invalid Center",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			QIn: homestay.QueryHomestayDirectoryQIn{
+				Lat: "120",
+				Lng: "107.6",
+			},
+		},
+		{
+			Name:               "Query Homestay Directory Fail, Radius Without Center",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			QIn: homestay.QueryHomestayDirectoryQIn{
+				Radius: "10",
+			},
+		},
+		{
+			Name:               "Query Homestay Directory Fail, Invalid Bbox",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			QIn: homestay.QueryHomestayDirectoryQIn{
+				Bbox: "114,-9,116",
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := homestayDeps.QueryHomestayDirectory(context.Background(), c.QIn)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if len(res.Res.Homestays) != c.ExpectedTotal {
+				t.Fatalf("Expected homestays length %d. Got %d\n", c.ExpectedTotal, len(res.Res.Homestays))
+			}
+		})
+	}
+}
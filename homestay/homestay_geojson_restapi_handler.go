@@ -0,0 +1,44 @@
+package homestay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+)
+
+func (d *HomestayDeps) GetHomestayGeoJSON(w http.ResponseWriter, r *http.Request) {
+	bbox := r.URL.Query().Get("bbox")
+	amenities := r.URL.Query().Get("amenities")
+	out := d.QueryHomestayGeoJSON(r.Context(), bbox, amenities)
+	if out.Error != nil {
+		out.HttpJSON(w, nil)
+		return
+	}
+
+	b, err := json.Marshal(out.Res)
+	if err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	sum := sha256.Sum256(b)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Header().Set("ETag", etag)
+
+	for _, t := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		if t = strings.TrimPrefix(strings.Trim(t, " "), "W/"); t == etag || t == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
@@ -0,0 +1,95 @@
+package homestay
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/pkg/errors"
+)
+
+type (
+	GeoJSONGeometry struct {
+		Type        string     `json:"type"`
+		Coordinates [2]float64 `json:"coordinates"`
+	}
+	GeoJSONProperties struct {
+		Id           int64  `json:"id"`
+		Name         string `json:"name"`
+		Address      string `json:"address"`
+		ThumbnailUrl string `json:"thumbnail_url"`
+		OwnerName    string `json:"owner_name"`
+		DetailUrl    string `json:"detail_url"`
+	}
+	GeoJSONFeature struct {
+		Type       string            `json:"type"`
+		Id         int64             `json:"id"`
+		Geometry   GeoJSONGeometry   `json:"geometry"`
+		Properties GeoJSONProperties `json:"properties"`
+	}
+	GeoJSONFeatureCollection struct {
+		Type     string           `json:"type"`
+		Features []GeoJSONFeature `json:"features"`
+	}
+	QueryHomestayGeoJSONOut struct {
+		resp.Response
+		Res GeoJSONFeatureCollection
+	}
+)
+
+func (d *HomestayDeps) QueryHomestayGeoJSON(ctx context.Context, bbox, amenities string) (out QueryHomestayGeoJSONOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	f := DirectoryFilter{}
+	if bbox != "" {
+		f.MinLat, f.MinLng, f.MaxLat, f.MaxLng, err = ParseBbox(bbox)
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+
+		f.HasBox = true
+	}
+
+	if f.AmenityIds, err = ParseAmenityIds(amenities); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	homestays, err := d.MemberHomestayRepository.QueryDirectory(ctx, f)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query homestay directory"))
+		return
+	}
+
+	features := make([]GeoJSONFeature, len(homestays))
+	for i, h := range homestays {
+		id := int64(h.Id)
+		features[i] = GeoJSONFeature{
+			Type: "Feature",
+			Id:   id,
+			Geometry: GeoJSONGeometry{
+				Type: "Point",
+				// GeoJSON position is longitude first
+				Coordinates: [2]float64{h.Longitude, h.Latitude},
+			},
+			Properties: GeoJSONProperties{
+				Id:           id,
+				Name:         h.Name,
+				Address:      h.Address,
+				ThumbnailUrl: h.ThumbnailUrl,
+				OwnerName:    h.OwnerName,
+				DetailUrl:    "/api/v1/homestays/" + strconv.FormatInt(id, 10) + "/" + h.MemberId,
+			},
+		}
+	}
+
+	out.Res = GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: features,
+	}
+
+	return
+}
@@ -0,0 +1,64 @@
+package homestay_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestQueryHomestayGeoJSON(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = createMemberHomestay(memberHomestayRepository, muid, homestaySeed); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		ExpectedTotal      int
+		Bbox               string
+	}{
+		{
+			Name:               "Query Homestay GeoJSON Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      1,
+			Bbox:               "",
+		},
+		{
+			Name:               "Query Homestay GeoJSON outside Bbox Return Empty",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      0,
+			Bbox:               "114,-9,116,-8",
+		},
+		{
+			Name:               "Query Homestay GeoJSON Fail, Invalid Bbox",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			ExpectedTotal:      0,
+			Bbox:               "a,b,c,d",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := homestayDeps.QueryHomestayGeoJSON(context.Background(), c.Bbox, "")
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if len(res.Res.Features) != c.ExpectedTotal {
+				t.Fatalf("Expected features length %d. Got %d\n", c.ExpectedTotal, len(res.Res.Features))
+			}
+		})
+	}
+}
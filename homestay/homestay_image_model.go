@@ -10,6 +10,8 @@ type HomestayImageModel struct {
 	Name             string
 	AlphnumName      string
 	Url              string
+	Caption          string
+	Position         int64
 	CreatedAt        time.Time
 	MemberHomestayId sql.NullInt64
 	DeletedAt        sql.NullTime
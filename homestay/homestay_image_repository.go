@@ -34,11 +34,13 @@ func (r *HomestayImageRepository) Save(ctx context.Context, m HomestayImageModel
 			name,
 			alphnum_name,
 			url,
+			caption,
+			position,
 			member_homestay_id,
 			created_at,
 			deleted_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id
 	`
 
@@ -59,6 +61,8 @@ func (r *HomestayImageRepository) Save(ctx context.Context, m HomestayImageModel
 		m.Name,
 		m.AlphnumName,
 		m.Url,
+		m.Caption,
+		m.Position,
 		m.MemberHomestayId,
 		t,
 		nil,
@@ -81,6 +85,8 @@ func (r *HomestayImageRepository) FindById(ctx context.Context, id uint64) (m Ho
 			name,
 			alphnum_name,
 			url,
+			caption,
+			position,
 			member_homestay_id,
 			created_at,
 			deleted_at
@@ -122,13 +128,15 @@ func (r *HomestayImageRepository) FindByMemberHomestayId(ctx context.Context, id
 			name,
 			alphnum_name,
 			url,
+			caption,
+			position,
 			member_homestay_id,
 			created_at,
 			deleted_at
 		FROM homestay_images 
 		WHERE deleted_at IS NULL
 			AND member_homestay_id = $1
-		ORDER BY id ASC
+		ORDER BY position ASC, id ASC
 	`
 
 	rows, _ := r.PostgreDb.Query(
@@ -183,6 +191,7 @@ func (r *HomestayImageRepository) DeleteById(ctx context.Context, id uint64) err
 	return nil
 }
 
+// The images are returned in the order of the given ids
 func (r *HomestayImageRepository) QueryInId(ctx context.Context, ids []uint64) (ms []HomestayImageModel, err error) {
 	sqlQuery := `
 		SELECT 
@@ -190,13 +199,15 @@ func (r *HomestayImageRepository) QueryInId(ctx context.Context, ids []uint64) (
 			name,
 			alphnum_name,
 			url,
+			caption,
+			position,
 			member_homestay_id,
 			created_at,
 			deleted_at
 		FROM homestay_images
 		WHERE deleted_at IS NULL
 		AND id = ANY($1)
-		ORDER BY id ASC
+		ORDER BY array_position($1, id) ASC
 	`
 
 	var query HomestayImageQuerier
@@ -229,10 +240,13 @@ func (r *HomestayImageRepository) QueryInId(ctx context.Context, ids []uint64) (
 	return ms, nil
 }
 
+// Link the images to the homestay, the images are positioned in the
+// order of the given ids
 func (r *HomestayImageRepository) UpdateHomestayIdInId(ctx context.Context, id uint64, ids []uint64) error {
 	sqlQuery := `
 	UPDATE homestay_images
-	SET member_homestay_id = $1
+	SET member_homestay_id = $1,
+		position = array_position($2, id)
 	WHERE id = ANY($2)
 `
 
@@ -284,3 +298,61 @@ func (r *HomestayImageRepository) DeleteInId(ctx context.Context, ids []uint64)
 
 	return nil
 }
+
+// Reposition the images of the homestay in the order of the given ids
+func (r *HomestayImageRepository) UpdatePositionInId(ctx context.Context, homestayId uint64, ids []uint64) error {
+	sqlQuery := `
+		UPDATE homestay_images
+		SET position = array_position($2, id)
+		WHERE member_homestay_id = $1
+		AND id = ANY($2)
+	`
+
+	var exec HomestayImageExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		homestayId,
+		ids,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *HomestayImageRepository) UpdateCaptionById(ctx context.Context, id uint64, caption string) error {
+	sqlQuery := `
+		UPDATE homestay_images
+		SET caption = $1
+		WHERE id = $2
+	`
+
+	var exec HomestayImageExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		caption,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
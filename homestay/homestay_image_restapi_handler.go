@@ -1,9 +1,11 @@
 package homestay
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/httpdecode"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
 	"github.com/go-chi/chi/v5"
 )
@@ -24,3 +26,57 @@ func (d *HomestayDeps) DeleteHomestayImage(w http.ResponseWriter, r *http.Reques
 	out := d.RemoveHomestayImage(r.Context(), id)
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
+
+func (d *HomestayDeps) PutHomestayImagesOrder(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	decoder := json.NewDecoder(r.Body)
+
+	var in ReorderHomestayImagesIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.ReorderHomestayImages(r.Context(), jwtPayload.Uid, id, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) PatchHomestayImage(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	iid := chi.URLParam(r, "iid")
+	decoder := json.NewDecoder(r.Body)
+
+	var in EditHomestayImageIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.EditHomestayImage(r.Context(), jwtPayload.Uid, id, iid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) PostHomestayCover(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	iid := chi.URLParam(r, "iid")
+	out := d.SetHomestayCover(r.Context(), jwtPayload.Uid, id, iid)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
@@ -25,7 +25,8 @@ var (
 
 type (
 	AddHomestayImageIn struct {
-		File httpdecode.FileHeader `mapstructure:"file"`
+		File    httpdecode.FileHeader `mapstructure:"file"`
+		Caption string                `mapstructure:"caption"`
 	}
 	AddHomestayImageRes struct {
 		Id  int64  `json:"id"`
@@ -84,6 +85,7 @@ func (d *HomestayDeps) AddHomestayImage(ctx context.Context, in AddHomestayImage
 		Name:        in.File.Filename,
 		AlphnumName: string(re.ReplaceAll([]byte(in.File.Filename), []byte(" "))),
 		Url:         fileUrl,
+		Caption:     strings.Trim(in.Caption, " "),
 	}
 
 	if image, err = d.HomestayImageRepository.Save(ctx, image); err != nil {
@@ -119,7 +121,7 @@ func (d *HomestayDeps) RemoveHomestayImage(ctx context.Context, pid string) (out
 		return
 	}
 
-	_, err = d.HomestayImageRepository.FindById(ctx, id)
+	image, err := d.HomestayImageRepository.FindById(ctx, id)
 	if errors.Is(err, pgx.ErrNoRows) {
 		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayImageNotFound)
 		return
@@ -134,7 +136,214 @@ func (d *HomestayDeps) RemoveHomestayImage(ctx context.Context, pid string) (out
 		return
 	}
 
+	if image.MemberHomestayId.Valid {
+		if err = d.fallbackHomestayCover(ctx, uint64(image.MemberHomestayId.Int64), image); err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "fallback homestay cover"))
+			return
+		}
+	}
+
 	out.Res.Id = int64(id)
 
 	return
 }
+
+// Pick the first remaining image of the gallery as the new cover when the
+// removed image was the cover, the cover is emptied if no image is left
+func (d *HomestayDeps) fallbackHomestayCover(ctx context.Context, homestayId uint64, removed HomestayImageModel) error {
+	memberHomestay, err := d.MemberHomestayRepository.FindUndeletedById(ctx, homestayId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "find member homestay by id")
+	}
+
+	if memberHomestay.ThumbnailUrl != removed.Url {
+		return nil
+	}
+
+	images, err := d.HomestayImageRepository.FindByMemberHomestayId(ctx, homestayId)
+	if err != nil {
+		return errors.Wrap(err, "query images")
+	}
+
+	var thumbnailUrl string
+	for _, v := range images {
+		if v.Id != removed.Id {
+			thumbnailUrl = v.Url
+			break
+		}
+	}
+
+	if err = d.MemberHomestayRepository.UpdateThumbnailById(ctx, homestayId, thumbnailUrl); err != nil {
+		return errors.Wrap(err, "update member homestay thumbnail by id")
+	}
+
+	return nil
+}
+
+// Find the image in the gallery of the homestay owned by the member
+func (d *HomestayDeps) findOwnedHomestayImage(ctx context.Context, uid, hid, iid string) (h MemberHomestayModel, m HomestayImageModel, res resp.Response) {
+	h, res = d.findOwnedHomestay(ctx, uid, hid)
+	if res.Error != nil {
+		return
+	}
+
+	id, err := strconv.ParseUint(iid, 10, 64)
+	if err != nil {
+		res = resp.NewResponse(http.StatusNotFound, "", ErrHomestayImageNotFound)
+		return
+	}
+
+	m, err = d.HomestayImageRepository.FindById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && uint64(m.MemberHomestayId.Int64) != h.Id) {
+		res = resp.NewResponse(http.StatusNotFound, "", ErrHomestayImageNotFound)
+		return
+	}
+	if err != nil {
+		res = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find image by id"))
+		return
+	}
+
+	return
+}
+
+type (
+	ReorderHomestayImagesIn struct {
+		ImageIds []int64 `json:"image_ids"`
+	}
+	ReorderHomestayImagesRes struct {
+		Id int64 `json:"id"`
+	}
+	ReorderHomestayImagesOut struct {
+		resp.Response
+		Res ReorderHomestayImagesRes
+	}
+)
+
+func (d *HomestayDeps) ReorderHomestayImages(ctx context.Context, uid, hid string, in ReorderHomestayImagesIn) (out ReorderHomestayImagesOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateReorderHomestayImagesIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	memberHomestay, res := d.findOwnedHomestay(ctx, uid, hid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	images, err := d.HomestayImageRepository.FindByMemberHomestayId(ctx, memberHomestay.Id)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query images"))
+		return
+	}
+
+	// The new order must list every image of the gallery exactly once
+	isInGallery := make(map[uint64]bool, len(images))
+	for _, v := range images {
+		isInGallery[v.Id] = true
+	}
+
+	ids := make([]uint64, len(in.ImageIds))
+	for i, v := range in.ImageIds {
+		id := uint64(v)
+		if v < 1 || !isInGallery[id] {
+			out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidHomestayImageOrder)
+			return
+		}
+
+		isInGallery[id] = false
+		ids[i] = id
+	}
+
+	if len(ids) != len(images) {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidHomestayImageOrder)
+		return
+	}
+
+	if err = d.HomestayImageRepository.UpdatePositionInId(ctx, memberHomestay.Id, ids); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update image position in id"))
+		return
+	}
+
+	out.Res.Id = int64(memberHomestay.Id)
+
+	return
+}
+
+type (
+	EditHomestayImageIn struct {
+		Caption string `json:"caption"`
+	}
+	EditHomestayImageRes struct {
+		Id int64 `json:"id"`
+	}
+	EditHomestayImageOut struct {
+		resp.Response
+		Res EditHomestayImageRes
+	}
+)
+
+func (d *HomestayDeps) EditHomestayImage(ctx context.Context, uid, hid, iid string, in EditHomestayImageIn) (out EditHomestayImageOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateEditHomestayImageIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	_, image, res := d.findOwnedHomestayImage(ctx, uid, hid, iid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	if err = d.HomestayImageRepository.UpdateCaptionById(ctx, image.Id, strings.Trim(in.Caption, " ")); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update image caption by id"))
+		return
+	}
+
+	out.Res.Id = int64(image.Id)
+
+	return
+}
+
+type (
+	SetHomestayCoverRes struct {
+		Id           int64  `json:"id"`
+		ThumbnailUrl string `json:"thumbnail_url"`
+	}
+	SetHomestayCoverOut struct {
+		resp.Response
+		Res SetHomestayCoverRes
+	}
+)
+
+func (d *HomestayDeps) SetHomestayCover(ctx context.Context, uid, hid, iid string) (out SetHomestayCoverOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	memberHomestay, image, res := d.findOwnedHomestayImage(ctx, uid, hid, iid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	if err = d.MemberHomestayRepository.UpdateThumbnailById(ctx, memberHomestay.Id, image.Url); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update member homestay thumbnail by id"))
+		return
+	}
+
+	out.Res = SetHomestayCoverRes{
+		Id:           int64(image.Id),
+		ThumbnailUrl: image.Url,
+	}
+
+	return
+}
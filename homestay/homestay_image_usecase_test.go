@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 
 	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
@@ -132,3 +133,133 @@ func TestRemoveHomestayImage(t *testing.T) {
 		})
 	}
 }
+
+func TestReorderAndCoverHomestayImages(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var imageIds []int64
+	for i := 0; i < 3; i++ {
+		image := fileSeed
+		image.Url = "http://localhost:5000/file-" + strconv.Itoa(i) + ".jpg"
+
+		id, err := createHomestayImage(homestayImageRepository, image)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		imageIds = append(imageIds, id)
+	}
+
+	h := homestayDeps.AddMemberHomestay(context.Background(), muid, homestay.AddMemberHomestayIn{
+		Name:      "Homestay Name",
+		Address:   "Homestay Address",
+		Latitude:  "-6.9",
+		Longitude: "107.6",
+		ImageIds:  imageIds,
+	})
+	if h.Error != nil {
+		t.Fatal(h.Error)
+	}
+	hid := strconv.FormatInt(h.Res.Id, 10)
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		In                 homestay.ReorderHomestayImagesIn
+	}{
+		{
+			Name:               "Reorder Homestay Images Success",
+			ExpectedStatusCode: http.StatusOK,
+			In: homestay.ReorderHomestayImagesIn{
+				ImageIds: []int64{imageIds[2], imageIds[0], imageIds[1]},
+			},
+		},
+		{
+			Name:               "Reorder Homestay Images Fail, Image Missing",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: homestay.ReorderHomestayImagesIn{
+				ImageIds: []int64{imageIds[2], imageIds[0]},
+			},
+		},
+		{
+			Name:               "Reorder Homestay Images Fail, Image Repeated",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: homestay.ReorderHomestayImagesIn{
+				ImageIds: []int64{imageIds[2], imageIds[2], imageIds[1]},
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := homestayDeps.ReorderHomestayImages(context.Background(), muid, hid, c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+
+	t.Run("Find Member Homestay Success, Images in New Order", func(t *testing.T) {
+		res := homestayDeps.FindMemberHomestay(context.Background(), hid, muid, muid)
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		expected := []int64{imageIds[2], imageIds[0], imageIds[1]}
+		for i, v := range res.Res.HomestayImages {
+			if v.Id != expected[i] {
+				t.Fatalf("Expected image %d at position %d. Got %d\n", expected[i], i, v.Id)
+			}
+		}
+
+		if !res.Res.HomestayImages[1].IsCover {
+			t.Fatalf("Expected the first uploaded image to stay the cover\n")
+		}
+	})
+
+	t.Run("Set Homestay Cover Success", func(t *testing.T) {
+		res := homestayDeps.SetHomestayCover(context.Background(), muid, hid, strconv.FormatInt(imageIds[1], 10))
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		if res.Res.ThumbnailUrl != "http://localhost:5000/file-1.jpg" {
+			t.Fatalf("Expected new cover url. Got %s\n", res.Res.ThumbnailUrl)
+		}
+	})
+
+	t.Run("Edit Homestay Image Fail, Caption over 200 characters", func(t *testing.T) {
+		res := homestayDeps.EditHomestayImage(context.Background(), muid, hid, strconv.FormatInt(imageIds[1], 10), homestay.EditHomestayImageIn{
+			Caption: strings.Repeat("a", 201),
+		})
+		if res.StatusCode != http.StatusUnprocessableEntity {
+			t.Fatalf("Expected response code %d. Got %d\n", http.StatusUnprocessableEntity, res.StatusCode)
+		}
+	})
+
+	t.Run("Remove Homestay Image Success, Cover Fallback", func(t *testing.T) {
+		res := homestayDeps.RemoveHomestayImage(context.Background(), strconv.FormatInt(imageIds[1], 10))
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		detail := homestayDeps.FindMemberHomestay(context.Background(), hid, muid, muid)
+		if detail.Error != nil {
+			t.Fatal(detail.Error)
+		}
+
+		if detail.Res.ThumbnailUrl != "http://localhost:5000/file-2.jpg" {
+			t.Fatalf("Expected the first image in order as cover. Got %s\n", detail.Res.ThumbnailUrl)
+		}
+	})
+}
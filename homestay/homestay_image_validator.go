@@ -8,8 +8,10 @@ import (
 )
 
 var (
-	ErrHomestayImageRequired = errors.New("foto atau gambar tidak boleh kosong")
-	ErrMaxHomestayImageName  = errors.New("nama foto atau gambar tidak dapat lebih dari 200 karakter")
+	ErrHomestayImageRequired     = errors.New("foto atau gambar tidak boleh kosong")
+	ErrMaxHomestayImageName      = errors.New("nama foto atau gambar tidak dapat lebih dari 200 karakter")
+	ErrMaxHomestayImageCaption   = errors.New("keterangan foto atau gambar tidak dapat lebih dari 200 karakter")
+	ErrInvalidHomestayImageOrder = errors.New("urutan foto atau gambar harus memuat setiap foto homestay tepat satu kali")
 )
 
 func ValidateAddHomestayImageIn(i AddHomestayImageIn) error {
@@ -27,6 +29,44 @@ func ValidateAddHomestayImageIn(i AddHomestayImageIn) error {
 		}
 		return nil
 	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Caption) > 200 {
+			return ErrMaxHomestayImageCaption
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ValidateReorderHomestayImagesIn(i ReorderHomestayImagesIn) error {
+	g := new(errgroup.Group)
+
+	g.Go(func() error {
+		if len(i.ImageIds) == 0 {
+			return ErrHomestayImageRequired
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ValidateEditHomestayImageIn(i EditHomestayImageIn) error {
+	g := new(errgroup.Group)
+
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Caption) > 200 {
+			return ErrMaxHomestayImageCaption
+		}
+		return nil
+	})
 
 	if err := g.Wait(); err != nil {
 		return err
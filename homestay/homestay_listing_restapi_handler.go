@@ -0,0 +1,31 @@
+package homestay
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/go-chi/chi/v5"
+)
+
+func (d *HomestayDeps) GetHomestayListings(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	cursor := r.URL.Query().Get("cursor")
+	limit := r.URL.Query().Get("limit")
+	out := d.QueryHomestayListings(r.Context(), status, cursor, limit)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) PatchHomestayListing(w http.ResponseWriter, r *http.Request) {
+	hid := chi.URLParam(r, "hid")
+	decoder := json.NewDecoder(r.Body)
+
+	var in ReviewHomestayListingIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.ReviewHomestayListing(r.Context(), hid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
@@ -0,0 +1,164 @@
+package homestay
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrInvalidHomestayStatus = errors.New("status homestay harus berupa pending, approved, rejected, atau suspended")
+)
+
+type (
+	HomestayListingOut struct {
+		Id           int64     `json:"id"`
+		Name         string    `json:"name"`
+		Address      string    `json:"address"`
+		ThumbnailUrl string    `json:"thumbnail_url"`
+		MemberId     string    `json:"member_id"`
+		Status       string    `json:"status"`
+		StatusReason string    `json:"status_reason"`
+		ReviewedAt   null.Time `json:"reviewed_at"`
+	}
+	QueryHomestayListingsRes struct {
+		Cursor   int64                `json:"cursor"`
+		Total    int64                `json:"total"`
+		Listings []HomestayListingOut `json:"listings"`
+	}
+	QueryHomestayListingsOut struct {
+		resp.Response
+		Res QueryHomestayListingsRes
+	}
+)
+
+// Homestays for the admin review, empty status query the homestays
+// waiting for review
+func (d *HomestayDeps) QueryHomestayListings(ctx context.Context, status, cursor, limit string) (out QueryHomestayListingsOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if status == "" {
+		status = HomestayPending.String
+	}
+	if _, err = homestayStatusFromString(status); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidHomestayStatus)
+		return
+	}
+
+	fromCursor, _ := strconv.ParseInt(cursor, 10, 64)
+	nlimit, _ := strconv.ParseInt(limit, 10, 64)
+	if nlimit == 0 {
+		nlimit = 25
+	}
+
+	total, err := d.MemberHomestayRepository.CountByStatus(ctx, status)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "count member homestays by status"))
+		return
+	}
+
+	memberHomestays, err := d.MemberHomestayRepository.QueryByStatus(ctx, status, fromCursor, nlimit)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query member homestays by status"))
+		return
+	}
+
+	var nextCursor int64
+	if len(memberHomestays) != 0 {
+		nextCursor = int64(memberHomestays[len(memberHomestays)-1].Id)
+	}
+
+	listings := make([]HomestayListingOut, len(memberHomestays))
+	for i, m := range memberHomestays {
+		listings[i] = HomestayListingOut{
+			Id:           int64(m.Id),
+			Name:         m.Name,
+			Address:      m.Address,
+			ThumbnailUrl: m.ThumbnailUrl,
+			MemberId:     m.MemberId,
+			Status:       m.Status.String,
+			StatusReason: m.StatusReason,
+			ReviewedAt:   null.NewTime(m.ReviewedAt.Time, m.ReviewedAt.Valid),
+		}
+	}
+
+	out.Res = QueryHomestayListingsRes{
+		Cursor:   nextCursor,
+		Total:    total,
+		Listings: listings,
+	}
+
+	return
+}
+
+type (
+	ReviewHomestayListingIn struct {
+		Status string `json:"status"`
+		Reason string `json:"reason"`
+	}
+	ReviewHomestayListingRes struct {
+		Id     int64  `json:"id"`
+		Status string `json:"status"`
+	}
+	ReviewHomestayListingOut struct {
+		resp.Response
+		Res ReviewHomestayListingRes
+	}
+)
+
+// Approve, reject, or suspend a homestay. The reason is only kept for a
+// rejected or suspended homestay so the owner knows what to fix.
+func (d *HomestayDeps) ReviewHomestayListing(ctx context.Context, hid string, in ReviewHomestayListingIn) (out ReviewHomestayListingOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(hid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+
+	if err = ValidateReviewHomestayListingIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	_, err = d.MemberHomestayRepository.FindUndeletedById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member homestay by id"))
+		return
+	}
+
+	status, err := homestayStatusFromString(in.Status)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidListingStatus)
+		return
+	}
+
+	reason := in.Reason
+	if status == HomestayApproved {
+		reason = ""
+	}
+
+	if err = d.MemberHomestayRepository.UpdateStatusById(ctx, id, status, reason); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update member homestay status by id"))
+		return
+	}
+
+	out.Res = ReviewHomestayListingRes{
+		Id:     int64(id),
+		Status: status.String,
+	}
+
+	return
+}
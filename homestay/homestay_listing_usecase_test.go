@@ -0,0 +1,209 @@
+package homestay_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/homestay"
+)
+
+func TestReviewHomestayListing(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imageId, err := createHomestayImage(homestayImageRepository, fileSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := homestayDeps.AddMemberHomestay(context.Background(), muid, homestay.AddMemberHomestayIn{
+		Name:      "Homestay Name",
+		Address:   "Homestay Address",
+		Latitude:  "-6.9",
+		Longitude: "107.6",
+		ImageIds:  []int64{imageId},
+	})
+	if h.Error != nil {
+		t.Fatal(h.Error)
+	}
+	hid := strconv.FormatInt(h.Res.Id, 10)
+
+	// assertVisibility check the homestay is only visible to the public
+	// when it is expected to
+	assertVisibility := func(t *testing.T, isPublic bool) {
+		expectedStatusCode := http.StatusNotFound
+		var expectedTotal int64
+		if isPublic {
+			expectedStatusCode = http.StatusOK
+			expectedTotal = 1
+		}
+
+		public := homestayDeps.FindMemberHomestay(context.Background(), hid, muid, "")
+		if public.StatusCode != expectedStatusCode {
+			t.Fatalf("Expected public response code %d. Got %d\n", expectedStatusCode, public.StatusCode)
+		}
+
+		owner := homestayDeps.FindMemberHomestay(context.Background(), hid, muid, muid)
+		if owner.StatusCode != http.StatusOK {
+			t.Fatalf("Expected owner response code %d. Got %d\n", http.StatusOK, owner.StatusCode)
+		}
+
+		publicList := homestayDeps.QueryMemberHomestays(context.Background(), muid, "", "", "", "")
+		if publicList.Res.Total != expectedTotal {
+			t.Fatalf("Expected public member homestays total %d. Got %d\n", expectedTotal, publicList.Res.Total)
+		}
+
+		ownerList := homestayDeps.QueryMemberHomestays(context.Background(), muid, muid, "", "", "")
+		if ownerList.Res.Total != 1 {
+			t.Fatalf("Expected owner member homestays total 1. Got %d\n", ownerList.Res.Total)
+		}
+
+		directory := homestayDeps.QueryHomestayDirectory(context.Background(), homestay.QueryHomestayDirectoryQIn{})
+		if int64(len(directory.Res.Homestays)) != expectedTotal {
+			t.Fatalf("Expected directory homestays length %d. Got %d\n", expectedTotal, len(directory.Res.Homestays))
+		}
+
+		rooms := homestayDeps.QueryHomestayRooms(context.Background(), hid)
+		if rooms.StatusCode != expectedStatusCode {
+			t.Fatalf("Expected rooms response code %d. Got %d\n", expectedStatusCode, rooms.StatusCode)
+		}
+	}
+
+	t.Run("New Homestay Pending, Hidden from Public", func(t *testing.T) {
+		assertVisibility(t, false)
+
+		listings := homestayDeps.QueryHomestayListings(context.Background(), "", "", "")
+		if listings.Error != nil {
+			t.Fatal(listings.Error)
+		}
+		if listings.Res.Total != 1 || listings.Res.Listings[0].Status != homestay.HomestayPending.String {
+			t.Fatalf("Expected 1 pending listing. Got %#v\n", listings.Res)
+		}
+	})
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Hid                string
+		In                 homestay.ReviewHomestayListingIn
+	}{
+		{
+			Name:               "Review Homestay Listing Fail, Status Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Hid:                hid,
+			In:                 homestay.ReviewHomestayListingIn{},
+		},
+		{
+			Name:               "Review Homestay Listing Fail, Invalid Status",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Hid:                hid,
+			In: homestay.ReviewHomestayListingIn{
+				Status: homestay.HomestayPending.String,
+			},
+		},
+		{
+			Name:               "Review Homestay Listing Fail, Rejection Reason Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Hid:                hid,
+			In: homestay.ReviewHomestayListingIn{
+				Status: homestay.HomestayRejected.String,
+			},
+		},
+		{
+			Name:               "Review Homestay Listing Fail, Reason over 500 characters",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Hid:                hid,
+			In: homestay.ReviewHomestayListingIn{
+				Status: homestay.HomestayRejected.String,
+				Reason: strings.Repeat("a", 501),
+			},
+		},
+		{
+			Name:               "Review Homestay Listing Fail, Homestay not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Hid:                strconv.FormatInt(h.Res.Id+1, 10),
+			In: homestay.ReviewHomestayListingIn{
+				Status: homestay.HomestayApproved.String,
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := homestayDeps.ReviewHomestayListing(context.Background(), c.Hid, c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+
+	t.Run("Reject Homestay Listing Success, Back to Pending after Edited", func(t *testing.T) {
+		res := homestayDeps.ReviewHomestayListing(context.Background(), hid, homestay.ReviewHomestayListingIn{
+			Status: homestay.HomestayRejected.String,
+			Reason: "Foto homestay tidak jelas",
+		})
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+		assertVisibility(t, false)
+
+		owner := homestayDeps.FindMemberHomestay(context.Background(), hid, muid, muid)
+		if owner.Res.Status != homestay.HomestayRejected.String || owner.Res.StatusReason != "Foto homestay tidak jelas" {
+			t.Fatalf("Expected rejected homestay with reason. Got %#v\n", owner.Res)
+		}
+
+		edit := homestayDeps.EditMemberHomestay(context.Background(), hid, muid, homestay.EditMemberHomestayIn{
+			Name:      "Homestay Name",
+			Address:   "Homestay Address",
+			Latitude:  "-6.9",
+			Longitude: "107.6",
+			ImageIds:  []int64{imageId},
+		})
+		if edit.Error != nil {
+			t.Fatal(edit.Error)
+		}
+
+		listings := homestayDeps.QueryHomestayListings(context.Background(), homestay.HomestayPending.String, "", "")
+		if listings.Res.Total != 1 || listings.Res.Listings[0].StatusReason != "" {
+			t.Fatalf("Expected 1 pending listing without reason. Got %#v\n", listings.Res)
+		}
+	})
+
+	t.Run("Approve Homestay Listing Success, Visible to Public", func(t *testing.T) {
+		res := homestayDeps.ReviewHomestayListing(context.Background(), hid, homestay.ReviewHomestayListingIn{
+			Status: homestay.HomestayApproved.String,
+		})
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+		assertVisibility(t, true)
+	})
+
+	t.Run("Suspend Homestay Listing Success, Hidden from Public", func(t *testing.T) {
+		res := homestayDeps.ReviewHomestayListing(context.Background(), hid, homestay.ReviewHomestayListingIn{
+			Status: homestay.HomestaySuspended.String,
+			Reason: "Dilaporkan tamu",
+		})
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+		assertVisibility(t, false)
+
+		listings := homestayDeps.QueryHomestayListings(context.Background(), "unknown", "", "")
+		if listings.StatusCode != http.StatusUnprocessableEntity {
+			t.Fatalf("Expected response code %d. Got %d\n", http.StatusUnprocessableEntity, listings.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package homestay
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrListingStatusRequired = errors.New("status homestay tidak boleh kosong")
+	ErrInvalidListingStatus  = errors.New("status homestay harus berupa approved, rejected, atau suspended")
+	ErrListingReasonRequired = errors.New("alasan penolakan atau penangguhan homestay tidak boleh kosong")
+	ErrMaxListingReason      = errors.New("alasan penolakan atau penangguhan homestay tidak dapat lebih dari 500 karakter")
+)
+
+func ValidateReviewHomestayListingIn(i ReviewHomestayListingIn) error {
+	g := new(errgroup.Group)
+
+	g.Go(func() error {
+		if strings.Trim(i.Status, " ") == "" {
+			return ErrListingStatusRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		switch i.Status {
+		case "", HomestayApproved.String, HomestayRejected.String, HomestaySuspended.String:
+			return nil
+		}
+		return ErrInvalidListingStatus
+	})
+	g.Go(func() error {
+		isReasonRequired := i.Status == HomestayRejected.String || i.Status == HomestaySuspended.String
+		if isReasonRequired && strings.Trim(i.Reason, " ") == "" {
+			return ErrListingReasonRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Reason) > 500 {
+			return ErrMaxListingReason
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package homestay
+
+import (
+	"database/sql"
+	"time"
+)
+
+type HomestayReviewModel struct {
+	Id                   uint64
+	MemberHomestayId     uint64
+	HomestayBookingId    sql.NullInt64
+	HomestayReviewCodeId sql.NullInt64
+	ReviewerName         string
+	Rating               int64
+	Review               string
+	OwnerReply           string
+	RepliedAt            sql.NullTime
+	IsHidden             bool
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// One-time code given by the owner to a guest that stayed without booking
+// through the directory, so the guest can still leave a review
+type HomestayReviewCodeModel struct {
+	Id               uint64
+	MemberHomestayId uint64
+	Code             string
+	ExpiredAt        time.Time
+	UsedAt           sql.NullTime
+	CreatedAt        time.Time
+}
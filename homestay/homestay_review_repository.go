@@ -0,0 +1,374 @@
+package homestay
+
+import (
+	"context"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type HomestayReviewRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewHomestayReviewRepository(postgreDb *pgxpool.Pool) *HomestayReviewRepository {
+	return &HomestayReviewRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+type (
+	HomestayReviewExecutor   func(ctx context.Context, sql string, arguments ...interface{}) (commandTag pgconn.CommandTag, err error)
+	HomestayReviewQuerierRow func(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	HomestayReviewQuerier    func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+)
+
+func (r *HomestayReviewRepository) Save(ctx context.Context, m HomestayReviewModel) (nm HomestayReviewModel, err error) {
+	sqlQuery := `
+		INSERT INTO homestay_reviews (
+			member_homestay_id,
+			homestay_booking_id,
+			homestay_review_code_id,
+			reviewer_name,
+			rating,
+			review,
+			owner_reply,
+			replied_at,
+			is_hidden,
+			created_at,
+			updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`
+
+	var queryRow HomestayReviewQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	var lastInsertId uint64
+	t := time.Now()
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		m.MemberHomestayId,
+		m.HomestayBookingId,
+		m.HomestayReviewCodeId,
+		m.ReviewerName,
+		m.Rating,
+		m.Review,
+		"",
+		nil,
+		false,
+		t,
+		t,
+	).Scan(&lastInsertId)
+
+	if err != nil {
+		return HomestayReviewModel{}, err
+	}
+
+	m.Id = lastInsertId
+	m.CreatedAt = t
+	m.UpdatedAt = t
+
+	return m, nil
+}
+
+func (r *HomestayReviewRepository) FindById(ctx context.Context, id uint64) (m HomestayReviewModel, err error) {
+	querystr := `
+		SELECT
+			id,
+			member_homestay_id,
+			homestay_booking_id,
+			homestay_review_code_id,
+			reviewer_name,
+			rating,
+			review,
+			owner_reply,
+			replied_at,
+			is_hidden,
+			created_at,
+			updated_at
+		FROM homestay_reviews
+		WHERE id = $1
+	`
+
+	var query HomestayReviewQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+	rows, err = query(
+		context.Background(),
+		querystr,
+		id,
+	)
+
+	if err != nil {
+		return HomestayReviewModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return HomestayReviewModel{}, err
+	}
+
+	return m, nil
+}
+
+// Zero homestay id query the reviews of every homestay, hidden reviews are
+// only included when asked
+func (r *HomestayReviewRepository) Query(ctx context.Context, homestayId uint64, includeHidden bool, id, limit int64) ([]HomestayReviewModel, error) {
+	fromId := "id > $1"
+	if id != 0 {
+		fromId = "id < $1"
+	}
+
+	sqlQuery := `
+		SELECT
+			id,
+			member_homestay_id,
+			homestay_booking_id,
+			homestay_review_code_id,
+			reviewer_name,
+			rating,
+			review,
+			owner_reply,
+			replied_at,
+			is_hidden,
+			created_at,
+			updated_at
+		FROM homestay_reviews
+		WHERE ` + fromId + `
+			AND ($2 = 0 OR member_homestay_id = $2)
+			AND ($3 OR is_hidden = false)
+		ORDER BY id DESC
+		LIMIT $4
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		id,
+		homestayId,
+		includeHidden,
+		limit,
+	)
+	defer rows.Close()
+
+	var mps []*HomestayReviewModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []HomestayReviewModel{}, err
+	}
+
+	ms := make([]HomestayReviewModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *HomestayReviewRepository) Count(ctx context.Context, homestayId uint64, includeHidden bool) (n int64, err error) {
+	sqlQuery := `
+		SELECT COUNT(id) AS n
+		FROM homestay_reviews
+		WHERE ($1 = 0 OR member_homestay_id = $1)
+		AND ($2 OR is_hidden = false)
+	`
+
+	var queryRow HomestayReviewQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		homestayId,
+		includeHidden,
+	).Scan(&n)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Set the owner reply only if the review has not been replied yet, the
+// returned number of updated review is zero otherwise
+func (r *HomestayReviewRepository) UpdateReplyById(ctx context.Context, homestayId, id uint64, reply string) (n int64, err error) {
+	sqlQuery := `
+		UPDATE homestay_reviews SET (
+			owner_reply,
+			replied_at,
+			updated_at
+		) = ($1, $2, $3)
+		WHERE member_homestay_id = $4 AND id = $5
+		AND replied_at IS NULL
+	`
+
+	var exec HomestayReviewExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	t := time.Now()
+
+	tag, err := exec(
+		context.Background(),
+		sqlQuery,
+		reply,
+		t,
+		t,
+		homestayId,
+		id,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (r *HomestayReviewRepository) UpdateHiddenById(ctx context.Context, id uint64, isHidden bool) error {
+	sqlQuery := `
+		UPDATE homestay_reviews SET (
+			is_hidden,
+			updated_at
+		) = ($1, $2)
+		WHERE id = $3
+	`
+
+	var exec HomestayReviewExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		isHidden,
+		time.Now(),
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *HomestayReviewRepository) SaveCode(ctx context.Context, m HomestayReviewCodeModel) (nm HomestayReviewCodeModel, err error) {
+	sqlQuery := `
+		INSERT INTO homestay_review_codes (
+			member_homestay_id,
+			code,
+			expired_at,
+			used_at,
+			created_at
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	var queryRow HomestayReviewQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	var lastInsertId uint64
+	t := time.Now()
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		m.MemberHomestayId,
+		m.Code,
+		m.ExpiredAt,
+		nil,
+		t,
+	).Scan(&lastInsertId)
+
+	if err != nil {
+		return HomestayReviewCodeModel{}, err
+	}
+
+	m.Id = lastInsertId
+	m.CreatedAt = t
+
+	return m, nil
+}
+
+// Mark the code of the homestay as used, it fail with pgx.ErrNoRows when
+// the code does not exist, is expired, or is already used
+func (r *HomestayReviewRepository) UseCode(ctx context.Context, homestayId uint64, code string) (m HomestayReviewCodeModel, err error) {
+	querystr := `
+		UPDATE homestay_review_codes
+		SET used_at = $1
+		WHERE member_homestay_id = $2 AND code = $3
+		AND used_at IS NULL
+		AND expired_at > $1
+		RETURNING
+			id,
+			member_homestay_id,
+			code,
+			expired_at,
+			used_at,
+			created_at
+	`
+
+	var query HomestayReviewQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+	rows, err = query(
+		context.Background(),
+		querystr,
+		time.Now(),
+		homestayId,
+		code,
+	)
+
+	if err != nil {
+		return HomestayReviewCodeModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return HomestayReviewCodeModel{}, err
+	}
+
+	return m, nil
+}
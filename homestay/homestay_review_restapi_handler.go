@@ -0,0 +1,87 @@
+package homestay
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/go-chi/chi/v5"
+)
+
+func (d *HomestayDeps) PostHomestayReview(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	decoder := json.NewDecoder(r.Body)
+
+	var in AddHomestayReviewIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.AddHomestayReview(r.Context(), id, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) GetHomestayReviews(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	cursor := r.URL.Query().Get("cursor")
+	limit := r.URL.Query().Get("limit")
+	out := d.QueryHomestayReviews(r.Context(), id, cursor, limit)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) PostHomestayReviewCode(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	out := d.AddHomestayReviewCode(r.Context(), jwtPayload.Uid, id)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) PostHomestayReviewReply(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	vid := chi.URLParam(r, "vid")
+	decoder := json.NewDecoder(r.Body)
+
+	var in ReplyHomestayReviewIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.ReplyHomestayReview(r.Context(), jwtPayload.Uid, id, vid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) GetAllHomestayReviews(w http.ResponseWriter, r *http.Request) {
+	homestayId := r.URL.Query().Get("homestay_id")
+	cursor := r.URL.Query().Get("cursor")
+	limit := r.URL.Query().Get("limit")
+	out := d.QueryAllHomestayReviews(r.Context(), homestayId, cursor, limit)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) PatchHomestayReview(w http.ResponseWriter, r *http.Request) {
+	vid := chi.URLParam(r, "vid")
+	decoder := json.NewDecoder(r.Body)
+
+	var in ModerateHomestayReviewIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.ModerateHomestayReview(r.Context(), vid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
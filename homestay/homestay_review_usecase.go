@@ -0,0 +1,509 @@
+package homestay
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrHomestayReviewNotFound = errors.New("ulasan homestay tidak ditemukan")
+	ErrBookingNotCompleted    = errors.New("ulasan hanya dapat diberikan setelah menginap selesai")
+	ErrInvalidReviewCode      = errors.New("kode ulasan tidak valid, sudah digunakan, atau kedaluwarsa")
+	ErrReviewAlreadyExists    = errors.New("ulasan untuk pemesanan atau kode ini sudah ada")
+	ErrReviewAlreadyReplied   = errors.New("ulasan sudah dibalas")
+)
+
+// Postgres error code of a unique constraint violation
+const uniqueViolation = "23505"
+
+const (
+	// Number of days a review code can be used after it is issued
+	reviewCodeValidDays = 30
+	reviewCodeLength    = 8
+	// Without 0, O, 1, I, and L so a code read out loud is not mistyped
+	reviewCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+)
+
+func newReviewCode() (string, error) {
+	max := big.NewInt(int64(len(reviewCodeAlphabet)))
+
+	b := make([]byte, reviewCodeLength)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+
+		b[i] = reviewCodeAlphabet[n.Int64()]
+	}
+
+	return string(b), nil
+}
+
+// Keep only the digits of the phone number and write the Indonesian
+// trunk prefix 0 as the country code 62, so "+62 821-1111" and "0821 1111"
+// are the same number
+func normalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+
+	digits := b.String()
+	if strings.HasPrefix(digits, "0") {
+		digits = "62" + digits[1:]
+	}
+
+	return digits
+}
+
+type HomestayReviewOut struct {
+	Id             int64     `json:"id"`
+	HomestayId     int64     `json:"homestay_id"`
+	ReviewerName   string    `json:"reviewer_name"`
+	Rating         int64     `json:"rating"`
+	Review         string    `json:"review"`
+	OwnerReply     string    `json:"owner_reply"`
+	RepliedAt      string    `json:"replied_at"`
+	IsVerifiedStay bool      `json:"is_verified_stay"`
+	IsHidden       bool      `json:"is_hidden"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func toHomestayReviewsOut(reviews []HomestayReviewModel) []HomestayReviewOut {
+	outReviews := make([]HomestayReviewOut, len(reviews))
+	for i, r := range reviews {
+		var repliedAt string
+		if r.RepliedAt.Valid {
+			repliedAt = r.RepliedAt.Time.Format(time.RFC3339)
+		}
+
+		outReviews[i] = HomestayReviewOut{
+			Id:             int64(r.Id),
+			HomestayId:     int64(r.MemberHomestayId),
+			ReviewerName:   r.ReviewerName,
+			Rating:         r.Rating,
+			Review:         r.Review,
+			OwnerReply:     r.OwnerReply,
+			RepliedAt:      repliedAt,
+			IsVerifiedStay: r.HomestayBookingId.Valid,
+			IsHidden:       r.IsHidden,
+			CreatedAt:      r.CreatedAt,
+		}
+	}
+
+	return outReviews
+}
+
+type (
+	AddHomestayReviewIn struct {
+		BookingId    int64  `json:"booking_id"`
+		GuestPhone   string `json:"guest_phone"`
+		Code         string `json:"code"`
+		ReviewerName string `json:"reviewer_name"`
+		Rating       int64  `json:"rating"`
+		Review       string `json:"review"`
+	}
+	AddHomestayReviewRes struct {
+		Id int64 `json:"id"`
+	}
+	AddHomestayReviewOut struct {
+		resp.Response
+		Res AddHomestayReviewRes
+	}
+)
+
+// A guest can review either with a booking of the homestay that was
+// accepted and already checked out, proven by the guest phone number of
+// the booking, or with a one-time code issued by the owner. One booking or
+// code can only be used for one review.
+func (d *HomestayDeps) AddHomestayReview(ctx context.Context, hid string, in AddHomestayReviewIn) (out AddHomestayReviewOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusCreated, "", nil)
+
+	id, err := strconv.ParseUint(hid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+
+	if err = ValidateAddHomestayReviewIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	_, err = d.MemberHomestayRepository.FindApprovedById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member homestay by id"))
+		return
+	}
+
+	review := HomestayReviewModel{
+		MemberHomestayId: id,
+		ReviewerName:     in.ReviewerName,
+		Rating:           in.Rating,
+		Review:           in.Review,
+	}
+
+	if in.BookingId > 0 && strings.Trim(in.GuestPhone, " ") != "" {
+		booking, err := d.HomestayBookingRepository.FindById(ctx, id, uint64(in.BookingId))
+		if errors.Is(err, pgx.ErrNoRows) {
+			out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayBookingNotFound)
+			return
+		}
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find homestay booking by id"))
+			return
+		}
+
+		// A wrong phone number is reported the same as a missing booking so
+		// booking ids can not be probed
+		if normalizePhone(booking.GuestPhone) != normalizePhone(in.GuestPhone) {
+			out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayBookingNotFound)
+			return
+		}
+
+		if booking.Status != BookingAccepted || booking.CheckOut.After(time.Now().UTC().Truncate(24*time.Hour)) {
+			out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrBookingNotCompleted)
+			return
+		}
+
+		review.HomestayBookingId = sql.NullInt64{Int64: int64(booking.Id), Valid: true}
+	} else {
+		code, err := d.HomestayReviewRepository.UseCode(ctx, id, strings.ToUpper(strings.Trim(in.Code, " ")))
+		if errors.Is(err, pgx.ErrNoRows) {
+			out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidReviewCode)
+			return
+		}
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "use homestay review code"))
+			return
+		}
+
+		review.HomestayReviewCodeId = sql.NullInt64{Int64: int64(code.Id), Valid: true}
+	}
+
+	review, err = d.HomestayReviewRepository.Save(ctx, review)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		out.Response = resp.NewResponse(http.StatusConflict, "", ErrReviewAlreadyExists)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save homestay review"))
+		return
+	}
+
+	if err = d.MemberHomestayRepository.UpdateRatingById(ctx, id); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update member homestay rating by id"))
+		return
+	}
+
+	out.Res.Id = int64(review.Id)
+
+	return
+}
+
+type (
+	QueryHomestayReviewsRes struct {
+		RatingAvg   float64             `json:"rating_avg"`
+		RatingCount int64               `json:"rating_count"`
+		Cursor      int64               `json:"cursor"`
+		Total       int64               `json:"total"`
+		Reviews     []HomestayReviewOut `json:"reviews"`
+	}
+	QueryHomestayReviewsOut struct {
+		resp.Response
+		Res QueryHomestayReviewsRes
+	}
+)
+
+func (d *HomestayDeps) QueryHomestayReviews(ctx context.Context, hid, cursor, limit string) (out QueryHomestayReviewsOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(hid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+
+	fromCursor, _ := strconv.ParseInt(cursor, 10, 64)
+	nlimit, _ := strconv.ParseInt(limit, 10, 64)
+	if nlimit == 0 {
+		nlimit = 25
+	}
+
+	memberHomestay, err := d.MemberHomestayRepository.FindApprovedById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member homestay by id"))
+		return
+	}
+
+	reviews, err := d.HomestayReviewRepository.Query(ctx, id, false, fromCursor, nlimit)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query homestay reviews"))
+		return
+	}
+
+	var nextCursor int64
+	if len(reviews) != 0 {
+		nextCursor = int64(reviews[len(reviews)-1].Id)
+	}
+
+	out.Res = QueryHomestayReviewsRes{
+		RatingAvg:   memberHomestay.RatingAvg,
+		RatingCount: memberHomestay.RatingCount,
+		Cursor:      nextCursor,
+		Total:       memberHomestay.RatingCount,
+		Reviews:     toHomestayReviewsOut(reviews),
+	}
+
+	return
+}
+
+type (
+	AddHomestayReviewCodeRes struct {
+		Code      string    `json:"code"`
+		ExpiredAt time.Time `json:"expired_at"`
+	}
+	AddHomestayReviewCodeOut struct {
+		resp.Response
+		Res AddHomestayReviewCodeRes
+	}
+)
+
+func (d *HomestayDeps) AddHomestayReviewCode(ctx context.Context, uid, hid string) (out AddHomestayReviewCodeOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusCreated, "", nil)
+
+	memberHomestay, res := d.findOwnedHomestay(ctx, uid, hid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	code, err := newReviewCode()
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "generate review code"))
+		return
+	}
+
+	reviewCode := HomestayReviewCodeModel{
+		MemberHomestayId: memberHomestay.Id,
+		Code:             code,
+		ExpiredAt:        time.Now().AddDate(0, 0, reviewCodeValidDays),
+	}
+	if reviewCode, err = d.HomestayReviewRepository.SaveCode(ctx, reviewCode); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save homestay review code"))
+		return
+	}
+
+	out.Res = AddHomestayReviewCodeRes{
+		Code:      reviewCode.Code,
+		ExpiredAt: reviewCode.ExpiredAt,
+	}
+
+	return
+}
+
+type (
+	ReplyHomestayReviewIn struct {
+		Reply string `json:"reply"`
+	}
+	ReplyHomestayReviewRes struct {
+		Id int64 `json:"id"`
+	}
+	ReplyHomestayReviewOut struct {
+		resp.Response
+		Res ReplyHomestayReviewRes
+	}
+)
+
+// The owner can reply to a review of the homestay only once
+func (d *HomestayDeps) ReplyHomestayReview(ctx context.Context, uid, hid, vid string, in ReplyHomestayReviewIn) (out ReplyHomestayReviewOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(vid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayReviewNotFound)
+		return
+	}
+
+	if err = ValidateReplyHomestayReviewIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	memberHomestay, res := d.findOwnedHomestay(ctx, uid, hid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	review, err := d.HomestayReviewRepository.FindById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && review.MemberHomestayId != memberHomestay.Id) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayReviewNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find homestay review by id"))
+		return
+	}
+
+	if review.RepliedAt.Valid {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrReviewAlreadyReplied)
+		return
+	}
+
+	n, err := d.HomestayReviewRepository.UpdateReplyById(ctx, memberHomestay.Id, id, in.Reply)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update homestay review reply by id"))
+		return
+	}
+	// Replied in between by a concurrent request
+	if n == 0 {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrReviewAlreadyReplied)
+		return
+	}
+
+	out.Res.Id = int64(id)
+
+	return
+}
+
+type (
+	QueryAllHomestayReviewsRes struct {
+		Cursor  int64               `json:"cursor"`
+		Total   int64               `json:"total"`
+		Reviews []HomestayReviewOut `json:"reviews"`
+	}
+	QueryAllHomestayReviewsOut struct {
+		resp.Response
+		Res QueryAllHomestayReviewsRes
+	}
+)
+
+// Reviews for moderation, hidden ones included. Empty homestay id query
+// the reviews of every homestay.
+func (d *HomestayDeps) QueryAllHomestayReviews(ctx context.Context, hid, cursor, limit string) (out QueryAllHomestayReviewsOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	var id uint64
+	if hid != "" {
+		if id, err = strconv.ParseUint(hid, 10, 64); err != nil {
+			out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+			return
+		}
+	}
+
+	fromCursor, _ := strconv.ParseInt(cursor, 10, 64)
+	nlimit, _ := strconv.ParseInt(limit, 10, 64)
+	if nlimit == 0 {
+		nlimit = 25
+	}
+
+	total, err := d.HomestayReviewRepository.Count(ctx, id, true)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "count homestay reviews"))
+		return
+	}
+
+	reviews, err := d.HomestayReviewRepository.Query(ctx, id, true, fromCursor, nlimit)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query homestay reviews"))
+		return
+	}
+
+	var nextCursor int64
+	if len(reviews) != 0 {
+		nextCursor = int64(reviews[len(reviews)-1].Id)
+	}
+
+	out.Res = QueryAllHomestayReviewsRes{
+		Cursor:  nextCursor,
+		Total:   total,
+		Reviews: toHomestayReviewsOut(reviews),
+	}
+
+	return
+}
+
+type (
+	ModerateHomestayReviewIn struct {
+		IsHidden null.Bool `json:"is_hidden"`
+	}
+	ModerateHomestayReviewRes struct {
+		Id int64 `json:"id"`
+	}
+	ModerateHomestayReviewOut struct {
+		resp.Response
+		Res ModerateHomestayReviewRes
+	}
+)
+
+// Hide or show back a review, a hidden review does not count toward the
+// homestay rating
+func (d *HomestayDeps) ModerateHomestayReview(ctx context.Context, vid string, in ModerateHomestayReviewIn) (out ModerateHomestayReviewOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(vid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayReviewNotFound)
+		return
+	}
+
+	if err = ValidateModerateHomestayReviewIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	review, err := d.HomestayReviewRepository.FindById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayReviewNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find homestay review by id"))
+		return
+	}
+
+	if err = d.HomestayReviewRepository.UpdateHiddenById(ctx, id, in.IsHidden.Bool); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update homestay review hidden by id"))
+		return
+	}
+
+	if err = d.MemberHomestayRepository.UpdateRatingById(ctx, review.MemberHomestayId); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update member homestay rating by id"))
+		return
+	}
+
+	out.Res.Id = int64(id)
+
+	return
+}
@@ -0,0 +1,288 @@
+package homestay_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/homestay"
+	"gopkg.in/guregu/null.v4"
+)
+
+func createBooking(r *homestay.HomestayBookingRepository, homestayId, roomId int64, checkInDays, checkOutDays int, status homestay.BookingStatus) (id int64, err error) {
+	checkIn, _ := time.Parse("2006-01-02", dateFromNow(checkInDays))
+	checkOut, _ := time.Parse("2006-01-02", dateFromNow(checkOutDays))
+
+	booking, err := r.Save(context.Background(), homestay.HomestayBookingModel{
+		MemberHomestayId: uint64(homestayId),
+		HomestayRoomId:   uint64(roomId),
+		GuestName:        "Guest",
+		GuestPhone:       "+62 821-2222-0000",
+		GuestCount:       1,
+		CheckIn:          checkIn,
+		CheckOut:         checkOut,
+		Status:           status,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(booking.Id), nil
+}
+
+func TestAddHomestayReview(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hid, err := createMemberHomestay(memberHomestayRepository, muid, homestaySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rid, err := createHomestayRoom(homestayRoomRepository, hid, roomSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completedBid, err := createBooking(homestayBookingRepository, hid, rid, -5, -3, homestay.BookingAccepted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	upcomingBid, err := createBooking(homestayBookingRepository, hid, rid, 3, 5, homestay.BookingAccepted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := homestayDeps.AddHomestayReviewCode(context.Background(), muid, strconv.FormatInt(hid, 10))
+	if code.Error != nil {
+		t.Fatal(code.Error)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		In                 homestay.AddHomestayReviewIn
+	}{
+		{
+			Name:               "Add Homestay Review with Booking Success",
+			ExpectedStatusCode: http.StatusCreated,
+			In: homestay.AddHomestayReviewIn{
+				BookingId:    completedBid,
+				GuestPhone:   "0821 2222 0000",
+				ReviewerName: "Guest",
+				Rating:       5,
+				Review:       "Bersih dan nyaman",
+			},
+		},
+		{
+			Name:               "Add Homestay Review with Booking Fail, Already Reviewed",
+			ExpectedStatusCode: http.StatusConflict,
+			In: homestay.AddHomestayReviewIn{
+				BookingId:    completedBid,
+				GuestPhone:   "+62 821-2222-0000",
+				ReviewerName: "Guest",
+				Rating:       1,
+			},
+		},
+		{
+			Name:               "Add Homestay Review with Booking Fail, Wrong Phone",
+			ExpectedStatusCode: http.StatusNotFound,
+			In: homestay.AddHomestayReviewIn{
+				BookingId:    completedBid,
+				GuestPhone:   "+62 821-9999-0000",
+				ReviewerName: "Guest",
+				Rating:       5,
+			},
+		},
+		{
+			Name:               "Add Homestay Review with Booking Fail, Stay not Completed",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: homestay.AddHomestayReviewIn{
+				BookingId:    upcomingBid,
+				GuestPhone:   "+62 821-2222-0000",
+				ReviewerName: "Guest",
+				Rating:       5,
+			},
+		},
+		{
+			Name:               "Add Homestay Review with Code Success",
+			ExpectedStatusCode: http.StatusCreated,
+			In: homestay.AddHomestayReviewIn{
+				Code:         code.Res.Code,
+				ReviewerName: "Walk-in Guest",
+				Rating:       4,
+			},
+		},
+		{
+			Name:               "Add Homestay Review with Code Fail, Code Already Used",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: homestay.AddHomestayReviewIn{
+				Code:         code.Res.Code,
+				ReviewerName: "Walk-in Guest",
+				Rating:       4,
+			},
+		},
+		{
+			Name:               "Add Homestay Review Fail, Invalid Rating",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: homestay.AddHomestayReviewIn{
+				Code:         "ABCDEFGH",
+				ReviewerName: "Guest",
+				Rating:       6,
+			},
+		},
+		{
+			Name:               "Add Homestay Review Fail, Proof Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: homestay.AddHomestayReviewIn{
+				ReviewerName: "Guest",
+				Rating:       5,
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := homestayDeps.AddHomestayReview(context.Background(), strconv.FormatInt(hid, 10), c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+
+	t.Run("Find Member Homestay Success, Aggregated Rating", func(t *testing.T) {
+		res := homestayDeps.FindMemberHomestay(context.Background(), strconv.FormatInt(hid, 10), muid, "")
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		if res.Res.RatingCount != 2 {
+			t.Fatalf("Expected rating count 2. Got %d\n", res.Res.RatingCount)
+		}
+		if res.Res.RatingAvg != 4.5 {
+			t.Fatalf("Expected rating average 4.5. Got %f\n", res.Res.RatingAvg)
+		}
+	})
+}
+
+func TestReplyAndModerateHomestayReview(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hid, err := createMemberHomestay(memberHomestayRepository, muid, homestaySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := homestayDeps.AddHomestayReviewCode(context.Background(), muid, strconv.FormatInt(hid, 10))
+	if code.Error != nil {
+		t.Fatal(code.Error)
+	}
+
+	review := homestayDeps.AddHomestayReview(context.Background(), strconv.FormatInt(hid, 10), homestay.AddHomestayReviewIn{
+		Code:         code.Res.Code,
+		ReviewerName: "Guest",
+		Rating:       2,
+		Review:       "Air panas tidak menyala",
+	})
+	if review.Error != nil {
+		t.Fatal(review.Error)
+	}
+	vid := strconv.FormatInt(review.Res.Id, 10)
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Vid                string
+		In                 homestay.ReplyHomestayReviewIn
+	}{
+		{
+			Name:               "Reply Homestay Review Success",
+			ExpectedStatusCode: http.StatusOK,
+			Vid:                vid,
+			In: homestay.ReplyHomestayReviewIn{
+				Reply: "Mohon maaf, sudah kami perbaiki",
+			},
+		},
+		{
+			Name:               "Reply Homestay Review Fail, Already Replied",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Vid:                vid,
+			In: homestay.ReplyHomestayReviewIn{
+				Reply: "Terima kasih",
+			},
+		},
+		{
+			Name:               "Reply Homestay Review Fail, Review not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Vid:                strconv.FormatInt(review.Res.Id+1, 10),
+			In: homestay.ReplyHomestayReviewIn{
+				Reply: "Terima kasih",
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := homestayDeps.ReplyHomestayReview(context.Background(), muid, strconv.FormatInt(hid, 10), c.Vid, c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+
+	t.Run("Moderate Homestay Review Success, Hidden from Public", func(t *testing.T) {
+		res := homestayDeps.ModerateHomestayReview(context.Background(), vid, homestay.ModerateHomestayReviewIn{
+			IsHidden: null.BoolFrom(true),
+		})
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		reviews := homestayDeps.QueryHomestayReviews(context.Background(), strconv.FormatInt(hid, 10), "", "")
+		if reviews.Error != nil {
+			t.Fatal(reviews.Error)
+		}
+
+		if len(reviews.Res.Reviews) != 0 || reviews.Res.RatingCount != 0 {
+			t.Fatalf("Expected no visible review. Got %d reviews and rating count %d\n", len(reviews.Res.Reviews), reviews.Res.RatingCount)
+		}
+
+		all := homestayDeps.QueryAllHomestayReviews(context.Background(), "", "", "")
+		if all.Error != nil {
+			t.Fatal(all.Error)
+		}
+
+		if all.Res.Total != 1 {
+			t.Fatalf("Expected total moderated reviews 1. Got %d\n", all.Res.Total)
+		}
+	})
+
+	t.Run("Moderate Homestay Review Fail, Decision Required", func(t *testing.T) {
+		res := homestayDeps.ModerateHomestayReview(context.Background(), vid, homestay.ModerateHomestayReviewIn{})
+		if res.StatusCode != http.StatusUnprocessableEntity {
+			t.Fatalf("Expected response code %d. Got %d\n", http.StatusUnprocessableEntity, res.StatusCode)
+		}
+	})
+}
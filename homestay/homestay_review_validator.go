@@ -0,0 +1,91 @@
+package homestay
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrReviewerNameRequired  = errors.New("nama pengulas tidak boleh kosong")
+	ErrMaxReviewerName       = errors.New("nama pengulas tidak dapat lebih dari 100 karakter")
+	ErrInvalidRating         = errors.New("rating harus berupa angka 1 sampai 5")
+	ErrMaxReview             = errors.New("ulasan tidak dapat lebih dari 1000 karakter")
+	ErrReviewProofRequired   = errors.New("nomor pemesanan dan nomor telepon tamu, atau kode ulasan tidak boleh kosong")
+	ErrReviewReplyRequired   = errors.New("balasan ulasan tidak boleh kosong")
+	ErrMaxReviewReply        = errors.New("balasan ulasan tidak dapat lebih dari 1000 karakter")
+	ErrReviewModerationEmpty = errors.New("status tampil ulasan tidak boleh kosong")
+)
+
+func ValidateAddHomestayReviewIn(i AddHomestayReviewIn) error {
+	g := new(errgroup.Group)
+
+	g.Go(func() error {
+		if strings.Trim(i.ReviewerName, " ") == "" {
+			return ErrReviewerNameRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.ReviewerName) > 100 {
+			return ErrMaxReviewerName
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if i.Rating < 1 || i.Rating > 5 {
+			return ErrInvalidRating
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Review) > 1000 {
+			return ErrMaxReview
+		}
+		return nil
+	})
+	g.Go(func() error {
+		hasBooking := i.BookingId > 0 && strings.Trim(i.GuestPhone, " ") != ""
+		hasCode := strings.Trim(i.Code, " ") != ""
+		if !hasBooking && !hasCode {
+			return ErrReviewProofRequired
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ValidateReplyHomestayReviewIn(i ReplyHomestayReviewIn) error {
+	g := new(errgroup.Group)
+
+	g.Go(func() error {
+		if strings.Trim(i.Reply, " ") == "" {
+			return ErrReviewReplyRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Reply) > 1000 {
+			return ErrMaxReviewReply
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ValidateModerateHomestayReviewIn(i ModerateHomestayReviewIn) error {
+	if !i.IsHidden.Valid {
+		return ErrReviewModerationEmpty
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package homestay
+
+import (
+	"context"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type HomestayRoomIcalFeedRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewHomestayRoomIcalFeedRepository(postgreDb *pgxpool.Pool) *HomestayRoomIcalFeedRepository {
+	return &HomestayRoomIcalFeedRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+type (
+	HomestayRoomIcalFeedExecutor   func(ctx context.Context, sql string, arguments ...interface{}) (commandTag pgconn.CommandTag, err error)
+	HomestayRoomIcalFeedQuerierRow func(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	HomestayRoomIcalFeedQuerier    func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+)
+
+func (r *HomestayRoomIcalFeedRepository) Save(ctx context.Context, m HomestayRoomIcalFeedModel) (nm HomestayRoomIcalFeedModel, err error) {
+	sqlQuery := `
+		INSERT INTO homestay_room_ical_feeds (
+			homestay_room_id,
+			name,
+			url,
+			last_synced_at,
+			last_error,
+			created_at,
+			updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	var queryRow HomestayRoomIcalFeedQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	var lastInsertId uint64
+	t := time.Now()
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		m.HomestayRoomId,
+		m.Name,
+		m.Url,
+		nil,
+		"",
+		t,
+		t,
+	).Scan(&lastInsertId)
+
+	if err != nil {
+		return HomestayRoomIcalFeedModel{}, err
+	}
+
+	m.Id = lastInsertId
+	m.CreatedAt = t
+	m.UpdatedAt = t
+
+	return m, nil
+}
+
+func (r *HomestayRoomIcalFeedRepository) FindById(ctx context.Context, roomId, id uint64) (m HomestayRoomIcalFeedModel, err error) {
+	querystr := `
+		SELECT
+			id,
+			homestay_room_id,
+			name,
+			url,
+			last_synced_at,
+			last_error,
+			created_at,
+			updated_at
+		FROM homestay_room_ical_feeds
+		WHERE homestay_room_id = $1 AND id = $2
+	`
+
+	var query HomestayRoomIcalFeedQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+	rows, err = query(
+		context.Background(),
+		querystr,
+		roomId,
+		id,
+	)
+
+	if err != nil {
+		return HomestayRoomIcalFeedModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return HomestayRoomIcalFeedModel{}, err
+	}
+
+	return m, nil
+}
+
+func (r *HomestayRoomIcalFeedRepository) QueryByRoomId(ctx context.Context, roomId uint64) ([]HomestayRoomIcalFeedModel, error) {
+	sqlQuery := `
+		SELECT
+			id,
+			homestay_room_id,
+			name,
+			url,
+			last_synced_at,
+			last_error,
+			created_at,
+			updated_at
+		FROM homestay_room_ical_feeds
+		WHERE homestay_room_id = $1
+		ORDER BY id ASC
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		roomId,
+	)
+	defer rows.Close()
+
+	var mps []*HomestayRoomIcalFeedModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []HomestayRoomIcalFeedModel{}, err
+	}
+
+	ms := make([]HomestayRoomIcalFeedModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+// Feeds of every undeleted room of every undeleted homestay, the least
+// recently synced first
+func (r *HomestayRoomIcalFeedRepository) QueryAll(ctx context.Context) ([]HomestayRoomIcalFeedModel, error) {
+	sqlQuery := `
+		SELECT
+			f.id,
+			f.homestay_room_id,
+			f.name,
+			f.url,
+			f.last_synced_at,
+			f.last_error,
+			f.created_at,
+			f.updated_at
+		FROM homestay_room_ical_feeds f
+			JOIN homestay_rooms hr ON hr.id = f.homestay_room_id
+			JOIN member_homestays mh ON mh.id = hr.member_homestay_id
+		WHERE hr.deleted_at IS NULL
+			AND mh.deleted_at IS NULL
+		ORDER BY f.last_synced_at ASC NULLS FIRST, f.id ASC
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+	)
+	defer rows.Close()
+
+	var mps []*HomestayRoomIcalFeedModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []HomestayRoomIcalFeedModel{}, err
+	}
+
+	ms := make([]HomestayRoomIcalFeedModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *HomestayRoomIcalFeedRepository) UpdateSyncById(ctx context.Context, id uint64, syncedAt time.Time, lastError string) error {
+	sqlQuery := `
+		UPDATE homestay_room_ical_feeds SET (
+			last_synced_at,
+			last_error,
+			updated_at
+		) = ($1, $2, $3)
+		WHERE id = $4
+	`
+
+	var exec HomestayRoomIcalFeedExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		syncedAt,
+		lastError,
+		time.Now(),
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *HomestayRoomIcalFeedRepository) DeleteById(ctx context.Context, roomId, id uint64) error {
+	sqlQuery := `
+		DELETE FROM homestay_room_ical_feeds
+		WHERE homestay_room_id = $1
+		AND id = $2
+	`
+
+	var exec HomestayRoomIcalFeedExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		roomId,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package homestay
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/ical"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/go-chi/chi/v5"
+)
+
+func (d *HomestayDeps) GetHomestayRoomIcal(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	rid := chi.URLParam(r, "rid")
+	out := d.QueryHomestayRoomIcal(r.Context(), jwtPayload.Uid, id, rid)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) PostHomestayRoomIcalToken(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	rid := chi.URLParam(r, "rid")
+	out := d.RotateHomestayRoomIcalToken(r.Context(), jwtPayload.Uid, id, rid)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) PostHomestayRoomIcalFeed(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	rid := chi.URLParam(r, "rid")
+	decoder := json.NewDecoder(r.Body)
+
+	var in AddHomestayRoomIcalFeedIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.AddHomestayRoomIcalFeed(r.Context(), jwtPayload.Uid, id, rid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) DeleteHomestayRoomIcalFeed(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	rid := chi.URLParam(r, "rid")
+	fid := chi.URLParam(r, "fid")
+	out := d.RemoveHomestayRoomIcalFeed(r.Context(), jwtPayload.Uid, id, rid, fid)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) GetHomestayRoomCalendar(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	out := d.ExportHomestayRoomIcal(r.Context(), token)
+	if out.Error != nil {
+		out.HttpJSON(w, nil)
+		return
+	}
+
+	var b bytes.Buffer
+	if err := ical.Encode(&b, icalProdId, out.Res.Name, time.Now(), out.Res.Events); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b.Bytes())
+}
@@ -0,0 +1,535 @@
+package homestay
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/ical"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrIcalFeedNotFound = errors.New("kalender eksternal tidak ditemukan")
+	ErrIcalFeedTooLarge = errors.New("ukuran kalender eksternal tidak dapat lebih dari 1 MB")
+)
+
+const (
+	// Maximum size of an external iCal feed body
+	maxIcalFeedSize = 1 << 20
+	// Number of days ahead, from today, an external iCal feed is imported
+	// and the room calendar is exported
+	icalHorizonDays = 365
+	// Number of past days still exported, so a stay that just ended does
+	// not vanish from the calendar of the other sites
+	icalExportPastDays = 30
+	icalProdId         = "-//uhomestay//homestay room calendar//ID"
+)
+
+// The token is the only credential of the exported calendar, it must not
+// be guessable
+func newIcalToken() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func icalExportPath(token string) string {
+	return "/api/v1/calendars/" + token + ".ics"
+}
+
+func icalToday() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+// Find the undeleted room with the given id only if its homestay is owned
+// by the member with the given uid
+func (d *HomestayDeps) findOwnedRoom(ctx context.Context, uid, hid, rid string) (m HomestayRoomModel, res resp.Response) {
+	id, err := strconv.ParseUint(rid, 10, 64)
+	if err != nil {
+		res = resp.NewResponse(http.StatusNotFound, "", ErrHomestayRoomNotFound)
+		return
+	}
+
+	memberHomestay, res := d.findOwnedHomestay(ctx, uid, hid)
+	if res.Error != nil {
+		return
+	}
+
+	m, err = d.HomestayRoomRepository.FindUndeletedById(ctx, memberHomestay.Id, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		res = resp.NewResponse(http.StatusNotFound, "", ErrHomestayRoomNotFound)
+		return
+	}
+	if err != nil {
+		res = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find homestay room by id"))
+		return
+	}
+
+	return
+}
+
+type (
+	HomestayRoomIcalFeedOut struct {
+		Id           int64  `json:"id"`
+		Name         string `json:"name"`
+		Url          string `json:"url"`
+		LastSyncedAt string `json:"last_synced_at"`
+		LastError    string `json:"last_error"`
+	}
+	QueryHomestayRoomIcalRes struct {
+		ExportPath string                    `json:"export_path"`
+		Feeds      []HomestayRoomIcalFeedOut `json:"feeds"`
+	}
+	QueryHomestayRoomIcalOut struct {
+		resp.Response
+		Res QueryHomestayRoomIcalRes
+	}
+)
+
+func (d *HomestayDeps) QueryHomestayRoomIcal(ctx context.Context, uid, hid, rid string) (out QueryHomestayRoomIcalOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	room, res := d.findOwnedRoom(ctx, uid, hid, rid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	// Room added before the calendar export existed does not have a token yet
+	if room.IcalToken == "" {
+		if room.IcalToken, err = newIcalToken(); err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "generate ical token"))
+			return
+		}
+
+		if err = d.HomestayRoomRepository.UpdateIcalTokenById(ctx, room.MemberHomestayId, room.Id, room.IcalToken); err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update homestay room ical token by id"))
+			return
+		}
+	}
+
+	feeds, err := d.HomestayRoomIcalFeedRepository.QueryByRoomId(ctx, room.Id)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query homestay room ical feeds"))
+		return
+	}
+
+	outFeeds := make([]HomestayRoomIcalFeedOut, len(feeds))
+	for i, f := range feeds {
+		var lastSyncedAt string
+		if f.LastSyncedAt.Valid {
+			lastSyncedAt = f.LastSyncedAt.Time.Format(time.RFC3339)
+		}
+
+		outFeeds[i] = HomestayRoomIcalFeedOut{
+			Id:           int64(f.Id),
+			Name:         f.Name,
+			Url:          f.Url,
+			LastSyncedAt: lastSyncedAt,
+			LastError:    f.LastError,
+		}
+	}
+
+	out.Res = QueryHomestayRoomIcalRes{
+		ExportPath: icalExportPath(room.IcalToken),
+		Feeds:      outFeeds,
+	}
+
+	return
+}
+
+type (
+	RotateHomestayRoomIcalTokenRes struct {
+		ExportPath string `json:"export_path"`
+	}
+	RotateHomestayRoomIcalTokenOut struct {
+		resp.Response
+		Res RotateHomestayRoomIcalTokenRes
+	}
+)
+
+// Replace the token of the exported calendar, the previous url stop
+// working immediately
+func (d *HomestayDeps) RotateHomestayRoomIcalToken(ctx context.Context, uid, hid, rid string) (out RotateHomestayRoomIcalTokenOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	room, res := d.findOwnedRoom(ctx, uid, hid, rid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	token, err := newIcalToken()
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "generate ical token"))
+		return
+	}
+
+	if err = d.HomestayRoomRepository.UpdateIcalTokenById(ctx, room.MemberHomestayId, room.Id, token); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update homestay room ical token by id"))
+		return
+	}
+
+	out.Res.ExportPath = icalExportPath(token)
+
+	return
+}
+
+type (
+	AddHomestayRoomIcalFeedIn struct {
+		Name string `json:"name"`
+		Url  string `json:"url"`
+	}
+	AddHomestayRoomIcalFeedRes struct {
+		Id        int64  `json:"id"`
+		LastError string `json:"last_error"`
+	}
+	AddHomestayRoomIcalFeedOut struct {
+		resp.Response
+		Res AddHomestayRoomIcalFeedRes
+	}
+)
+
+// Register an external calendar and import it right away. A failing import
+// does not reject the feed, it is retried by the periodic sync and the
+// reason is reported in the last error.
+func (d *HomestayDeps) AddHomestayRoomIcalFeed(ctx context.Context, uid, hid, rid string, in AddHomestayRoomIcalFeedIn) (out AddHomestayRoomIcalFeedOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusCreated, "", nil)
+
+	if err = ValidateAddHomestayRoomIcalFeedIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	room, res := d.findOwnedRoom(ctx, uid, hid, rid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	feed := HomestayRoomIcalFeedModel{
+		HomestayRoomId: room.Id,
+		Name:           in.Name,
+		Url:            in.Url,
+	}
+	if feed, err = d.HomestayRoomIcalFeedRepository.Save(ctx, feed); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save homestay room ical feed"))
+		return
+	}
+
+	out.Res.Id = int64(feed.Id)
+
+	lastError, err := d.syncIcalFeed(ctx, feed)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", err)
+		return
+	}
+
+	out.Res.LastError = lastError
+
+	return
+}
+
+type (
+	RemoveHomestayRoomIcalFeedRes struct {
+		Id int64 `json:"id"`
+	}
+	RemoveHomestayRoomIcalFeedOut struct {
+		resp.Response
+		Res RemoveHomestayRoomIcalFeedRes
+	}
+)
+
+// Remove the external calendar together with the blocks it imported
+func (d *HomestayDeps) RemoveHomestayRoomIcalFeed(ctx context.Context, uid, hid, rid, fid string) (out RemoveHomestayRoomIcalFeedOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(fid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrIcalFeedNotFound)
+		return
+	}
+
+	room, res := d.findOwnedRoom(ctx, uid, hid, rid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	_, err = d.HomestayRoomIcalFeedRepository.FindById(ctx, room.Id, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrIcalFeedNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find homestay room ical feed by id"))
+		return
+	}
+
+	if err = d.HomestayRoomRepository.DeleteFeedBlocks(ctx, id, nil); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "delete homestay room feed blocks"))
+		return
+	}
+
+	if err = d.HomestayRoomIcalFeedRepository.DeleteById(ctx, room.Id, id); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "delete homestay room ical feed by id"))
+		return
+	}
+
+	out.Res.Id = int64(id)
+
+	return
+}
+
+// Fetch and parse the feed, then make the imported blocks of the room
+// match the nights of its events from today up to the horizon. On any
+// fetch or parse failure the previously imported blocks are kept.
+func (d *HomestayDeps) pullIcalFeed(ctx context.Context, feed HomestayRoomIcalFeedModel) error {
+	body, err := d.FetchIcal(ctx, feed.Url)
+	if err != nil {
+		return errors.Wrap(err, "fetch ical feed")
+	}
+	defer body.Close()
+
+	// Read one byte past the limit to tell a feed of exactly the limit
+	// from a larger one
+	b, err := io.ReadAll(io.LimitReader(body, maxIcalFeedSize+1))
+	if err != nil {
+		return errors.Wrap(err, "read ical feed")
+	}
+	if len(b) > maxIcalFeedSize {
+		return ErrIcalFeedTooLarge
+	}
+
+	events, err := ical.Parse(bytes.NewReader(b))
+	if err != nil {
+		return errors.Wrap(err, "parse ical feed")
+	}
+
+	today := icalToday()
+	horizon := today.AddDate(0, 0, icalHorizonDays)
+
+	seen := make(map[time.Time]bool)
+	dates := []time.Time{}
+	for _, e := range events {
+		for t := e.Start; t.Before(e.End) && t.Before(horizon); t = t.AddDate(0, 0, 1) {
+			if t.Before(today) || seen[t] {
+				continue
+			}
+
+			seen[t] = true
+			dates = append(dates, t)
+		}
+	}
+
+	// The removal and the import are one transaction, a failed import keeps
+	// the blocks already imported. Inside a request transaction this is a
+	// savepoint of it.
+	var tx pgx.Tx
+	if reqTx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx); ok {
+		tx, err = reqTx.Begin(context.Background())
+	} else {
+		tx, err = d.HomestayRoomRepository.PostgreDb.Begin(context.Background())
+	}
+	if err != nil {
+		return errors.Wrap(err, "begin ical feed transaction")
+	}
+	defer tx.Rollback(context.Background())
+
+	txCtx := context.WithValue(ctx, arbitary.TrxX{}, tx)
+
+	if err = d.HomestayRoomRepository.DeleteFeedBlocks(txCtx, feed.Id, dates); err != nil {
+		return errors.Wrap(err, "delete homestay room feed blocks")
+	}
+
+	if len(dates) != 0 {
+		if err = d.HomestayRoomRepository.SaveFeedBlocks(txCtx, feed.Id, feed.HomestayRoomId, dates, feed.Name); err != nil {
+			return errors.Wrap(err, "save homestay room feed blocks")
+		}
+	}
+
+	if err = tx.Commit(context.Background()); err != nil {
+		return errors.Wrap(err, "commit ical feed transaction")
+	}
+
+	return nil
+}
+
+// Pull the feed and record the outcome on it. The returned last error is
+// the reason the pull failed, empty on success, while the returned error
+// is only for failing to record the outcome.
+func (d *HomestayDeps) syncIcalFeed(ctx context.Context, feed HomestayRoomIcalFeedModel) (lastError string, err error) {
+	if err := d.pullIcalFeed(ctx, feed); err != nil {
+		lastError = err.Error()
+	}
+
+	if err = d.HomestayRoomIcalFeedRepository.UpdateSyncById(ctx, feed.Id, time.Now(), lastError); err != nil {
+		return "", errors.Wrap(err, "update homestay room ical feed sync by id")
+	}
+
+	return lastError, nil
+}
+
+// Sync every external calendar once, the failed ones are counted and
+// their reason is recorded on each feed
+func (d *HomestayDeps) SyncIcalFeeds(ctx context.Context) (failed int, err error) {
+	feeds, err := d.HomestayRoomIcalFeedRepository.QueryAll(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "query all homestay room ical feeds")
+	}
+
+	for _, f := range feeds {
+		lastError, err := d.syncIcalFeed(ctx, f)
+		if err != nil {
+			return failed, err
+		}
+		if lastError != "" {
+			failed++
+		}
+	}
+
+	return failed, nil
+}
+
+// Sync every external calendar on each interval until the context is done,
+// meant to be run in its own goroutine. The outcome of each feed is shown to
+// the owner as its last error and last sync time, a sync that could not be
+// recorded is retried on the next interval.
+func (d *HomestayDeps) RunIcalSync(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = d.SyncIcalFeeds(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type (
+	ExportHomestayRoomIcalRes struct {
+		Name   string
+		Events []ical.Event
+	}
+	ExportHomestayRoomIcalOut struct {
+		resp.Response
+		Res ExportHomestayRoomIcalRes
+	}
+)
+
+// The calendar of a room for other booking sites, it holds accepted
+// bookings and manual blocks. Blocks imported from other calendars are
+// left out so two sites that import each other do not echo forever, and
+// no guest detail is exposed.
+func (d *HomestayDeps) ExportHomestayRoomIcal(ctx context.Context, token string) (out ExportHomestayRoomIcalOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if token == "" {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayRoomNotFound)
+		return
+	}
+
+	room, err := d.HomestayRoomRepository.FindUndeletedByIcalToken(ctx, token)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayRoomNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find homestay room by ical token"))
+		return
+	}
+
+	memberHomestay, err := d.MemberHomestayRepository.FindUndeletedById(ctx, room.MemberHomestayId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayRoomNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member homestay by id"))
+		return
+	}
+
+	today := icalToday()
+	start := today.AddDate(0, 0, -icalExportPastDays)
+	end := today.AddDate(0, 0, icalHorizonDays)
+
+	bookings, err := d.HomestayBookingRepository.QueryAccepted(ctx, room.MemberHomestayId, start, end)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query accepted homestay bookings"))
+		return
+	}
+
+	blocks, err := d.HomestayRoomRepository.QueryBlocks(ctx, room.MemberHomestayId, start, end)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query homestay room blocks"))
+		return
+	}
+
+	events := []ical.Event{}
+	for _, b := range bookings {
+		if b.HomestayRoomId != room.Id {
+			continue
+		}
+
+		events = append(events, ical.Event{
+			Uid:     "booking-" + strconv.FormatUint(b.Id, 10) + "@uhomestay",
+			Summary: "Dipesan",
+			Start:   b.CheckIn,
+			End:     b.CheckOut,
+		})
+	}
+
+	// Blocks are ordered by date, consecutive dates are merged into one event
+	var blockEvent *ical.Event
+	for _, b := range blocks {
+		if b.HomestayRoomId != room.Id || b.HomestayRoomIcalFeedId.Valid {
+			continue
+		}
+
+		if blockEvent != nil && blockEvent.End.Equal(b.Date) {
+			blockEvent.End = b.Date.AddDate(0, 0, 1)
+			continue
+		}
+		if blockEvent != nil {
+			events = append(events, *blockEvent)
+		}
+
+		blockEvent = &ical.Event{
+			Uid:     "block-" + strconv.FormatUint(room.Id, 10) + "-" + b.Date.Format("20060102") + "@uhomestay",
+			Summary: "Tidak tersedia",
+			Start:   b.Date,
+			End:     b.Date.AddDate(0, 0, 1),
+		}
+	}
+	if blockEvent != nil {
+		events = append(events, *blockEvent)
+	}
+
+	out.Res = ExportHomestayRoomIcalRes{
+		Name:   memberHomestay.Name + " - " + room.Type,
+		Events: events,
+	}
+
+	return
+}
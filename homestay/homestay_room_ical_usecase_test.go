@@ -0,0 +1,285 @@
+package homestay_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/homestay"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+func icalDateFromNow(days int) string {
+	return time.Now().AddDate(0, 0, days).Format("20060102")
+}
+
+func TestAddHomestayRoomIcalFeed(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hid, err := createMemberHomestay(memberHomestayRepository, muid, homestaySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rid, err := createHomestayRoom(homestayRoomRepository, hid, roomSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Local stand-in of another booking site, the nights of [10, 12) days
+	// from now are taken there
+	calendar := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:elsewhere-1\r\n" +
+		"DTSTART;VALUE=DATE:" + icalDateFromNow(10) + "\r\n" +
+		"DTEND;VALUE=DATE:" + icalDateFromNow(12) + "\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/calendar.ics" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Write([]byte(calendar))
+	}))
+	defer srv.Close()
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		ExpectLastError    bool
+		In                 homestay.AddHomestayRoomIcalFeedIn
+	}{
+		{
+			Name:               "Add Homestay Room Ical Feed Success",
+			ExpectedStatusCode: http.StatusCreated,
+			In: homestay.AddHomestayRoomIcalFeedIn{
+				Name: "Elsewhere",
+				Url:  srv.URL + "/calendar.ics",
+			},
+		},
+		{
+			Name:               "Add Homestay Room Ical Feed Success, Unreachable Feed Recorded",
+			ExpectedStatusCode: http.StatusCreated,
+			ExpectLastError:    true,
+			In: homestay.AddHomestayRoomIcalFeedIn{
+				Name: "Missing",
+				Url:  srv.URL + "/missing.ics",
+			},
+		},
+		{
+			Name:               "Add Homestay Room Ical Feed Fail, Invalid Url",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: homestay.AddHomestayRoomIcalFeedIn{
+				Name: "Elsewhere",
+				Url:  "ftp://localhost/calendar.ics",
+			},
+		},
+		{
+			Name:               "Add Homestay Room Ical Feed Fail, Name Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: homestay.AddHomestayRoomIcalFeedIn{
+				Url: srv.URL + "/calendar.ics",
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := homestayDeps.AddHomestayRoomIcalFeed(context.Background(), muid, strconv.FormatInt(hid, 10), strconv.FormatInt(rid, 10), c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if (res.Res.LastError != "") != c.ExpectLastError {
+				t.Fatalf("Expected last error %t. Got %q\n", c.ExpectLastError, res.Res.LastError)
+			}
+		})
+	}
+
+	t.Run("Imported Feed Block Homestay Availability", func(t *testing.T) {
+		res := homestayDeps.QueryHomestayAvailability(context.Background(), strconv.FormatInt(hid, 10), dateFromNow(9), dateFromNow(12))
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		expected := []bool{true, false, false, true}
+		for i, v := range expected {
+			if res.Res.Days[i].IsAvailable != v {
+				t.Fatalf("Expected day %s availability %t. Got %t\n", res.Res.Days[i].Date, v, res.Res.Days[i].IsAvailable)
+			}
+		}
+	})
+
+	t.Run("Sync Homestay Room Ical Feeds Success, Follow Feed Change", func(t *testing.T) {
+		calendar = "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"
+
+		failed, err := homestayDeps.SyncIcalFeeds(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if failed != 1 {
+			t.Fatalf("Expected failed feeds 1. Got %d\n", failed)
+		}
+
+		res := homestayDeps.QueryHomestayAvailability(context.Background(), strconv.FormatInt(hid, 10), dateFromNow(10), dateFromNow(11))
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		for _, d := range res.Res.Days {
+			if !d.IsAvailable {
+				t.Fatalf("Expected day %s available after the feed event removed\n", d.Date)
+			}
+		}
+	})
+}
+
+func TestIcalHttpClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Write([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+	}))
+	defer srv.Close()
+
+	fetch := homestay.HttpIcalFetch(homestay.NewIcalHttpClient(5 * time.Second))
+
+	testCases := []struct {
+		Name string
+		Url  string
+	}{
+		{
+			Name: "Fetch Ical Fail, Loopback Address",
+			Url:  srv.URL,
+		},
+		{
+			Name: "Fetch Ical Fail, Private Address",
+			Url:  "http://10.0.0.1/calendar.ics",
+		},
+		{
+			Name: "Fetch Ical Fail, Link Local Address",
+			Url:  "http://169.254.169.254/latest/meta-data",
+		},
+		{
+			Name: "Fetch Ical Fail, Unspecified Address",
+			Url:  "http://0.0.0.0/calendar.ics",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			_, err := fetch(context.Background(), c.Url)
+			if !errors.Is(err, homestay.ErrIcalFeedAddressNotAllowed) {
+				t.Fatalf("Expected error %v. Got %v\n", homestay.ErrIcalFeedAddressNotAllowed, err)
+			}
+		})
+	}
+}
+
+func TestExportHomestayRoomIcal(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hid, err := createMemberHomestay(memberHomestayRepository, muid, homestaySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	room := homestayDeps.AddHomestayRoom(context.Background(), muid, strconv.FormatInt(hid, 10), homestay.AddHomestayRoomIn{
+		Type:            "Deluxe",
+		Capacity:        2,
+		IdrNightlyPrice: "250000",
+	})
+	if room.Error != nil {
+		t.Fatal(room.Error)
+	}
+	rid := strconv.FormatInt(room.Res.Id, 10)
+
+	// Manual block of two consecutive days, merged into one event
+	block := homestayDeps.BlockHomestayDates(context.Background(), muid, strconv.FormatInt(hid, 10), homestay.BlockHomestayDatesIn{
+		StartDate: dateFromNow(20),
+		EndDate:   dateFromNow(21),
+	})
+	if block.Error != nil {
+		t.Fatal(block.Error)
+	}
+
+	booking := homestayDeps.AddHomestayBooking(context.Background(), strconv.FormatInt(hid, 10), homestay.AddHomestayBookingIn{
+		RoomId:     room.Res.Id,
+		GuestName:  "Guest",
+		GuestPhone: "+62 821-2222-0000",
+		GuestCount: 1,
+		CheckIn:    dateFromNow(10),
+		CheckOut:   dateFromNow(12),
+	})
+	if booking.Error != nil {
+		t.Fatal(booking.Error)
+	}
+
+	decide := homestayDeps.DecideHomestayBooking(context.Background(), muid, strconv.FormatInt(hid, 10), strconv.FormatInt(booking.Res.Id, 10), homestay.DecideHomestayBookingIn{
+		IsAccepted: null.BoolFrom(true),
+	})
+	if decide.Error != nil {
+		t.Fatal(decide.Error)
+	}
+
+	info := homestayDeps.QueryHomestayRoomIcal(context.Background(), muid, strconv.FormatInt(hid, 10), rid)
+	if info.Error != nil {
+		t.Fatal(info.Error)
+	}
+
+	token := strings.TrimSuffix(strings.TrimPrefix(info.Res.ExportPath, "/api/v1/calendars/"), ".ics")
+
+	t.Run("Export Homestay Room Ical Success", func(t *testing.T) {
+		res := homestayDeps.ExportHomestayRoomIcal(context.Background(), token)
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		if len(res.Res.Events) != 2 {
+			t.Fatalf("Expected events length 2. Got %d\n", len(res.Res.Events))
+		}
+
+		for _, e := range res.Res.Events {
+			if nights := int(e.End.Sub(e.Start).Hours() / 24); nights != 2 {
+				t.Fatalf("Expected event of 2 nights. Got %d\n", nights)
+			}
+		}
+	})
+
+	t.Run("Export Homestay Room Ical Fail, Rotated Token", func(t *testing.T) {
+		rotate := homestayDeps.RotateHomestayRoomIcalToken(context.Background(), muid, strconv.FormatInt(hid, 10), rid)
+		if rotate.Error != nil {
+			t.Fatal(rotate.Error)
+		}
+
+		res := homestayDeps.ExportHomestayRoomIcal(context.Background(), token)
+		if res.StatusCode != http.StatusNotFound {
+			t.Fatalf("Expected response code %d. Got %d\n", http.StatusNotFound, res.StatusCode)
+		}
+	})
+}
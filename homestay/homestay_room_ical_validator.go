@@ -0,0 +1,59 @@
+package homestay
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrIcalFeedNameRequired = errors.New("nama kalender tidak boleh kosong")
+	ErrMaxIcalFeedName      = errors.New("nama kalender tidak dapat lebih dari 100 karakter")
+	ErrIcalFeedUrlRequired  = errors.New("url kalender tidak boleh kosong")
+	ErrInvalidIcalFeedUrl   = errors.New("url kalender harus berupa url http atau https")
+	// Given when the feed host resolves to an address of the server or its
+	// private network
+	ErrIcalFeedAddressNotAllowed = errors.New("alamat kalender tidak diizinkan")
+)
+
+func ValidateAddHomestayRoomIcalFeedIn(i AddHomestayRoomIcalFeedIn) error {
+	g := new(errgroup.Group)
+
+	g.Go(func() error {
+		if strings.Trim(i.Name, " ") == "" {
+			return ErrIcalFeedNameRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Name) > 100 {
+			return ErrMaxIcalFeedName
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.Url, " ") == "" {
+			return ErrIcalFeedUrlRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.Url, " ") == "" {
+			return nil
+		}
+
+		u, err := url.Parse(i.Url)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidIcalFeedUrl
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return nil
+}
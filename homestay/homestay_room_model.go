@@ -0,0 +1,37 @@
+package homestay
+
+import (
+	"database/sql"
+	"time"
+)
+
+type HomestayRoomModel struct {
+	Id               uint64
+	Type             string
+	Capacity         int64
+	IdrNightlyPrice  int64
+	MemberHomestayId uint64
+	IcalToken        string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        sql.NullTime
+}
+
+type HomestayRoomBlockModel struct {
+	HomestayRoomId         uint64
+	Date                   time.Time
+	Note                   string
+	HomestayRoomIcalFeedId sql.NullInt64
+	CreatedAt              time.Time
+}
+
+type HomestayRoomIcalFeedModel struct {
+	Id             uint64
+	HomestayRoomId uint64
+	Name           string
+	Url            string
+	LastSyncedAt   sql.NullTime
+	LastError      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
@@ -0,0 +1,489 @@
+package homestay
+
+import (
+	"context"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type HomestayRoomRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewHomestayRoomRepository(postgreDb *pgxpool.Pool) *HomestayRoomRepository {
+	return &HomestayRoomRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+type (
+	HomestayRoomExecutor   func(ctx context.Context, sql string, arguments ...interface{}) (commandTag pgconn.CommandTag, err error)
+	HomestayRoomQuerierRow func(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	HomestayRoomQuerier    func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+)
+
+func (r *HomestayRoomRepository) Save(ctx context.Context, m HomestayRoomModel) (nm HomestayRoomModel, err error) {
+	sqlQuery := `
+		INSERT INTO homestay_rooms (
+			type,
+			capacity,
+			idr_nightly_price,
+			member_homestay_id,
+			ical_token,
+			created_at,
+			updated_at,
+			deleted_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	var queryRow HomestayRoomQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	var lastInsertId uint64
+	t := time.Now()
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		m.Type,
+		m.Capacity,
+		m.IdrNightlyPrice,
+		m.MemberHomestayId,
+		m.IcalToken,
+		t,
+		t,
+		nil,
+	).Scan(&lastInsertId)
+
+	if err != nil {
+		return HomestayRoomModel{}, err
+	}
+
+	m.Id = lastInsertId
+	m.CreatedAt = t
+	m.UpdatedAt = t
+
+	return m, nil
+}
+
+func (r *HomestayRoomRepository) UpdateById(ctx context.Context, homestayId, id uint64, m HomestayRoomModel) error {
+	sqlQuery := `
+		UPDATE homestay_rooms SET (
+			type,
+			capacity,
+			idr_nightly_price,
+			updated_at
+		) = ($1, $2, $3, $4)
+		WHERE member_homestay_id = $5 AND id = $6
+	`
+
+	var exec HomestayRoomExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		m.Type,
+		m.Capacity,
+		m.IdrNightlyPrice,
+		time.Now(),
+		homestayId,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *HomestayRoomRepository) FindUndeletedById(ctx context.Context, homestayId, id uint64) (m HomestayRoomModel, err error) {
+	querystr := `
+		SELECT
+			id,
+			type,
+			capacity,
+			idr_nightly_price,
+			member_homestay_id,
+			ical_token,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM homestay_rooms
+		WHERE deleted_at IS NULL
+		AND member_homestay_id = $1 AND id = $2
+	`
+
+	var query HomestayRoomQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+	rows, err = query(
+		context.Background(),
+		querystr,
+		homestayId,
+		id,
+	)
+
+	if err != nil {
+		return HomestayRoomModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return HomestayRoomModel{}, err
+	}
+
+	return m, nil
+}
+
+func (r *HomestayRoomRepository) QueryByHomestayId(ctx context.Context, homestayId uint64) ([]HomestayRoomModel, error) {
+	sqlQuery := `
+		SELECT
+			id,
+			type,
+			capacity,
+			idr_nightly_price,
+			member_homestay_id,
+			ical_token,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM homestay_rooms
+		WHERE deleted_at IS NULL
+			AND member_homestay_id = $1
+		ORDER BY id ASC
+	`
+
+	var query HomestayRoomQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	rows, _ := query(
+		context.Background(),
+		sqlQuery,
+		homestayId,
+	)
+	defer rows.Close()
+
+	var mps []*HomestayRoomModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []HomestayRoomModel{}, err
+	}
+
+	ms := make([]HomestayRoomModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *HomestayRoomRepository) FindUndeletedByIcalToken(ctx context.Context, token string) (m HomestayRoomModel, err error) {
+	querystr := `
+		SELECT
+			hr.id,
+			hr.type,
+			hr.capacity,
+			hr.idr_nightly_price,
+			hr.member_homestay_id,
+			hr.ical_token,
+			hr.created_at,
+			hr.updated_at,
+			hr.deleted_at
+		FROM homestay_rooms hr
+			JOIN member_homestays mh ON mh.id = hr.member_homestay_id
+		WHERE hr.deleted_at IS NULL
+			AND mh.deleted_at IS NULL
+			AND hr.ical_token <> ''
+			AND hr.ical_token = $1
+	`
+
+	rows, err := r.PostgreDb.Query(
+		context.Background(),
+		querystr,
+		token,
+	)
+	if err != nil {
+		return HomestayRoomModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return HomestayRoomModel{}, err
+	}
+
+	return m, nil
+}
+
+func (r *HomestayRoomRepository) UpdateIcalTokenById(ctx context.Context, homestayId, id uint64, token string) error {
+	sqlQuery := `
+		UPDATE homestay_rooms SET (
+			ical_token,
+			updated_at
+		) = ($1, $2)
+		WHERE member_homestay_id = $3 AND id = $4
+	`
+
+	var exec HomestayRoomExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		token,
+		time.Now(),
+		homestayId,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *HomestayRoomRepository) DeleteById(ctx context.Context, homestayId, id uint64) error {
+	sqlQuery := `
+		UPDATE homestay_rooms
+		SET deleted_at = $1
+		WHERE member_homestay_id = $2
+		AND id = $3
+	`
+
+	var exec HomestayRoomExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		time.Now(),
+		homestayId,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Block every date from start to end, both inclusive, for each of the
+// given rooms. Blocking an already blocked date replace its note and
+// turn a block imported from an iCal feed into a manual one.
+func (r *HomestayRoomRepository) SaveBlocks(ctx context.Context, roomIds []uint64, start, end time.Time, note string) error {
+	sqlQuery := `
+		INSERT INTO homestay_room_blocks (
+			homestay_room_id,
+			date,
+			note,
+			created_at
+		)
+		SELECT r.id, d::date, $4::text, $5::timestamp
+		FROM unnest($1::bigint[]) AS r(id)
+			CROSS JOIN generate_series($2::date, $3::date, '1 day'::interval) AS d
+		ON CONFLICT (homestay_room_id, date) DO UPDATE SET (
+			note,
+			homestay_room_ical_feed_id
+		) = (EXCLUDED.note, NULL)
+	`
+
+	var exec HomestayRoomExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		roomIds,
+		start,
+		end,
+		note,
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *HomestayRoomRepository) DeleteBlocks(ctx context.Context, roomIds []uint64, start, end time.Time) error {
+	sqlQuery := `
+		DELETE FROM homestay_room_blocks
+		WHERE homestay_room_id = ANY($1::bigint[])
+		AND date BETWEEN $2::date AND $3::date
+	`
+
+	var exec HomestayRoomExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		roomIds,
+		start,
+		end,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *HomestayRoomRepository) QueryBlocks(ctx context.Context, homestayId uint64, start, end time.Time) ([]HomestayRoomBlockModel, error) {
+	sqlQuery := `
+		SELECT
+			b.homestay_room_id,
+			b.date,
+			b.note,
+			b.homestay_room_ical_feed_id,
+			b.created_at
+		FROM homestay_room_blocks b
+			JOIN homestay_rooms r ON r.id = b.homestay_room_id
+		WHERE r.deleted_at IS NULL
+			AND r.member_homestay_id = $1
+			AND b.date BETWEEN $2::date AND $3::date
+		ORDER BY b.date ASC, b.homestay_room_id ASC
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		homestayId,
+		start,
+		end,
+	)
+	defer rows.Close()
+
+	var mps []*HomestayRoomBlockModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []HomestayRoomBlockModel{}, err
+	}
+
+	ms := make([]HomestayRoomBlockModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+// Block the given dates of the room on behalf of an iCal feed. A date
+// that is already blocked, manually or by another feed, is left as is.
+func (r *HomestayRoomRepository) SaveFeedBlocks(ctx context.Context, feedId, roomId uint64, dates []time.Time, note string) error {
+	sqlQuery := `
+		INSERT INTO homestay_room_blocks (
+			homestay_room_id,
+			date,
+			note,
+			homestay_room_ical_feed_id,
+			created_at
+		)
+		SELECT $1::bigint, d, $3::text, $4::bigint, $5::timestamp
+		FROM unnest($2::date[]) AS d
+		ON CONFLICT (homestay_room_id, date) DO NOTHING
+	`
+
+	var exec HomestayRoomExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		roomId,
+		dates,
+		note,
+		feedId,
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Remove the blocks imported by the feed, except the ones on the given
+// dates. Empty dates remove every block of the feed.
+func (r *HomestayRoomRepository) DeleteFeedBlocks(ctx context.Context, feedId uint64, keep []time.Time) error {
+	sqlQuery := `
+		DELETE FROM homestay_room_blocks
+		WHERE homestay_room_ical_feed_id = $1
+		AND NOT (date = ANY($2::date[]))
+	`
+
+	var exec HomestayRoomExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	if keep == nil {
+		keep = []time.Time{}
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		feedId,
+		keep,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package homestay
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/go-chi/chi/v5"
+)
+
+func (d *HomestayDeps) PostHomestayRoom(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	decoder := json.NewDecoder(r.Body)
+
+	var in AddHomestayRoomIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.AddHomestayRoom(r.Context(), jwtPayload.Uid, id, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) PutHomestayRoom(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	rid := chi.URLParam(r, "rid")
+	decoder := json.NewDecoder(r.Body)
+
+	var in EditHomestayRoomIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.EditHomestayRoom(r.Context(), jwtPayload.Uid, id, rid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) DeleteHomestayRoom(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	rid := chi.URLParam(r, "rid")
+	out := d.RemoveHomestayRoom(r.Context(), jwtPayload.Uid, id, rid)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) GetHomestayRooms(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	out := d.QueryHomestayRooms(r.Context(), id)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) PostHomestayBlocks(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	decoder := json.NewDecoder(r.Body)
+
+	var in BlockHomestayDatesIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.BlockHomestayDates(r.Context(), jwtPayload.Uid, id, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) DeleteHomestayBlocks(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	out := d.UnblockHomestayDates(r.Context(), jwtPayload.Uid, id, UnblockHomestayDatesQIn{
+		RoomIds:   r.URL.Query()["room_id"],
+		StartDate: r.URL.Query().Get("start_date"),
+		EndDate:   r.URL.Query().Get("end_date"),
+	})
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *HomestayDeps) GetHomestayAvailability(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	startDate := r.URL.Query().Get("start_date")
+	endDate := r.URL.Query().Get("end_date")
+	out := d.QueryHomestayAvailability(r.Context(), id, startDate, endDate)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
@@ -0,0 +1,559 @@
+package homestay
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrHomestayRoomNotFound  = errors.New("kamar homestay tidak ditemukan")
+	ErrDateFormat            = errors.New("format tanggal tidak sesuai <tahun>-<bulan>-<hari>")
+	ErrEndDateLowerThanStart = errors.New("tanggal berakhir tidak boleh sebelum tanggal mulai")
+	ErrMaxDateRange          = errors.New("rentang tanggal tidak dapat lebih dari 366 hari")
+)
+
+// Maximum number of days, both ends inclusive, that can be blocked or
+// queried for availability in one request
+const maxDateRangeDays = 366
+
+func ParseDateRange(startDate, endDate string) (start, end time.Time, err error) {
+	if start, err = time.Parse("2006-01-02", startDate); err != nil {
+		return time.Time{}, time.Time{}, ErrDateFormat
+	}
+	if end, err = time.Parse("2006-01-02", endDate); err != nil {
+		return time.Time{}, time.Time{}, ErrDateFormat
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, ErrEndDateLowerThanStart
+	}
+	if end.Sub(start) >= maxDateRangeDays*24*time.Hour {
+		return time.Time{}, time.Time{}, ErrMaxDateRange
+	}
+
+	return start, end, nil
+}
+
+// Find the homestay with the given id only if it is owned by the member
+// with the given uid
+func (d *HomestayDeps) findOwnedHomestay(ctx context.Context, uid, hid string) (m MemberHomestayModel, res resp.Response) {
+	res = resp.NewResponse(http.StatusOK, "", nil)
+
+	if _, err := uuid.FromString(uid); err != nil {
+		res = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	id, err := strconv.ParseUint(hid, 10, 64)
+	if err != nil {
+		res = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+
+	m, err = d.MemberHomestayRepository.FindById(ctx, uid, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		res = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+	if err != nil {
+		res = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member homestay by id"))
+		return
+	}
+
+	return
+}
+
+type (
+	AddHomestayRoomIn struct {
+		Type            string `json:"type"`
+		Capacity        int64  `json:"capacity"`
+		IdrNightlyPrice string `json:"idr_nightly_price"`
+	}
+	AddHomestayRoomRes struct {
+		Id int64 `json:"id"`
+	}
+	AddHomestayRoomOut struct {
+		resp.Response
+		Res AddHomestayRoomRes
+	}
+)
+
+func (d *HomestayDeps) AddHomestayRoom(ctx context.Context, uid, hid string, in AddHomestayRoomIn) (out AddHomestayRoomOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusCreated, "", nil)
+
+	if err = ValidateAddHomestayRoomIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	memberHomestay, res := d.findOwnedHomestay(ctx, uid, hid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	price, err := strconv.ParseInt(in.IdrNightlyPrice, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidNightlyPrice)
+		return
+	}
+
+	icalToken, err := newIcalToken()
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "generate ical token"))
+		return
+	}
+
+	room := HomestayRoomModel{
+		Type:             in.Type,
+		Capacity:         in.Capacity,
+		IdrNightlyPrice:  price,
+		MemberHomestayId: memberHomestay.Id,
+		IcalToken:        icalToken,
+	}
+	if room, err = d.HomestayRoomRepository.Save(ctx, room); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save homestay room"))
+		return
+	}
+
+	out.Res.Id = int64(room.Id)
+
+	return
+}
+
+type (
+	EditHomestayRoomIn struct {
+		Type            string `json:"type"`
+		Capacity        int64  `json:"capacity"`
+		IdrNightlyPrice string `json:"idr_nightly_price"`
+	}
+	EditHomestayRoomRes struct {
+		Id int64 `json:"id"`
+	}
+	EditHomestayRoomOut struct {
+		resp.Response
+		Res EditHomestayRoomRes
+	}
+)
+
+func (d *HomestayDeps) EditHomestayRoom(ctx context.Context, uid, hid, rid string, in EditHomestayRoomIn) (out EditHomestayRoomOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(rid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayRoomNotFound)
+		return
+	}
+
+	if err = ValidateEditHomestayRoomIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	memberHomestay, res := d.findOwnedHomestay(ctx, uid, hid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	room, err := d.HomestayRoomRepository.FindUndeletedById(ctx, memberHomestay.Id, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayRoomNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find homestay room by id"))
+		return
+	}
+
+	room.Type = in.Type
+	room.Capacity = in.Capacity
+	if room.IdrNightlyPrice, err = strconv.ParseInt(in.IdrNightlyPrice, 10, 64); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidNightlyPrice)
+		return
+	}
+
+	if err = d.HomestayRoomRepository.UpdateById(ctx, memberHomestay.Id, id, room); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update homestay room by id"))
+		return
+	}
+
+	out.Res.Id = int64(room.Id)
+
+	return
+}
+
+type (
+	RemoveHomestayRoomRes struct {
+		Id int64 `json:"id"`
+	}
+	RemoveHomestayRoomOut struct {
+		resp.Response
+		Res RemoveHomestayRoomRes
+	}
+)
+
+func (d *HomestayDeps) RemoveHomestayRoom(ctx context.Context, uid, hid, rid string) (out RemoveHomestayRoomOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(rid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayRoomNotFound)
+		return
+	}
+
+	memberHomestay, res := d.findOwnedHomestay(ctx, uid, hid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	_, err = d.HomestayRoomRepository.FindUndeletedById(ctx, memberHomestay.Id, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayRoomNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find homestay room by id"))
+		return
+	}
+
+	if err = d.HomestayRoomRepository.DeleteById(ctx, memberHomestay.Id, id); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "delete homestay room by id"))
+		return
+	}
+
+	out.Res.Id = int64(id)
+
+	return
+}
+
+type (
+	HomestayRoomOut struct {
+		Id              int64  `json:"id"`
+		Type            string `json:"type"`
+		Capacity        int64  `json:"capacity"`
+		IdrNightlyPrice string `json:"idr_nightly_price"`
+	}
+	QueryHomestayRoomsRes struct {
+		Total int64             `json:"total"`
+		Rooms []HomestayRoomOut `json:"rooms"`
+	}
+	QueryHomestayRoomsOut struct {
+		resp.Response
+		Res QueryHomestayRoomsRes
+	}
+)
+
+func toHomestayRoomsOut(rooms []HomestayRoomModel) []HomestayRoomOut {
+	outRooms := make([]HomestayRoomOut, len(rooms))
+	for i, r := range rooms {
+		outRooms[i] = HomestayRoomOut{
+			Id:              int64(r.Id),
+			Type:            r.Type,
+			Capacity:        r.Capacity,
+			IdrNightlyPrice: strconv.FormatInt(r.IdrNightlyPrice, 10),
+		}
+	}
+
+	return outRooms
+}
+
+func (d *HomestayDeps) QueryHomestayRooms(ctx context.Context, hid string) (out QueryHomestayRoomsOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(hid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+
+	_, err = d.MemberHomestayRepository.FindApprovedById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member homestay by id"))
+		return
+	}
+
+	rooms, err := d.HomestayRoomRepository.QueryByHomestayId(ctx, id)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query homestay rooms"))
+		return
+	}
+
+	out.Res = QueryHomestayRoomsRes{
+		Total: int64(len(rooms)),
+		Rooms: toHomestayRoomsOut(rooms),
+	}
+
+	return
+}
+
+type (
+	BlockHomestayDatesIn struct {
+		RoomIds   []int64 `json:"room_ids"`
+		StartDate string  `json:"start_date"`
+		EndDate   string  `json:"end_date"`
+		Note      string  `json:"note"`
+	}
+	BlockHomestayDatesRes struct {
+		Id int64 `json:"id"`
+	}
+	BlockHomestayDatesOut struct {
+		resp.Response
+		Res BlockHomestayDatesRes
+	}
+)
+
+// Resolve the given room ids against the rooms of the homestay, empty
+// room ids mean every room of the homestay
+func (d *HomestayDeps) resolveRoomIds(ctx context.Context, homestayId uint64, roomIds []int64) ([]uint64, resp.Response) {
+	rooms, err := d.HomestayRoomRepository.QueryByHomestayId(ctx, homestayId)
+	if err != nil {
+		return nil, resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query homestay rooms"))
+	}
+
+	owned := make(map[uint64]bool, len(rooms))
+	for _, r := range rooms {
+		owned[r.Id] = true
+	}
+
+	var ids []uint64
+	if len(roomIds) == 0 {
+		for _, r := range rooms {
+			ids = append(ids, r.Id)
+		}
+	}
+	for _, v := range roomIds {
+		if v < 1 || !owned[uint64(v)] {
+			return nil, resp.NewResponse(http.StatusNotFound, "", ErrHomestayRoomNotFound)
+		}
+
+		ids = append(ids, uint64(v))
+	}
+
+	if len(ids) == 0 {
+		return nil, resp.NewResponse(http.StatusNotFound, "", ErrHomestayRoomNotFound)
+	}
+
+	return ids, resp.NewResponse(http.StatusOK, "", nil)
+}
+
+func (d *HomestayDeps) BlockHomestayDates(ctx context.Context, uid, hid string, in BlockHomestayDatesIn) (out BlockHomestayDatesOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusCreated, "", nil)
+
+	if err = ValidateBlockHomestayDatesIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	start, end, err := ParseDateRange(in.StartDate, in.EndDate)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	memberHomestay, res := d.findOwnedHomestay(ctx, uid, hid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	roomIds, res := d.resolveRoomIds(ctx, memberHomestay.Id, in.RoomIds)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	if err = d.HomestayRoomRepository.SaveBlocks(ctx, roomIds, start, end, in.Note); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save homestay room blocks"))
+		return
+	}
+
+	out.Res.Id = int64(memberHomestay.Id)
+
+	return
+}
+
+type (
+	UnblockHomestayDatesQIn struct {
+		RoomIds   []string
+		StartDate string
+		EndDate   string
+	}
+	UnblockHomestayDatesRes struct {
+		Id int64 `json:"id"`
+	}
+	UnblockHomestayDatesOut struct {
+		resp.Response
+		Res UnblockHomestayDatesRes
+	}
+)
+
+func (d *HomestayDeps) UnblockHomestayDates(ctx context.Context, uid, hid string, qin UnblockHomestayDatesQIn) (out UnblockHomestayDatesOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	start, end, err := ParseDateRange(qin.StartDate, qin.EndDate)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	var inRoomIds []int64
+	for _, v := range qin.RoomIds {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusNotFound, "", ErrHomestayRoomNotFound)
+			return
+		}
+
+		inRoomIds = append(inRoomIds, id)
+	}
+
+	memberHomestay, res := d.findOwnedHomestay(ctx, uid, hid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	roomIds, res := d.resolveRoomIds(ctx, memberHomestay.Id, inRoomIds)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	if err = d.HomestayRoomRepository.DeleteBlocks(ctx, roomIds, start, end); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "delete homestay room blocks"))
+		return
+	}
+
+	out.Res.Id = int64(memberHomestay.Id)
+
+	return
+}
+
+type (
+	HomestayAvailabilityDayOut struct {
+		Date             string  `json:"date"`
+		IsAvailable      bool    `json:"is_available"`
+		AvailableRoomIds []int64 `json:"available_room_ids"`
+	}
+	QueryHomestayAvailabilityRes struct {
+		StartDate string                       `json:"start_date"`
+		EndDate   string                       `json:"end_date"`
+		Rooms     []HomestayRoomOut            `json:"rooms"`
+		Days      []HomestayAvailabilityDayOut `json:"days"`
+	}
+	QueryHomestayAvailabilityOut struct {
+		resp.Response
+		Res QueryHomestayAvailabilityRes
+	}
+)
+
+func (d *HomestayDeps) QueryHomestayAvailability(ctx context.Context, hid, startDate, endDate string) (out QueryHomestayAvailabilityOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(hid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+
+	start, end, err := ParseDateRange(startDate, endDate)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	_, err = d.MemberHomestayRepository.FindApprovedById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member homestay by id"))
+		return
+	}
+
+	rooms, err := d.HomestayRoomRepository.QueryByHomestayId(ctx, id)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query homestay rooms"))
+		return
+	}
+
+	blocks, err := d.HomestayRoomRepository.QueryBlocks(ctx, id, start, end)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query homestay room blocks"))
+		return
+	}
+
+	bookings, err := d.HomestayBookingRepository.QueryAccepted(ctx, id, start, end)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query accepted homestay bookings"))
+		return
+	}
+
+	blocked := make(map[string]map[uint64]bool)
+	block := func(t time.Time, roomId uint64) {
+		date := t.Format("2006-01-02")
+		if blocked[date] == nil {
+			blocked[date] = make(map[uint64]bool)
+		}
+
+		blocked[date][roomId] = true
+	}
+
+	for _, b := range blocks {
+		block(b.Date, b.HomestayRoomId)
+	}
+	// The check out date is not a booked night
+	for _, b := range bookings {
+		for t := b.CheckIn; t.Before(b.CheckOut); t = t.AddDate(0, 0, 1) {
+			block(t, b.HomestayRoomId)
+		}
+	}
+
+	var days []HomestayAvailabilityDayOut
+	for t := start; !t.After(end); t = t.AddDate(0, 0, 1) {
+		date := t.Format("2006-01-02")
+
+		availableRoomIds := make([]int64, 0, len(rooms))
+		for _, r := range rooms {
+			if !blocked[date][r.Id] {
+				availableRoomIds = append(availableRoomIds, int64(r.Id))
+			}
+		}
+
+		days = append(days, HomestayAvailabilityDayOut{
+			Date:             date,
+			IsAvailable:      len(availableRoomIds) != 0,
+			AvailableRoomIds: availableRoomIds,
+		})
+	}
+
+	out.Res = QueryHomestayAvailabilityRes{
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   end.Format("2006-01-02"),
+		Rooms:     toHomestayRoomsOut(rooms),
+		Days:      days,
+	}
+
+	return
+}
@@ -0,0 +1,296 @@
+package homestay_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/homestay"
+)
+
+func TestAddHomestayRoom(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherMember := memberSeed
+	otherMember.Username = "othermember"
+	otherMember.WaPhone = "+62 821-1111-0001"
+	otherMember.OtherPhone = "+62 821-1111-0001"
+	otherUid, err := createUser(memberRepository, otherMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hid, err := createMemberHomestay(memberHomestayRepository, muid, homestaySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Uid                string
+		Hid                string
+		In                 homestay.AddHomestayRoomIn
+	}{
+		{
+			Name:               "Add Homestay Room Success",
+			ExpectedStatusCode: http.StatusCreated,
+			Uid:                muid,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.AddHomestayRoomIn{
+				Type:            "Deluxe",
+				Capacity:        2,
+				IdrNightlyPrice: "250000",
+			},
+		},
+		{
+			Name:               "Add Homestay Room Fail, Not the Owner",
+			ExpectedStatusCode: http.StatusNotFound,
+			Uid:                otherUid,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.AddHomestayRoomIn{
+				Type:            "Deluxe",
+				Capacity:        2,
+				IdrNightlyPrice: "250000",
+			},
+		},
+		{
+			Name:               "Add Homestay Room Fail, Type Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Uid:                muid,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.AddHomestayRoomIn{
+				Type:            "",
+				Capacity:        2,
+				IdrNightlyPrice: "250000",
+			},
+		},
+		{
+			Name:               "Add Homestay Room Fail, Zero Capacity",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Uid:                muid,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.AddHomestayRoomIn{
+				Type:            "Deluxe",
+				Capacity:        0,
+				IdrNightlyPrice: "250000",
+			},
+		},
+		{
+			Name:               "Add Homestay Room Fail, Invalid Price",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Uid:                muid,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.AddHomestayRoomIn{
+				Type:            "Deluxe",
+				Capacity:        2,
+				IdrNightlyPrice: "-1",
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := homestayDeps.AddHomestayRoom(context.Background(), c.Uid, c.Hid, c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestBlockHomestayDates(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hid, err := createMemberHomestay(memberHomestayRepository, muid, homestaySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rid, err := createHomestayRoom(homestayRoomRepository, hid, roomSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Uid                string
+		Hid                string
+		In                 homestay.BlockHomestayDatesIn
+	}{
+		{
+			Name:               "Block Homestay Dates Success",
+			ExpectedStatusCode: http.StatusCreated,
+			Uid:                muid,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.BlockHomestayDatesIn{
+				RoomIds:   []int64{rid},
+				StartDate: "2026-12-24",
+				EndDate:   "2026-12-26",
+				Note:      "Renovasi",
+			},
+		},
+		{
+			Name:               "Block Homestay Dates Success, All Rooms",
+			ExpectedStatusCode: http.StatusCreated,
+			Uid:                muid,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.BlockHomestayDatesIn{
+				StartDate: "2026-12-31",
+				EndDate:   "2026-12-31",
+			},
+		},
+		{
+			Name:               "Block Homestay Dates Fail, Room of Other Homestay",
+			ExpectedStatusCode: http.StatusNotFound,
+			Uid:                muid,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.BlockHomestayDatesIn{
+				RoomIds:   []int64{rid + 1},
+				StartDate: "2026-12-24",
+				EndDate:   "2026-12-26",
+			},
+		},
+		{
+			Name:               "Block Homestay Dates Fail, End Date Before Start Date",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Uid:                muid,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.BlockHomestayDatesIn{
+				StartDate: "2026-12-26",
+				EndDate:   "2026-12-24",
+			},
+		},
+		{
+			Name:               "Block Homestay Dates Fail, Invalid Date Format",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Uid:                muid,
+			Hid:                strconv.FormatInt(hid, 10),
+			In: homestay.BlockHomestayDatesIn{
+				StartDate: "24-12-2026",
+				EndDate:   "26-12-2026",
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := homestayDeps.BlockHomestayDates(context.Background(), c.Uid, c.Hid, c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestQueryHomestayAvailability(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hid, err := createMemberHomestay(memberHomestayRepository, muid, homestaySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rid, err := createHomestayRoom(homestayRoomRepository, hid, roomSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := homestayDeps.BlockHomestayDates(context.Background(), muid, strconv.FormatInt(hid, 10), homestay.BlockHomestayDatesIn{
+		RoomIds:   []int64{rid},
+		StartDate: "2026-12-24",
+		EndDate:   "2026-12-25",
+	})
+	if res.Error != nil {
+		t.Fatal(res.Error)
+	}
+
+	testCases := []struct {
+		Name                string
+		ExpectedStatusCode  int
+		ExpectedDays        int
+		ExpectedUnavailable int
+		Hid                 string
+		StartDate           string
+		EndDate             string
+	}{
+		{
+			Name:                "Query Homestay Availability Success",
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedDays:        4,
+			ExpectedUnavailable: 2,
+			Hid:                 strconv.FormatInt(hid, 10),
+			StartDate:           "2026-12-23",
+			EndDate:             "2026-12-26",
+		},
+		{
+			Name:               "Query Homestay Availability Fail, Homestay not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Hid:                "99999",
+			StartDate:          "2026-12-23",
+			EndDate:            "2026-12-26",
+		},
+		{
+			Name:               "Query Homestay Availability Fail, Range too Long",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Hid:                strconv.FormatInt(hid, 10),
+			StartDate:          "2026-01-01",
+			EndDate:            "2027-12-31",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := homestayDeps.QueryHomestayAvailability(context.Background(), c.Hid, c.StartDate, c.EndDate)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if len(res.Res.Days) != c.ExpectedDays {
+				t.Fatalf("Expected days length %d. Got %d\n", c.ExpectedDays, len(res.Res.Days))
+			}
+
+			var unavailable int
+			for _, d := range res.Res.Days {
+				if !d.IsAvailable {
+					unavailable++
+				}
+			}
+			if unavailable != c.ExpectedUnavailable {
+				t.Fatalf("Expected unavailable days %d. Got %d\n", c.ExpectedUnavailable, unavailable)
+			}
+		})
+	}
+}
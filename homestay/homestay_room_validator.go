@@ -0,0 +1,100 @@
+package homestay
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrRoomTypeRequired     = errors.New("tipe kamar tidak boleh kosong")
+	ErrMaxRoomType          = errors.New("tipe kamar tidak dapat lebih dari 100 karakter")
+	ErrInvalidRoomCapacity  = errors.New("kapasitas kamar harus lebih dari 0")
+	ErrNightlyPriceRequired = errors.New("harga per malam tidak boleh kosong")
+	ErrInvalidNightlyPrice  = errors.New("harga per malam harus berupa angka rupiah tidak negatif")
+	ErrStartDateRequired    = errors.New("tanggal mulai tidak boleh kosong")
+	ErrEndDateRequired      = errors.New("tanggal berakhir tidak boleh kosong")
+	ErrMaxRoomBlockNote     = errors.New("catatan penutupan tanggal tidak dapat lebih dari 200 karakter")
+)
+
+func validateRoomIn(roomType string, capacity int64, idrNightlyPrice string) error {
+	g := new(errgroup.Group)
+
+	g.Go(func() error {
+		if strings.Trim(roomType, " ") == "" {
+			return ErrRoomTypeRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(roomType) > 100 {
+			return ErrMaxRoomType
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if capacity < 1 {
+			return ErrInvalidRoomCapacity
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(idrNightlyPrice, " ") == "" {
+			return ErrNightlyPriceRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(idrNightlyPrice, " ") == "" {
+			return nil
+		}
+		if p, err := strconv.ParseInt(idrNightlyPrice, 10, 64); err != nil || p < 0 {
+			return ErrInvalidNightlyPrice
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ValidateAddHomestayRoomIn(i AddHomestayRoomIn) error {
+	return validateRoomIn(i.Type, i.Capacity, i.IdrNightlyPrice)
+}
+
+func ValidateEditHomestayRoomIn(i EditHomestayRoomIn) error {
+	return validateRoomIn(i.Type, i.Capacity, i.IdrNightlyPrice)
+}
+
+func ValidateBlockHomestayDatesIn(i BlockHomestayDatesIn) error {
+	g := new(errgroup.Group)
+
+	g.Go(func() error {
+		if strings.Trim(i.StartDate, " ") == "" {
+			return ErrStartDateRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.EndDate, " ") == "" {
+			return ErrEndDateRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Note) > 200 {
+			return ErrMaxRoomBlockNote
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return nil
+}
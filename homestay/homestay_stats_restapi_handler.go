@@ -0,0 +1,15 @@
+package homestay
+
+import (
+	"net/http"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+)
+
+func (d *HomestayDeps) GetHomestayStats(w http.ResponseWriter, r *http.Request) {
+	homestayId := r.URL.Query().Get("homestay_id")
+	startMonth := r.URL.Query().Get("start_month")
+	endMonth := r.URL.Query().Get("end_month")
+	out := d.QueryHomestayStats(r.Context(), homestayId, startMonth, endMonth)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
@@ -0,0 +1,151 @@
+package homestay
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrMonthFormat            = errors.New("format bulan tidak sesuai <tahun>-<bulan>")
+	ErrEndMonthLowerThanStart = errors.New("bulan akhir tidak boleh lebih awal dari bulan awal")
+	ErrMaxMonthRange          = errors.New("rentang bulan tidak dapat lebih dari 36 bulan")
+)
+
+// Maximum number of months of one stats query
+const maxMonthRange = 36
+
+// Number of months shown when the range is not given, the current month
+// included
+const defaultMonthRange = 12
+
+// Empty start and end month default to the last twelve months until the
+// month of now. The returned times are the first day of the month.
+func ParseMonthRange(startMonth, endMonth string, now time.Time) (start, end time.Time, err error) {
+	end = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if endMonth != "" {
+		if end, err = time.Parse("2006-01", endMonth); err != nil {
+			return time.Time{}, time.Time{}, ErrMonthFormat
+		}
+	}
+
+	start = end.AddDate(0, -(defaultMonthRange - 1), 0)
+	if startMonth != "" {
+		if start, err = time.Parse("2006-01", startMonth); err != nil {
+			return time.Time{}, time.Time{}, ErrMonthFormat
+		}
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, ErrEndMonthLowerThanStart
+	}
+	if !start.AddDate(0, maxMonthRange, 0).After(end) {
+		return time.Time{}, time.Time{}, ErrMaxMonthRange
+	}
+
+	return start, end, nil
+}
+
+type (
+	HomestayMonthlyStatsOut struct {
+		Month              string  `json:"month"`
+		ListingTotal       int64   `json:"listing_total"`
+		RoomTotal          int64   `json:"room_total"`
+		RoomNights         int64   `json:"room_nights"`
+		BookedNights       int64   `json:"booked_nights"`
+		OccupancyRate      float64 `json:"occupancy_rate"`
+		IdrAvgNightlyPrice string  `json:"idr_avg_nightly_price"`
+		IdrRevenue         string  `json:"idr_revenue"`
+	}
+	QueryHomestayStatsRes struct {
+		HomestayId int64                     `json:"homestay_id"`
+		Months     []HomestayMonthlyStatsOut `json:"months"`
+	}
+	QueryHomestayStatsOut struct {
+		resp.Response
+		Res QueryHomestayStatsRes
+	}
+)
+
+// Occupancy rate is the percentage of the room nights that are booked,
+// rounded to two decimals
+func toHomestayMonthlyStatsOut(m HomestayMonthlyStatsModel) HomestayMonthlyStatsOut {
+	var occupancyRate float64
+	if m.RoomNights != 0 {
+		occupancyRate = math.Round(float64(m.BookedNights)/float64(m.RoomNights)*10000) / 100
+	}
+
+	var avgNightlyPrice int64
+	if m.BookedNights != 0 {
+		avgNightlyPrice = m.IdrRevenue / m.BookedNights
+	}
+
+	return HomestayMonthlyStatsOut{
+		Month:              m.Month.Format("2006-01"),
+		ListingTotal:       m.ListingTotal,
+		RoomTotal:          m.RoomTotal,
+		RoomNights:         m.RoomNights,
+		BookedNights:       m.BookedNights,
+		OccupancyRate:      occupancyRate,
+		IdrAvgNightlyPrice: strconv.FormatInt(avgNightlyPrice, 10),
+		IdrRevenue:         strconv.FormatInt(m.IdrRevenue, 10),
+	}
+}
+
+// Monthly listing, occupancy, and revenue stats of one homestay, or of
+// every homestay of the association with an accepted booking in the range
+// when the homestay id is empty
+func (d *HomestayDeps) QueryHomestayStats(ctx context.Context, hid, startMonth, endMonth string) (out QueryHomestayStatsOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	var id uint64
+	if hid != "" {
+		if id, err = strconv.ParseUint(hid, 10, 64); err != nil {
+			out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+			return
+		}
+	}
+
+	start, end, err := ParseMonthRange(startMonth, endMonth, time.Now())
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	if id != 0 {
+		_, err = d.MemberHomestayRepository.FindUndeletedById(ctx, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+			return
+		}
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member homestay by id"))
+			return
+		}
+	}
+
+	stats, err := d.MemberHomestayRepository.QueryMonthlyStats(ctx, id, start, end)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query member homestay monthly stats"))
+		return
+	}
+
+	months := make([]HomestayMonthlyStatsOut, len(stats))
+	for i, m := range stats {
+		months[i] = toHomestayMonthlyStatsOut(m)
+	}
+
+	out.Res = QueryHomestayStatsRes{
+		HomestayId: int64(id),
+		Months:     months,
+	}
+
+	return
+}
@@ -0,0 +1,198 @@
+package homestay_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/homestay"
+)
+
+func TestQueryHomestayStats(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muid, err := createUser(memberRepository, memberSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hid, err := createMemberHomestay(memberHomestayRepository, muid, homestaySeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rid, err := createHomestayRoom(homestayRoomRepository, hid, roomSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pendingHomestay := homestaySeed
+	pendingHomestay.Status = homestay.HomestayPending
+	pendingHid, err := createMemberHomestay(memberHomestayRepository, muid, pendingHomestay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pendingRid, err := createHomestayRoom(homestayRoomRepository, pendingHid, roomSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newHid, err := createMemberHomestay(memberHomestayRepository, muid, pendingHomestay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newRid, err := createHomestayRoom(homestayRoomRepository, newHid, roomSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	firstMonth := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	secondMonth := firstMonth.AddDate(0, 1, 0)
+
+	bookings := []struct {
+		HomestayId int64
+		RoomId     int64
+		CheckIn    time.Time
+		CheckOut   time.Time
+		Status     homestay.BookingStatus
+	}{
+		// 3 nights in the first month
+		{hid, rid, firstMonth, firstMonth.AddDate(0, 0, 3), homestay.BookingAccepted},
+		// 1 night in the first month and 1 night in the second month
+		{hid, rid, secondMonth.AddDate(0, 0, -1), secondMonth.AddDate(0, 0, 1), homestay.BookingAccepted},
+		// Not accepted yet
+		{hid, rid, firstMonth.AddDate(0, 0, 5), firstMonth.AddDate(0, 0, 7), homestay.BookingPending},
+		// Homestay sent back for review, its past bookings still count
+		{pendingHid, pendingRid, firstMonth, firstMonth.AddDate(0, 0, 3), homestay.BookingAccepted},
+		// Homestay without an accepted booking in the range
+		{newHid, newRid, firstMonth, firstMonth.AddDate(0, 0, 2), homestay.BookingPending},
+	}
+	for _, b := range bookings {
+		_, err = homestayBookingRepository.Save(context.Background(), homestay.HomestayBookingModel{
+			MemberHomestayId: uint64(b.HomestayId),
+			HomestayRoomId:   uint64(b.RoomId),
+			GuestName:        "Guest",
+			GuestPhone:       "+62 821-1111-0000",
+			GuestCount:       1,
+			CheckIn:          b.CheckIn,
+			CheckOut:         b.CheckOut,
+			IdrNightlyPrice:  roomSeed.IdrNightlyPrice,
+			Status:           b.Status,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	firstMonthNights := int64(secondMonth.Sub(firstMonth).Hours() / 24)
+	secondMonthNights := int64(secondMonth.AddDate(0, 1, 0).Sub(secondMonth).Hours() / 24)
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		ExpectedMonths     []homestay.HomestayMonthlyStatsOut
+		Hid                string
+		StartMonth         string
+		EndMonth           string
+	}{
+		{
+			Name:               "Query Homestay Stats Success, Association Wide",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMonths: []homestay.HomestayMonthlyStatsOut{
+				{
+					Month:              firstMonth.Format("2006-01"),
+					ListingTotal:       2,
+					RoomTotal:          2,
+					RoomNights:         firstMonthNights * 2,
+					BookedNights:       7,
+					IdrAvgNightlyPrice: "250000",
+					IdrRevenue:         "1750000",
+				},
+				{
+					Month:              secondMonth.Format("2006-01"),
+					ListingTotal:       2,
+					RoomTotal:          2,
+					RoomNights:         secondMonthNights * 2,
+					BookedNights:       1,
+					IdrAvgNightlyPrice: "250000",
+					IdrRevenue:         "250000",
+				},
+			},
+			StartMonth: firstMonth.Format("2006-01"),
+			EndMonth:   secondMonth.Format("2006-01"),
+		},
+		{
+			Name:               "Query Homestay Stats Success, Per Homestay",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMonths: []homestay.HomestayMonthlyStatsOut{
+				{
+					Month:              firstMonth.Format("2006-01"),
+					ListingTotal:       1,
+					RoomTotal:          1,
+					RoomNights:         firstMonthNights,
+					BookedNights:       3,
+					IdrAvgNightlyPrice: "250000",
+					IdrRevenue:         "750000",
+				},
+			},
+			Hid:        strconv.FormatInt(pendingHid, 10),
+			StartMonth: firstMonth.Format("2006-01"),
+			EndMonth:   firstMonth.Format("2006-01"),
+		},
+		{
+			Name:               "Query Homestay Stats Fail, Invalid Month Format",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			StartMonth:         "2022-13",
+		},
+		{
+			Name:               "Query Homestay Stats Fail, End Month Lower than Start",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			StartMonth:         secondMonth.Format("2006-01"),
+			EndMonth:           firstMonth.Format("2006-01"),
+		},
+		{
+			Name:               "Query Homestay Stats Fail, Range over 36 Months",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			StartMonth:         firstMonth.Format("2006-01"),
+			EndMonth:           firstMonth.AddDate(0, 36, 0).Format("2006-01"),
+		},
+		{
+			Name:               "Query Homestay Stats Fail, Homestay not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Hid:                strconv.FormatInt(pendingHid+1, 10),
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := homestayDeps.QueryHomestayStats(context.Background(), c.Hid, c.StartMonth, c.EndMonth)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if len(res.Res.Months) != len(c.ExpectedMonths) {
+				t.Fatalf("Expected months length %d. Got %d\n", len(c.ExpectedMonths), len(res.Res.Months))
+			}
+
+			for i, m := range res.Res.Months {
+				expected := c.ExpectedMonths[i]
+				// Occupancy rate is derived from the nights, no need to
+				// repeat its rounding here
+				m.OccupancyRate = 0
+				if m != expected {
+					t.Fatalf("Expected month stats %#v. Got %#v\n", expected, m)
+				}
+			}
+		})
+	}
+}
@@ -2,18 +2,119 @@ package homestay
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"time"
+
+	"github.com/pkg/errors"
+)
+
+type HomestayStatus struct {
+	String string
+}
+
+var (
+	HomestayUnknown   = HomestayStatus{""}
+	HomestayPending   = HomestayStatus{"pending"}
+	HomestayApproved  = HomestayStatus{"approved"}
+	HomestayRejected  = HomestayStatus{"rejected"}
+	HomestaySuspended = HomestayStatus{"suspended"}
 )
 
+func homestayStatusFromString(s string) (HomestayStatus, error) {
+	switch s {
+	case HomestayPending.String:
+		return HomestayPending, nil
+	case HomestayApproved.String:
+		return HomestayApproved, nil
+	case HomestayRejected.String:
+		return HomestayRejected, nil
+	case HomestaySuspended.String:
+		return HomestaySuspended, nil
+	}
+
+	return HomestayUnknown, errors.New("unknown type: " + s)
+}
+
+func (u *HomestayStatus) Scan(src interface{}) error {
+	if src == nil {
+		u.String = ""
+		return nil
+	}
+
+	s, ok := src.(string)
+	if !ok {
+		u.String = ""
+		return nil
+	}
+
+	hs, _ := homestayStatusFromString(s)
+	u.String = hs.String
+	return nil
+}
+
+func (u HomestayStatus) Value() (driver.Value, error) {
+	hs, err := homestayStatusFromString(u.String)
+	if err != nil {
+		hs = HomestayPending
+	}
+
+	return hs.String, nil
+}
+
 type MemberHomestayModel struct {
+	Id                  uint64
+	Name                string
+	Address             string
+	Latitude            float64
+	Longitude           float64
+	IsCoordinateInvalid bool
+	ThumbnailUrl        string
+	MemberId            string
+	RatingAvg           float64
+	RatingCount         int64
+	Status              HomestayStatus
+	StatusReason        string
+	ReviewedAt          sql.NullTime
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	DeletedAt           sql.NullTime
+}
+
+type HomestayDirectoryModel struct {
 	Id           uint64
 	Name         string
 	Address      string
-	Latitude     string
-	Longitude    string
+	Latitude     float64
+	Longitude    float64
 	ThumbnailUrl string
 	MemberId     string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	DeletedAt    sql.NullTime
+	OwnerName    string
+	Distance     sql.NullFloat64
+	Total        int64
+}
+
+type DirectoryFilter struct {
+	Q          string
+	HasCenter  bool
+	Lat        float64
+	Lng        float64
+	RadiusKm   float64
+	HasBox     bool
+	MinLat     float64
+	MinLng     float64
+	MaxLat     float64
+	MaxLng     float64
+	AmenityIds []uint64
+	SortBy     string
+	Offset     int64
+	Limit      int64
+}
+
+type HomestayMonthlyStatsModel struct {
+	Month        time.Time
+	ListingTotal int64
+	RoomTotal    int64
+	RoomNights   int64
+	BookedNights int64
+	IdrRevenue   int64
 }
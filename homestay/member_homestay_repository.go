@@ -28,6 +28,39 @@ type (
 	MemberHomestayQuerier    func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
 )
 
+// Condition of a homestay having all the amenities in the given bigint
+// array parameter, a null or empty array match every homestay
+func amenityFilter(idCol, param string) string {
+	return `(
+		COALESCE(cardinality(` + param + `::bigint[]), 0) = 0
+		OR ` + idCol + ` IN (
+			SELECT mha.member_homestay_id
+			FROM member_homestay_amenities mha
+				JOIN homestay_amenities a ON a.id = mha.homestay_amenity_id
+			WHERE a.deleted_at IS NULL
+				AND mha.homestay_amenity_id = ANY(` + param + `::bigint[])
+			GROUP BY mha.member_homestay_id
+			HAVING COUNT(*) = cardinality(` + param + `::bigint[])
+		)
+	)`
+}
+
+// Condition of a homestay owned by an active member, the homestays of
+// suspended, resigned, or deceased members are hidden from the public
+func activeOwnerFilter(memberIdCol string) string {
+	return `EXISTS (
+		SELECT 1
+		FROM members ao
+		WHERE ao.id = ` + memberIdCol + `
+			AND ao.deleted_at IS NULL
+			AND ao.is_approved = true
+			AND (
+				ao.status = 'active'
+				OR (ao.status = 'suspended' AND ao.suspended_until <= NOW())
+			)
+	)`
+}
+
 func (r *MemberHomestayRepository) Save(ctx context.Context, m MemberHomestayModel) (nm MemberHomestayModel, err error) {
 	sqlQuery := `
 		INSERT INTO member_homestays (
@@ -37,11 +70,12 @@ func (r *MemberHomestayRepository) Save(ctx context.Context, m MemberHomestayMod
 			longitude,
 			thumbnail_url,
 			member_id,
+			status,
 			created_at,
 			updated_at,
 			deleted_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id
 	`
 
@@ -65,6 +99,7 @@ func (r *MemberHomestayRepository) Save(ctx context.Context, m MemberHomestayMod
 		m.Longitude,
 		m.ThumbnailUrl,
 		m.MemberId,
+		m.Status,
 		t,
 		t,
 		nil,
@@ -89,9 +124,10 @@ func (r *MemberHomestayRepository) UpdateById(ctx context.Context, uid string, i
 			longitude,
 			thumbnail_url,
 			member_id,
+			is_coordinate_invalid,
 			created_at,
 			updated_at
-		) = ($1, $2, $3, $4, $5, $6, $7, $8)
+		) = ($1, $2, $3, $4, $5, $6, false, $7, $8)
 		WHERE member_id = $9 AND id = $10
 	`
 
@@ -135,8 +171,14 @@ func (r *MemberHomestayRepository) FindById(ctx context.Context, uid string, id
 			address,
 			latitude,
 			longitude,
+			is_coordinate_invalid,
 			thumbnail_url,
 			member_id,
+			rating_avg,
+			rating_count,
+			status,
+			status_reason,
+			reviewed_at,
 			created_at,
 			updated_at,
 			deleted_at
@@ -172,7 +214,114 @@ func (r *MemberHomestayRepository) FindById(ctx context.Context, uid string, id
 	return m, nil
 }
 
-func (r *MemberHomestayRepository) Query(ctx context.Context, uid string, id, limit int64) ([]MemberHomestayModel, error) {
+func (r *MemberHomestayRepository) FindUndeletedById(ctx context.Context, id uint64) (m MemberHomestayModel, err error) {
+	querystr := `
+		SELECT
+			id,
+			name,
+			address,
+			latitude,
+			longitude,
+			is_coordinate_invalid,
+			thumbnail_url,
+			member_id,
+			rating_avg,
+			rating_count,
+			status,
+			status_reason,
+			reviewed_at,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM member_homestays
+		WHERE deleted_at IS NULL
+		AND id = $1
+	`
+
+	var query MemberHomestayQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+	rows, err = query(
+		context.Background(),
+		querystr,
+		id,
+	)
+
+	if err != nil {
+		return MemberHomestayModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return MemberHomestayModel{}, err
+	}
+
+	return m, nil
+}
+
+// Approved homestays of active members are the only ones shown to the
+// public
+func (r *MemberHomestayRepository) FindApprovedById(ctx context.Context, id uint64) (m MemberHomestayModel, err error) {
+	querystr := `
+		SELECT
+			id,
+			name,
+			address,
+			latitude,
+			longitude,
+			is_coordinate_invalid,
+			thumbnail_url,
+			member_id,
+			rating_avg,
+			rating_count,
+			status,
+			status_reason,
+			reviewed_at,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM member_homestays
+		WHERE deleted_at IS NULL
+		AND status = 'approved'
+		AND ` + activeOwnerFilter("member_id") + `
+		AND id = $1
+	`
+
+	var query MemberHomestayQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+	rows, err = query(
+		context.Background(),
+		querystr,
+		id,
+	)
+
+	if err != nil {
+		return MemberHomestayModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return MemberHomestayModel{}, err
+	}
+
+	return m, nil
+}
+
+// Homestays having every one of the given amenities are returned, empty
+// amenity ids does not filter the homestays. Only approved homestays of an
+// active member are returned when isApprovedOnly is set.
+func (r *MemberHomestayRepository) Query(ctx context.Context, uid string, amenityIds []uint64, isApprovedOnly bool, id, limit int64) ([]MemberHomestayModel, error) {
 	fromId := "id > $1"
 	if id != 0 {
 		fromId = "id < $1"
@@ -185,8 +334,14 @@ func (r *MemberHomestayRepository) Query(ctx context.Context, uid string, id, li
 			address,
 			latitude,
 			longitude,
+			is_coordinate_invalid,
 			thumbnail_url,
 			member_id,
+			rating_avg,
+			rating_count,
+			status,
+			status_reason,
+			reviewed_at,
 			created_at,
 			updated_at,
 			deleted_at
@@ -194,6 +349,8 @@ func (r *MemberHomestayRepository) Query(ctx context.Context, uid string, id, li
 		WHERE deleted_at IS NULL
 			AND ` + fromId + `
 			AND member_id = $2
+			AND ` + amenityFilter("id", "$4") + `
+			AND ($5 = false OR (status = 'approved' AND ` + activeOwnerFilter("member_id") + `))
 		ORDER BY id DESC
 		LIMIT $3
 	`
@@ -204,6 +361,8 @@ func (r *MemberHomestayRepository) Query(ctx context.Context, uid string, id, li
 		id,
 		uid,
 		limit,
+		amenityIds,
+		isApprovedOnly,
 	)
 	defer rows.Close()
 
@@ -220,6 +379,112 @@ func (r *MemberHomestayRepository) Query(ctx context.Context, uid string, id, li
 	return ms, nil
 }
 
+// Only approved homestays of active and undeleted members are part of
+// the public directory, homestays with a coordinate waiting for correction
+// are left out of it. Distance is the haversine great-circle distance in
+// kilometers and is only computed when a center point is given. Zero
+// limit return all the matching homestays.
+func (r *MemberHomestayRepository) QueryDirectory(ctx context.Context, f DirectoryFilter) ([]HomestayDirectoryModel, error) {
+	distance := "NULL::double precision"
+	if f.HasCenter {
+		distance = `(
+			6371 * 2 * asin(sqrt(
+				power(sin(radians(mh.latitude - $2::double precision) / 2), 2) +
+				cos(radians($2::double precision)) * cos(radians(mh.latitude)) *
+				power(sin(radians(mh.longitude - $3::double precision) / 2), 2)
+			))
+		)`
+	}
+
+	search := "$1::text = ''"
+	if f.Q != "" {
+		search = `(
+			mh.textsearchable_index_col @@ plainto_tsquery('simple', $1)
+			OR mh.name ILIKE '%' || $1 || '%'
+			OR mh.address ILIKE '%' || $1 || '%'
+		)`
+	}
+
+	radius := "$2::double precision IS NOT NULL AND $3::double precision IS NOT NULL AND $4::double precision >= 0"
+	if f.HasCenter && f.RadiusKm > 0 {
+		radius = distance + " <= $4::double precision"
+	}
+
+	box := "$5::double precision IS NOT NULL AND $6::double precision IS NOT NULL AND $7::double precision IS NOT NULL AND $8::double precision IS NOT NULL"
+	if f.HasBox {
+		box = "mh.latitude BETWEEN $5::double precision AND $7::double precision AND mh.longitude BETWEEN $6::double precision AND $8::double precision"
+		// A viewport crossing the antimeridian has its west edge
+		// greater than its east edge
+		if f.MinLng > f.MaxLng {
+			box = "mh.latitude BETWEEN $5::double precision AND $7::double precision AND (mh.longitude >= $6::double precision OR mh.longitude <= $8::double precision)"
+		}
+	}
+
+	order := "mh.id DESC"
+	switch {
+	case f.SortBy == "distance" && f.HasCenter:
+		order = "distance ASC, mh.id DESC"
+	case f.SortBy == "name":
+		order = "mh.name ASC, mh.id DESC"
+	}
+
+	sqlQuery := `
+		SELECT
+			mh.id,
+			mh.name,
+			mh.address,
+			mh.latitude,
+			mh.longitude,
+			mh.thumbnail_url,
+			mh.member_id,
+			m.name AS owner_name,
+			` + distance + ` AS distance,
+			COUNT(*) OVER() AS total
+		FROM member_homestays mh
+			JOIN members m ON m.id = mh.member_id
+		WHERE mh.deleted_at IS NULL
+			AND mh.status = 'approved'
+			AND mh.is_coordinate_invalid = false
+			AND ` + activeOwnerFilter("mh.member_id") + `
+			AND ` + search + `
+			AND ` + radius + `
+			AND ` + box + `
+			AND ` + amenityFilter("mh.id", "$11") + `
+		ORDER BY ` + order + `
+		OFFSET $9
+		LIMIT NULLIF($10::bigint, 0)
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		f.Q,
+		f.Lat,
+		f.Lng,
+		f.RadiusKm,
+		f.MinLat,
+		f.MinLng,
+		f.MaxLat,
+		f.MaxLng,
+		f.Offset,
+		f.Limit,
+		f.AmenityIds,
+	)
+	defer rows.Close()
+
+	var mps []*HomestayDirectoryModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []HomestayDirectoryModel{}, err
+	}
+
+	ms := make([]HomestayDirectoryModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
 func (r *MemberHomestayRepository) DeleteById(ctx context.Context, uid string, id uint64) error {
 	sqlQuery := `
 		UPDATE member_homestays
@@ -254,12 +519,14 @@ func (r *MemberHomestayRepository) DeleteById(ctx context.Context, uid string, i
 	return nil
 }
 
-func (r *MemberHomestayRepository) CountMemberHomestay(ctx context.Context, uid string) (n int64, err error) {
+func (r *MemberHomestayRepository) CountMemberHomestay(ctx context.Context, uid string, amenityIds []uint64, isApprovedOnly bool) (n int64, err error) {
 	sqlQuery := `
 		SELECT COUNT(id) AS n
 		FROM member_homestays
 		WHERE deleted_at IS NULL
 		AND member_id = $1
+		AND ` + amenityFilter("id", "$2") + `
+		AND ($3 = false OR (status = 'approved' AND ` + activeOwnerFilter("member_id") + `))
 	`
 
 	var queryRow MemberHomestayQuerierRow
@@ -274,6 +541,8 @@ func (r *MemberHomestayRepository) CountMemberHomestay(ctx context.Context, uid
 		context.Background(),
 		sqlQuery,
 		uid,
+		amenityIds,
+		isApprovedOnly,
 	).Scan(&n)
 
 	if err != nil {
@@ -282,3 +551,291 @@ func (r *MemberHomestayRepository) CountMemberHomestay(ctx context.Context, uid
 
 	return n, nil
 }
+
+// Recompute the denormalized rating of the homestay from its visible
+// reviews, the average is rounded to two decimals
+func (r *MemberHomestayRepository) UpdateRatingById(ctx context.Context, id uint64) error {
+	sqlQuery := `
+		UPDATE member_homestays mh SET (
+			rating_avg,
+			rating_count
+		) = (
+			SELECT
+				COALESCE(ROUND(AVG(hr.rating), 2), 0)::double precision,
+				COUNT(hr.id)
+			FROM homestay_reviews hr
+			WHERE hr.member_homestay_id = mh.id
+			AND hr.is_hidden = false
+		)
+		WHERE mh.id = $1
+	`
+
+	var exec MemberHomestayExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *MemberHomestayRepository) UpdateThumbnailById(ctx context.Context, id uint64, thumbnailUrl string) error {
+	sqlQuery := `
+		UPDATE member_homestays SET (
+			thumbnail_url,
+			updated_at
+		) = ($1, $2)
+		WHERE id = $3
+	`
+
+	var exec MemberHomestayExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		thumbnailUrl,
+		time.Now(),
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Empty status query the homestays of every review status
+func (r *MemberHomestayRepository) QueryByStatus(ctx context.Context, status string, id, limit int64) ([]MemberHomestayModel, error) {
+	fromId := "id > $1"
+	if id != 0 {
+		fromId = "id < $1"
+	}
+
+	sqlQuery := `
+		SELECT
+			id,
+			name,
+			address,
+			latitude,
+			longitude,
+			is_coordinate_invalid,
+			thumbnail_url,
+			member_id,
+			rating_avg,
+			rating_count,
+			status,
+			status_reason,
+			reviewed_at,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM member_homestays
+		WHERE deleted_at IS NULL
+			AND ` + fromId + `
+			AND ($2 = '' OR status::text = $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		id,
+		status,
+		limit,
+	)
+	defer rows.Close()
+
+	var mps []*MemberHomestayModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []MemberHomestayModel{}, err
+	}
+
+	ms := make([]MemberHomestayModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *MemberHomestayRepository) CountByStatus(ctx context.Context, status string) (n int64, err error) {
+	sqlQuery := `
+		SELECT COUNT(id) AS n
+		FROM member_homestays
+		WHERE deleted_at IS NULL
+		AND ($1 = '' OR status::text = $1)
+	`
+
+	var queryRow MemberHomestayQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		status,
+	).Scan(&n)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func (r *MemberHomestayRepository) UpdateStatusById(ctx context.Context, id uint64, status HomestayStatus, reason string) error {
+	sqlQuery := `
+		UPDATE member_homestays SET (
+			status,
+			status_reason,
+			updated_at,
+			reviewed_at
+		) = ($1, $2, $3, $4)
+		WHERE id = $5
+	`
+
+	var exec MemberHomestayExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	t := time.Now()
+	// A homestay sent back for review has not been reviewed yet
+	var reviewedAt interface{} = t
+	if status == HomestayPending {
+		reviewedAt = nil
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		status,
+		reason,
+		t,
+		reviewedAt,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Monthly stats of a homestay from the start month to the end month, both
+// given as the first day of the month. Zero homestay id aggregate every
+// homestay of the association with an accepted booking in the range, so a
+// listing reviewed again later keep its past months. Room nights are the nights
+// each room existed in the month, booked nights and revenue come from the
+// accepted bookings and their nightly price at the time of booking.
+func (r *MemberHomestayRepository) QueryMonthlyStats(ctx context.Context, homestayId uint64, start, end time.Time) ([]HomestayMonthlyStatsModel, error) {
+	sqlQuery := `
+		WITH months AS (
+			SELECT
+				month::date AS month,
+				(month + INTERVAL '1 month')::date AS next_month
+			FROM generate_series($1::date, $2::date, INTERVAL '1 month') AS month
+		), homestays AS (
+			SELECT mh.id, mh.created_at, mh.deleted_at
+			FROM member_homestays mh
+			WHERE (
+				$3::bigint = 0
+				AND EXISTS (
+					SELECT 1
+					FROM homestay_bookings hb
+					WHERE hb.member_homestay_id = mh.id
+					AND hb.status = 'accepted'
+					AND hb.check_in < ($2::date + INTERVAL '1 month')
+					AND hb.check_out > $1::date
+				)
+			)
+			OR mh.id = $3::bigint
+		)
+		SELECT
+			m.month,
+			(
+				SELECT COUNT(h.id)
+				FROM homestays h
+				WHERE h.created_at < m.next_month
+				AND (h.deleted_at IS NULL OR h.deleted_at >= m.month)
+			) AS listing_total,
+			rs.room_total,
+			rs.room_nights,
+			bs.booked_nights,
+			bs.idr_revenue
+		FROM months m
+			CROSS JOIN LATERAL (
+				SELECT
+					COUNT(hr.id) AS room_total,
+					COALESCE(SUM(GREATEST(
+						LEAST(COALESCE(hr.deleted_at::date, m.next_month), m.next_month)
+						- GREATEST(hr.created_at::date, m.month),
+						0
+					)), 0)::bigint AS room_nights
+				FROM homestay_rooms hr
+					JOIN homestays h ON h.id = hr.member_homestay_id
+				WHERE hr.created_at < m.next_month
+				AND (hr.deleted_at IS NULL OR hr.deleted_at >= m.month)
+			) rs
+			CROSS JOIN LATERAL (
+				SELECT
+					COUNT(night) AS booked_nights,
+					COALESCE(SUM(hb.idr_nightly_price), 0)::bigint AS idr_revenue
+				FROM homestay_bookings hb
+					JOIN homestays h ON h.id = hb.member_homestay_id
+					CROSS JOIN generate_series(hb.check_in, hb.check_out - 1, INTERVAL '1 day') AS night
+				WHERE hb.status = 'accepted'
+				AND hb.check_in < m.next_month
+				AND hb.check_out > m.month
+				AND night >= m.month
+				AND night < m.next_month
+			) bs
+		ORDER BY m.month ASC
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		start,
+		end,
+		homestayId,
+	)
+	defer rows.Close()
+
+	var mps []*HomestayMonthlyStatsModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []HomestayMonthlyStatsModel{}, err
+	}
+
+	ms := make([]HomestayMonthlyStatsModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
@@ -2,8 +2,10 @@ package homestay
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
 	"github.com/go-chi/chi/v5"
 )
@@ -32,16 +34,31 @@ func (d *HomestayDeps) DeleteMemberHomestay(w http.ResponseWriter, r *http.Reque
 
 func (d *HomestayDeps) GetMemberHomestays(w http.ResponseWriter, r *http.Request) {
 	uid := chi.URLParam(r, "uid")
+	amenities := r.URL.Query().Get("amenities")
 	cursor := r.URL.Query().Get("cursor")
 	limit := r.URL.Query().Get("limit")
-	out := d.QueryMemberHomestays(r.Context(), uid, cursor, limit)
+
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil && !errors.Is(err, jwt.ErrClaimsNotFound) {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.QueryMemberHomestays(r.Context(), uid, jwtPayload.Uid, amenities, cursor, limit)
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
 
 func (d *HomestayDeps) GetMemberHomestay(w http.ResponseWriter, r *http.Request) {
 	uid := chi.URLParam(r, "uid")
 	id := chi.URLParam(r, "id")
-	out := d.FindMemberHomestay(r.Context(), id, uid)
+
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil && !errors.Is(err, jwt.ErrClaimsNotFound) {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.FindMemberHomestay(r.Context(), id, uid, jwtPayload.Uid)
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
 
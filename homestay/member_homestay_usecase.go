@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/geo"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
 	"github.com/gofrs/uuid"
 	"github.com/jackc/pgx/v4"
 	"github.com/pkg/errors"
@@ -18,11 +20,12 @@ var (
 
 type (
 	AddMemberHomestayIn struct {
-		Name      string  `json:"name"`
-		Address   string  `json:"address"`
-		Latitude  string  `json:"latitude"`
-		Longitude string  `json:"longitude"`
-		ImageIds  []int64 `json:"image_ids"`
+		Name       string  `json:"name"`
+		Address    string  `json:"address"`
+		Latitude   string  `json:"latitude"`
+		Longitude  string  `json:"longitude"`
+		ImageIds   []int64 `json:"image_ids"`
+		AmenityIds []int64 `json:"amenity_ids"`
 	}
 	AddMemberHomestayRes struct {
 		Id int64 `json:"id"`
@@ -78,11 +81,33 @@ func (d *HomestayDeps) AddMemberHomestay(ctx context.Context, uid string, in Add
 		return
 	}
 
+	amenityIds, err := d.findHomestayAmenityIds(ctx, in.AmenityIds)
+	if errors.Is(err, ErrAmenityNotFound) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", err)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find homestay amenity ids"))
+		return
+	}
+
+	latitude, err := geo.ParseLatitude(in.Latitude)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidHomestayLat)
+		return
+	}
+
+	longitude, err := geo.ParseLongitude(in.Longitude)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidHomestayLng)
+		return
+	}
+
 	memberHomestay := MemberHomestayModel{
 		Name:         in.Name,
 		Address:      in.Address,
-		Latitude:     in.Latitude,
-		Longitude:    in.Longitude,
+		Latitude:     latitude,
+		Longitude:    longitude,
 		ThumbnailUrl: homestayImages[0].Url,
 		MemberId:     uid,
 	}
@@ -102,6 +127,11 @@ func (d *HomestayDeps) AddMemberHomestay(ctx context.Context, uid string, in Add
 		return
 	}
 
+	if err = d.saveHomestayAmenities(ctx, memberHomestay.Id, amenityIds); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save homestay amenities"))
+		return
+	}
+
 	out.Res.Id = int64(memberHomestay.Id)
 
 	return
@@ -109,11 +139,12 @@ func (d *HomestayDeps) AddMemberHomestay(ctx context.Context, uid string, in Add
 
 type (
 	EditMemberHomestayIn struct {
-		Name      string  `json:"name"`
-		Address   string  `json:"address"`
-		Latitude  string  `json:"latitude"`
-		Longitude string  `json:"longitude"`
-		ImageIds  []int64 `json:"image_ids"`
+		Name       string  `json:"name"`
+		Address    string  `json:"address"`
+		Latitude   string  `json:"latitude"`
+		Longitude  string  `json:"longitude"`
+		ImageIds   []int64 `json:"image_ids"`
+		AmenityIds []int64 `json:"amenity_ids"`
 	}
 	EditMemberHomestayRes struct {
 		Id int64 `json:"id"`
@@ -181,17 +212,51 @@ func (d *HomestayDeps) EditMemberHomestay(ctx context.Context, pid, uid string,
 		return
 	}
 
+	amenityIds, err := d.findHomestayAmenityIds(ctx, in.AmenityIds)
+	if errors.Is(err, ErrAmenityNotFound) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", err)
+		return
+	}
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find homestay amenity ids"))
+		return
+	}
+
 	memberHomestay.Name = in.Name
 	memberHomestay.Address = in.Address
-	memberHomestay.Latitude = in.Latitude
-	memberHomestay.Longitude = in.Longitude
-	memberHomestay.ThumbnailUrl = homestayImages[0].Url
+	if memberHomestay.Latitude, err = geo.ParseLatitude(in.Latitude); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidHomestayLat)
+		return
+	}
+
+	if memberHomestay.Longitude, err = geo.ParseLongitude(in.Longitude); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidHomestayLng)
+		return
+	}
+	// Keep the chosen cover as long as its image is still in the gallery
+	var isCoverKept bool
+	for _, v := range homestayImages {
+		if isCoverKept = v.Url == memberHomestay.ThumbnailUrl; isCoverKept {
+			break
+		}
+	}
+	if !isCoverKept {
+		memberHomestay.ThumbnailUrl = homestayImages[0].Url
+	}
 
 	if err = d.MemberHomestayRepository.UpdateById(ctx, uid, id, memberHomestay); err != nil {
 		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update member homestay by id"))
 		return
 	}
 
+	// A rejected homestay is sent back for review once the owner fix it
+	if memberHomestay.Status == HomestayRejected {
+		if err = d.MemberHomestayRepository.UpdateStatusById(ctx, id, HomestayPending, ""); err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update member homestay status by id"))
+			return
+		}
+	}
+
 	var newHomestayImagesIds []uint64
 	for _, v := range homestayImages {
 		newHomestayImagesIds = append(newHomestayImagesIds, v.Id)
@@ -230,6 +295,11 @@ func (d *HomestayDeps) EditMemberHomestay(ctx context.Context, pid, uid string,
 		}
 	}
 
+	if err = d.saveHomestayAmenities(ctx, id, amenityIds); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save homestay amenities"))
+		return
+	}
+
 	out.Res.Id = int64(memberHomestay.Id)
 
 	return
@@ -237,10 +307,14 @@ func (d *HomestayDeps) EditMemberHomestay(ctx context.Context, pid, uid string,
 
 type (
 	MemberHomestaysRes struct {
-		Id           int64  `json:"id"`
-		Name         string `json:"name"`
-		Address      string `json:"address"`
-		ThumbnailUrl string `json:"thumbnail_url"`
+		Id           int64   `json:"id"`
+		Name         string  `json:"name"`
+		Address      string  `json:"address"`
+		ThumbnailUrl string  `json:"thumbnail_url"`
+		RatingAvg    float64 `json:"rating_avg"`
+		RatingCount  int64   `json:"rating_count"`
+		Status       string  `json:"status"`
+		StatusReason string  `json:"status_reason"`
 	}
 	QueryMemberHomestayRes struct {
 		Cursor          int64                `json:"cursor"`
@@ -253,23 +327,32 @@ type (
 	}
 )
 
-func (d *HomestayDeps) QueryMemberHomestays(ctx context.Context, uid, cursor, limit string) (out QueryMemberHomestayImageOut) {
+// Other than the owner only see the approved homestays of the member
+func (d *HomestayDeps) QueryMemberHomestays(ctx context.Context, uid, viewerUid, amenities, cursor, limit string) (out QueryMemberHomestayImageOut) {
 	var err error
 	out.Response = resp.NewResponse(http.StatusOK, "", nil)
 
+	amenityIds, err := ParseAmenityIds(amenities)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
 	fromCursor, _ := strconv.ParseInt(cursor, 10, 64)
 	nlimit, _ := strconv.ParseInt(limit, 10, 64)
 	if nlimit == 0 {
 		nlimit = 25
 	}
 
-	memberHomestayNumber, err := d.MemberHomestayRepository.CountMemberHomestay(ctx, uid)
+	isApprovedOnly := viewerUid != uid
+
+	memberHomestayNumber, err := d.MemberHomestayRepository.CountMemberHomestay(ctx, uid, amenityIds, isApprovedOnly)
 	if err != nil {
 		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "count image"))
 		return
 	}
 
-	memberHomestays, err := d.MemberHomestayRepository.Query(ctx, uid, fromCursor, nlimit)
+	memberHomestays, err := d.MemberHomestayRepository.Query(ctx, uid, amenityIds, isApprovedOnly, fromCursor, nlimit)
 	if err != nil {
 		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query member homestays"))
 		return
@@ -289,6 +372,10 @@ func (d *HomestayDeps) QueryMemberHomestays(ctx context.Context, uid, cursor, li
 			Name:         p.Name,
 			ThumbnailUrl: p.ThumbnailUrl,
 			Address:      p.Address,
+			RatingAvg:    p.RatingAvg,
+			RatingCount:  p.RatingCount,
+			Status:       p.Status.String,
+			StatusReason: p.StatusReason,
 		}
 	}
 
@@ -377,16 +464,26 @@ func (d *HomestayDeps) RemoveMemberHomestay(ctx context.Context, pid, uid string
 
 type (
 	HomestayImageRes struct {
-		Id  int64  `json:"id"`
-		Url string `json:"url"`
+		Id       int64  `json:"id"`
+		Url      string `json:"url"`
+		Caption  string `json:"caption"`
+		Position int64  `json:"position"`
+		IsCover  bool   `json:"is_cover"`
 	}
 	MemberHomestayRes struct {
-		Id             int64              `json:"id"`
-		Name           string             `json:"name"`
-		Address        string             `json:"address"`
-		Latitude       string             `json:"latitude"`
-		Longitude      string             `json:"longitude"`
-		HomestayImages []HomestayImageRes `json:"images"`
+		Id                  int64                `json:"id"`
+		Name                string               `json:"name"`
+		Address             string               `json:"address"`
+		ThumbnailUrl        string               `json:"thumbnail_url"`
+		Latitude            string               `json:"latitude"`
+		Longitude           string               `json:"longitude"`
+		IsCoordinateInvalid bool                 `json:"is_coordinate_invalid"`
+		RatingAvg           float64              `json:"rating_avg"`
+		RatingCount         int64                `json:"rating_count"`
+		Status              string               `json:"status"`
+		StatusReason        string               `json:"status_reason"`
+		HomestayImages      []HomestayImageRes   `json:"images"`
+		Amenities           []HomestayAmenityOut `json:"amenities"`
 	}
 	MemberHomestayOut struct {
 		resp.Response
@@ -394,7 +491,8 @@ type (
 	}
 )
 
-func (d *HomestayDeps) FindMemberHomestay(ctx context.Context, pid, uid string) (out MemberHomestayOut) {
+// A homestay that is not approved yet is only found by its owner
+func (d *HomestayDeps) FindMemberHomestay(ctx context.Context, pid, uid, viewerUid string) (out MemberHomestayOut) {
 	var err error
 	out.Response = resp.NewResponse(http.StatusOK, "", nil)
 
@@ -410,7 +508,7 @@ func (d *HomestayDeps) FindMemberHomestay(ctx context.Context, pid, uid string)
 		return
 	}
 
-	_, err = d.MemberRepository.FindById(ctx, uid)
+	member, err := d.MemberRepository.FindById(ctx, uid)
 	if errors.Is(err, pgx.ErrNoRows) {
 		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
 		return
@@ -430,6 +528,12 @@ func (d *HomestayDeps) FindMemberHomestay(ctx context.Context, pid, uid string)
 		return
 	}
 
+	isHidden := memberHomestay.Status != HomestayApproved || user.EffectiveMemberStatus(member) != user.MemberStatusActive
+	if isHidden && viewerUid != uid {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberHomestayNotFound)
+		return
+	}
+
 	homestayImages, err := d.HomestayImageRepository.FindByMemberHomestayId(ctx, id)
 	if err != nil {
 		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query images"))
@@ -439,18 +543,34 @@ func (d *HomestayDeps) FindMemberHomestay(ctx context.Context, pid, uid string)
 	newHomestayImages := make([]HomestayImageRes, len(homestayImages))
 	for i, v := range homestayImages {
 		newHomestayImages[i] = HomestayImageRes{
-			Id:  int64(v.Id),
-			Url: v.Url,
+			Id:       int64(v.Id),
+			Url:      v.Url,
+			Caption:  v.Caption,
+			Position: v.Position,
+			IsCover:  v.Url == memberHomestay.ThumbnailUrl,
 		}
 	}
 
+	amenities, err := d.HomestayAmenityRepository.QueryByHomestayId(ctx, id)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query homestay amenities"))
+		return
+	}
+
 	out.Res = MemberHomestayRes{
-		Id:             int64(memberHomestay.Id),
-		Name:           memberHomestay.Name,
-		Address:        memberHomestay.Address,
-		Latitude:       memberHomestay.Latitude,
-		Longitude:      memberHomestay.Longitude,
-		HomestayImages: newHomestayImages,
+		Id:                  int64(memberHomestay.Id),
+		Name:                memberHomestay.Name,
+		Address:             memberHomestay.Address,
+		ThumbnailUrl:        memberHomestay.ThumbnailUrl,
+		Latitude:            strconv.FormatFloat(memberHomestay.Latitude, 'f', -1, 64),
+		Longitude:           strconv.FormatFloat(memberHomestay.Longitude, 'f', -1, 64),
+		IsCoordinateInvalid: memberHomestay.IsCoordinateInvalid,
+		RatingAvg:           memberHomestay.RatingAvg,
+		RatingCount:         memberHomestay.RatingCount,
+		Status:              memberHomestay.Status.String,
+		StatusReason:        memberHomestay.StatusReason,
+		HomestayImages:      newHomestayImages,
+		Amenities:           toHomestayAmenitiesOut(amenities),
 	}
 
 	return
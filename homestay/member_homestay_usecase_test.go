@@ -40,7 +40,7 @@ func TestAddMemberHomestay(t *testing.T) {
 			In: homestay.AddMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -52,7 +52,7 @@ func TestAddMemberHomestay(t *testing.T) {
 			In: homestay.AddMemberHomestayIn{
 				Name:      "",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -64,7 +64,7 @@ func TestAddMemberHomestay(t *testing.T) {
 			In: homestay.AddMemberHomestayIn{
 				Name:      strings.Repeat("a", 101),
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -76,7 +76,7 @@ func TestAddMemberHomestay(t *testing.T) {
 			In: homestay.AddMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -88,7 +88,7 @@ func TestAddMemberHomestay(t *testing.T) {
 			In: homestay.AddMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   strings.Repeat("a", 201),
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -118,13 +118,37 @@ func TestAddMemberHomestay(t *testing.T) {
 			},
 		},
 		{
-			Name:               "Add Member Homestay Fail, Longitude Required",
+			Name:               "Add Member Homestay Fail, Latitude out of range",
 			ExpectedStatusCode: http.StatusUnprocessableEntity,
 			Uid:                muid,
 			In: homestay.AddMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "Homestay Address",
 				Latitude:  "120.12312312",
+				Longitude: "90.1212321",
+				ImageIds:  []int64{imageId},
+			},
+		},
+		{
+			Name:               "Add Member Homestay Fail, Longitude not a number",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Uid:                muid,
+			In: homestay.AddMemberHomestayIn{
+				Name:      "Homestay Name",
+				Address:   "Homestay Address",
+				Latitude:  "-6.12312312",
+				Longitude: "ninety",
+				ImageIds:  []int64{imageId},
+			},
+		},
+		{
+			Name:               "Add Member Homestay Fail, Longitude Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Uid:                muid,
+			In: homestay.AddMemberHomestayIn{
+				Name:      "Homestay Name",
+				Address:   "Homestay Address",
+				Latitude:  "-6.12312312",
 				Longitude: "",
 				ImageIds:  []int64{imageId},
 			},
@@ -136,7 +160,7 @@ func TestAddMemberHomestay(t *testing.T) {
 			In: homestay.AddMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: strings.Repeat("0", 51),
 				ImageIds:  []int64{imageId},
 			},
@@ -148,7 +172,7 @@ func TestAddMemberHomestay(t *testing.T) {
 			In: homestay.AddMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: strings.Repeat("0", 51),
 				ImageIds:  []int64{99},
 			},
@@ -160,7 +184,7 @@ func TestAddMemberHomestay(t *testing.T) {
 			In: homestay.AddMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -172,7 +196,7 @@ func TestAddMemberHomestay(t *testing.T) {
 			In: homestay.AddMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -238,7 +262,7 @@ func TestEditMemberHomestay(t *testing.T) {
 			In: homestay.EditMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -251,7 +275,7 @@ func TestEditMemberHomestay(t *testing.T) {
 			In: homestay.EditMemberHomestayIn{
 				Name:      "",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -264,7 +288,7 @@ func TestEditMemberHomestay(t *testing.T) {
 			In: homestay.EditMemberHomestayIn{
 				Name:      strings.Repeat("a", 101),
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -277,7 +301,7 @@ func TestEditMemberHomestay(t *testing.T) {
 			In: homestay.EditMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -290,7 +314,7 @@ func TestEditMemberHomestay(t *testing.T) {
 			In: homestay.EditMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   strings.Repeat("a", 201),
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -329,7 +353,7 @@ func TestEditMemberHomestay(t *testing.T) {
 			In: homestay.EditMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "",
 				ImageIds:  []int64{imageId},
 			},
@@ -342,7 +366,7 @@ func TestEditMemberHomestay(t *testing.T) {
 			In: homestay.EditMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: strings.Repeat("0", 51),
 				ImageIds:  []int64{imageId},
 			},
@@ -355,7 +379,7 @@ func TestEditMemberHomestay(t *testing.T) {
 			In: homestay.EditMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: strings.Repeat("0", 51),
 				ImageIds:  []int64{99},
 			},
@@ -368,7 +392,7 @@ func TestEditMemberHomestay(t *testing.T) {
 			In: homestay.EditMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -381,7 +405,7 @@ func TestEditMemberHomestay(t *testing.T) {
 			In: homestay.EditMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -394,7 +418,7 @@ func TestEditMemberHomestay(t *testing.T) {
 			In: homestay.EditMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -407,7 +431,7 @@ func TestEditMemberHomestay(t *testing.T) {
 			In: homestay.EditMemberHomestayIn{
 				Name:      "Homestay Name",
 				Address:   "Homestay Address",
-				Latitude:  "120.12312312",
+				Latitude:  "-6.12312312",
 				Longitude: "90.1212321",
 				ImageIds:  []int64{imageId},
 			},
@@ -576,7 +600,7 @@ func TestFindMemberHomestay(t *testing.T) {
 			}
 
 			ctx := context.WithValue(context.Background(), arbitary.TrxX{}, tx)
-			res := homestayDeps.FindMemberHomestay(ctx, c.Pid, c.Uid)
+			res := homestayDeps.FindMemberHomestay(ctx, c.Pid, c.Uid, "")
 			tx.Commit(context.Background())
 			tx.Rollback(context.Background())
 
@@ -625,7 +649,7 @@ func TestQueryMemberHomestay(t *testing.T) {
 			}
 
 			ctx := context.WithValue(context.Background(), arbitary.TrxX{}, tx)
-			res := homestayDeps.QueryMemberHomestays(ctx, c.Uid, "", "")
+			res := homestayDeps.QueryMemberHomestays(ctx, c.Uid, "", "", "", "")
 			tx.Commit(context.Background())
 			tx.Rollback(context.Background())
 
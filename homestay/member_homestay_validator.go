@@ -5,6 +5,7 @@ import (
 	"strings"
 	"unicode/utf8"
 
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/geo"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -18,6 +19,8 @@ var (
 	ErrMaxHomestayLat         = errors.New("titik garis bujur map homestay tidak dapat lebih dari 50 karakter")
 	ErrMaxHomestayLng         = errors.New("titik garis lintang map homestay tidak dapat lebih dari 50 karakter")
 	ErrHomestayPhotosRequired = errors.New("foto homestay tidak boleh kosong")
+	ErrInvalidHomestayLat     = errors.New("titik garis lintang map homestay harus berupa angka antara -90 dan 90")
+	ErrInvalidHomestayLng     = errors.New("titik garis bujur map homestay harus berupa angka antara -180 dan 180")
 )
 
 func ValidateAddMemberHomestayIn(i AddMemberHomestayIn) error {
@@ -72,6 +75,24 @@ func ValidateAddMemberHomestayIn(i AddMemberHomestayIn) error {
 		}
 		return nil
 	})
+	g.Go(func() error {
+		if strings.Trim(i.Latitude, " ") == "" || utf8.RuneCountInString(i.Latitude) > 50 {
+			return nil
+		}
+		if _, err := geo.ParseLatitude(i.Latitude); err != nil {
+			return ErrInvalidHomestayLat
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.Longitude, " ") == "" || utf8.RuneCountInString(i.Longitude) > 50 {
+			return nil
+		}
+		if _, err := geo.ParseLongitude(i.Longitude); err != nil {
+			return ErrInvalidHomestayLng
+		}
+		return nil
+	})
 	g.Go(func() error {
 		if len(i.ImageIds) == 0 {
 			return ErrHomestayImageRequired
@@ -137,6 +158,24 @@ func ValidateEditMemberHomestayIn(i EditMemberHomestayIn) error {
 		}
 		return nil
 	})
+	g.Go(func() error {
+		if strings.Trim(i.Latitude, " ") == "" || utf8.RuneCountInString(i.Latitude) > 50 {
+			return nil
+		}
+		if _, err := geo.ParseLatitude(i.Latitude); err != nil {
+			return ErrInvalidHomestayLat
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.Longitude, " ") == "" || utf8.RuneCountInString(i.Longitude) > 50 {
+			return nil
+		}
+		if _, err := geo.ParseLongitude(i.Longitude); err != nil {
+			return ErrInvalidHomestayLng
+		}
+		return nil
+	})
 	g.Go(func() error {
 		if len(i.ImageIds) == 0 {
 			return ErrHomestayImageRequired
@@ -0,0 +1,180 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+var ErrNotCalendar = errors.New("content is not an iCalendar VCALENDAR")
+
+// An all-day event, End is exclusive as in the iCalendar DTEND of a
+// VALUE=DATE event
+type Event struct {
+	Uid     string
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+const dateLayout = "20060102"
+
+// Ref: https://datatracker.ietf.org/doc/html/rfc5545#section-3.3.11
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+var textUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, ";",
+	`\,`, ",",
+	`\n`, "\n",
+	`\N`, "\n",
+)
+
+// Write the content line folded at 75 octets without splitting a UTF-8
+// sequence, the continuation lines start with a single space
+func writeLine(w *bufio.Writer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+func Encode(wr io.Writer, prodId, name string, stamp time.Time, events []Event) error {
+	w := bufio.NewWriter(wr)
+
+	writeLine(w, "BEGIN:VCALENDAR")
+	writeLine(w, "VERSION:2.0")
+	writeLine(w, "PRODID:"+prodId)
+	writeLine(w, "CALSCALE:GREGORIAN")
+	writeLine(w, "METHOD:PUBLISH")
+	writeLine(w, "X-WR-CALNAME:"+textEscaper.Replace(name))
+
+	dtStamp := stamp.UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		writeLine(w, "BEGIN:VEVENT")
+		writeLine(w, "UID:"+e.Uid)
+		writeLine(w, "DTSTAMP:"+dtStamp)
+		writeLine(w, "DTSTART;VALUE=DATE:"+e.Start.Format(dateLayout))
+		writeLine(w, "DTEND;VALUE=DATE:"+e.End.Format(dateLayout))
+		writeLine(w, "SUMMARY:"+textEscaper.Replace(e.Summary))
+		writeLine(w, "TRANSP:OPAQUE")
+		writeLine(w, "END:VEVENT")
+	}
+
+	writeLine(w, "END:VCALENDAR")
+
+	return w.Flush()
+}
+
+// Only the date of DTSTART and DTEND is used, the time and the time zone
+// are ignored because an availability block always cover whole nights
+func parseDate(value string) (time.Time, bool) {
+	if len(value) < len(dateLayout) {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(dateLayout, value[:len(dateLayout)])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// Parse the VEVENT of a VCALENDAR into all-day events. Cancelled events
+// and events without a valid DTSTART are skipped, an event without a
+// usable DTEND last for one day.
+func Parse(r io.Reader) ([]Event, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) != 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var (
+		isCalendar  bool
+		inEvent     bool
+		isCancelled bool
+		hasStart    bool
+		e           Event
+		events      []Event
+	)
+	for _, line := range lines {
+		colon := strings.Index(line, ":")
+		if colon == -1 {
+			continue
+		}
+
+		name, value := line[:colon], line[colon+1:]
+		if semicolon := strings.Index(name, ";"); semicolon != -1 {
+			name = name[:semicolon]
+		}
+		name = strings.ToUpper(name)
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCALENDAR"):
+			isCalendar = true
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent, isCancelled, hasStart = true, false, false
+			e = Event{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			inEvent = false
+			if !hasStart || isCancelled {
+				continue
+			}
+			if !e.End.After(e.Start) {
+				e.End = e.Start.AddDate(0, 0, 1)
+			}
+
+			events = append(events, e)
+		case !inEvent:
+			continue
+		case name == "UID":
+			e.Uid = value
+		case name == "SUMMARY":
+			e.Summary = textUnescaper.Replace(value)
+		case name == "STATUS":
+			isCancelled = strings.EqualFold(value, "CANCELLED")
+		case name == "DTSTART":
+			e.Start, hasStart = parseDate(value)
+		case name == "DTEND":
+			e.End, _ = parseDate(value)
+		}
+	}
+
+	if !isCalendar {
+		return nil, ErrNotCalendar
+	}
+
+	return events, nil
+}
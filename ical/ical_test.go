@@ -0,0 +1,146 @@
+package ical_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/ical"
+)
+
+func date(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestEncodeParse(t *testing.T) {
+	events := []ical.Event{
+		{
+			Uid:     "booking-1@uhomestay",
+			Summary: "Dipesan, Kamar; Deluxe",
+			Start:   date("2026-12-24"),
+			End:     date("2026-12-27"),
+		},
+		{
+			Uid:     "block-1-20261231@uhomestay",
+			Summary: strings.Repeat("Tutup ", 20),
+			Start:   date("2026-12-31"),
+			End:     date("2027-01-01"),
+		},
+	}
+
+	var b bytes.Buffer
+	if err := ical.Encode(&b, "-//uhomestay//id", "Deluxe", time.Now(), events); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range strings.Split(b.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("Expected line length at most 75. Got %d\n", len(line))
+		}
+	}
+
+	parsed, err := ical.Parse(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(parsed) != len(events) {
+		t.Fatalf("Expected events length %d. Got %d\n", len(events), len(parsed))
+	}
+
+	for i, e := range events {
+		if parsed[i] != e {
+			t.Fatalf("Expected event %#v. Got %#v\n", e, parsed[i])
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		in       string
+		expected []ical.Event
+		isErr    bool
+	}{
+		{
+			name: "Parse All Day Event Success",
+			in: "BEGIN:VCALENDAR\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"UID:abc\r\n" +
+				"DTSTART;VALUE=DATE:20261224\r\n" +
+				"DTEND;VALUE=DATE:20261226\r\n" +
+				"SUMMARY:Reserved\r\n" +
+				"END:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+			expected: []ical.Event{
+				{Uid: "abc", Summary: "Reserved", Start: date("2026-12-24"), End: date("2026-12-26")},
+			},
+		},
+		{
+			name: "Parse Date Time Event with Folded Line Success",
+			in: "BEGIN:VCALENDAR\n" +
+				"BEGIN:VEVENT\n" +
+				"UID:a\n" +
+				" bc\n" +
+				"DTSTART;TZID=Asia/Jakarta:20261224T140000\n" +
+				"DTEND;TZID=Asia/Jakarta:20261226T120000\n" +
+				"END:VEVENT\n" +
+				"END:VCALENDAR\n",
+			expected: []ical.Event{
+				{Uid: "abc", Start: date("2026-12-24"), End: date("2026-12-26")},
+			},
+		},
+		{
+			name: "Parse Event without End Success, Last One Day",
+			in: "BEGIN:VCALENDAR\n" +
+				"BEGIN:VEVENT\n" +
+				"DTSTART;VALUE=DATE:20261231\n" +
+				"END:VEVENT\n" +
+				"END:VCALENDAR\n",
+			expected: []ical.Event{
+				{Start: date("2026-12-31"), End: date("2027-01-01")},
+			},
+		},
+		{
+			name: "Parse Cancelled Event Success, Skipped",
+			in: "BEGIN:VCALENDAR\n" +
+				"BEGIN:VEVENT\n" +
+				"DTSTART;VALUE=DATE:20261231\n" +
+				"STATUS:CANCELLED\n" +
+				"END:VEVENT\n" +
+				"END:VCALENDAR\n",
+		},
+		{
+			name:  "Parse Fail, Not a Calendar",
+			in:    "<html></html>",
+			isErr: true,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			res, err := ical.Parse(strings.NewReader(c.in))
+			if c.isErr {
+				if err == nil {
+					t.Fatal("Expected error. Got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(res) != len(c.expected) {
+				t.Fatalf("Expected events length %d. Got %d\n", len(c.expected), len(res))
+			}
+
+			for i, e := range c.expected {
+				if res[i] != e {
+					t.Fatalf("Expected event %#v. Got %#v\n", e, res[i])
+				}
+			}
+		})
+	}
+}
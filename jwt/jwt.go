@@ -14,7 +14,23 @@ import (
 	"github.com/go-jose/go-jose/v3/jwt"
 )
 
-func NewMiddleware(jwtKey []byte, jwtIssuerUrl string, jwtAudiences []string, customClaims validator.CustomClaims) func(next http.Handler) http.Handler {
+var ErrClaimsNotFound = errors.New("jwt claims not found")
+
+// Report whether the session of the token id is still alive, a token is
+// rejected once its session is revoked
+type SessionChecker func(ctx context.Context, sessionId string) (bool, error)
+
+func NewMiddleware(jwtKey []byte, jwtIssuerUrl string, jwtAudiences []string, customClaims validator.CustomClaims, checkSession SessionChecker) func(next http.Handler) http.Handler {
+	return newMiddleware(jwtKey, jwtIssuerUrl, jwtAudiences, customClaims, checkSession, false)
+}
+
+// Request without a valid token is passed through without claims, an
+// invalid, expired or revoked token is treated as an anonymous request
+func NewOptionalMiddleware(jwtKey []byte, jwtIssuerUrl string, jwtAudiences []string, customClaims validator.CustomClaims, checkSession SessionChecker) func(next http.Handler) http.Handler {
+	return newMiddleware(jwtKey, jwtIssuerUrl, jwtAudiences, customClaims, checkSession, true)
+}
+
+func newMiddleware(jwtKey []byte, jwtIssuerUrl string, jwtAudiences []string, customClaims validator.CustomClaims, checkSession SessionChecker, isCredentialsOptional bool) func(next http.Handler) http.Handler {
 	keyFunc := func(ctx context.Context) (interface{}, error) {
 		// Our token must be signed using this data.
 		return jwtKey, nil
@@ -34,22 +50,115 @@ func NewMiddleware(jwtKey []byte, jwtIssuerUrl string, jwtAudiences []string, cu
 		log.Fatalf("Fail setup jwt validator: %s", err)
 	}
 
+	tokenExtractor := jwtmiddleware.MultiTokenExtractor(
+		jwtmiddleware.AuthHeaderTokenExtractor,
+		jwtmiddleware.CookieTokenExtractor("jwt"),
+	)
+
+	if isCredentialsOptional {
+		return func(next http.Handler) http.Handler {
+			return optionalMiddleware(jwtValidator.ValidateToken, tokenExtractor, checkSession, next)
+		}
+	}
+
 	// Set up the middleware.
 	jwtMidd := jwtmiddleware.New(
 		jwtValidator.ValidateToken,
-		jwtmiddleware.WithTokenExtractor(
-			jwtmiddleware.MultiTokenExtractor(
-				jwtmiddleware.AuthHeaderTokenExtractor,
-				jwtmiddleware.CookieTokenExtractor("jwt"),
-			),
-		),
+		jwtmiddleware.WithTokenExtractor(tokenExtractor),
 	).CheckJWT
 
-	return jwtMidd
+	return func(next http.Handler) http.Handler {
+		return jwtMidd(sessionMiddleware(checkSession, next))
+	}
+}
+
+// The token must carry an expiry and the id of a session still alive, the
+// response follow the ones of the jwt middleware
+func sessionMiddleware(checkSession SessionChecker, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := r.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		isAlive := false
+		if claims.RegisteredClaims.ID != "" && claims.RegisteredClaims.Expiry != 0 {
+			var err error
+			isAlive, err = checkSession(r.Context(), claims.RegisteredClaims.ID)
+			if err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"message":"Something went wrong while checking the JWT."}`))
+				return
+			}
+		}
+
+		if !isAlive {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"JWT is invalid."}`))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// The claims are only set for a valid token of a session still alive, any
+// other request continue as an anonymous one
+func optionalMiddleware(validateToken jwtmiddleware.ValidateToken, tokenExtractor jwtmiddleware.TokenExtractor, checkSession SessionChecker, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := tokenExtractor(r)
+		if err != nil || token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		validToken, err := validateToken(r.Context(), token)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		claims, ok := validToken.(*validator.ValidatedClaims)
+		if !ok || claims.RegisteredClaims.ID == "" || claims.RegisteredClaims.Expiry == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		isAlive, err := checkSession(r.Context(), claims.RegisteredClaims.ID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"Something went wrong while checking the JWT."}`))
+			return
+		}
+
+		if !isAlive {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.Clone(context.WithValue(r.Context(), jwtmiddleware.ContextKey{}, validToken)))
+	})
+}
+
+// Id of the session the token belongs to
+func SessionId(r *http.Request) (string, error) {
+	claims, ok := r.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	if !ok {
+		return "", ErrClaimsNotFound
+	}
+
+	return claims.RegisteredClaims.ID, nil
 }
 
 func MarshalClaims(r *http.Request) ([]byte, error) {
-	claims := r.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	claims, ok := r.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	if !ok {
+		return []byte{}, ErrClaimsNotFound
+	}
 
 	payload, err := json.Marshal(claims)
 	if err != nil {
@@ -60,7 +169,10 @@ func MarshalClaims(r *http.Request) ([]byte, error) {
 }
 
 func MarshalCustomClaims(r *http.Request) ([]byte, error) {
-	claims := r.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	claims, ok := r.Context().Value(jwtmiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	if !ok {
+		return []byte{}, ErrClaimsNotFound
+	}
 
 	payload, err := json.Marshal(claims.CustomClaims)
 	if err != nil {
@@ -0,0 +1,101 @@
+package jwt_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
+)
+
+var (
+	jwtKey       = []byte("secret")
+	jwtIssuerUrl = "https://uhomestay.test"
+	jwtAudiences = []string{"https://uhomestay.test"}
+)
+
+func TestOptionalMiddleware(t *testing.T) {
+	now := time.Now()
+
+	sign := func(id string, expiry time.Time) string {
+		token, err := jwt.Sign(id, "uid", jwtIssuerUrl, jwtKey, jwtAudiences, now.Add(-time.Minute), expiry, now.Add(-time.Minute), jwt.JwtPrivateClaim{Uid: "uid"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return token
+	}
+
+	checkSession := func(ctx context.Context, sessionId string) (bool, error) {
+		return sessionId == "alive", nil
+	}
+
+	testCases := []struct {
+		Name              string
+		ExpectedSessionId string
+		Token             string
+		IsCookie          bool
+	}{
+		{
+			Name: "No Token, Anonymous",
+		},
+		{
+			Name:              "Valid Token, Claims Set",
+			ExpectedSessionId: "alive",
+			Token:             sign("alive", now.Add(time.Hour)),
+		},
+		{
+			Name:     "Expired Token in Cookie, Anonymous",
+			Token:    sign("alive", now.Add(-time.Second)),
+			IsCookie: true,
+		},
+		{
+			Name:     "Revoked Session in Cookie, Anonymous",
+			Token:    sign("revoked", now.Add(time.Hour)),
+			IsCookie: true,
+		},
+		{
+			Name:  "Malformed Token, Anonymous",
+			Token: "not-a-token",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			sessionId := ""
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sessionId, _ = jwt.SessionId(r)
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/members", nil)
+			if c.Token != "" && c.IsCookie {
+				r.AddCookie(&http.Cookie{Name: "jwt", Value: c.Token})
+			} else if c.Token != "" {
+				r.Header.Set("Authorization", "Bearer "+c.Token)
+			}
+
+			w := httptest.NewRecorder()
+			jwt.NewOptionalMiddleware(jwtKey, jwtIssuerUrl, jwtAudiences, &jwt.JwtPrivateClaim{}, checkSession)(next).ServeHTTP(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected response code %d. Got %d\n", http.StatusOK, w.Code)
+			}
+
+			if sessionId != c.ExpectedSessionId {
+				t.Fatalf("Expected session id %q. Got %q\n", c.ExpectedSessionId, sessionId)
+			}
+		})
+	}
+
+	// The required middleware still reject a revoked session
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/profile", nil)
+	r.Header.Set("Authorization", "Bearer "+sign("revoked", now.Add(time.Hour)))
+	w := httptest.NewRecorder()
+	jwt.NewMiddleware(jwtKey, jwtIssuerUrl, jwtAudiences, &jwt.JwtPrivateClaim{}, checkSession)(http.NotFoundHandler()).ServeHTTP(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusUnauthorized, w.Code)
+	}
+}
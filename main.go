@@ -4,6 +4,8 @@ import (
 	"context"
 	"embed"
 	"log"
+	"net/http"
+	"time"
 
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/article"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/cashflow"
@@ -70,6 +72,13 @@ func main() {
 	orgRepository := user.NewOrgStructureRepository(posgrePool)
 	periodRepository := user.NewOrgPeriodRepository(posgrePool)
 	goalRepository := user.NewGoalRepository(posgrePool)
+	memberErasureRepository := user.NewMemberErasureRepository(posgrePool)
+	memberProfileChangeRepository := user.NewMemberProfileChangeRepository(posgrePool)
+	memberInvitationRepository := user.NewMemberInvitationRepository(posgrePool)
+	memberTotpRepository := user.NewMemberTotpRepository(posgrePool)
+	loginThrottleRepository := user.NewLoginThrottleRepository(posgrePool)
+	memberSessionRepository := user.NewMemberSessionRepository(posgrePool)
+	fileRemovalRepository := user.NewFileRemovalRepository(posgrePool)
 	documentRepository := document.NewRepository(posgrePool)
 	cashflowRepository := cashflow.NewRepository(posgrePool)
 	duesRepository := dues.NewDeusRepository(posgrePool)
@@ -83,6 +92,9 @@ func main() {
 		"imgchc",
 		posgrePool,
 	)
+	categoryRepository := article.NewCategoryRepository(posgrePool)
+	tagRepository := article.NewTagRepository(posgrePool)
+	revisionRepository := article.NewRevisionRepository(posgrePool)
 
 	memberHomestayRepository := homestay.NewMemberHomestayRepository(
 		posgrePool,
@@ -90,6 +102,21 @@ func main() {
 	homestayImageRepository := homestay.NewHomestayImageRepository(
 		posgrePool,
 	)
+	homestayRoomRepository := homestay.NewHomestayRoomRepository(
+		posgrePool,
+	)
+	homestayBookingRepository := homestay.NewHomestayBookingRepository(
+		posgrePool,
+	)
+	homestayRoomIcalFeedRepository := homestay.NewHomestayRoomIcalFeedRepository(
+		posgrePool,
+	)
+	homestayReviewRepository := homestay.NewHomestayReviewRepository(
+		posgrePool,
+	)
+	homestayAmenityRepository := homestay.NewHomestayAmenityRepository(
+		posgrePool,
+	)
 
 	userDeps := user.NewDeps(
 		conf.JwtKey,
@@ -102,12 +129,20 @@ func main() {
 			Folder:         "uhomestay/profile",
 			ResourceType:   "image",
 		}, cld.Upload.Upload),
+		user.FileRemove("image", cld.Upload.Destroy),
 		tmpl,
 		memberRepository,
 		positionRepository,
 		orgRepository,
 		periodRepository,
 		goalRepository,
+		memberErasureRepository,
+		memberProfileChangeRepository,
+		memberInvitationRepository,
+		memberTotpRepository,
+		loginThrottleRepository,
+		memberSessionRepository,
+		fileRemovalRepository,
 	)
 
 	documentDeps := document.NewDeps(
@@ -134,6 +169,9 @@ func main() {
 			ResourceType: "raw",
 		}, cld.Upload.Upload),
 		articleRepository,
+		categoryRepository,
+		tagRepository,
+		revisionRepository,
 	)
 
 	cashflowDeps := cashflow.NewDeps(
@@ -172,12 +210,26 @@ func main() {
 			Folder:       "uhomestay/homestay",
 			ResourceType: "raw",
 		}, cld.Upload.Upload),
+		homestay.HttpIcalFetch(homestay.NewIcalHttpClient(30*time.Second)),
 		homestayImageRepository,
 		memberHomestayRepository,
+		homestayRoomRepository,
+		homestayBookingRepository,
+		homestayRoomIcalFeedRepository,
+		homestayReviewRepository,
+		homestayAmenityRepository,
 		memberRepository,
 	)
 
+	go homestayDeps.RunIcalSync(context.Background(), conf.IcalSyncInterval)
+	go userDeps.RunRejectedMemberPurge(context.Background(), conf.RegistrationPurgeInterval, conf.RejectedMemberRetention)
+	go userDeps.RunFileRemovalRetry(context.Background(), conf.FileRemovalRetryInterval)
+
 	dashboardDeps := dashboard.NewDeps(
+		conf.PublicUrl,
+		dashboard.HttpFileFetch(&http.Client{
+			Timeout: 30 * time.Second,
+		}),
 		historyDeps,
 		imageDeps,
 		homestayDeps,
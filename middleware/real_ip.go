@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// HTTP middleware setting the request remote address to the client IP.
+// Only the X-Forwarded-For entries appended by the given number of trusted
+// proxies in front of the app are used, the entries before them are sent
+// by the client and can be anything. The forwarding headers are removed
+// afterward so nothing down the chain read them.
+func NewRealIpMiddleware(trustedProxies int) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := forwardedIp(r.Header.Values("X-Forwarded-For"), trustedProxies); ip != "" {
+				r.RemoteAddr = ip
+			}
+
+			r.Header.Del("X-Forwarded-For")
+			r.Header.Del("X-Real-IP")
+			r.Header.Del("True-Client-IP")
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func forwardedIp(headers []string, trustedProxies int) string {
+	if trustedProxies <= 0 {
+		return ""
+	}
+
+	var ips []string
+	for _, h := range headers {
+		for _, ip := range strings.Split(h, ",") {
+			ips = append(ips, strings.TrimSpace(ip))
+		}
+	}
+
+	if len(ips) < trustedProxies {
+		return ""
+	}
+
+	ip := net.ParseIP(ips[len(ips)-trustedProxies])
+	if ip == nil {
+		return ""
+	}
+
+	return ip.String()
+}
+
+// Client IP of the request without the port
+func ClientIp(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,93 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mw "github.com/PA-D3RPLA/d3if43-htt-uhomestay/middleware"
+)
+
+func TestRealIpMiddleware(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		ExpectedIp     string
+		TrustedProxies int
+		RemoteAddr     string
+		Headers        map[string]string
+	}{
+		{
+			Name:           "No Trusted Proxy, Forwarding Headers Ignored",
+			ExpectedIp:     "10.0.0.1",
+			TrustedProxies: 0,
+			RemoteAddr:     "10.0.0.1:5000",
+			Headers: map[string]string{
+				"X-Forwarded-For": "1.1.1.1",
+				"X-Real-IP":       "1.1.1.1",
+				"True-Client-IP":  "1.1.1.1",
+			},
+		},
+		{
+			Name:           "One Trusted Proxy, Last Entry Used",
+			ExpectedIp:     "2.2.2.2",
+			TrustedProxies: 1,
+			RemoteAddr:     "10.0.0.1:5000",
+			Headers: map[string]string{
+				"X-Forwarded-For": "1.1.1.1, 2.2.2.2",
+			},
+		},
+		{
+			Name:           "Two Trusted Proxies, Spoofed Entry Ignored",
+			ExpectedIp:     "2.2.2.2",
+			TrustedProxies: 2,
+			RemoteAddr:     "10.0.0.1:5000",
+			Headers: map[string]string{
+				"X-Forwarded-For": "1.1.1.1, 2.2.2.2, 10.0.0.2",
+			},
+		},
+		{
+			Name:           "Trusted Proxy, Missing Header",
+			ExpectedIp:     "10.0.0.1",
+			TrustedProxies: 1,
+			RemoteAddr:     "10.0.0.1:5000",
+		},
+		{
+			Name:           "Trusted Proxy, Invalid Entry",
+			ExpectedIp:     "10.0.0.1",
+			TrustedProxies: 1,
+			RemoteAddr:     "10.0.0.1:5000",
+			Headers: map[string]string{
+				"X-Forwarded-For": "not-an-ip",
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			var ip string
+			var headers http.Header
+			h := mw.NewRealIpMiddleware(c.TrustedProxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ip = mw.ClientIp(r)
+				headers = r.Header
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = c.RemoteAddr
+			for k, v := range c.Headers {
+				req.Header.Set(k, v)
+			}
+
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if ip != c.ExpectedIp {
+				t.Fatalf("Expected ip %s. Got %s\n", c.ExpectedIp, ip)
+			}
+
+			for k := range c.Headers {
+				if headers.Get(k) != "" {
+					t.Fatalf("Expected header %s removed\n", k)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,496 @@
+package qrcode
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+var ErrContentTooLong = errors.New("content is too long for a qr code")
+
+// Error correction level M blocks of the version 1 to 10, enough for the
+// short tokens this app encode
+// Ref: ISO/IEC 18004:2015 table 9
+type versionInfo struct {
+	totalCodewords int
+	ecPerBlock     int
+	blocks         int
+	alignments     []int
+}
+
+var versions = [...]versionInfo{
+	1:  {26, 10, 1, nil},
+	2:  {44, 16, 1, []int{6, 18}},
+	3:  {70, 26, 1, []int{6, 22}},
+	4:  {100, 18, 2, []int{6, 26}},
+	5:  {134, 24, 2, []int{6, 30}},
+	6:  {172, 16, 4, []int{6, 34}},
+	7:  {196, 18, 4, []int{6, 22, 38}},
+	8:  {242, 22, 4, []int{6, 24, 42}},
+	9:  {292, 22, 5, []int{6, 26, 46}},
+	10: {346, 26, 5, []int{6, 28, 50}},
+}
+
+func (v versionInfo) dataCodewords() int {
+	return v.totalCodewords - v.ecPerBlock*v.blocks
+}
+
+// A square of modules, true is a dark module
+type Code struct {
+	Size    int
+	modules [][]bool
+}
+
+func (c *Code) Dark(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+		return false
+	}
+
+	return c.modules[y][x]
+}
+
+// Draw the code with each module as scale x scale pixels surrounded by a
+// quiet zone of border modules
+func (c *Code) Image(scale, border int) *image.Gray {
+	n := (c.Size + border*2) * scale
+	img := image.NewGray(image.Rect(0, 0, n, n))
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			v := color.Gray{Y: 255}
+			if c.Dark(x/scale-border, y/scale-border) {
+				v = color.Gray{Y: 0}
+			}
+			img.SetGray(x, y, v)
+		}
+	}
+
+	return img
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, (v>>i)&1 == 1)
+	}
+}
+
+// Encode the content in byte mode using the smallest version that fit
+func Encode(content []byte) (*Code, error) {
+	version := 0
+	for v := 1; v < len(versions); v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+len(content)*8 <= versions[v].dataCodewords()*8 {
+			version = v
+			break
+		}
+	}
+
+	if version == 0 {
+		return nil, ErrContentTooLong
+	}
+
+	info := versions[version]
+	countBits := 8
+	if version >= 10 {
+		countBits = 16
+	}
+
+	var bb bitBuffer
+	bb.append(0x4, 4)
+	bb.append(len(content), countBits)
+	for _, b := range content {
+		bb.append(int(b), 8)
+	}
+
+	capacity := info.dataCodewords() * 8
+	terminator := capacity - len(bb)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bb.append(0, terminator)
+	bb.append(0, (8-len(bb)%8)%8)
+	for pad := 0xEC; len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+
+	data := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			data[i>>3] |= 1 << (7 - uint(i&7))
+		}
+	}
+
+	c := newCode(version)
+	c.drawCodewords(interleave(info, data))
+
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if penalty := c.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		c.applyMask(mask)
+	}
+
+	c.applyMask(bestMask)
+	c.drawFormatBits(bestMask)
+
+	return &c.Code, nil
+}
+
+// Split the data into the blocks, append the error correction of each
+// block, then take the codewords of the blocks in turn
+func interleave(info versionInfo, data []byte) []byte {
+	shortBlocks := info.blocks - info.totalCodewords%info.blocks
+	shortBlockLen := info.totalCodewords / info.blocks
+	divisor := rsDivisor(info.ecPerBlock)
+
+	var dataBlocks, ecBlocks [][]byte
+	k := 0
+	for i := 0; i < info.blocks; i++ {
+		n := shortBlockLen - info.ecPerBlock
+		if i >= shortBlocks {
+			n++
+		}
+		block := data[k : k+n]
+		k += n
+		dataBlocks = append(dataBlocks, block)
+		ecBlocks = append(ecBlocks, rsRemainder(block, divisor))
+	}
+
+	result := make([]byte, 0, info.totalCodewords)
+	for i := 0; i <= shortBlockLen-info.ecPerBlock; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < info.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+
+	return result
+}
+
+// Multiplication in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+
+	return byte(z)
+}
+
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	var root byte = 1
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+
+	return result
+}
+
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMultiply(d, factor)
+		}
+	}
+
+	return result
+}
+
+type builder struct {
+	Code
+	isFunction [][]bool
+}
+
+func newCode(version int) *builder {
+	size := version*4 + 17
+	c := &builder{
+		Code: Code{
+			Size:    size,
+			modules: make([][]bool, size),
+		},
+		isFunction: make([][]bool, size),
+	}
+	for i := 0; i < size; i++ {
+		c.modules[i] = make([]bool, size)
+		c.isFunction[i] = make([]bool, size)
+	}
+
+	for i := 0; i < size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(size-4, 3)
+	c.drawFinder(3, size-4)
+
+	alignments := versions[version].alignments
+	last := len(alignments) - 1
+	for i, ax := range alignments {
+		for j, ay := range alignments {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			c.drawAlignment(ax, ay)
+		}
+	}
+
+	// Reserve the format area, the bits are drawn once the mask is chosen
+	c.drawFormatBits(0)
+
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+		}
+		bits := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := (bits>>uint(i))&1 == 1
+			a := size - 11 + i%3
+			b := i / 3
+			c.setFunction(a, b, dark)
+			c.setFunction(b, a, dark)
+		}
+	}
+
+	return c
+}
+
+func (c *builder) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.isFunction[y][x] = true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func (c *builder) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || yy < 0 || xx >= c.Size || yy >= c.Size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *builder) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// Level M with the mask, protected by a BCH code and drawn twice
+func (c *builder) drawFormatBits(mask int) {
+	data := mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool {
+		return (bits>>uint(i))&1 == 1
+	}
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(i))
+	}
+	c.setFunction(8, 7, bit(6))
+	c.setFunction(8, 8, bit(7))
+	c.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(i))
+	}
+	c.setFunction(8, c.Size-8, true)
+}
+
+// Place the codewords in the two module wide columns zigzagging from the
+// bottom right, skipping the vertical timing pattern
+func (c *builder) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if c.isFunction[y][x] || i >= len(data)*8 {
+					continue
+				}
+				c.modules[y][x] = (data[i>>3]>>(7-uint(i&7)))&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// Applying the same mask twice undo it
+func (c *builder) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !c.isFunction[y][x] {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// Score of the masked symbol, lower is easier to scan
+// Ref: ISO/IEC 18004:2015 section 7.8.3
+func (c *builder) penalty() int {
+	result := 0
+	line := make([]bool, c.Size)
+	for _, horizontal := range []bool{true, false} {
+		for i := 0; i < c.Size; i++ {
+			for j := 0; j < c.Size; j++ {
+				if horizontal {
+					line[j] = c.modules[i][j]
+				} else {
+					line[j] = c.modules[j][i]
+				}
+			}
+			result += linePenalty(line)
+		}
+	}
+
+	dark := 0
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x+1 < c.Size && y+1 < c.Size {
+				v := c.modules[y][x]
+				if v == c.modules[y][x+1] && v == c.modules[y+1][x] && v == c.modules[y+1][x+1] {
+					result += 3
+				}
+			}
+		}
+	}
+
+	total := c.Size * c.Size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	result += k * 10
+
+	return result
+}
+
+var finderLike = []bool{true, false, true, true, true, false, true}
+
+func linePenalty(line []bool) int {
+	result := 0
+
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			result += 3 + run - 5
+		}
+		run = 1
+	}
+
+	light := func(from, to int) bool {
+		for i := from; i < to; i++ {
+			if i >= 0 && i < len(line) && line[i] {
+				return false
+			}
+		}
+		return true
+	}
+	for i := 0; i+len(finderLike) <= len(line); i++ {
+		match := true
+		for j, v := range finderLike {
+			if line[i+j] != v {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		if light(i-4, i) || light(i+len(finderLike), i+len(finderLike)+4) {
+			result += 40
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,453 @@
+package qrcode_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/qrcode"
+)
+
+// Format bits of the first copy, the most significant bit first
+func formatBits(c *qrcode.Code) int {
+	var bits int
+	push := func(x, y int) {
+		bits <<= 1
+		if c.Dark(x, y) {
+			bits |= 1
+		}
+	}
+
+	for x := 0; x <= 5; x++ {
+		push(x, 8)
+	}
+	push(7, 8)
+	push(8, 8)
+	push(8, 7)
+	for y := 5; y >= 0; y-- {
+		push(8, y)
+	}
+
+	return bits
+}
+
+func TestEncode(t *testing.T) {
+	testCases := []struct {
+		name         string
+		content      string
+		expectedSize int
+		isErr        bool
+	}{
+		{
+			name:         "Encode Short Content Success",
+			content:      "uhomestay",
+			expectedSize: 21,
+		},
+		{
+			name:         "Encode Token Success",
+			content:      strings.Repeat("a", 71),
+			expectedSize: 37,
+		},
+		{
+			name:         "Encode Content with Version Information Success",
+			content:      strings.Repeat("a", 150),
+			expectedSize: 49,
+		},
+		{
+			name:    "Encode Fail, Content Too Long",
+			content: strings.Repeat("a", 214),
+			isErr:   true,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			code, err := qrcode.Encode([]byte(c.content))
+			if (err != nil) != c.isErr {
+				t.Fatalf("Expected error %t. Got %v", c.isErr, err)
+			}
+
+			if c.isErr {
+				return
+			}
+
+			if code.Size != c.expectedSize {
+				t.Fatalf("Expected size %d. Got %d", c.expectedSize, code.Size)
+			}
+
+			// The finder pattern on the three corners
+			for _, corner := range [][2]int{{0, 0}, {code.Size - 7, 0}, {0, code.Size - 7}} {
+				for i := 0; i < 7; i++ {
+					if !code.Dark(corner[0]+i, corner[1]) || !code.Dark(corner[0], corner[1]+i) {
+						t.Fatalf("Expected finder pattern at %v", corner)
+					}
+				}
+				if code.Dark(corner[0]+1, corner[1]+1) || !code.Dark(corner[0]+3, corner[1]+3) {
+					t.Fatalf("Expected finder pattern at %v", corner)
+				}
+			}
+
+			// Level M is 00 and the mask use the next three bits
+			bits := formatBits(code) ^ 0x5412
+			if bits>>13 != 0 {
+				t.Fatalf("Expected level M format bits. Got %015b", bits)
+			}
+
+			if !code.Dark(8, code.Size-8) {
+				t.Fatal("Expected the dark module")
+			}
+		})
+	}
+}
+
+func TestCodeImage(t *testing.T) {
+	code, err := qrcode.Encode([]byte("uhomestay"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := code.Image(4, 4)
+	if img.Bounds().Dx() != (21+8)*4 {
+		t.Fatalf("Expected width %d. Got %d", (21+8)*4, img.Bounds().Dx())
+	}
+
+	if img.GrayAt(0, 0).Y != 255 {
+		t.Fatal("Expected light quiet zone")
+	}
+
+	if img.GrayAt(16, 16).Y != 0 {
+		t.Fatal("Expected dark finder pattern")
+	}
+}
+
+// Level M blocks of the version 1 to 10
+// Ref: ISO/IEC 18004:2015 table 9
+var blocksM = [...]struct{ total, ecPerBlock, blocks int }{
+	1:  {26, 10, 1},
+	2:  {44, 16, 1},
+	3:  {70, 26, 1},
+	4:  {100, 18, 2},
+	5:  {134, 24, 2},
+	6:  {172, 16, 4},
+	7:  {196, 18, 4},
+	8:  {242, 22, 4},
+	9:  {292, 22, 5},
+	10: {346, 26, 5},
+}
+
+// Ref: ISO/IEC 18004:2015 table E.1
+var alignmentsM = [...][]int{
+	2:  {6, 18},
+	3:  {6, 22},
+	4:  {6, 26},
+	5:  {6, 30},
+	6:  {6, 34},
+	7:  {6, 22, 38},
+	8:  {6, 24, 42},
+	9:  {6, 26, 46},
+	10: {6, 28, 50},
+}
+
+func gfMul(x, y byte) byte {
+	var z byte
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x1d)
+		z ^= ((y >> i) & 1) * x
+	}
+
+	return z
+}
+
+// A block with its error correction is a multiple of the generator, so it
+// evaluate to zero at the roots a^0 to a^(ec-1)
+func hasZeroSyndromes(block []byte, ec int) bool {
+	var root byte = 1
+	for i := 0; i < ec; i++ {
+		var v byte
+		for _, b := range block {
+			v = gfMul(v, root) ^ b
+		}
+		if v != 0 {
+			return false
+		}
+		root = gfMul(root, 2)
+	}
+
+	return true
+}
+
+// BCH code of the format or version information
+func bch(data, poly, dataBits, ecBits int) int {
+	rem := data << ecBits
+	for i := dataBits + ecBits - 1; i >= ecBits; i-- {
+		if (rem>>i)&1 == 1 {
+			rem ^= poly << (i - ecBits)
+		}
+	}
+
+	return data<<ecBits | rem
+}
+
+func isMasked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// Read the content back the way a scanner does, independently of the
+// encoder, checking the format, the version, and the error correction
+// Ref: ISO/IEC 18004:2015 section 12
+func decode(c *qrcode.Code) ([]byte, error) {
+	version := (c.Size - 17) / 4
+	if version < 1 || version >= len(blocksM) || c.Size != version*4+17 {
+		return nil, errors.New("unexpected size")
+	}
+
+	bits := formatBits(c)
+	second := 0
+	for i := 14; i >= 8; i-- {
+		second <<= 1
+		if c.Dark(8, c.Size-15+i) {
+			second |= 1
+		}
+	}
+	for i := 7; i >= 0; i-- {
+		second <<= 1
+		if c.Dark(c.Size-1-i, 8) {
+			second |= 1
+		}
+	}
+	if bits != second {
+		return nil, errors.New("format copies differ")
+	}
+
+	bits ^= 0x5412
+	if bits != bch(bits>>10, 0x537, 5, 10) || bits>>13 != 0 {
+		return nil, errors.New("invalid level M format")
+	}
+	mask := (bits >> 10) & 7
+
+	if version >= 7 {
+		info := 0
+		for i := 17; i >= 0; i-- {
+			a, b := c.Size-11+i%3, i/3
+			if c.Dark(a, b) != c.Dark(b, a) {
+				return nil, errors.New("version copies differ")
+			}
+			info <<= 1
+			if c.Dark(a, b) {
+				info |= 1
+			}
+		}
+		if info != bch(version, 0x1f25, 6, 12) {
+			return nil, errors.New("invalid version information")
+		}
+	}
+
+	isFunction := func(x, y int) bool {
+		switch {
+		case x <= 8 && y <= 8, x >= c.Size-8 && y <= 8, x <= 8 && y >= c.Size-8:
+			return true
+		case x == 6 || y == 6:
+			return true
+		case version >= 7 && x >= c.Size-11 && y < 6, version >= 7 && y >= c.Size-11 && x < 6:
+			return true
+		}
+
+		a := alignmentsM[version]
+		for i, ax := range a {
+			for j, ay := range a {
+				if (i == 0 && j == 0) || (i == 0 && j == len(a)-1) || (i == len(a)-1 && j == 0) {
+					continue
+				}
+				if x >= ax-2 && x <= ax+2 && y >= ay-2 && y <= ay+2 {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+
+	var codewords []byte
+	var cw, n int
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if isFunction(x, y) {
+					continue
+				}
+				cw <<= 1
+				if c.Dark(x, y) != isMasked(mask, x, y) {
+					cw |= 1
+				}
+				if n++; n%8 == 0 {
+					codewords = append(codewords, byte(cw))
+					cw = 0
+				}
+			}
+		}
+	}
+
+	info := blocksM[version]
+	if len(codewords) != info.total {
+		return nil, errors.New("unexpected number of codewords")
+	}
+
+	shortBlocks := info.blocks - info.total%info.blocks
+	shortData := info.total/info.blocks - info.ecPerBlock
+	blocks := make([][]byte, info.blocks)
+	i := 0
+	for k := 0; k <= shortData; k++ {
+		for b := range blocks {
+			if k < shortData || b >= shortBlocks {
+				blocks[b] = append(blocks[b], codewords[i])
+				i++
+			}
+		}
+	}
+	for k := 0; k < info.ecPerBlock; k++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[i])
+			i++
+		}
+	}
+
+	var data []byte
+	for _, b := range blocks {
+		if !hasZeroSyndromes(b, info.ecPerBlock) {
+			return nil, errors.New("invalid error correction")
+		}
+		data = append(data, b[:len(b)-info.ecPerBlock]...)
+	}
+
+	pos := 0
+	read := func(n int) int {
+		v := 0
+		for k := 0; k < n; k++ {
+			v = v<<1 | int(data[pos/8]>>(7-pos%8)&1)
+			pos++
+		}
+		return v
+	}
+
+	if read(4) != 0x4 {
+		return nil, errors.New("expected byte mode")
+	}
+
+	countBits := 8
+	if version >= 10 {
+		countBits = 16
+	}
+	count := read(countBits)
+	if pos+count*8 > len(data)*8 {
+		return nil, errors.New("count over the capacity")
+	}
+
+	content := make([]byte, count)
+	for k := range content {
+		content[k] = byte(read(8))
+	}
+
+	if len(data)*8-pos >= 4 && read(4) != 0 {
+		return nil, errors.New("expected terminator")
+	}
+
+	return content, nil
+}
+
+func TestDecodeSyndromes(t *testing.T) {
+	// The 1-M symbol of "01234567"
+	// Ref: ISO/IEC 18004:2015 annex I
+	block := []byte{
+		0x10, 0x20, 0x0c, 0x56, 0x61, 0x80, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11,
+		0xa5, 0x24, 0xd4, 0xc1, 0xed, 0x36, 0xc7, 0x87, 0x2c, 0x55,
+	}
+	if !hasZeroSyndromes(block, 10) {
+		t.Fatal("Expected zero syndromes")
+	}
+
+	block[3] ^= 0x01
+	if hasZeroSyndromes(block, 10) {
+		t.Fatal("Expected non zero syndromes")
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	testCases := []struct {
+		name            string
+		content         string
+		expectedVersion int
+	}{
+		{
+			name:            "Round Trip Version 1",
+			content:         "uhomestay",
+			expectedVersion: 1,
+		},
+		{
+			name:            "Round Trip Version 4, Two Blocks",
+			content:         strings.Repeat("b", 60),
+			expectedVersion: 4,
+		},
+		{
+			name:            "Round Trip Member Card Verify Url",
+			content:         "https://uhomestay.herokuapp.com/api/v1/cards/verify?token=AZQ0i3x5dR-mkH0oVnWXyAAAAABpS2Fg.Sk9ZhcD9Vn1k1Jmvb2HtC3N9lA0jGiT7mC0m0ZrVqQc",
+			expectedVersion: 8,
+		},
+		{
+			name:            "Round Trip Version 9, Blocks of Two Lengths",
+			content:         strings.Repeat("\x00\xff", 90),
+			expectedVersion: 9,
+		},
+		{
+			name:            "Round Trip Version 10",
+			content:         strings.Repeat("z", 213),
+			expectedVersion: 10,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			code, err := qrcode.Encode([]byte(c.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if version := (code.Size - 17) / 4; version != c.expectedVersion {
+				t.Fatalf("Expected version %d. Got %d", c.expectedVersion, version)
+			}
+
+			content, err := decode(code)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(content, []byte(c.content)) {
+				t.Fatalf("Expected content %q. Got %q", c.content, content)
+			}
+		})
+	}
+}
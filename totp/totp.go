@@ -0,0 +1,119 @@
+// Package totp implement the time-based one-time password of RFC 6238 with
+// the parameters every authenticator app support: HMAC-SHA1, 6 digits, and
+// 30 seconds steps.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	Digits = 6
+	Period = 30
+	// Number of steps accepted before and after the current one, so a
+	// clock a little off still work
+	Skew = 1
+)
+
+var ErrInvalidSecret = errors.New("secret must be base32 encoded")
+
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// A random 160 bits secret, the size recommended for HMAC-SHA1
+func NewSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return b32.EncodeToString(b), nil
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := b32.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+
+	return key, nil
+}
+
+// Step of the time, the counter of the code
+func Step(t time.Time) int64 {
+	return t.Unix() / Period
+}
+
+func hotp(key []byte, counter int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	n := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	code := make([]byte, Digits)
+	for i := Digits - 1; i >= 0; i-- {
+		code[i] = byte('0' + n%10)
+		n /= 10
+	}
+
+	return string(code)
+}
+
+// The code of the secret at the time
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return hotp(key, Step(t)), nil
+}
+
+// Check the code against the steps around the time, the matching step is
+// returned so the caller can refuse a code used before
+func Verify(secret, code string, t time.Time) (step int64, ok bool) {
+	key, err := decodeSecret(secret)
+	if err != nil || len(code) != Digits {
+		return 0, false
+	}
+
+	current := Step(t)
+	for s := current - Skew; s <= current+Skew; s++ {
+		if hmac.Equal([]byte(hotp(key, s)), []byte(code)) {
+			return s, true
+		}
+	}
+
+	return 0, false
+}
+
+// The otpauth uri read by the authenticator apps, usually from a qr code
+func URI(issuer, account, secret string) string {
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", "6")
+	q.Set("period", "30")
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: q.Encode(),
+	}
+
+	return u.String()
+}
@@ -0,0 +1,102 @@
+package totp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/totp"
+)
+
+// The SHA1 secret "12345678901234567890" of RFC 6238 appendix B
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		unix     int64
+		expected string
+	}{
+		{name: "RFC 6238 time 59", unix: 59, expected: "287082"},
+		{name: "RFC 6238 time 1111111109", unix: 1111111109, expected: "081804"},
+		{name: "RFC 6238 time 1111111111", unix: 1111111111, expected: "050471"},
+		{name: "RFC 6238 time 1234567890", unix: 1234567890, expected: "005924"},
+		{name: "RFC 6238 time 2000000000", unix: 2000000000, expected: "279037"},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			code, err := totp.Code(rfcSecret, time.Unix(c.unix, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if code != c.expected {
+				t.Fatalf("Expected code %s. Got %s\n", c.expected, code)
+			}
+		})
+	}
+
+	if _, err := totp.Code("not base32!", time.Now()); err != totp.ErrInvalidSecret {
+		t.Fatalf("Expected error %v. Got %v\n", totp.ErrInvalidSecret, err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	code, _ := totp.Code(rfcSecret, now)
+	previous, _ := totp.Code(rfcSecret, now.Add(-totp.Period*time.Second))
+	stale, _ := totp.Code(rfcSecret, now.Add(-3*totp.Period*time.Second))
+
+	testCases := []struct {
+		name         string
+		code         string
+		expectedOk   bool
+		expectedStep int64
+	}{
+		{name: "Current step", code: code, expectedOk: true, expectedStep: totp.Step(now)},
+		{name: "Previous step within skew", code: previous, expectedOk: true, expectedStep: totp.Step(now) - 1},
+		{name: "Step outside skew", code: stale, expectedOk: false},
+		{name: "Wrong length", code: "12345", expectedOk: false},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			step, ok := totp.Verify(rfcSecret, c.code, now)
+			if ok != c.expectedOk {
+				t.Fatalf("Expected ok %t. Got %t\n", c.expectedOk, ok)
+			}
+
+			if ok && step != c.expectedStep {
+				t.Fatalf("Expected step %d. Got %d\n", c.expectedStep, step)
+			}
+		})
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	secret, err := totp.NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(secret) != 32 {
+		t.Fatalf("Expected secret length 32. Got %d\n", len(secret))
+	}
+
+	if _, err := totp.Code(secret, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := totp.URI("U-Homestay", "admin", rfcSecret)
+
+	if !strings.HasPrefix(uri, "otpauth://totp/U-Homestay:admin?") {
+		t.Fatalf("Unexpected uri %s\n", uri)
+	}
+
+	if !strings.Contains(uri, "secret="+rfcSecret) {
+		t.Fatalf("Expected the secret in uri %s\n", uri)
+	}
+}
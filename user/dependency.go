@@ -5,26 +5,38 @@ import (
 	"embed"
 	"io"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/cloudinary/cloudinary-go/api/uploader"
+	"github.com/pkg/errors"
 )
 
 type (
 	FileUploader func(filename string, file io.Reader) (string, error)
+	FileRemover  func(url string) error
 )
 
 type UserDeps struct {
-	JwtKey                 []byte
-	JwtIssuerUrl           string
-	Argon2Salt             string
-	JwtAudiences           []string
-	Upload                 FileUploader
-	Tmpl                   embed.FS
-	MemberRepository       *MemberRepository
-	PositionRepository     *PositionRepository
-	OrgStructureRepository *OrgStructureRepository
-	OrgPeriodRepository    *OrgPeriodRepository
-	GoalRepository         *GoalRepository
+	JwtKey                        []byte
+	JwtIssuerUrl                  string
+	Argon2Salt                    string
+	JwtAudiences                  []string
+	Upload                        FileUploader
+	Remove                        FileRemover
+	Tmpl                          embed.FS
+	MemberRepository              *MemberRepository
+	PositionRepository            *PositionRepository
+	OrgStructureRepository        *OrgStructureRepository
+	OrgPeriodRepository           *OrgPeriodRepository
+	GoalRepository                *GoalRepository
+	MemberErasureRepository       *MemberErasureRepository
+	MemberProfileChangeRepository *MemberProfileChangeRepository
+	MemberInvitationRepository    *MemberInvitationRepository
+	MemberTotpRepository          *MemberTotpRepository
+	LoginThrottleRepository       *LoginThrottleRepository
+	MemberSessionRepository       *MemberSessionRepository
+	FileRemovalRepository         *FileRemovalRepository
 }
 
 func NewDeps(
@@ -33,25 +45,41 @@ func NewDeps(
 	argon2Salt string,
 	jwtAudiences []string,
 	upload FileUploader,
+	remove FileRemover,
 	tmpl embed.FS,
 	memberRepository *MemberRepository,
 	positionRepository *PositionRepository,
 	orgStructureRepository *OrgStructureRepository,
 	orgPeriodRepository *OrgPeriodRepository,
 	goalRepository *GoalRepository,
+	memberErasureRepository *MemberErasureRepository,
+	memberProfileChangeRepository *MemberProfileChangeRepository,
+	memberInvitationRepository *MemberInvitationRepository,
+	memberTotpRepository *MemberTotpRepository,
+	loginThrottleRepository *LoginThrottleRepository,
+	memberSessionRepository *MemberSessionRepository,
+	fileRemovalRepository *FileRemovalRepository,
 ) *UserDeps {
 	return &UserDeps{
-		JwtKey:                 jwtKey,
-		JwtIssuerUrl:           jwtIssuerUrl,
-		Argon2Salt:             argon2Salt,
-		JwtAudiences:           jwtAudiences,
-		Upload:                 upload,
-		Tmpl:                   tmpl,
-		MemberRepository:       memberRepository,
-		PositionRepository:     positionRepository,
-		OrgStructureRepository: orgStructureRepository,
-		OrgPeriodRepository:    orgPeriodRepository,
-		GoalRepository:         goalRepository,
+		JwtKey:                        jwtKey,
+		JwtIssuerUrl:                  jwtIssuerUrl,
+		Argon2Salt:                    argon2Salt,
+		JwtAudiences:                  jwtAudiences,
+		Upload:                        upload,
+		Remove:                        remove,
+		Tmpl:                          tmpl,
+		MemberRepository:              memberRepository,
+		PositionRepository:            positionRepository,
+		OrgStructureRepository:        orgStructureRepository,
+		OrgPeriodRepository:           orgPeriodRepository,
+		GoalRepository:                goalRepository,
+		MemberErasureRepository:       memberErasureRepository,
+		MemberProfileChangeRepository: memberProfileChangeRepository,
+		MemberInvitationRepository:    memberInvitationRepository,
+		MemberTotpRepository:          memberTotpRepository,
+		LoginThrottleRepository:       loginThrottleRepository,
+		MemberSessionRepository:       memberSessionRepository,
+		FileRemovalRepository:         fileRemovalRepository,
 	}
 }
 
@@ -69,3 +97,45 @@ func FileUpload(uploadParams uploader.UploadParams, upload func(ctx context.Cont
 		return resp.SecureURL, nil
 	}
 }
+
+var fileVersionRe = regexp.MustCompile(`^v[0-9]+/`)
+
+func FileRemove(resourceType string, destroy func(ctx context.Context, params uploader.DestroyParams) (*uploader.DestroyResult, error)) FileRemover {
+	return func(url string) error {
+		// The public id is the path after the upload type and version,
+		// without the file extension
+		// The files not uploaded by the app, e.g. the urls of imported
+		// members, have nothing to remove
+		i := strings.Index(url, "/upload/")
+		if i == -1 {
+			return nil
+		}
+
+		// The resource type in the url takes precedence, the homestay
+		// images for example are uploaded as raw files
+		rt := resourceType
+		if j := strings.LastIndex(url[:i], "/"); j != -1 {
+			rt = url[j+1 : i]
+		}
+
+		publicId := fileVersionRe.ReplaceAllString(url[i+len("/upload/"):], "")
+		// Raw files keep the extension in their public id
+		if rt != "raw" {
+			publicId = publicId[:len(publicId)-len(filepath.Ext(publicId))]
+		}
+
+		resp, err := destroy(context.Background(), uploader.DestroyParams{
+			PublicID:     publicId,
+			ResourceType: rt,
+		})
+		if err != nil {
+			return err
+		}
+
+		if resp.Result != "ok" && resp.Result != "not found" {
+			return errors.New("destroy file: " + resp.Error.Message)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,54 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
+	"github.com/cloudinary/cloudinary-go/api/uploader"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileRemove(t *testing.T) {
+	testCases := []struct {
+		Name                 string
+		Url                  string
+		ExpectedPublicId     string
+		ExpectedResourceType string
+	}{
+		{
+			Name:                 "Remove Uploaded Image",
+			Url:                  "https://res.cloudinary.com/demo/image/upload/v1666000000/uhomestay/profile/photo.jpg",
+			ExpectedPublicId:     "uhomestay/profile/photo",
+			ExpectedResourceType: "image",
+		},
+		{
+			Name:                 "Remove Uploaded Raw File",
+			Url:                  "https://res.cloudinary.com/demo/raw/upload/v1666000000/uhomestay/homestay/photo.jpg",
+			ExpectedPublicId:     "uhomestay/homestay/photo.jpg",
+			ExpectedResourceType: "raw",
+		},
+		{
+			Name: "Remove Not Uploaded File, Nothing to Remove",
+			Url:  "https://example.com/photo.jpg",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			var params uploader.DestroyParams
+			remove := user.FileRemove("image", func(ctx context.Context, p uploader.DestroyParams) (*uploader.DestroyResult, error) {
+				params = p
+				return &uploader.DestroyResult{Result: "ok"}, nil
+			})
+
+			err := remove(c.Url)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, c.ExpectedPublicId, params.PublicID)
+			assert.Equal(t, c.ExpectedResourceType, params.ResourceType)
+		})
+	}
+}
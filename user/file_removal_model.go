@@ -0,0 +1,17 @@
+package user
+
+import (
+	"database/sql"
+	"time"
+)
+
+// An uploaded file that could not be removed, the removal is retried until
+// it succeeds and the reason of the last failure is kept
+type FileRemovalModel struct {
+	Id              uint64
+	Url             string
+	Attempts        int
+	LastError       string
+	LastAttemptedAt sql.NullTime
+	CreatedAt       time.Time
+}
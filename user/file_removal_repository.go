@@ -0,0 +1,134 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// The failed removals are always written outside of the request
+// transaction, the file may already be unreferenced by the time the
+// request ends with an error and roll back
+type FileRemovalRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewFileRemovalRepository(postgreDb *pgxpool.Pool) *FileRemovalRepository {
+	return &FileRemovalRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+func (r *FileRemovalRepository) Save(ctx context.Context, m FileRemovalModel) (nm FileRemovalModel, err error) {
+	sqlQuery := `
+		INSERT INTO file_removals (
+			url,
+			attempts,
+			last_error,
+			last_attempted_at,
+			created_at
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	var lastInsertId uint64
+	t := time.Now()
+
+	err = r.PostgreDb.QueryRow(
+		context.Background(),
+		sqlQuery,
+		m.Url,
+		m.Attempts,
+		m.LastError,
+		m.LastAttemptedAt,
+		t,
+	).Scan(&lastInsertId)
+
+	if err != nil {
+		return FileRemovalModel{}, err
+	}
+
+	m.Id = lastInsertId
+	m.CreatedAt = t
+
+	return m, nil
+}
+
+func (r *FileRemovalRepository) Query(ctx context.Context, limit int64) ([]FileRemovalModel, error) {
+	sqlQuery := `
+		SELECT
+			id,
+			url,
+			attempts,
+			last_error,
+			last_attempted_at,
+			created_at
+		FROM file_removals
+		ORDER BY id
+		LIMIT NULLIF($1::bigint, 0)
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		limit,
+	)
+	defer rows.Close()
+
+	var fps []*FileRemovalModel
+	if err := pgxscan.ScanAll(&fps, rows); err != nil {
+		return []FileRemovalModel{}, err
+	}
+
+	fs := make([]FileRemovalModel, len(fps))
+	for i, f := range fps {
+		fs[i] = *f
+	}
+
+	return fs, nil
+}
+
+func (r *FileRemovalRepository) UpdateAttemptById(ctx context.Context, id uint64, lastError string, t time.Time) error {
+	sqlQuery := `
+		UPDATE file_removals
+		SET
+			attempts = attempts + 1,
+			last_error = $1,
+			last_attempted_at = $2
+		WHERE id = $3
+	`
+
+	_, err := r.PostgreDb.Exec(
+		context.Background(),
+		sqlQuery,
+		lastError,
+		t,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *FileRemovalRepository) DeleteById(ctx context.Context, id uint64) error {
+	sqlQuery := `
+		DELETE FROM file_removals
+		WHERE id = $1
+	`
+
+	_, err := r.PostgreDb.Exec(
+		context.Background(),
+		sqlQuery,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package user
+
+import (
+	"net/http"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+)
+
+func (d *UserDeps) GetFileRemovals(w http.ResponseWriter, r *http.Request) {
+	limit := r.URL.Query().Get("limit")
+	out := d.QueryFileRemovals(r.Context(), limit)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
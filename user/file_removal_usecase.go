@@ -0,0 +1,135 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Remove a file no longer referenced, a failed removal does not fail the
+// caller. It is recorded with its reason and retried by the periodic
+// retry instead, the error is only returned when it can not be recorded.
+func (d *UserDeps) removeFile(ctx context.Context, url string) error {
+	if url == "" {
+		return nil
+	}
+
+	removeErr := d.Remove(url)
+	if removeErr == nil {
+		return nil
+	}
+
+	f := FileRemovalModel{
+		Url:       url,
+		Attempts:  1,
+		LastError: removeErr.Error(),
+	}
+	f.LastAttemptedAt.Scan(time.Now())
+
+	if _, err := d.FileRemovalRepository.Save(ctx, f); err != nil {
+		return errors.Wrap(err, "save file removal")
+	}
+
+	return nil
+}
+
+// Retry every failed removal once, the failed ones are counted and their
+// reason is recorded on each removal
+func (d *UserDeps) RetryFileRemovals(ctx context.Context) (failed int, err error) {
+	removals, err := d.FileRemovalRepository.Query(ctx, 0)
+	if err != nil {
+		return 0, errors.Wrap(err, "query file removals")
+	}
+
+	for _, f := range removals {
+		if removeErr := d.Remove(f.Url); removeErr != nil {
+			if err = d.FileRemovalRepository.UpdateAttemptById(ctx, f.Id, removeErr.Error(), time.Now()); err != nil {
+				return failed, errors.Wrap(err, "update file removal attempt by id")
+			}
+
+			failed++
+			continue
+		}
+
+		if err = d.FileRemovalRepository.DeleteById(ctx, f.Id); err != nil {
+			return failed, errors.Wrap(err, "delete file removal by id")
+		}
+	}
+
+	return failed, nil
+}
+
+// Retry the failed removals on each interval until the context is done,
+// meant to be run in its own goroutine. The removals still failing are
+// shown to the admins with their last error, a retry that could not be
+// recorded is tried again on the next interval.
+func (d *UserDeps) RunFileRemovalRetry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = d.RetryFileRemovals(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type (
+	FileRemovalRes struct {
+		Id              int64     `json:"id"`
+		Url             string    `json:"url"`
+		Attempts        int       `json:"attempts"`
+		LastError       string    `json:"last_error"`
+		LastAttemptedAt null.Time `json:"last_attempted_at"`
+		CreatedAt       time.Time `json:"created_at"`
+	}
+	QueryFileRemovalsRes struct {
+		FileRemovals []FileRemovalRes `json:"file_removals"`
+	}
+	QueryFileRemovalsOut struct {
+		resp.Response
+		Res QueryFileRemovalsRes
+	}
+)
+
+// The uploaded files waiting to be removed again
+func (d *UserDeps) QueryFileRemovals(ctx context.Context, limit string) (out QueryFileRemovalsOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	l, _ := strconv.ParseInt(limit, 10, 64)
+	if l <= 0 || l > 100 {
+		l = 25
+	}
+
+	removals, err := d.FileRemovalRepository.Query(ctx, l)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query file removals"))
+		return
+	}
+
+	res := make([]FileRemovalRes, len(removals))
+	for i, f := range removals {
+		res[i] = FileRemovalRes{
+			Id:              int64(f.Id),
+			Url:             f.Url,
+			Attempts:        f.Attempts,
+			LastError:       f.LastError,
+			LastAttemptedAt: null.NewTime(f.LastAttemptedAt.Time, f.LastAttemptedAt.Valid),
+			CreatedAt:       f.CreatedAt,
+		}
+	}
+
+	out.Res.FileRemovals = res
+
+	return
+}
@@ -23,15 +23,22 @@ import (
 )
 
 var (
-	db                  *pgxpool.Pool
-	memberRepository    *user.MemberRepository
-	positionRepository  *user.PositionRepository
-	orgRepository       *user.OrgStructureRepository
-	orgPeriodRepository *user.OrgPeriodRepository
-	goalRepository      *user.GoalRepository
-	userDeps            *user.UserDeps
-	tmpl                embed.FS
-	conf                = config.Config{
+	db                            *pgxpool.Pool
+	memberRepository              *user.MemberRepository
+	positionRepository            *user.PositionRepository
+	orgRepository                 *user.OrgStructureRepository
+	orgPeriodRepository           *user.OrgPeriodRepository
+	goalRepository                *user.GoalRepository
+	memberErasureRepository       *user.MemberErasureRepository
+	memberProfileChangeRepository *user.MemberProfileChangeRepository
+	memberInvitationRepository    *user.MemberInvitationRepository
+	memberTotpRepository          *user.MemberTotpRepository
+	loginThrottleRepository       *user.LoginThrottleRepository
+	memberSessionRepository       *user.MemberSessionRepository
+	fileRemovalRepository         *user.FileRemovalRepository
+	userDeps                      *user.UserDeps
+	tmpl                          embed.FS
+	conf                          = config.Config{
 		JwtKey:          []byte("testestestest"),
 		JwtAudiencesStr: "this",
 		JwtKeyStr:       "testestestest",
@@ -112,6 +119,10 @@ var (
 	}
 )
 
+var remove user.FileRemover = func(url string) error {
+	return nil
+}
+
 var upload user.FileUploader = func(filename string, file io.Reader) (string, error) {
 	return filename, nil
 }
@@ -159,6 +170,9 @@ func ClearTables(conn *pgxpool.Pool) error {
 		`TRUNCATE positions CASCADE`,
 		`TRUNCATE org_periods CASCADE`,
 		`TRUNCATE goals CASCADE`,
+		`TRUNCATE login_lock_histories`,
+		`TRUNCATE login_throttles`,
+		`TRUNCATE file_removals`,
 	}
 
 	for _, v := range queries {
@@ -319,6 +333,13 @@ func TestMain(m *testing.M) {
 	orgRepository = user.NewOrgStructureRepository(db)
 	orgPeriodRepository = user.NewOrgPeriodRepository(db)
 	goalRepository = user.NewGoalRepository(db)
+	memberErasureRepository = user.NewMemberErasureRepository(db)
+	memberProfileChangeRepository = user.NewMemberProfileChangeRepository(db)
+	memberInvitationRepository = user.NewMemberInvitationRepository(db)
+	memberTotpRepository = user.NewMemberTotpRepository(db)
+	loginThrottleRepository = user.NewLoginThrottleRepository(db)
+	memberSessionRepository = user.NewMemberSessionRepository(db)
+	fileRemovalRepository = user.NewFileRemovalRepository(db)
 
 	userDeps = user.NewDeps(
 		conf.JwtKey,
@@ -326,12 +347,20 @@ func TestMain(m *testing.M) {
 		conf.Argon2Salt,
 		conf.JwtAudiences,
 		upload,
+		remove,
 		tmpl,
 		memberRepository,
 		positionRepository,
 		orgRepository,
 		orgPeriodRepository,
 		goalRepository,
+		memberErasureRepository,
+		memberProfileChangeRepository,
+		memberInvitationRepository,
+		memberTotpRepository,
+		loginThrottleRepository,
+		memberSessionRepository,
+		fileRemovalRepository,
 	)
 
 	if err := LoadTables(db); err != nil {
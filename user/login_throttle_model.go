@@ -0,0 +1,137 @@
+package user
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type LoginThrottleKind struct {
+	String string
+}
+
+var (
+	LoginThrottleUnknown  = LoginThrottleKind{""}
+	LoginThrottleUsername = LoginThrottleKind{"username"}
+	LoginThrottleIp       = LoginThrottleKind{"ip"}
+)
+
+func loginThrottleKindFromString(s string) (LoginThrottleKind, error) {
+	switch s {
+	case LoginThrottleUsername.String:
+		return LoginThrottleUsername, nil
+	case LoginThrottleIp.String:
+		return LoginThrottleIp, nil
+	}
+
+	return LoginThrottleUnknown, errors.New("unknown type: " + s)
+}
+
+func (u *LoginThrottleKind) Scan(src interface{}) error {
+	if src == nil {
+		u.String = ""
+		return nil
+	}
+
+	s, ok := src.(string)
+	if !ok {
+		u.String = ""
+		return nil
+	}
+
+	k, _ := loginThrottleKindFromString(s)
+	u.String = k.String
+	return nil
+}
+
+func (u LoginThrottleKind) Value() (driver.Value, error) {
+	k, err := loginThrottleKindFromString(u.String)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.String, nil
+}
+
+type LoginLockAction struct {
+	String string
+}
+
+var (
+	LoginLockUnknown  = LoginLockAction{""}
+	LoginLockLocked   = LoginLockAction{"locked"}
+	LoginLockUnlocked = LoginLockAction{"unlocked"}
+)
+
+func loginLockActionFromString(s string) (LoginLockAction, error) {
+	switch s {
+	case LoginLockLocked.String:
+		return LoginLockLocked, nil
+	case LoginLockUnlocked.String:
+		return LoginLockUnlocked, nil
+	}
+
+	return LoginLockUnknown, errors.New("unknown type: " + s)
+}
+
+func (u *LoginLockAction) Scan(src interface{}) error {
+	if src == nil {
+		u.String = ""
+		return nil
+	}
+
+	s, ok := src.(string)
+	if !ok {
+		u.String = ""
+		return nil
+	}
+
+	a, _ := loginLockActionFromString(s)
+	u.String = a.String
+	return nil
+}
+
+func (u LoginLockAction) Value() (driver.Value, error) {
+	a, err := loginLockActionFromString(u.String)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.String, nil
+}
+
+// Failed logins of a username or an ip, the login is refused until
+// blocked_until. LockedAt is set when the failures reach the lockout.
+type LoginThrottleModel struct {
+	Kind         LoginThrottleKind
+	Identifier   string
+	Failures     int
+	BlockedUntil sql.NullTime
+	LockedAt     sql.NullTime
+	LastFailedAt time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type LoginLockModel struct {
+	Username     string
+	MemberId     string
+	MemberName   string
+	Failures     int
+	BlockedUntil time.Time
+	LockedAt     time.Time
+}
+
+// A lockout or an unlock of a login, ActorId is the admin unlocking it and
+// is empty for a lockout
+type LoginLockHistoryModel struct {
+	Id         uint64
+	Kind       LoginThrottleKind
+	Identifier string
+	Action     LoginLockAction
+	Failures   int
+	ActorId    string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,228 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// The failures are always written outside of the request transaction, a
+// failed login ends with an error response which roll back the transaction
+// and would forget the failure
+type LoginThrottleRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewLoginThrottleRepository(postgreDb *pgxpool.Pool) *LoginThrottleRepository {
+	return &LoginThrottleRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+// Throttles of the username and the ip still blocking at the time
+func (r *LoginThrottleRepository) FindBlocked(ctx context.Context, username, ip string, t time.Time) ([]LoginThrottleModel, error) {
+	sqlQuery := `
+		SELECT
+			kind,
+			identifier,
+			failures,
+			blocked_until,
+			locked_at,
+			last_failed_at,
+			created_at,
+			updated_at
+		FROM login_throttles
+		WHERE blocked_until > $3
+		AND (
+			(kind = 'username' AND identifier = $1)
+			OR (kind = 'ip' AND identifier = $2)
+		)
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		username,
+		ip,
+		t,
+	)
+	defer rows.Close()
+
+	var lps []*LoginThrottleModel
+	if err := pgxscan.ScanAll(&lps, rows); err != nil {
+		return []LoginThrottleModel{}, err
+	}
+
+	ls := make([]LoginThrottleModel, len(lps))
+	for i, l := range lps {
+		ls[i] = *l
+	}
+
+	return ls, nil
+}
+
+// Count a failure and return the failures so far, the count start over
+// when the last failure is before windowStart
+func (r *LoginThrottleRepository) AddFailure(ctx context.Context, kind LoginThrottleKind, identifier string, t, windowStart time.Time) (failures int, err error) {
+	sqlQuery := `
+		INSERT INTO login_throttles (
+			kind,
+			identifier,
+			failures,
+			last_failed_at,
+			created_at,
+			updated_at
+		)
+		VALUES ($1, $2, 1, $3, $3, $3)
+		ON CONFLICT (kind, identifier) DO UPDATE
+		SET
+			failures = CASE
+				WHEN login_throttles.last_failed_at < $4 THEN 1
+				ELSE login_throttles.failures + 1
+			END,
+			locked_at = CASE
+				WHEN login_throttles.last_failed_at < $4 THEN NULL
+				ELSE login_throttles.locked_at
+			END,
+			last_failed_at = EXCLUDED.last_failed_at,
+			updated_at = EXCLUDED.updated_at
+		RETURNING failures
+	`
+
+	err = r.PostgreDb.QueryRow(
+		context.Background(),
+		sqlQuery,
+		kind,
+		identifier,
+		t,
+		windowStart,
+	).Scan(&failures)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return failures, nil
+}
+
+// Refuse the login until blockedUntil, the throttle is marked as locked
+// when isLocked
+func (r *LoginThrottleRepository) BlockUntil(ctx context.Context, kind LoginThrottleKind, identifier string, blockedUntil time.Time, isLocked bool) error {
+	sqlQuery := `
+		UPDATE login_throttles
+		SET
+			blocked_until = $3,
+			locked_at = CASE WHEN $4::boolean THEN COALESCE(locked_at, $5) ELSE locked_at END,
+			updated_at = $5
+		WHERE kind = $1
+		AND identifier = $2
+	`
+
+	_, err := r.PostgreDb.Exec(
+		context.Background(),
+		sqlQuery,
+		kind,
+		identifier,
+		blockedUntil,
+		isLocked,
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *LoginThrottleRepository) DeleteByIdentifier(ctx context.Context, kind LoginThrottleKind, identifier string) (n int64, err error) {
+	sqlQuery := `
+		DELETE FROM login_throttles
+		WHERE kind = $1
+		AND identifier = $2
+	`
+
+	cmd, err := r.PostgreDb.Exec(
+		context.Background(),
+		sqlQuery,
+		kind,
+		identifier,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return cmd.RowsAffected(), nil
+}
+
+func (r *LoginThrottleRepository) SaveLockHistory(ctx context.Context, m LoginLockHistoryModel) error {
+	sqlQuery := `
+		INSERT INTO login_lock_histories (
+			kind,
+			identifier,
+			action,
+			failures,
+			actor_id,
+			created_at
+		)
+		VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, $6)
+	`
+
+	_, err := r.PostgreDb.Exec(
+		context.Background(),
+		sqlQuery,
+		m.Kind,
+		m.Identifier,
+		m.Action,
+		m.Failures,
+		m.ActorId,
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Usernames locked at the time, with the member when the username
+// exists
+func (r *LoginThrottleRepository) QueryLocked(ctx context.Context, t time.Time, limit int64) ([]LoginLockModel, error) {
+	sqlQuery := `
+		SELECT
+			lt.identifier AS username,
+			COALESCE(m.id::text, '') AS member_id,
+			COALESCE(m.name, '') AS member_name,
+			lt.failures,
+			lt.blocked_until,
+			lt.locked_at
+		FROM login_throttles lt
+			LEFT JOIN members m ON LOWER(m.username) = lt.identifier AND m.deleted_at IS NULL
+		WHERE lt.kind = 'username'
+		AND lt.locked_at IS NOT NULL
+		AND lt.blocked_until > $1
+		ORDER BY lt.locked_at DESC
+		LIMIT $2
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		t,
+		limit,
+	)
+	defer rows.Close()
+
+	var lps []*LoginLockModel
+	if err := pgxscan.ScanAll(&lps, rows); err != nil {
+		return []LoginLockModel{}, err
+	}
+
+	ls := make([]LoginLockModel, len(lps))
+	for i, l := range lps {
+		ls[i] = *l
+	}
+
+	return ls, nil
+}
@@ -0,0 +1,271 @@
+package user
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	LoginMaxBackoff    = 5 * time.Minute
+	LoginFailureWindow = 24 * time.Hour
+	LoginLockout       = 30 * time.Minute
+	// Failures allowed before the login has to wait, and before the lockout.
+	// An ip is shared by many members behind the same network, so it is
+	// given more than a username.
+	UsernameFreeAttempts = 3
+	UsernameMaxFailures  = 10
+	IpFreeAttempts       = 10
+	IpMaxFailures        = 50
+)
+
+var (
+	ErrLoginThrottled = errors.New("terlalu banyak percobaan login gagal")
+	ErrLoginLocked    = errors.New("akun dikunci sementara karena terlalu banyak percobaan login gagal")
+)
+
+func loginThrottleUsername(identifier string) string {
+	return strings.ToLower(strings.Trim(identifier, " "))
+}
+
+// The wait after the free attempts doubles on each failure
+func loginBackoff(kind LoginThrottleKind, failures int) time.Duration {
+	freeAttempts := UsernameFreeAttempts
+	if kind == LoginThrottleIp {
+		freeAttempts = IpFreeAttempts
+	}
+
+	if failures <= freeAttempts {
+		return 0
+	}
+
+	backoff := time.Second
+	for i := freeAttempts + 1; i < failures && backoff < LoginMaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > LoginMaxBackoff {
+		return LoginMaxBackoff
+	}
+
+	return backoff
+}
+
+func loginMaxFailures(kind LoginThrottleKind) int {
+	if kind == LoginThrottleIp {
+		return IpMaxFailures
+	}
+
+	return UsernameMaxFailures
+}
+
+// Refuse the login while the username or the ip is still blocked
+func (d *UserDeps) loginThrottleResponse(ctx context.Context, username, ip string) resp.Response {
+	t := time.Now()
+	throttles, err := d.LoginThrottleRepository.FindBlocked(ctx, loginThrottleUsername(username), ip, t)
+	if err != nil {
+		return resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find blocked login throttle"))
+	}
+
+	if len(throttles) == 0 {
+		return resp.NewResponse(http.StatusOK, "", nil)
+	}
+
+	var blockedUntil time.Time
+	isLocked := false
+	for _, v := range throttles {
+		if v.BlockedUntil.Time.After(blockedUntil) {
+			blockedUntil = v.BlockedUntil.Time
+		}
+		if v.Kind == LoginThrottleUsername && v.LockedAt.Valid {
+			isLocked = true
+		}
+	}
+
+	err = ErrLoginThrottled
+	if isLocked {
+		err = ErrLoginLocked
+	}
+
+	seconds := int64(math.Ceil(blockedUntil.Sub(t).Seconds()))
+	message := err.Error() + ", coba lagi dalam " + strconv.FormatInt(seconds, 10) + " detik"
+
+	return resp.NewResponse(http.StatusTooManyRequests, message, err)
+}
+
+func (d *UserDeps) addLoginFailure(ctx context.Context, kind LoginThrottleKind, identifier string) error {
+	t := time.Now()
+	failures, err := d.LoginThrottleRepository.AddFailure(ctx, kind, identifier, t, t.Add(-LoginFailureWindow))
+	if err != nil {
+		return errors.Wrap(err, "add login failure")
+	}
+
+	isLocked := failures >= loginMaxFailures(kind)
+	backoff := loginBackoff(kind, failures)
+	if isLocked {
+		backoff = LoginLockout
+	}
+
+	if backoff == 0 {
+		return nil
+	}
+
+	if err = d.LoginThrottleRepository.BlockUntil(ctx, kind, identifier, t.Add(backoff), isLocked); err != nil {
+		return errors.Wrap(err, "block login throttle")
+	}
+
+	if isLocked {
+		lh := LoginLockHistoryModel{
+			Kind:       kind,
+			Identifier: identifier,
+			Action:     LoginLockLocked,
+			Failures:   failures,
+		}
+		if err = d.LoginThrottleRepository.SaveLockHistory(ctx, lh); err != nil {
+			return errors.Wrap(err, "save login lock history")
+		}
+	}
+
+	return nil
+}
+
+// Count the failure of the username and the ip then give back the
+// response of the failure
+func (d *UserDeps) loginFailedResponse(ctx context.Context, username, ip string, res resp.Response) resp.Response {
+	if err := d.addLoginFailure(ctx, LoginThrottleUsername, loginThrottleUsername(username)); err != nil {
+		return resp.NewResponse(http.StatusInternalServerError, "", err)
+	}
+
+	if ip != "" {
+		if err := d.addLoginFailure(ctx, LoginThrottleIp, ip); err != nil {
+			return resp.NewResponse(http.StatusInternalServerError, "", err)
+		}
+	}
+
+	return res
+}
+
+// The failures of the username are forgotten once the jwt is issued, the
+// ones of the ip only expire so a valid account can not clear them
+func (d *UserDeps) resetLoginFailures(ctx context.Context, username string) error {
+	if _, err := d.LoginThrottleRepository.DeleteByIdentifier(ctx, LoginThrottleUsername, loginThrottleUsername(username)); err != nil {
+		return errors.Wrap(err, "delete login throttle by identifier")
+	}
+
+	return nil
+}
+
+type (
+	LoginLockRes struct {
+		Username     string    `json:"username"`
+		MemberId     string    `json:"member_id"`
+		MemberName   string    `json:"member_name"`
+		Failures     int       `json:"failures"`
+		BlockedUntil time.Time `json:"blocked_until"`
+		LockedAt     time.Time `json:"locked_at"`
+	}
+	QueryLoginLocksRes struct {
+		Locks []LoginLockRes `json:"locks"`
+	}
+	QueryLoginLocksOut struct {
+		resp.Response
+		Res QueryLoginLocksRes
+	}
+)
+
+func (d *UserDeps) QueryLoginLocks(ctx context.Context, limit string) (out QueryLoginLocksOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	l, _ := strconv.ParseInt(limit, 10, 64)
+	if l <= 0 {
+		l = 25
+	}
+
+	locks, err := d.LoginThrottleRepository.QueryLocked(ctx, time.Now(), l)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query locked login throttle"))
+		return
+	}
+
+	res := make([]LoginLockRes, len(locks))
+	for i, v := range locks {
+		res[i] = LoginLockRes{
+			Username:     v.Username,
+			MemberId:     v.MemberId,
+			MemberName:   v.MemberName,
+			Failures:     v.Failures,
+			BlockedUntil: v.BlockedUntil,
+			LockedAt:     v.LockedAt,
+		}
+	}
+
+	out.Res.Locks = res
+
+	return
+}
+
+type (
+	UnlockMemberRes struct {
+		Id string `json:"id"`
+	}
+	UnlockMemberOut struct {
+		resp.Response
+		Res UnlockMemberRes
+	}
+)
+
+// Clear the failures of the username of the member, the member can log in
+// right away. The unlock is recorded with the admin doing it.
+func (d *UserDeps) UnlockMember(ctx context.Context, uid, actorUid string) (out UnlockMemberOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if _, err = uuid.FromString(uid); err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	member, err := d.MemberRepository.FindById(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by id"))
+		return
+	}
+
+	identifier := loginThrottleUsername(member.Username)
+	n, err := d.LoginThrottleRepository.DeleteByIdentifier(ctx, LoginThrottleUsername, identifier)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "delete login throttle by identifier"))
+		return
+	}
+
+	if n != 0 {
+		lh := LoginLockHistoryModel{
+			Kind:       LoginThrottleUsername,
+			Identifier: identifier,
+			Action:     LoginLockUnlocked,
+			ActorId:    actorUid,
+		}
+		if err = d.LoginThrottleRepository.SaveLockHistory(ctx, lh); err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save login lock history"))
+			return
+		}
+	}
+
+	out.Res.Id = uid
+
+	return
+}
@@ -0,0 +1,202 @@
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// Let the next attempt through without waiting for the backoff
+func clearLoginBackoff() error {
+	_, err := db.Exec(context.Background(), `UPDATE login_throttles SET blocked_until = NULL WHERE locked_at IS NULL`)
+	return err
+}
+
+func TestLoginThrottle(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrong := user.LoginIn{
+		Identifier: memberNormal.Username,
+		Password:   "wrong-password",
+		Ip:         "10.0.0.1",
+	}
+	right := user.LoginIn{
+		Identifier: memberNormal.Username,
+		Password:   memberNormal.Password,
+		Ip:         "10.0.0.1",
+	}
+
+	for i := 0; i < user.UsernameFreeAttempts; i++ {
+		res := userDeps.MemberLogin(context.Background(), wrong)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	}
+
+	res := userDeps.MemberLogin(context.Background(), right)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusOK, res.StatusCode)
+	}
+
+	// The success clear the failures of the username
+	for i := 0; i <= user.UsernameFreeAttempts; i++ {
+		res := userDeps.MemberLogin(context.Background(), wrong)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	}
+
+	res = userDeps.MemberLogin(context.Background(), right)
+	if res.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusTooManyRequests, res.StatusCode)
+	}
+
+	assert.True(t, errors.Is(res.Error, user.ErrLoginThrottled))
+
+	for i := user.UsernameFreeAttempts + 1; i < user.UsernameMaxFailures; i++ {
+		if err = clearLoginBackoff(); err != nil {
+			t.Fatal(err)
+		}
+
+		res := userDeps.MemberLogin(context.Background(), wrong)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	}
+
+	if err = clearLoginBackoff(); err != nil {
+		t.Fatal(err)
+	}
+
+	res = userDeps.MemberLogin(context.Background(), right)
+	if res.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusTooManyRequests, res.StatusCode)
+	}
+
+	assert.True(t, errors.Is(res.Error, user.ErrLoginLocked))
+
+	locks := userDeps.QueryLoginLocks(context.Background(), "")
+	if assert.Len(t, locks.Res.Locks, 1) {
+		assert.Equal(t, memberNormal.Username, locks.Res.Locks[0].Username)
+	}
+}
+
+func TestUnlockMember(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adminUid, err := createUser(memberRepository, memberAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < user.UsernameMaxFailures; i++ {
+		if err = clearLoginBackoff(); err != nil {
+			t.Fatal(err)
+		}
+
+		userDeps.MemberLogin(context.Background(), user.LoginIn{
+			Identifier: memberNormal.Username,
+			Password:   "wrong-password",
+		})
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Uid                string
+	}{
+		{
+			Name:               "Unlock Member Success",
+			ExpectedStatusCode: http.StatusOK,
+			Uid:                uid,
+		},
+		{
+			Name:               "Unlock Member Fail, Member Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Uid:                "1ed4a9d6-0000-6000-8000-000000000000",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.UnlockMember(context.Background(), c.Uid, adminUid)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+
+	rows, err := db.Query(
+		context.Background(),
+		`SELECT action::text, COALESCE(actor_id::text, '') FROM login_lock_histories WHERE kind = 'username' ORDER BY id`,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var histories [][2]string
+	for rows.Next() {
+		var action, actorId string
+		if err = rows.Scan(&action, &actorId); err != nil {
+			t.Fatal(err)
+		}
+		histories = append(histories, [2]string{action, actorId})
+	}
+
+	assert.Equal(t, [][2]string{{"locked", ""}, {"unlocked", adminUid}}, histories)
+
+	login := userDeps.MemberLogin(context.Background(), user.LoginIn{
+		Identifier: memberNormal.Username,
+		Password:   memberNormal.Password,
+	})
+	assert.Equal(t, http.StatusOK, login.StatusCode)
+}
+
+func TestRegistrationStatusThrottle(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = createUser(memberRepository, pendingMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i <= user.UsernameFreeAttempts; i++ {
+		res := userDeps.FindRegistrationStatus(context.Background(), user.LoginIn{
+			Identifier: pendingMember.Username,
+			Password:   "wrong-password",
+			Ip:         "10.0.0.1",
+		})
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	}
+
+	res := userDeps.FindRegistrationStatus(context.Background(), user.LoginIn{
+		Identifier: pendingMember.Username,
+		Password:   pendingMember.Password,
+		Ip:         "10.0.0.1",
+	})
+	if res.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusTooManyRequests, res.StatusCode)
+	}
+
+	assert.True(t, errors.Is(res.Error, user.ErrLoginThrottled))
+}
@@ -0,0 +1,173 @@
+package user
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidMemberCard = errors.New("kartu anggota tidak valid")
+
+const MemberCardValidity = 365 * 24 * time.Hour
+
+// Signature of the tokens issued outside the jwt, the purpose keep a token
+// of one kind from being accepted as another
+func (d *UserDeps) tokenSignature(purpose string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, d.JwtKey)
+	mac.Write([]byte(purpose + "."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// The token carry the member id and the expiry signed with the jwt key,
+// kept short so the qr code stay small enough to scan from a printed card
+func (d *UserDeps) signMemberCardToken(id uuid.UUID, expiry time.Time) string {
+	payload := make([]byte, uuid.Size+8)
+	copy(payload, id.Bytes())
+	binary.BigEndian.PutUint64(payload[uuid.Size:], uint64(expiry.Unix()))
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(d.tokenSignature("member-card", payload))
+}
+
+func (d *UserDeps) parseMemberCardToken(token string) (uuid.UUID, time.Time, error) {
+	p := strings.Split(token, ".")
+	if len(p) != 2 {
+		return uuid.Nil, time.Time{}, ErrInvalidMemberCard
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(p[0])
+	if err != nil || len(payload) != uuid.Size+8 {
+		return uuid.Nil, time.Time{}, ErrInvalidMemberCard
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(p[1])
+	if err != nil || !hmac.Equal(signature, d.tokenSignature("member-card", payload)) {
+		return uuid.Nil, time.Time{}, ErrInvalidMemberCard
+	}
+
+	id, err := uuid.FromBytes(payload[:uuid.Size])
+	if err != nil {
+		return uuid.Nil, time.Time{}, ErrInvalidMemberCard
+	}
+
+	expiry := time.Unix(int64(binary.BigEndian.Uint64(payload[uuid.Size:])), 0)
+
+	return id, expiry, nil
+}
+
+type (
+	IssueMemberCardRes struct {
+		Token      string
+		ValidUntil time.Time
+	}
+	IssueMemberCardOut struct {
+		resp.Response
+		Res IssueMemberCardRes
+	}
+)
+
+// Only an approved and active member get a card
+func (d *UserDeps) IssueMemberCard(ctx context.Context, uid string) (out IssueMemberCardOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := uuid.FromString(uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	member, err := d.MemberRepository.FindById(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by id"))
+		return
+	}
+
+	if !member.IsApproved {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrNotApprovedMember)
+		return
+	}
+
+	if res := inactiveResponse(member); res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	validUntil := time.Now().Add(MemberCardValidity)
+	out.Res = IssueMemberCardRes{
+		Token:      d.signMemberCardToken(id, validUntil),
+		ValidUntil: validUntil,
+	}
+
+	return
+}
+
+type (
+	VerifyMemberCardRes struct {
+		Id            string    `json:"id"`
+		Name          string    `json:"name"`
+		ProfilePicUrl string    `json:"profile_pic_url"`
+		Status        string    `json:"status"`
+		ValidUntil    time.Time `json:"valid_until"`
+		IsExpired     bool      `json:"is_expired"`
+		IsActive      bool      `json:"is_active"`
+	}
+	VerifyMemberCardOut struct {
+		resp.Response
+		Res VerifyMemberCardRes
+	}
+)
+
+// Check the token scanned from a card, the card only count when it is
+// not expired and the member is still active today
+func (d *UserDeps) VerifyMemberCard(ctx context.Context, token string) (out VerifyMemberCardOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, validUntil, err := d.parseMemberCardToken(token)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", err)
+		return
+	}
+
+	member, err := d.MemberRepository.FindById(ctx, id.String())
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by id"))
+		return
+	}
+
+	status := EffectiveMemberStatus(member)
+	isExpired := !validUntil.After(time.Now())
+
+	out.Res = VerifyMemberCardRes{
+		Id:            id.String(),
+		Name:          member.Name,
+		ProfilePicUrl: member.ProfilePicUrl,
+		Status:        status.String,
+		ValidUntil:    validUntil,
+		IsExpired:     isExpired,
+		IsActive:      member.IsApproved && status == MemberStatusActive && !isExpired,
+	}
+
+	return
+}
@@ -0,0 +1,130 @@
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueMemberCard(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pendingUid, err := createUser(memberRepository, pendingMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Uid                string
+	}{
+		{
+			Name:               "Issue Member Card Success",
+			ExpectedStatusCode: http.StatusOK,
+			Uid:                uid,
+		},
+		{
+			Name:               "Issue Member Card Fail, Member Not Approved",
+			ExpectedStatusCode: http.StatusBadRequest,
+			Uid:                pendingUid,
+		},
+		{
+			Name:               "Issue Member Card Fail, Member Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Uid:                "5e6b0ba4-5e1c-4f5a-9a3f-0f4b6f3f8f1e",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.IssueMemberCard(context.Background(), c.Uid)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestVerifyMemberCard(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issued := userDeps.IssueMemberCard(context.Background(), uid)
+	if issued.Error != nil {
+		t.Fatal(issued.Error)
+	}
+
+	token := issued.Res.Token
+	tampered := []byte(token)
+	if tampered[0] == 'A' {
+		tampered[0] = 'B'
+	} else {
+		tampered[0] = 'A'
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		ExpectedActive     bool
+		Token              string
+	}{
+		{
+			Name:               "Verify Member Card Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedActive:     true,
+			Token:              token,
+		},
+		{
+			Name:               "Verify Member Card Fail, Token Tampered",
+			ExpectedStatusCode: http.StatusBadRequest,
+			Token:              string(tampered),
+		},
+		{
+			Name:               "Verify Member Card Fail, Not a Token",
+			ExpectedStatusCode: http.StatusBadRequest,
+			Token:              "not-a-token",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.VerifyMemberCard(context.Background(), c.Token)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if res.Error != nil {
+				return
+			}
+
+			assert.Equal(t, c.ExpectedActive, res.Res.IsActive)
+			assert.Equal(t, uid, res.Res.Id)
+			assert.Equal(t, memberNormal.Name, res.Res.Name)
+			assert.WithinDuration(t, time.Now().Add(user.MemberCardValidity), res.Res.ValidUntil, time.Minute)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package user
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type MemberErasureStatus struct {
+	String string
+}
+
+var (
+	MemberErasureUnknown   = MemberErasureStatus{""}
+	MemberErasurePending   = MemberErasureStatus{"pending"}
+	MemberErasureConfirmed = MemberErasureStatus{"confirmed"}
+	MemberErasureRejected  = MemberErasureStatus{"rejected"}
+)
+
+func memberErasureStatusFromString(s string) (MemberErasureStatus, error) {
+	switch s {
+	case MemberErasurePending.String:
+		return MemberErasurePending, nil
+	case MemberErasureConfirmed.String:
+		return MemberErasureConfirmed, nil
+	case MemberErasureRejected.String:
+		return MemberErasureRejected, nil
+	}
+
+	return MemberErasureUnknown, errors.New("unknown type: " + s)
+}
+
+func (u *MemberErasureStatus) Scan(src interface{}) error {
+	if src == nil {
+		u.String = ""
+		return nil
+	}
+
+	s, ok := src.(string)
+	if !ok {
+		u.String = ""
+		return nil
+	}
+
+	es, _ := memberErasureStatusFromString(s)
+	u.String = es.String
+	return nil
+}
+
+func (u MemberErasureStatus) Value() (driver.Value, error) {
+	es, err := memberErasureStatusFromString(u.String)
+	if err != nil {
+		es = MemberErasurePending
+	}
+
+	return es.String, nil
+}
+
+type MemberErasureModel struct {
+	Id           uint64
+	MemberId     string
+	MemberName   string
+	Reason       string
+	Status       MemberErasureStatus
+	ReviewReason string
+	ReviewerId   string
+	ReviewedAt   sql.NullTime
+	CreatedAt    time.Time
+}
@@ -0,0 +1,257 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type MemberErasureRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewMemberErasureRepository(postgreDb *pgxpool.Pool) *MemberErasureRepository {
+	return &MemberErasureRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+type (
+	MemberErasureExecutor   func(ctx context.Context, sql string, arguments ...interface{}) (commandTag pgconn.CommandTag, err error)
+	MemberErasureQuerierRow func(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	MemberErasureQuerier    func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+)
+
+func (r *MemberErasureRepository) Save(ctx context.Context, m MemberErasureModel) (nm MemberErasureModel, err error) {
+	sqlQuery := `
+		INSERT INTO member_erasure_requests (
+			member_id,
+			reason,
+			status,
+			created_at
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	var queryRow MemberErasureQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	var lastInsertId uint64
+	t := time.Now()
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		m.MemberId,
+		m.Reason,
+		MemberErasurePending,
+		t,
+	).Scan(&lastInsertId)
+
+	if err != nil {
+		return MemberErasureModel{}, err
+	}
+
+	m.Id = lastInsertId
+	m.Status = MemberErasurePending
+	m.CreatedAt = t
+
+	return m, nil
+}
+
+const memberErasureColumns = `
+	e.id,
+	e.member_id,
+	m.name AS member_name,
+	e.reason,
+	e.status,
+	e.review_reason,
+	COALESCE(e.reviewer_id::text, '') AS reviewer_id,
+	e.reviewed_at,
+	e.created_at
+`
+
+func (r *MemberErasureRepository) FindById(ctx context.Context, id uint64) (m MemberErasureModel, err error) {
+	sqlQuery := `
+		SELECT ` + memberErasureColumns + `
+		FROM member_erasure_requests e
+			JOIN members m ON m.id = e.member_id
+		WHERE e.id = $1
+	`
+
+	var query MemberErasureQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+
+	rows, err = query(
+		context.Background(),
+		sqlQuery,
+		id,
+	)
+
+	if err != nil {
+		return MemberErasureModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return MemberErasureModel{}, err
+	}
+
+	return m, nil
+}
+
+func (r *MemberErasureRepository) FindLatestByMemberId(ctx context.Context, uid string) (m MemberErasureModel, err error) {
+	sqlQuery := `
+		SELECT ` + memberErasureColumns + `
+		FROM member_erasure_requests e
+			JOIN members m ON m.id = e.member_id
+		WHERE e.member_id = $1
+		ORDER BY e.id DESC
+		LIMIT 1
+	`
+
+	var query MemberErasureQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+
+	rows, err = query(
+		context.Background(),
+		sqlQuery,
+		uid,
+	)
+
+	if err != nil {
+		return MemberErasureModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return MemberErasureModel{}, err
+	}
+
+	return m, nil
+}
+
+// Empty status query the requests of every status
+func (r *MemberErasureRepository) Query(ctx context.Context, status string, id, limit int64) ([]MemberErasureModel, error) {
+	fromId := "e.id > $1"
+	if id != 0 {
+		fromId = "e.id < $1"
+	}
+
+	sqlQuery := `
+		SELECT ` + memberErasureColumns + `
+		FROM member_erasure_requests e
+			JOIN members m ON m.id = e.member_id
+		WHERE ` + fromId + `
+			AND ($2 = '' OR e.status::text = $2)
+		ORDER BY e.id DESC
+		LIMIT $3
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		id,
+		status,
+		limit,
+	)
+	defer rows.Close()
+
+	var mps []*MemberErasureModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []MemberErasureModel{}, err
+	}
+
+	ms := make([]MemberErasureModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *MemberErasureRepository) CountByStatus(ctx context.Context, status string) (n int64, err error) {
+	sqlQuery := `
+		SELECT COUNT(id) AS n
+		FROM member_erasure_requests
+		WHERE ($1 = '' OR status::text = $1)
+	`
+
+	var queryRow MemberErasureQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		status,
+	).Scan(&n)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func (r *MemberErasureRepository) UpdateStatusById(ctx context.Context, id uint64, m MemberErasureModel) error {
+	sqlQuery := `
+		UPDATE member_erasure_requests SET (
+			status,
+			review_reason,
+			reviewer_id,
+			reviewed_at
+		) = ($1, $2, NULLIF($3, '')::uuid, $4)
+		WHERE id = $5
+	`
+
+	var exec MemberErasureExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		m.Status,
+		m.ReviewReason,
+		m.ReviewerId,
+		time.Now(),
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Name shown in place of an erased member, in the org structures and the
+// dues of the association
+const ErasedMemberName = "Anggota Terhapus"
+
+var (
+	ErrMemberErasureNotFound = errors.New("permintaan penghapusan data tidak ditemukan")
+	ErrMemberErasurePending  = errors.New("permintaan penghapusan data sebelumnya masih menunggu konfirmasi pengelola")
+	ErrMemberErasureReviewed = errors.New("permintaan penghapusan data sudah ditinjau")
+)
+
+type (
+	MemberErasureIn struct {
+		Reason string `json:"reason"`
+	}
+	MemberErasureRes struct {
+		Id           int64     `json:"id"`
+		MemberId     string    `json:"member_id"`
+		MemberName   string    `json:"member_name"`
+		Reason       string    `json:"reason"`
+		Status       string    `json:"status"`
+		ReviewReason string    `json:"review_reason"`
+		ReviewedAt   null.Time `json:"reviewed_at"`
+		CreatedAt    time.Time `json:"created_at"`
+	}
+	MemberErasureOut struct {
+		resp.Response
+		Res MemberErasureRes
+	}
+)
+
+func newMemberErasureRes(m MemberErasureModel) MemberErasureRes {
+	return MemberErasureRes{
+		Id:           int64(m.Id),
+		MemberId:     m.MemberId,
+		MemberName:   m.MemberName,
+		Reason:       m.Reason,
+		Status:       m.Status.String,
+		ReviewReason: m.ReviewReason,
+		ReviewedAt:   null.NewTime(m.ReviewedAt.Time, m.ReviewedAt.Valid),
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+// A member only has one request waiting for the admin at a time
+func (d *UserDeps) RequestMemberErasure(ctx context.Context, uid string, in MemberErasureIn) (out MemberErasureOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusCreated, "", nil)
+
+	if err = ValidateMemberErasureIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	_, err = uuid.FromString(uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	member, err := d.MemberRepository.FindById(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by id"))
+		return
+	}
+
+	latest, err := d.MemberErasureRepository.FindLatestByMemberId(ctx, uid)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find latest member erasure by member id"))
+		return
+	}
+
+	if err == nil && latest.Status == MemberErasurePending {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrMemberErasurePending)
+		return
+	}
+
+	erasure, err := d.MemberErasureRepository.Save(ctx, MemberErasureModel{
+		MemberId: uid,
+		Reason:   in.Reason,
+	})
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save member erasure"))
+		return
+	}
+
+	erasure.MemberName = member.Name
+	out.Res = newMemberErasureRes(erasure)
+
+	return
+}
+
+// The latest erasure request of the member, so the member know whether
+// the admin already reviewed it
+func (d *UserDeps) FindMemberErasure(ctx context.Context, uid string) (out MemberErasureOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	_, err = uuid.FromString(uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberErasureNotFound)
+		return
+	}
+
+	erasure, err := d.MemberErasureRepository.FindLatestByMemberId(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberErasureNotFound)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find latest member erasure by member id"))
+		return
+	}
+
+	out.Res = newMemberErasureRes(erasure)
+
+	return
+}
+
+type (
+	QueryMemberErasuresRes struct {
+		Cursor   int64              `json:"cursor"`
+		Total    int64              `json:"total"`
+		Erasures []MemberErasureRes `json:"erasures"`
+	}
+	QueryMemberErasuresOut struct {
+		resp.Response
+		Res QueryMemberErasuresRes
+	}
+)
+
+// Erasure requests for the admin, empty status query the requests waiting
+// for confirmation
+func (d *UserDeps) QueryMemberErasures(ctx context.Context, status, cursor, limit string) (out QueryMemberErasuresOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if status == "" {
+		status = MemberErasurePending.String
+	}
+	if _, err = memberErasureStatusFromString(status); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidErasureStatus)
+		return
+	}
+
+	fromCursor, _ := strconv.ParseInt(cursor, 10, 64)
+	nlimit, _ := strconv.ParseInt(limit, 10, 64)
+	if nlimit == 0 {
+		nlimit = 25
+	}
+
+	total, err := d.MemberErasureRepository.CountByStatus(ctx, status)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "count member erasures by status"))
+		return
+	}
+
+	erasures, err := d.MemberErasureRepository.Query(ctx, status, fromCursor, nlimit)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query member erasures"))
+		return
+	}
+
+	var nextCursor int64
+	if len(erasures) != 0 {
+		nextCursor = int64(erasures[len(erasures)-1].Id)
+	}
+
+	res := make([]MemberErasureRes, len(erasures))
+	for i, m := range erasures {
+		res[i] = newMemberErasureRes(m)
+	}
+
+	out.Res = QueryMemberErasuresRes{
+		Cursor:   nextCursor,
+		Total:    total,
+		Erasures: res,
+	}
+
+	return
+}
+
+type ReviewMemberErasureIn struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// A confirmed request delete the uploaded files of the member before
+// anonymising the personal fields, a failed removal leave the request
+// pending so it can be confirmed again. Dues and their proves are kept
+// for the books.
+func (d *UserDeps) ReviewMemberErasure(ctx context.Context, rid, actorUid string, in ReviewMemberErasureIn) (out MemberErasureOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateReviewMemberErasureIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	id, err := strconv.ParseUint(rid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberErasureNotFound)
+		return
+	}
+
+	erasure, err := d.MemberErasureRepository.FindById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberErasureNotFound)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member erasure by id"))
+		return
+	}
+
+	if erasure.Status != MemberErasurePending {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrMemberErasureReviewed)
+		return
+	}
+
+	status, _ := memberErasureStatusFromString(in.Status)
+	if status == MemberErasureConfirmed {
+		urls, err := d.MemberRepository.QueryFileUrls(ctx, erasure.MemberId)
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query member file urls"))
+			return
+		}
+
+		for _, url := range urls {
+			if err = d.Remove(url); err != nil {
+				out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "remove member file"))
+				return
+			}
+		}
+
+		// The sessions are deleted with the rest of the data, so the member
+		// is logged out in the same transaction
+		if err = d.MemberRepository.EraseById(ctx, erasure.MemberId); err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "erase member by id"))
+			return
+		}
+
+		erasure.MemberName = ErasedMemberName
+	}
+
+	erasure.Status = status
+	erasure.ReviewReason = in.Reason
+	erasure.ReviewerId = actorUid
+	if err = d.MemberErasureRepository.UpdateStatusById(ctx, id, erasure); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update member erasure status"))
+		return
+	}
+
+	erasure.ReviewedAt.Scan(time.Now())
+	out.Res = newMemberErasureRes(erasure)
+
+	return
+}
@@ -0,0 +1,212 @@
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestMemberErasure(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Uid                string
+		In                 user.MemberErasureIn
+	}{
+		{
+			Name:               "Request Member Erasure Success",
+			ExpectedStatusCode: http.StatusCreated,
+			Uid:                uid,
+			In: user.MemberErasureIn{
+				Reason: "Tidak lagi menjadi anggota",
+			},
+		},
+		{
+			Name:               "Request Member Erasure Fail, Previous Request Pending",
+			ExpectedStatusCode: http.StatusBadRequest,
+			Uid:                uid,
+			In:                 user.MemberErasureIn{},
+		},
+		{
+			Name:               "Request Member Erasure Fail, Member Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Uid:                "5e6b0ba4-5e1c-4f5a-9a3f-0f4b6f3f8f1e",
+			In:                 user.MemberErasureIn{},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.RequestMemberErasure(context.Background(), c.Uid, c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+
+	latest := userDeps.FindMemberErasure(context.Background(), uid)
+	if latest.Error != nil {
+		t.Fatal(latest.Error)
+	}
+
+	assert.Equal(t, user.MemberErasurePending.String, latest.Res.Status)
+	assert.Equal(t, "Tidak lagi menjadi anggota", latest.Res.Reason)
+}
+
+func TestReviewMemberErasure(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actorUid, err := createUser(memberRepository, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rejected := userDeps.RequestMemberErasure(context.Background(), uid, user.MemberErasureIn{})
+	if rejected.Error != nil {
+		t.Fatal(rejected.Error)
+	}
+
+	tokenId, _, err := loginMemberSession(memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		ExpectedStatus     string
+		Init               func() string
+		In                 user.ReviewMemberErasureIn
+	}{
+		{
+			Name:               "Review Member Erasure Fail, Reason Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Init: func() string {
+				return strconv.FormatInt(rejected.Res.Id, 10)
+			},
+			In: user.ReviewMemberErasureIn{
+				Status: user.MemberErasureRejected.String,
+			},
+		},
+		{
+			Name:               "Review Member Erasure Reject Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedStatus:     user.MemberErasureRejected.String,
+			Init: func() string {
+				return strconv.FormatInt(rejected.Res.Id, 10)
+			},
+			In: user.ReviewMemberErasureIn{
+				Status: user.MemberErasureRejected.String,
+				Reason: "Masih memiliki iuran yang belum lunas",
+			},
+		},
+		{
+			Name:               "Review Member Erasure Fail, Already Reviewed",
+			ExpectedStatusCode: http.StatusBadRequest,
+			Init: func() string {
+				return strconv.FormatInt(rejected.Res.Id, 10)
+			},
+			In: user.ReviewMemberErasureIn{
+				Status: user.MemberErasureConfirmed.String,
+			},
+		},
+		{
+			Name:               "Review Member Erasure Confirm Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedStatus:     user.MemberErasureConfirmed.String,
+			Init: func() string {
+				out := userDeps.RequestMemberErasure(context.Background(), uid, user.MemberErasureIn{})
+				return strconv.FormatInt(out.Res.Id, 10)
+			},
+			In: user.ReviewMemberErasureIn{
+				Status: user.MemberErasureConfirmed.String,
+			},
+		},
+		{
+			Name:               "Review Member Erasure Fail, Request Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Init: func() string {
+				return "999"
+			},
+			In: user.ReviewMemberErasureIn{
+				Status: user.MemberErasureConfirmed.String,
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			rid := c.Init()
+
+			tx, err := db.Begin(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.WithValue(context.Background(), arbitary.TrxX{}, tx)
+			res := userDeps.ReviewMemberErasure(ctx, rid, actorUid, c.In)
+			tx.Commit(context.Background())
+			tx.Rollback(context.Background())
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if c.ExpectedStatus == "" {
+				return
+			}
+
+			assert.Equal(t, c.ExpectedStatus, res.Res.Status)
+		})
+	}
+
+	_, err = memberRepository.FindById(context.Background(), uid)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	isAlive, err := userDeps.IsSessionAlive(context.Background(), tokenId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, isAlive)
+
+	erasures := userDeps.QueryMemberErasures(context.Background(), user.MemberErasureConfirmed.String, "", "")
+	if erasures.Error != nil {
+		t.Fatal(erasures.Error)
+	}
+
+	assert.Len(t, erasures.Res.Erasures, 1)
+	assert.Equal(t, user.ErasedMemberName, erasures.Res.Erasures[0].MemberName)
+
+	login := userDeps.MemberLogin(context.Background(), user.LoginIn{
+		Identifier: memberNormal.Username,
+		Password:   memberNormal.Password,
+	})
+	assert.NotEqual(t, http.StatusOK, login.StatusCode)
+}
@@ -0,0 +1,61 @@
+package user
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrMaxErasureReason            = errors.New("alasan penghapusan data tidak dapat lebih dari 500 karakter")
+	ErrErasureStatusRequired       = errors.New("status permintaan penghapusan data tidak boleh kosong")
+	ErrInvalidErasureStatus        = errors.New("status permintaan penghapusan data harus berupa confirmed atau rejected")
+	ErrErasureReviewReasonRequired = errors.New("alasan penolakan permintaan penghapusan data tidak boleh kosong")
+	ErrMaxErasureReviewReason      = errors.New("alasan penolakan permintaan penghapusan data tidak dapat lebih dari 500 karakter")
+)
+
+func ValidateMemberErasureIn(i MemberErasureIn) error {
+	if utf8.RuneCountInString(i.Reason) > 500 {
+		return ErrMaxErasureReason
+	}
+
+	return nil
+}
+
+func ValidateReviewMemberErasureIn(i ReviewMemberErasureIn) error {
+	g := new(errgroup.Group)
+
+	g.Go(func() error {
+		if strings.Trim(i.Status, " ") == "" {
+			return ErrErasureStatusRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		switch i.Status {
+		case "", MemberErasureConfirmed.String, MemberErasureRejected.String:
+			return nil
+		}
+		return ErrInvalidErasureStatus
+	})
+	g.Go(func() error {
+		if i.Status == MemberErasureRejected.String && strings.Trim(i.Reason, " ") == "" {
+			return ErrErasureReviewReasonRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Reason) > 500 {
+			return ErrMaxErasureReviewReason
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,335 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/geo"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/httpdecode"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrImportFileRequired    = errors.New("file csv anggota tidak boleh kosong")
+	ErrInvalidImportFile     = errors.New("file csv anggota tidak valid")
+	ErrImportColumnRequired  = errors.New("kolom name, username, wa_phone, dan other_phone wajib ada pada file csv anggota")
+	ErrImportRowsEmpty       = errors.New("file csv anggota tidak memiliki baris data")
+	ErrMaxImportRows         = errors.New("file csv anggota tidak dapat lebih dari 500 baris data")
+	ErrInvalidImportPosition = errors.New("id jabatan harus berupa angka yang dipisahkan titik koma")
+	ErrNoActiveOrgPeriod     = errors.New("tidak ada periode organisasi aktif untuk jabatan anggota")
+)
+
+// Maximum number of data rows of one import file
+const maxImportRows = 500
+
+// Columns of the import file, the first four are required
+var importColumns = []string{
+	"name",
+	"username",
+	"wa_phone",
+	"other_phone",
+	"password",
+	"position_ids",
+	"homestay_name",
+	"homestay_address",
+	"homestay_latitude",
+	"homestay_longitude",
+}
+
+// Random password given to an imported member without one, the member is
+// expected to change it after the first login
+func newImportPassword() (string, error) {
+	b := make([]byte, 9)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Position ids of a row are separated by semicolon since comma is the
+// column separator
+func parseImportPositionIds(s string) ([]int64, error) {
+	var ids []int64
+	for _, v := range strings.Split(s, ";") {
+		v = strings.Trim(v, " ")
+		if v == "" {
+			continue
+		}
+
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id < 1 {
+			return nil, ErrInvalidImportPosition
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+type ImportMemberRowIn struct {
+	Line              int64
+	Name              string
+	Username          string
+	Password          string
+	WaPhone           string
+	OtherPhone        string
+	PositionIds       string
+	HomestayName      string
+	HomestayAddress   string
+	HomestayLatitude  string
+	HomestayLongitude string
+}
+
+// Read the rows of the import file, the header decide the column order
+func readImportMemberRows(file io.Reader) ([]ImportMemberRowIn, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrImportRowsEmpty
+	}
+	if err != nil {
+		return nil, ErrInvalidImportFile
+	}
+
+	columnIdx := make(map[string]int)
+	for i, v := range header {
+		columnIdx[strings.ToLower(strings.Trim(v, " \ufeff"))] = i
+	}
+	for _, v := range importColumns[:4] {
+		if _, ok := columnIdx[v]; !ok {
+			return nil, ErrImportColumnRequired
+		}
+	}
+
+	var rows []ImportMemberRowIn
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidImportFile
+		}
+
+		if len(rows) == maxImportRows {
+			return nil, ErrMaxImportRows
+		}
+
+		column := func(name string) string {
+			i, ok := columnIdx[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.Trim(record[i], " ")
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, ImportMemberRowIn{
+			Line:              int64(line),
+			Name:              column("name"),
+			Username:          column("username"),
+			Password:          column("password"),
+			WaPhone:           column("wa_phone"),
+			OtherPhone:        column("other_phone"),
+			PositionIds:       column("position_ids"),
+			HomestayName:      column("homestay_name"),
+			HomestayAddress:   column("homestay_address"),
+			HomestayLatitude:  column("homestay_latitude"),
+			HomestayLongitude: column("homestay_longitude"),
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrImportRowsEmpty
+	}
+
+	return rows, nil
+}
+
+type (
+	ImportMembersIn struct {
+		File httpdecode.FileHeader `mapstructure:"file"`
+	}
+	ImportMemberRowOut struct {
+		Line              int64  `json:"line"`
+		Username          string `json:"username"`
+		Id                string `json:"id"`
+		HomestayId        int64  `json:"homestay_id"`
+		GeneratedPassword string `json:"generated_password"`
+		Error             string `json:"error"`
+	}
+	ImportMembersRes struct {
+		Total   int64                `json:"total"`
+		Created int64                `json:"created"`
+		Failed  int64                `json:"failed"`
+		Rows    []ImportMemberRowOut `json:"rows"`
+	}
+	ImportMembersOut struct {
+		resp.Response
+		Res ImportMembersRes
+	}
+)
+
+// Every row is validated and saved on its own, a row failing does not stop
+// the other rows. The accepted rows are saved in the same transaction
+// through the member saver, so an unexpected error discard the whole
+// import. Positions are given in the active period.
+func (d *UserDeps) ImportMembers(ctx context.Context, in ImportMembersIn) (out ImportMembersOut) {
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	file := in.File.File
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	if file == nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrImportFileRequired)
+		return
+	}
+
+	rows, err := readImportMemberRows(file)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	var activePeriodId int64
+	activePeriod, err := d.OrgPeriodRepository.QueryActive(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query active period"))
+		return
+	}
+	if err == nil {
+		activePeriodId = int64(activePeriod.Id)
+	}
+
+	outRows := make([]ImportMemberRowOut, len(rows))
+	var created int64
+	for i, row := range rows {
+		outRow := ImportMemberRowOut{
+			Line:     row.Line,
+			Username: row.Username,
+		}
+
+		res, rowErr := d.importMember(ctx, row, activePeriodId, &outRow)
+		if res.Error != nil {
+			out.Response = res
+			return
+		}
+
+		if rowErr != nil {
+			outRow.Error = rowErr.Error()
+			outRow.GeneratedPassword = ""
+		} else {
+			created++
+		}
+
+		outRows[i] = outRow
+	}
+
+	out.Res = ImportMembersRes{
+		Total:   int64(len(rows)),
+		Created: created,
+		Failed:  int64(len(rows)) - created,
+		Rows:    outRows,
+	}
+
+	return
+}
+
+// The response is only set for an error that should abort the whole
+// import, while the returned error is the reason the row is rejected
+func (d *UserDeps) importMember(ctx context.Context, row ImportMemberRowIn, activePeriodId int64, out *ImportMemberRowOut) (resp.Response, error) {
+	var err error
+	res := resp.NewResponse(http.StatusOK, "", nil)
+
+	if row.Password == "" {
+		if row.Password, err = newImportPassword(); err != nil {
+			return resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "generate password")), nil
+		}
+		out.GeneratedPassword = row.Password
+	}
+
+	if err = ValidateImportMemberRowIn(row); err != nil {
+		return res, err
+	}
+
+	positionIds, err := parseImportPositionIds(row.PositionIds)
+	if err != nil {
+		return res, err
+	}
+
+	var periodId int64
+	if len(positionIds) != 0 {
+		if activePeriodId == 0 {
+			return res, ErrNoActiveOrgPeriod
+		}
+		periodId = activePeriodId
+	}
+
+	// Checked before the member is saved so a rejected row save nothing
+	var latitude, longitude float64
+	if row.HomestayName != "" {
+		if latitude, err = geo.ParseLatitude(row.HomestayLatitude); err != nil {
+			return res, ErrInvalidHomestayLat
+		}
+
+		if longitude, err = geo.ParseLongitude(row.HomestayLongitude); err != nil {
+			return res, ErrInvalidHomestayLng
+		}
+	}
+
+	saverOut := d.MemberSaver(ctx, AddMemberIn{
+		PeriodId:    periodId,
+		Name:        row.Name,
+		Username:    row.Username,
+		Password:    row.Password,
+		WaPhone:     row.WaPhone,
+		OtherPhone:  row.OtherPhone,
+		PositionIds: positionIds,
+		IsAdmin:     null.BoolFrom(false),
+	}, true)
+	if saverOut.StatusCode >= http.StatusInternalServerError {
+		return saverOut.Response, nil
+	}
+	if saverOut.Error != nil {
+		return res, saverOut.Error
+	}
+
+	out.Id = saverOut.Res.Id
+
+	if row.HomestayName == "" {
+		return res, nil
+	}
+
+	memberHomestay, err := d.MemberRepository.SaveMemberHomestay(ctx, MemberHomestayModel{
+		Name:      row.HomestayName,
+		Address:   row.HomestayAddress,
+		Latitude:  latitude,
+		Longitude: longitude,
+		MemberId:  saverOut.Res.Id,
+	})
+	if err != nil {
+		return resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save member homestay")), nil
+	}
+
+	out.HomestayId = int64(memberHomestay.Id)
+
+	return res, nil
+}
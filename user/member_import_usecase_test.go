@@ -0,0 +1,102 @@
+package user_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/httpdecode"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func generateCsvFile(s string) httpdecode.FileHeader {
+	return httpdecode.FileHeader{
+		Filename: "members.csv",
+		File:     io.NopCloser(strings.NewReader(s)),
+	}
+}
+
+func TestImportMembers(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, positionId, err := createFullUser(userDeps, member, period, position)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := "name,username,wa_phone,other_phone,password,position_ids,homestay_name,homestay_address,homestay_latitude,homestay_longitude\n"
+
+	cases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		In                 user.ImportMembersIn
+		ExpectedCreated    int64
+		ExpectedFailed     int64
+	}{
+		{
+			Name:               "Import Members Success With Row Report",
+			ExpectedStatusCode: http.StatusOK,
+			In: user.ImportMembersIn{
+				File: generateCsvFile(header +
+					fmt.Sprintf("Import One,importone,+62 821-2222-0001,+62 821-2222-0001,password,%d,Homestay One,Address One,-6.12312312,90.1212321\n", positionId) +
+					"Import Two,importtwo,+62 821-2222-0002,+62 821-2222-0002,,,,,,\n" +
+					"Import Three,importone,+62 821-2222-0003,+62 821-2222-0003,password,,,,,\n" +
+					"Import Four," + member.Username + ",+62 821-2222-0004,+62 821-2222-0004,password,,,,,\n" +
+					"Import Five,importfive,+62 821-2222-0005,+62 821-2222-0005,password,a;b,,,,\n" +
+					"Import Six,importsix,+62 821-2222-0006,+62 821-2222-0006,password,,Homestay Six,,,\n" +
+					",importseven,+62 821-2222-0007,+62 821-2222-0007,password,,,,,\n"),
+			},
+			ExpectedCreated: 2,
+			ExpectedFailed:  5,
+		},
+		{
+			Name:               "Import Members Fail, File Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In:                 user.ImportMembersIn{},
+		},
+		{
+			Name:               "Import Members Fail, Column Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: user.ImportMembersIn{
+				File: generateCsvFile("name,username\nImport,import\n"),
+			},
+		},
+		{
+			Name:               "Import Members Fail, Rows Empty",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: user.ImportMembersIn{
+				File: generateCsvFile(header),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			tx, err := db.Begin(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.WithValue(context.Background(), arbitary.TrxX{}, tx)
+			res := userDeps.ImportMembers(ctx, c.In)
+			tx.Commit(context.Background())
+			tx.Rollback(context.Background())
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			assert.Equal(t, c.ExpectedCreated, res.Res.Created)
+			assert.Equal(t, c.ExpectedFailed, res.Res.Failed)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package user
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type MemberInvitationStatus struct {
+	String string
+}
+
+var (
+	MemberInvitationUnknown = MemberInvitationStatus{""}
+	MemberInvitationPending = MemberInvitationStatus{"pending"}
+	MemberInvitationUsed    = MemberInvitationStatus{"used"}
+	MemberInvitationExpired = MemberInvitationStatus{"expired"}
+)
+
+func memberInvitationStatusFromString(s string) (MemberInvitationStatus, error) {
+	switch s {
+	case MemberInvitationPending.String:
+		return MemberInvitationPending, nil
+	case MemberInvitationUsed.String:
+		return MemberInvitationUsed, nil
+	case MemberInvitationExpired.String:
+		return MemberInvitationExpired, nil
+	}
+
+	return MemberInvitationUnknown, errors.New("unknown type: " + s)
+}
+
+type MemberInvitationModel struct {
+	Id          uint64
+	Token       string
+	Name        string
+	WaPhone     string
+	OtherPhone  string
+	IsAdmin     bool
+	OrgPeriodId uint64
+	PositionIds []int64
+	InviterId   string
+	MemberId    string
+	ExpiredAt   time.Time
+	UsedAt      sql.NullTime
+	CreatedAt   time.Time
+}
+
+// The status is not stored, an invitation expire by the time alone
+func (m MemberInvitationModel) Status() MemberInvitationStatus {
+	if m.UsedAt.Valid {
+		return MemberInvitationUsed
+	}
+
+	if !m.ExpiredAt.After(time.Now()) {
+		return MemberInvitationExpired
+	}
+
+	return MemberInvitationPending
+}
@@ -0,0 +1,291 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type MemberInvitationRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewMemberInvitationRepository(postgreDb *pgxpool.Pool) *MemberInvitationRepository {
+	return &MemberInvitationRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+type (
+	MemberInvitationExecutor   func(ctx context.Context, sql string, arguments ...interface{}) (commandTag pgconn.CommandTag, err error)
+	MemberInvitationQuerierRow func(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	MemberInvitationQuerier    func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+)
+
+func (r *MemberInvitationRepository) Save(ctx context.Context, m MemberInvitationModel) (nm MemberInvitationModel, err error) {
+	sqlQuery := `
+		INSERT INTO member_invitations (
+			token,
+			name,
+			wa_phone,
+			other_phone,
+			is_admin,
+			org_period_id,
+			position_ids,
+			inviter_id,
+			expired_at,
+			created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7, $8, $9, $10)
+		RETURNING id
+	`
+
+	var queryRow MemberInvitationQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	positionIds := m.PositionIds
+	if positionIds == nil {
+		positionIds = []int64{}
+	}
+
+	var lastInsertId uint64
+	t := time.Now()
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		m.Token,
+		m.Name,
+		m.WaPhone,
+		m.OtherPhone,
+		m.IsAdmin,
+		int64(m.OrgPeriodId),
+		positionIds,
+		m.InviterId,
+		m.ExpiredAt,
+		t,
+	).Scan(&lastInsertId)
+
+	if err != nil {
+		return MemberInvitationModel{}, err
+	}
+
+	m.Id = lastInsertId
+	m.PositionIds = positionIds
+	m.CreatedAt = t
+
+	return m, nil
+}
+
+const memberInvitationColumns = `
+	id,
+	token,
+	name,
+	wa_phone,
+	other_phone,
+	is_admin,
+	COALESCE(org_period_id, 0) AS org_period_id,
+	position_ids,
+	inviter_id::text AS inviter_id,
+	COALESCE(member_id::text, '') AS member_id,
+	expired_at,
+	used_at,
+	created_at
+`
+
+// Same rule as MemberInvitationModel.Status, so the list can be filtered
+const memberInvitationStatusExpr = `
+	CASE
+		WHEN used_at IS NOT NULL THEN 'used'
+		WHEN expired_at <= $1 THEN 'expired'
+		ELSE 'pending'
+	END
+`
+
+// Find the invitation that can still be used, it fail with pgx.ErrNoRows
+// when the token does not exist, is expired, or is already used
+func (r *MemberInvitationRepository) FindUnusedByToken(ctx context.Context, token string) (m MemberInvitationModel, err error) {
+	sqlQuery := `
+		SELECT ` + memberInvitationColumns + `
+		FROM member_invitations
+		WHERE token = $1
+		AND used_at IS NULL
+		AND expired_at > $2
+	`
+
+	var query MemberInvitationQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+
+	rows, err = query(
+		context.Background(),
+		sqlQuery,
+		token,
+		time.Now(),
+	)
+
+	if err != nil {
+		return MemberInvitationModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return MemberInvitationModel{}, err
+	}
+
+	return m, nil
+}
+
+// Mark the invitation as used by the member created from it, it fail with
+// pgx.ErrNoRows when the invitation is used or expired in the meantime
+func (r *MemberInvitationRepository) UseById(ctx context.Context, id uint64, memberId string) (m MemberInvitationModel, err error) {
+	sqlQuery := `
+		UPDATE member_invitations
+		SET
+			used_at = $1,
+			member_id = $2
+		WHERE id = $3
+		AND used_at IS NULL
+		AND expired_at > $1
+		RETURNING ` + memberInvitationColumns
+
+	var query MemberInvitationQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+
+	rows, err = query(
+		context.Background(),
+		sqlQuery,
+		time.Now(),
+		memberId,
+		id,
+	)
+
+	if err != nil {
+		return MemberInvitationModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return MemberInvitationModel{}, err
+	}
+
+	return m, nil
+}
+
+// Empty status query the invitations of every status
+func (r *MemberInvitationRepository) Query(ctx context.Context, status string, id, limit int64) ([]MemberInvitationModel, error) {
+	fromId := "id > $2"
+	if id != 0 {
+		fromId = "id < $2"
+	}
+
+	sqlQuery := `
+		SELECT ` + memberInvitationColumns + `
+		FROM member_invitations
+		WHERE ` + fromId + `
+			AND ($3 = '' OR ` + memberInvitationStatusExpr + ` = $3)
+		ORDER BY id DESC
+		LIMIT $4
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		time.Now(),
+		id,
+		status,
+		limit,
+	)
+	defer rows.Close()
+
+	var mps []*MemberInvitationModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []MemberInvitationModel{}, err
+	}
+
+	ms := make([]MemberInvitationModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *MemberInvitationRepository) CountByStatus(ctx context.Context, status string) (n int64, err error) {
+	sqlQuery := `
+		SELECT COUNT(id) AS n
+		FROM member_invitations
+		WHERE ($2 = '' OR ` + memberInvitationStatusExpr + ` = $2)
+	`
+
+	var queryRow MemberInvitationQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		time.Now(),
+		status,
+	).Scan(&n)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Only an invitation not used yet can be deleted, a used one is kept as the
+// record of how the member joined
+func (r *MemberInvitationRepository) DeleteUnusedById(ctx context.Context, id uint64) (n int64, err error) {
+	sqlQuery := `
+		DELETE FROM member_invitations
+		WHERE id = $1
+		AND used_at IS NULL
+	`
+
+	var exec MemberInvitationExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	cmd, err := exec(
+		context.Background(),
+		sqlQuery,
+		id,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return cmd.RowsAffected(), nil
+}
@@ -0,0 +1,390 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/httpdecode"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+const MemberInvitationValidity = 7 * 24 * time.Hour
+
+var (
+	ErrMemberInvitationNotFound = errors.New("undangan anggota tidak ditemukan")
+	ErrInvalidMemberInvitation  = errors.New("undangan anggota tidak valid, sudah digunakan, atau kedaluwarsa")
+)
+
+// The token is the only credential of the signup link, it must not be
+// guessable
+func newInvitationToken() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+type (
+	InviteMemberIn struct {
+		Name        string  `json:"name"`
+		WaPhone     string  `json:"wa_phone"`
+		OtherPhone  string  `json:"other_phone"`
+		IsAdmin     bool    `json:"is_admin"`
+		PeriodId    int64   `json:"period_id"`
+		PositionIds []int64 `json:"position_ids"`
+	}
+	InviteMemberRes struct {
+		Id        int64     `json:"id"`
+		Token     string    `json:"token"`
+		ExpiredAt time.Time `json:"expired_at"`
+	}
+	InviteMemberOut struct {
+		resp.Response
+		Res InviteMemberRes
+	}
+)
+
+// The admin fill the member data, the invitee only choose the credential
+// and upload the photos through the link built from the token
+func (d *UserDeps) InviteMember(ctx context.Context, inviterUid string, in InviteMemberIn) (out InviteMemberOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusCreated, "", nil)
+
+	if err = ValidateInviteMemberIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	if in.PeriodId != 0 {
+		if in.PeriodId < 0 {
+			out.Response = resp.NewResponse(http.StatusNotFound, "", ErrOrgPeriodNotFound)
+			return
+		}
+
+		_, err = d.OrgPeriodRepository.FindUndeletedById(ctx, uint64(in.PeriodId))
+		if errors.Is(err, pgx.ErrNoRows) {
+			out.Response = resp.NewResponse(http.StatusNotFound, "", ErrOrgPeriodNotFound)
+			return
+		}
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find period by id"))
+			return
+		}
+	}
+
+	if len(in.PositionIds) != 0 {
+		positionIds := make([]uint64, len(in.PositionIds))
+		for i, posId := range in.PositionIds {
+			if posId < 1 {
+				out.Response = resp.NewResponse(http.StatusNotFound, "", ErrPositionNotFound)
+				return
+			}
+			positionIds[i] = uint64(posId)
+		}
+
+		positions, err := d.PositionRepository.QueryUndeletedInId(ctx, positionIds)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find position by id"))
+			return
+		}
+
+		if len(positions) == 0 {
+			out.Response = resp.NewResponse(http.StatusNotFound, "", ErrPositionNotFound)
+			return
+		}
+	}
+
+	existingMember, err := d.MemberRepository.CheckUniqueField(ctx, MemberModel{
+		WaPhone:    in.WaPhone,
+		OtherPhone: in.OtherPhone,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "check unique field"))
+		return
+	}
+
+	if !existingMember.Id.UUID.IsNil() {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrDuplicateUniqueProperty)
+		return
+	}
+
+	token, err := newInvitationToken()
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "generate invitation token"))
+		return
+	}
+
+	invitation, err := d.MemberInvitationRepository.Save(ctx, MemberInvitationModel{
+		Token:       token,
+		Name:        in.Name,
+		WaPhone:     in.WaPhone,
+		OtherPhone:  in.OtherPhone,
+		IsAdmin:     in.IsAdmin,
+		OrgPeriodId: uint64(in.PeriodId),
+		PositionIds: in.PositionIds,
+		InviterId:   inviterUid,
+		ExpiredAt:   time.Now().Add(MemberInvitationValidity),
+	})
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save member invitation"))
+		return
+	}
+
+	out.Res = InviteMemberRes{
+		Id:        int64(invitation.Id),
+		Token:     invitation.Token,
+		ExpiredAt: invitation.ExpiredAt,
+	}
+
+	return
+}
+
+type MemberInvitationRes struct {
+	Id          int64     `json:"id"`
+	Name        string    `json:"name"`
+	WaPhone     string    `json:"wa_phone"`
+	OtherPhone  string    `json:"other_phone"`
+	IsAdmin     bool      `json:"is_admin"`
+	PeriodId    int64     `json:"period_id"`
+	PositionIds []int64   `json:"position_ids"`
+	Status      string    `json:"status"`
+	MemberId    string    `json:"member_id"`
+	ExpiredAt   time.Time `json:"expired_at"`
+	UsedAt      null.Time `json:"used_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func newMemberInvitationRes(m MemberInvitationModel) MemberInvitationRes {
+	positionIds := m.PositionIds
+	if positionIds == nil {
+		positionIds = []int64{}
+	}
+
+	return MemberInvitationRes{
+		Id:          int64(m.Id),
+		Name:        m.Name,
+		WaPhone:     m.WaPhone,
+		OtherPhone:  m.OtherPhone,
+		IsAdmin:     m.IsAdmin,
+		PeriodId:    int64(m.OrgPeriodId),
+		PositionIds: positionIds,
+		Status:      m.Status().String,
+		MemberId:    m.MemberId,
+		ExpiredAt:   m.ExpiredAt,
+		UsedAt:      null.NewTime(m.UsedAt.Time, m.UsedAt.Valid),
+		CreatedAt:   m.CreatedAt,
+	}
+}
+
+type (
+	FindMemberInvitationRes struct {
+		Name       string    `json:"name"`
+		WaPhone    string    `json:"wa_phone"`
+		OtherPhone string    `json:"other_phone"`
+		ExpiredAt  time.Time `json:"expired_at"`
+	}
+	FindMemberInvitationOut struct {
+		resp.Response
+		Res FindMemberInvitationRes
+	}
+)
+
+// The data filled by the admin, shown to the invitee opening the link
+func (d *UserDeps) FindMemberInvitation(ctx context.Context, token string) (out FindMemberInvitationOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	invitation, err := d.MemberInvitationRepository.FindUnusedByToken(ctx, strings.Trim(token, " "))
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrInvalidMemberInvitation)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find unused member invitation by token"))
+		return
+	}
+
+	out.Res = FindMemberInvitationRes{
+		Name:       invitation.Name,
+		WaPhone:    invitation.WaPhone,
+		OtherPhone: invitation.OtherPhone,
+		ExpiredAt:  invitation.ExpiredAt,
+	}
+
+	return
+}
+
+type AcceptMemberInvitationIn struct {
+	Token    string                `mapstructure:"token"`
+	Username string                `mapstructure:"username"`
+	Password string                `mapstructure:"password"`
+	Profile  httpdecode.FileHeader `mapstructure:"profile"`
+	IdCard   httpdecode.FileHeader `mapstructure:"id_card"`
+}
+
+// The member is saved already approved, the admin reviewed the data when
+// sending the invitation
+func (d *UserDeps) AcceptMemberInvitation(ctx context.Context, in AcceptMemberInvitationIn) (out AddMemberOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusCreated, "", nil)
+
+	if err = ValidateAcceptMemberInvitationIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	invitation, err := d.MemberInvitationRepository.FindUnusedByToken(ctx, strings.Trim(in.Token, " "))
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrInvalidMemberInvitation)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find unused member invitation by token"))
+		return
+	}
+
+	saverOut := d.MemberSaver(ctx, AddMemberIn{
+		PeriodId:    int64(invitation.OrgPeriodId),
+		Name:        invitation.Name,
+		Username:    in.Username,
+		Password:    in.Password,
+		WaPhone:     invitation.WaPhone,
+		OtherPhone:  invitation.OtherPhone,
+		PositionIds: invitation.PositionIds,
+		IsAdmin:     null.BoolFrom(invitation.IsAdmin),
+		Profile:     in.Profile,
+		IdCard:      in.IdCard,
+	}, true)
+	if saverOut.Error != nil {
+		out.Response = saverOut.Response
+		return
+	}
+
+	// Accepting the same link twice at once save the member twice, only the
+	// first one to mark the invitation is kept by the transaction
+	_, err = d.MemberInvitationRepository.UseById(ctx, invitation.Id, saverOut.Res.Id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrInvalidMemberInvitation)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "use member invitation by id"))
+		return
+	}
+
+	out.Res.Id = saverOut.Res.Id
+
+	return
+}
+
+type (
+	QueryMemberInvitationsRes struct {
+		Cursor      int64                 `json:"cursor"`
+		Total       int64                 `json:"total"`
+		Invitations []MemberInvitationRes `json:"invitations"`
+	}
+	QueryMemberInvitationsOut struct {
+		resp.Response
+		Res QueryMemberInvitationsRes
+	}
+)
+
+// Invitations for the admin, empty status query the invitations of every
+// status
+func (d *UserDeps) QueryMemberInvitations(ctx context.Context, status, cursor, limit string) (out QueryMemberInvitationsOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if status != "" {
+		if _, err = memberInvitationStatusFromString(status); err != nil {
+			out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidInvitationStatus)
+			return
+		}
+	}
+
+	fromCursor, _ := strconv.ParseInt(cursor, 10, 64)
+	nlimit, _ := strconv.ParseInt(limit, 10, 64)
+	if nlimit == 0 {
+		nlimit = 25
+	}
+
+	total, err := d.MemberInvitationRepository.CountByStatus(ctx, status)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "count member invitations by status"))
+		return
+	}
+
+	invitations, err := d.MemberInvitationRepository.Query(ctx, status, fromCursor, nlimit)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query member invitations"))
+		return
+	}
+
+	var nextCursor int64
+	if len(invitations) != 0 {
+		nextCursor = int64(invitations[len(invitations)-1].Id)
+	}
+
+	res := make([]MemberInvitationRes, len(invitations))
+	for i, m := range invitations {
+		res[i] = newMemberInvitationRes(m)
+	}
+
+	out.Res = QueryMemberInvitationsRes{
+		Cursor:      nextCursor,
+		Total:       total,
+		Invitations: res,
+	}
+
+	return
+}
+
+type (
+	RemoveMemberInvitationRes struct {
+		Id int64 `json:"id"`
+	}
+	RemoveMemberInvitationOut struct {
+		resp.Response
+		Res RemoveMemberInvitationRes
+	}
+)
+
+// Revoke the link of an invitation that is not used yet
+func (d *UserDeps) RemoveMemberInvitation(ctx context.Context, iid string) (out RemoveMemberInvitationOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(iid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberInvitationNotFound)
+		return
+	}
+
+	n, err := d.MemberInvitationRepository.DeleteUnusedById(ctx, id)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "delete unused member invitation by id"))
+		return
+	}
+
+	if n == 0 {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberInvitationNotFound)
+		return
+	}
+
+	out.Res.Id = int64(id)
+
+	return
+}
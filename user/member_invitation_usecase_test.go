@@ -0,0 +1,275 @@
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInviteMember(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adminUid, periodId, positionId, err := createFullUser(userDeps, member, period, position)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		In                 user.InviteMemberIn
+	}{
+		{
+			Name:               "Invite Member Success",
+			ExpectedStatusCode: http.StatusCreated,
+			In: user.InviteMemberIn{
+				Name:        "Invited",
+				WaPhone:     "+62 821-1111-1001",
+				OtherPhone:  "+62 821-1111-1001",
+				PeriodId:    int64(periodId),
+				PositionIds: []int64{int64(positionId)},
+			},
+		},
+		{
+			Name:               "Invite Member without Position Success",
+			ExpectedStatusCode: http.StatusCreated,
+			In: user.InviteMemberIn{
+				Name:       "Invited",
+				WaPhone:    "+62 821-1111-1002",
+				OtherPhone: "+62 821-1111-1002",
+			},
+		},
+		{
+			Name:               "Invite Member Fail, Phone Already Used",
+			ExpectedStatusCode: http.StatusBadRequest,
+			In: user.InviteMemberIn{
+				Name:       "Invited",
+				WaPhone:    member.WaPhone,
+				OtherPhone: "+62 821-1111-1003",
+			},
+		},
+		{
+			Name:               "Invite Member Fail, Position without Period",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: user.InviteMemberIn{
+				Name:        "Invited",
+				WaPhone:     "+62 821-1111-1004",
+				OtherPhone:  "+62 821-1111-1004",
+				PositionIds: []int64{int64(positionId)},
+			},
+		},
+		{
+			Name:               "Invite Member Fail, Period Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			In: user.InviteMemberIn{
+				Name:       "Invited",
+				WaPhone:    "+62 821-1111-1005",
+				OtherPhone: "+62 821-1111-1005",
+				PeriodId:   int64(periodId) + 999,
+			},
+		},
+		{
+			Name:               "Invite Member Fail, Name Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: user.InviteMemberIn{
+				WaPhone:    "+62 821-1111-1006",
+				OtherPhone: "+62 821-1111-1006",
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.InviteMember(context.Background(), adminUid, c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if res.Error == nil {
+				assert.NotEmpty(t, res.Res.Token)
+			}
+		})
+	}
+}
+
+func TestAcceptMemberInvitation(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adminUid, periodId, positionId, err := createFullUser(userDeps, member, period, position)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invited := userDeps.InviteMember(context.Background(), adminUid, user.InviteMemberIn{
+		Name:        "Invited",
+		WaPhone:     "+62 821-1111-1001",
+		OtherPhone:  "+62 821-1111-1001",
+		PeriodId:    int64(periodId),
+		PositionIds: []int64{int64(positionId)},
+	})
+	if invited.Error != nil {
+		t.Fatal(invited.Error)
+	}
+
+	found := userDeps.FindMemberInvitation(context.Background(), invited.Res.Token)
+	if found.Error != nil {
+		t.Fatal(found.Error)
+	}
+	assert.Equal(t, "Invited", found.Res.Name)
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		In                 user.AcceptMemberInvitationIn
+	}{
+		{
+			Name:               "Accept Member Invitation Success",
+			ExpectedStatusCode: http.StatusCreated,
+			In: user.AcceptMemberInvitationIn{
+				Token:    invited.Res.Token,
+				Username: "invitedusername",
+				Password: "password",
+				Profile:  generateFile(fileDir, fileName),
+				IdCard:   generateFile(fileDir, fileName),
+			},
+		},
+		{
+			Name:               "Accept Member Invitation Fail, Already Used",
+			ExpectedStatusCode: http.StatusNotFound,
+			In: user.AcceptMemberInvitationIn{
+				Token:    invited.Res.Token,
+				Username: "invitedusernametwo",
+				Password: "password",
+				Profile:  generateFile(fileDir, fileName),
+				IdCard:   generateFile(fileDir, fileName),
+			},
+		},
+		{
+			Name:               "Accept Member Invitation Fail, Token Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			In: user.AcceptMemberInvitationIn{
+				Token:    "not-a-token",
+				Username: "invitedusernamethree",
+				Password: "password",
+				Profile:  generateFile(fileDir, fileName),
+				IdCard:   generateFile(fileDir, fileName),
+			},
+		},
+		{
+			Name:               "Accept Member Invitation Fail, Id Card Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: user.AcceptMemberInvitationIn{
+				Token:    invited.Res.Token,
+				Username: "invitedusernamefour",
+				Password: "password",
+				Profile:  generateFile(fileDir, fileName),
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			tx, err := db.Begin(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.WithValue(context.Background(), arbitary.TrxX{}, tx)
+			res := userDeps.AcceptMemberInvitation(ctx, c.In)
+			tx.Commit(context.Background())
+			tx.Rollback(context.Background())
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if res.Error != nil {
+				return
+			}
+
+			// The invited member can log in right away
+			login := userDeps.MemberLogin(context.Background(), user.LoginIn{
+				Identifier: c.In.Username,
+				Password:   c.In.Password,
+			})
+			assert.Equal(t, http.StatusOK, login.StatusCode)
+
+			detail := userDeps.FindMemberDetail(context.Background(), res.Res.Id, adminUid)
+			assert.Equal(t, "Invited", detail.Res.Name)
+			assert.Len(t, detail.Res.Positions, 1)
+		})
+	}
+}
+
+func TestRemoveMemberInvitation(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adminUid, err := createUser(memberRepository, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invited := userDeps.InviteMember(context.Background(), adminUid, user.InviteMemberIn{
+		Name:       "Invited",
+		WaPhone:    "+62 821-1111-1001",
+		OtherPhone: "+62 821-1111-1001",
+	})
+	if invited.Error != nil {
+		t.Fatal(invited.Error)
+	}
+
+	id := strconv.FormatInt(invited.Res.Id, 10)
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Id                 string
+	}{
+		{
+			Name:               "Remove Member Invitation Success",
+			ExpectedStatusCode: http.StatusOK,
+			Id:                 id,
+		},
+		{
+			Name:               "Remove Member Invitation Fail, Already Removed",
+			ExpectedStatusCode: http.StatusNotFound,
+			Id:                 id,
+		},
+		{
+			Name:               "Remove Member Invitation Fail, Not a Number",
+			ExpectedStatusCode: http.StatusNotFound,
+			Id:                 "abc",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.RemoveMemberInvitation(context.Background(), c.Id)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+
+	found := userDeps.FindMemberInvitation(context.Background(), invited.Res.Token)
+	assert.Equal(t, http.StatusNotFound, found.StatusCode)
+}
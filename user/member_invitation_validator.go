@@ -0,0 +1,131 @@
+package user
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrInvitationTokenRequired = errors.New("token undangan anggota tidak boleh kosong")
+	ErrInvalidInvitationStatus = errors.New("status undangan anggota harus berupa pending, used, atau expired")
+)
+
+func ValidateInviteMemberIn(i InviteMemberIn) error {
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		if strings.Trim(i.Name, " ") == "" {
+			return ErrMemberNameRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.WaPhone, " ") == "" {
+			return ErrWaPhoneRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.OtherPhone, " ") == "" {
+			return ErrOtherPhoneRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Name) > 100 {
+			return ErrMaxName
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.WaPhone) > 50 {
+			return ErrMaxWaPhone
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.OtherPhone) > 50 {
+			return ErrMaxOtherPhone
+		}
+		return nil
+	})
+	g.Go(func() error {
+		// The positions are only saved in the structure of a period
+		if len(i.PositionIds) != 0 && i.PeriodId == 0 {
+			return ErrOrgPeriodRequired
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ValidateAcceptMemberInvitationIn(i AcceptMemberInvitationIn) error {
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		if strings.Trim(i.Token, " ") == "" {
+			return ErrInvitationTokenRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.Username, " ") == "" {
+			return ErrUsernameRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.Password, " ") == "" {
+			return ErrPasswordRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Username) > 50 {
+			return ErrMaxUsername
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Password) > 200 {
+			return ErrMaxPassword
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if i.Profile.File == nil || i.Profile.Filename == "" {
+			return ErrProfileRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Profile.Filename) > 200 {
+			return ErrProfileFileName
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if i.IdCard.File == nil || i.IdCard.Filename == "" {
+			return ErrIdCardRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.IdCard.Filename) > 200 {
+			return ErrIdCardFileName
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -2,33 +2,236 @@ package user
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"time"
 
 	pgtypeuuid "github.com/jackc/pgtype/ext/gofrs-uuid"
+	"github.com/pkg/errors"
 )
 
+type MemberApprovalStatus struct {
+	String string
+}
+
+var (
+	MemberApprovalUnknown       = MemberApprovalStatus{""}
+	MemberApprovalPending       = MemberApprovalStatus{"pending"}
+	MemberApprovalApproved      = MemberApprovalStatus{"approved"}
+	MemberApprovalRejected      = MemberApprovalStatus{"rejected"}
+	MemberApprovalInfoRequested = MemberApprovalStatus{"info_requested"}
+)
+
+func memberApprovalStatusFromString(s string) (MemberApprovalStatus, error) {
+	switch s {
+	case MemberApprovalPending.String:
+		return MemberApprovalPending, nil
+	case MemberApprovalApproved.String:
+		return MemberApprovalApproved, nil
+	case MemberApprovalRejected.String:
+		return MemberApprovalRejected, nil
+	case MemberApprovalInfoRequested.String:
+		return MemberApprovalInfoRequested, nil
+	}
+
+	return MemberApprovalUnknown, errors.New("unknown type: " + s)
+}
+
+func (u *MemberApprovalStatus) Scan(src interface{}) error {
+	if src == nil {
+		u.String = ""
+		return nil
+	}
+
+	s, ok := src.(string)
+	if !ok {
+		u.String = ""
+		return nil
+	}
+
+	as, _ := memberApprovalStatusFromString(s)
+	u.String = as.String
+	return nil
+}
+
+func (u MemberApprovalStatus) Value() (driver.Value, error) {
+	as, err := memberApprovalStatusFromString(u.String)
+	if err != nil {
+		as = MemberApprovalPending
+	}
+
+	return as.String, nil
+}
+
+type MemberStatus struct {
+	String string
+}
+
+var (
+	MemberStatusUnknown   = MemberStatus{""}
+	MemberStatusPending   = MemberStatus{"pending"}
+	MemberStatusActive    = MemberStatus{"active"}
+	MemberStatusSuspended = MemberStatus{"suspended"}
+	MemberStatusResigned  = MemberStatus{"resigned"}
+	MemberStatusDeceased  = MemberStatus{"deceased"}
+)
+
+func memberStatusFromString(s string) (MemberStatus, error) {
+	switch s {
+	case MemberStatusPending.String:
+		return MemberStatusPending, nil
+	case MemberStatusActive.String:
+		return MemberStatusActive, nil
+	case MemberStatusSuspended.String:
+		return MemberStatusSuspended, nil
+	case MemberStatusResigned.String:
+		return MemberStatusResigned, nil
+	case MemberStatusDeceased.String:
+		return MemberStatusDeceased, nil
+	}
+
+	return MemberStatusUnknown, errors.New("unknown type: " + s)
+}
+
+func (u *MemberStatus) Scan(src interface{}) error {
+	if src == nil {
+		u.String = ""
+		return nil
+	}
+
+	s, ok := src.(string)
+	if !ok {
+		u.String = ""
+		return nil
+	}
+
+	ms, _ := memberStatusFromString(s)
+	u.String = ms.String
+	return nil
+}
+
+func (u MemberStatus) Value() (driver.Value, error) {
+	ms, err := memberStatusFromString(u.String)
+	if err != nil {
+		ms = MemberStatusPending
+	}
+
+	return ms.String, nil
+}
+
+type FieldVisibility struct {
+	String string
+}
+
+var (
+	VisibilityUnknown = FieldVisibility{""}
+	VisibilityPublic  = FieldVisibility{"public"}
+	VisibilityMembers = FieldVisibility{"members"}
+	VisibilityAdmins  = FieldVisibility{"admins"}
+)
+
+func fieldVisibilityFromString(s string) (FieldVisibility, error) {
+	switch s {
+	case VisibilityPublic.String:
+		return VisibilityPublic, nil
+	case VisibilityMembers.String:
+		return VisibilityMembers, nil
+	case VisibilityAdmins.String:
+		return VisibilityAdmins, nil
+	}
+
+	return VisibilityUnknown, errors.New("unknown type: " + s)
+}
+
+func (u *FieldVisibility) Scan(src interface{}) error {
+	if src == nil {
+		u.String = ""
+		return nil
+	}
+
+	s, ok := src.(string)
+	if !ok {
+		u.String = ""
+		return nil
+	}
+
+	fv, _ := fieldVisibilityFromString(s)
+	u.String = fv.String
+	return nil
+}
+
+func (u FieldVisibility) Value() (driver.Value, error) {
+	fv, err := fieldVisibilityFromString(u.String)
+	if err != nil {
+		fv = VisibilityMembers
+	}
+
+	return fv.String, nil
+}
+
 type MemberModel struct {
-	IsAdmin       bool
-	IsApproved    bool
-	Name          string
-	OtherPhone    string
-	WaPhone       string
-	ProfilePicUrl string
-	IdCardUrl     string
-	Username      string
-	Password      string
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	DeletedAt     sql.NullTime
-	Id            pgtypeuuid.UUID
+	IsAdmin              bool
+	IsApproved           bool
+	ApprovalStatus       MemberApprovalStatus
+	ApprovalReason       string
+	ReviewedAt           sql.NullTime
+	Status               MemberStatus
+	SuspendedUntil       sql.NullTime
+	Name                 string
+	OtherPhone           string
+	WaPhone              string
+	ProfilePicUrl        string
+	IdCardUrl            string
+	Username             string
+	Password             string
+	UsernameVisibility   FieldVisibility
+	WaPhoneVisibility    FieldVisibility
+	OtherPhoneVisibility FieldVisibility
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	DeletedAt            sql.NullTime
+	Id                   pgtypeuuid.UUID
+}
+
+// Zero value of a field turn its filter off, ArrearsSince limit the unpaid
+// dues counted to the ones from that date
+type MemberFilter struct {
+	Q              string
+	ApprovalStatus string
+	IsAdmin        sql.NullBool
+	PositionId     uint64
+	OrgPeriodId    uint64
+	MinArrears     int64
+	ArrearsSince   sql.NullTime
+	CreatedFrom    sql.NullTime
+	CreatedUntil   sql.NullTime
+	SortBy         string
+	Offset         int64
+	Limit          int64
+}
+
+type MemberSearchModel struct {
+	MemberModel
+	DuesArrears int64
+	Total       int64
+}
+
+type MemberStatusHistoryModel struct {
+	Id             uint64
+	MemberId       string
+	FromStatus     MemberStatus
+	ToStatus       MemberStatus
+	Reason         string
+	SuspendedUntil sql.NullTime
+	ActorId        string
+	CreatedAt      time.Time
 }
 
 type MemberHomestayModel struct {
 	Id           uint64
 	Name         string
 	Address      string
-	Latitude     string
-	Longitude    string
+	Latitude     float64
+	Longitude    float64
 	ThumbnailUrl string
 	MemberId     string
 	CreatedAt    time.Time
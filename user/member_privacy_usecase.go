@@ -0,0 +1,164 @@
+package user
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+)
+
+// The widest visibility a caller can see, a member always see their own
+// fields regardless the setting
+type memberViewer struct {
+	Uid   string
+	Level FieldVisibility
+}
+
+func (v memberViewer) canView(uid string, f FieldVisibility) bool {
+	if v.Uid == uid || v.Level == VisibilityAdmins {
+		return true
+	}
+
+	switch f {
+	case VisibilityPublic:
+		return true
+	case VisibilityAdmins:
+		return false
+	}
+
+	return v.Level == VisibilityMembers
+}
+
+// Caller without a token, or with a token of a member not approved or no
+// longer exist, only see the public fields
+func (d *UserDeps) findMemberViewer(ctx context.Context, viewerUid string) (memberViewer, error) {
+	viewer := memberViewer{
+		Level: VisibilityPublic,
+	}
+
+	if _, err := uuid.FromString(viewerUid); err != nil {
+		return viewer, nil
+	}
+
+	member, err := d.MemberRepository.FindById(ctx, viewerUid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return viewer, nil
+	}
+	if err != nil {
+		return memberViewer{}, err
+	}
+
+	viewer.Uid = viewerUid
+	if !member.IsApproved {
+		return viewer, nil
+	}
+
+	viewer.Level = VisibilityMembers
+	if member.IsAdmin {
+		viewer.Level = VisibilityAdmins
+	}
+
+	return viewer, nil
+}
+
+type (
+	MemberPrivacyIn struct {
+		UsernameVisibility   string `json:"username_visibility"`
+		WaPhoneVisibility    string `json:"wa_phone_visibility"`
+		OtherPhoneVisibility string `json:"other_phone_visibility"`
+	}
+	MemberPrivacyRes struct {
+		UsernameVisibility   string `json:"username_visibility"`
+		WaPhoneVisibility    string `json:"wa_phone_visibility"`
+		OtherPhoneVisibility string `json:"other_phone_visibility"`
+	}
+	MemberPrivacyOut struct {
+		resp.Response
+		Res MemberPrivacyRes
+	}
+)
+
+func newMemberPrivacyRes(m MemberModel) MemberPrivacyRes {
+	// Member saved before the setting existed use the default
+	visibility := func(f FieldVisibility) string {
+		if f == VisibilityUnknown {
+			return VisibilityMembers.String
+		}
+		return f.String
+	}
+
+	return MemberPrivacyRes{
+		UsernameVisibility:   visibility(m.UsernameVisibility),
+		WaPhoneVisibility:    visibility(m.WaPhoneVisibility),
+		OtherPhoneVisibility: visibility(m.OtherPhoneVisibility),
+	}
+}
+
+func (d *UserDeps) FindMemberPrivacy(ctx context.Context, uid string) (out MemberPrivacyOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	_, err = uuid.FromString(uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	member, err := d.MemberRepository.FindById(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by id"))
+		return
+	}
+
+	out.Res = newMemberPrivacyRes(member)
+
+	return
+}
+
+func (d *UserDeps) UpdateMemberPrivacy(ctx context.Context, uid string, in MemberPrivacyIn) (out MemberPrivacyOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateMemberPrivacyIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	_, err = uuid.FromString(uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	member, err := d.MemberRepository.FindById(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by id"))
+		return
+	}
+
+	member.UsernameVisibility, _ = fieldVisibilityFromString(in.UsernameVisibility)
+	member.WaPhoneVisibility, _ = fieldVisibilityFromString(in.WaPhoneVisibility)
+	member.OtherPhoneVisibility, _ = fieldVisibilityFromString(in.OtherPhoneVisibility)
+
+	if err = d.MemberRepository.Update(ctx, uid, member); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update member"))
+		return
+	}
+
+	out.Res = newMemberPrivacyRes(member)
+
+	return
+}
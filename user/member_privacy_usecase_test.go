@@ -0,0 +1,186 @@
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateMemberPrivacy(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Id                 string
+		In                 user.MemberPrivacyIn
+	}{
+		{
+			Name:               "Update Member Privacy Success",
+			ExpectedStatusCode: http.StatusOK,
+			Id:                 uid,
+			In: user.MemberPrivacyIn{
+				UsernameVisibility:   user.VisibilityPublic.String,
+				WaPhoneVisibility:    user.VisibilityMembers.String,
+				OtherPhoneVisibility: user.VisibilityAdmins.String,
+			},
+		},
+		{
+			Name:               "Update Member Privacy Fail, Visibility Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Id:                 uid,
+			In: user.MemberPrivacyIn{
+				UsernameVisibility: user.VisibilityPublic.String,
+			},
+		},
+		{
+			Name:               "Update Member Privacy Fail, Invalid Visibility",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Id:                 uid,
+			In: user.MemberPrivacyIn{
+				UsernameVisibility:   "everyone",
+				WaPhoneVisibility:    user.VisibilityMembers.String,
+				OtherPhoneVisibility: user.VisibilityAdmins.String,
+			},
+		},
+		{
+			Name:               "Update Member Privacy Fail, Member Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Id:                 "12345678-1234-1234-1234-123456789012",
+			In: user.MemberPrivacyIn{
+				UsernameVisibility:   user.VisibilityPublic.String,
+				WaPhoneVisibility:    user.VisibilityMembers.String,
+				OtherPhoneVisibility: user.VisibilityAdmins.String,
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.UpdateMemberPrivacy(context.Background(), c.Id, c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if res.Error != nil {
+				return
+			}
+
+			assert.Equal(t, c.In.UsernameVisibility, res.Res.UsernameVisibility)
+			assert.Equal(t, c.In.WaPhoneVisibility, res.Res.WaPhoneVisibility)
+			assert.Equal(t, c.In.OtherPhoneVisibility, res.Res.OtherPhoneVisibility)
+		})
+	}
+}
+
+func TestFindMemberDetailPrivacy(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adminUid, err := createUser(memberRepository, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pendingUid, err := createUser(memberRepository, pendingMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherMember := user.MemberModel(memberNormal)
+	otherMember.Username = "existusernamefive"
+	otherMember.WaPhone = "+62 821-1111-9990"
+	otherMember.OtherPhone = "+62 821-1111-9990"
+	otherUid, err := createUser(memberRepository, otherMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := userDeps.UpdateMemberPrivacy(context.Background(), uid, user.MemberPrivacyIn{
+		UsernameVisibility:   user.VisibilityPublic.String,
+		WaPhoneVisibility:    user.VisibilityMembers.String,
+		OtherPhoneVisibility: user.VisibilityAdmins.String,
+	})
+	if res.Error != nil {
+		t.Fatal(res.Error)
+	}
+
+	testCases := []struct {
+		Name               string
+		ViewerUid          string
+		ExpectedWaPhone    string
+		ExpectedOtherPhone string
+		IsIdCardShown      bool
+	}{
+		{
+			Name: "Find Member Detail As Public",
+		},
+		{
+			Name:      "Find Member Detail As Not Approved Member",
+			ViewerUid: pendingUid,
+		},
+		{
+			Name:            "Find Member Detail As Member",
+			ViewerUid:       otherUid,
+			ExpectedWaPhone: memberNormal.WaPhone,
+		},
+		{
+			Name:               "Find Member Detail As Admin",
+			ViewerUid:          adminUid,
+			ExpectedWaPhone:    memberNormal.WaPhone,
+			ExpectedOtherPhone: memberNormal.OtherPhone,
+			IsIdCardShown:      true,
+		},
+		{
+			Name:               "Find Member Detail As Self",
+			ViewerUid:          uid,
+			ExpectedWaPhone:    memberNormal.WaPhone,
+			ExpectedOtherPhone: memberNormal.OtherPhone,
+			IsIdCardShown:      true,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.FindMemberDetail(context.Background(), uid, c.ViewerUid)
+			if res.Error != nil {
+				t.Fatal(res.Error)
+			}
+
+			assert.Equal(t, memberNormal.Username, res.Res.Username)
+			assert.Equal(t, c.ExpectedWaPhone, res.Res.WaPhone)
+			assert.Equal(t, c.ExpectedOtherPhone, res.Res.OtherPhone)
+
+			member, err := memberRepository.FindById(context.Background(), uid)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectedIdCardUrl := ""
+			if c.IsIdCardShown {
+				expectedIdCardUrl = member.IdCardUrl
+			}
+			assert.Equal(t, expectedIdCardUrl, res.Res.IdCardUrl)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package user
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type ProfileChangeField struct {
+	String string
+}
+
+var (
+	ProfileChangeFieldUnknown = ProfileChangeField{""}
+	ProfileChangeFieldName    = ProfileChangeField{"name"}
+	ProfileChangeFieldIdCard  = ProfileChangeField{"id_card"}
+)
+
+func profileChangeFieldFromString(s string) (ProfileChangeField, error) {
+	switch s {
+	case ProfileChangeFieldName.String:
+		return ProfileChangeFieldName, nil
+	case ProfileChangeFieldIdCard.String:
+		return ProfileChangeFieldIdCard, nil
+	}
+
+	return ProfileChangeFieldUnknown, errors.New("unknown type: " + s)
+}
+
+func (u *ProfileChangeField) Scan(src interface{}) error {
+	if src == nil {
+		u.String = ""
+		return nil
+	}
+
+	s, ok := src.(string)
+	if !ok {
+		u.String = ""
+		return nil
+	}
+
+	cf, _ := profileChangeFieldFromString(s)
+	u.String = cf.String
+	return nil
+}
+
+func (u ProfileChangeField) Value() (driver.Value, error) {
+	cf, err := profileChangeFieldFromString(u.String)
+	if err != nil {
+		return nil, err
+	}
+
+	return cf.String, nil
+}
+
+type ProfileChangeStatus struct {
+	String string
+}
+
+var (
+	ProfileChangeUnknown  = ProfileChangeStatus{""}
+	ProfileChangePending  = ProfileChangeStatus{"pending"}
+	ProfileChangeApproved = ProfileChangeStatus{"approved"}
+	ProfileChangeRejected = ProfileChangeStatus{"rejected"}
+)
+
+func profileChangeStatusFromString(s string) (ProfileChangeStatus, error) {
+	switch s {
+	case ProfileChangePending.String:
+		return ProfileChangePending, nil
+	case ProfileChangeApproved.String:
+		return ProfileChangeApproved, nil
+	case ProfileChangeRejected.String:
+		return ProfileChangeRejected, nil
+	}
+
+	return ProfileChangeUnknown, errors.New("unknown type: " + s)
+}
+
+func (u *ProfileChangeStatus) Scan(src interface{}) error {
+	if src == nil {
+		u.String = ""
+		return nil
+	}
+
+	s, ok := src.(string)
+	if !ok {
+		u.String = ""
+		return nil
+	}
+
+	cs, _ := profileChangeStatusFromString(s)
+	u.String = cs.String
+	return nil
+}
+
+func (u ProfileChangeStatus) Value() (driver.Value, error) {
+	cs, err := profileChangeStatusFromString(u.String)
+	if err != nil {
+		cs = ProfileChangePending
+	}
+
+	return cs.String, nil
+}
+
+type MemberProfileChangeModel struct {
+	Id           uint64
+	MemberId     string
+	MemberName   string
+	Field        ProfileChangeField
+	OldValue     string
+	NewValue     string
+	Status       ProfileChangeStatus
+	ReviewReason string
+	ReviewerId   string
+	ReviewedAt   sql.NullTime
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
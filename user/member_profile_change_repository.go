@@ -0,0 +1,337 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type MemberProfileChangeRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewMemberProfileChangeRepository(postgreDb *pgxpool.Pool) *MemberProfileChangeRepository {
+	return &MemberProfileChangeRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+type (
+	MemberProfileChangeExecutor   func(ctx context.Context, sql string, arguments ...interface{}) (commandTag pgconn.CommandTag, err error)
+	MemberProfileChangeQuerierRow func(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	MemberProfileChangeQuerier    func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+)
+
+const memberProfileChangeColumns = `
+	c.id,
+	c.member_id,
+	m.name AS member_name,
+	c.field,
+	c.old_value,
+	c.new_value,
+	c.status,
+	c.review_reason,
+	COALESCE(c.reviewer_id::text, '') AS reviewer_id,
+	c.reviewed_at,
+	c.created_at,
+	c.updated_at
+`
+
+func (r *MemberProfileChangeRepository) Save(ctx context.Context, m MemberProfileChangeModel) (nm MemberProfileChangeModel, err error) {
+	sqlQuery := `
+		INSERT INTO member_profile_changes (
+			member_id,
+			field,
+			old_value,
+			new_value,
+			status,
+			created_at,
+			updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	var queryRow MemberProfileChangeQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	var lastInsertId uint64
+	t := time.Now()
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		m.MemberId,
+		m.Field,
+		m.OldValue,
+		m.NewValue,
+		ProfileChangePending,
+		t,
+		t,
+	).Scan(&lastInsertId)
+
+	if err != nil {
+		return MemberProfileChangeModel{}, err
+	}
+
+	m.Id = lastInsertId
+	m.Status = ProfileChangePending
+	m.CreatedAt = t
+	m.UpdatedAt = t
+
+	return m, nil
+}
+
+// A newer edit of the field replace the value waiting for the review
+func (r *MemberProfileChangeRepository) UpdateNewValueById(ctx context.Context, id uint64, newValue string) error {
+	sqlQuery := `
+		UPDATE member_profile_changes SET (
+			new_value,
+			updated_at
+		) = ($1, $2)
+		WHERE id = $3
+	`
+
+	var exec MemberProfileChangeExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		newValue,
+		time.Now(),
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *MemberProfileChangeRepository) FindById(ctx context.Context, id uint64) (m MemberProfileChangeModel, err error) {
+	sqlQuery := `
+		SELECT ` + memberProfileChangeColumns + `
+		FROM member_profile_changes c
+			JOIN members m ON m.id = c.member_id
+		WHERE c.id = $1
+	`
+
+	var query MemberProfileChangeQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+
+	rows, err = query(
+		context.Background(),
+		sqlQuery,
+		id,
+	)
+
+	if err != nil {
+		return MemberProfileChangeModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return MemberProfileChangeModel{}, err
+	}
+
+	return m, nil
+}
+
+func (r *MemberProfileChangeRepository) FindPendingByMemberIdAndField(ctx context.Context, uid string, field ProfileChangeField) (m MemberProfileChangeModel, err error) {
+	sqlQuery := `
+		SELECT ` + memberProfileChangeColumns + `
+		FROM member_profile_changes c
+			JOIN members m ON m.id = c.member_id
+		WHERE c.member_id = $1
+			AND c.field = $2
+			AND c.status = 'pending'
+		ORDER BY c.id DESC
+		LIMIT 1
+	`
+
+	var query MemberProfileChangeQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+
+	rows, err = query(
+		context.Background(),
+		sqlQuery,
+		uid,
+		field,
+	)
+
+	if err != nil {
+		return MemberProfileChangeModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return MemberProfileChangeModel{}, err
+	}
+
+	return m, nil
+}
+
+func (r *MemberProfileChangeRepository) QueryByMemberId(ctx context.Context, uid string, limit int64) ([]MemberProfileChangeModel, error) {
+	sqlQuery := `
+		SELECT ` + memberProfileChangeColumns + `
+		FROM member_profile_changes c
+			JOIN members m ON m.id = c.member_id
+		WHERE c.member_id = $1
+		ORDER BY c.id DESC
+		LIMIT $2
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		uid,
+		limit,
+	)
+	defer rows.Close()
+
+	var mps []*MemberProfileChangeModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []MemberProfileChangeModel{}, err
+	}
+
+	ms := make([]MemberProfileChangeModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+// Empty status query the changes of every status
+func (r *MemberProfileChangeRepository) Query(ctx context.Context, status string, id, limit int64) ([]MemberProfileChangeModel, error) {
+	fromId := "c.id > $1"
+	if id != 0 {
+		fromId = "c.id < $1"
+	}
+
+	sqlQuery := `
+		SELECT ` + memberProfileChangeColumns + `
+		FROM member_profile_changes c
+			JOIN members m ON m.id = c.member_id
+		WHERE m.deleted_at IS NULL
+			AND ` + fromId + `
+			AND ($2 = '' OR c.status::text = $2)
+		ORDER BY c.id DESC
+		LIMIT $3
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		id,
+		status,
+		limit,
+	)
+	defer rows.Close()
+
+	var mps []*MemberProfileChangeModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []MemberProfileChangeModel{}, err
+	}
+
+	ms := make([]MemberProfileChangeModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *MemberProfileChangeRepository) CountByStatus(ctx context.Context, status string) (n int64, err error) {
+	sqlQuery := `
+		SELECT COUNT(c.id) AS n
+		FROM member_profile_changes c
+			JOIN members m ON m.id = c.member_id
+		WHERE m.deleted_at IS NULL
+			AND ($1 = '' OR c.status::text = $1)
+	`
+
+	var queryRow MemberProfileChangeQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		status,
+	).Scan(&n)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func (r *MemberProfileChangeRepository) UpdateStatusById(ctx context.Context, id uint64, m MemberProfileChangeModel) error {
+	sqlQuery := `
+		UPDATE member_profile_changes SET (
+			status,
+			review_reason,
+			reviewer_id,
+			reviewed_at,
+			updated_at
+		) = ($1, $2, NULLIF($3, '')::uuid, $4, $4)
+		WHERE id = $5
+	`
+
+	var exec MemberProfileChangeExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		m.Status,
+		m.ReviewReason,
+		m.ReviewerId,
+		time.Now(),
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrProfileChangeNotFound = errors.New("perubahan profil tidak ditemukan")
+	ErrProfileChangeReviewed = errors.New("perubahan profil sudah ditinjau")
+)
+
+// A sensitive field is only applied to the member once an admin approve
+// it, another edit of the field before the review replace the staged value
+func (d *UserDeps) stageProfileChange(ctx context.Context, uid string, field ProfileChangeField, oldValue, newValue string) error {
+	pending, err := d.MemberProfileChangeRepository.FindPendingByMemberIdAndField(ctx, uid, field)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return errors.Wrap(err, "find pending profile change by member id and field")
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		_, err = d.MemberProfileChangeRepository.Save(ctx, MemberProfileChangeModel{
+			MemberId: uid,
+			Field:    field,
+			OldValue: oldValue,
+			NewValue: newValue,
+		})
+		if err != nil {
+			return errors.Wrap(err, "save profile change")
+		}
+
+		return nil
+	}
+
+	if err = d.MemberProfileChangeRepository.UpdateNewValueById(ctx, pending.Id, newValue); err != nil {
+		return errors.Wrap(err, "update profile change new value")
+	}
+
+	// The id card uploaded for the replaced change is not used anymore
+	if field == ProfileChangeFieldIdCard {
+		if err = d.removeFile(ctx, pending.NewValue); err != nil {
+			return errors.Wrap(err, "remove replaced id card")
+		}
+	}
+
+	return nil
+}
+
+type (
+	ProfileChangeRes struct {
+		Id           int64     `json:"id"`
+		MemberId     string    `json:"member_id"`
+		MemberName   string    `json:"member_name"`
+		Field        string    `json:"field"`
+		OldValue     string    `json:"old_value"`
+		NewValue     string    `json:"new_value"`
+		Status       string    `json:"status"`
+		ReviewReason string    `json:"review_reason"`
+		ReviewedAt   null.Time `json:"reviewed_at"`
+		CreatedAt    time.Time `json:"created_at"`
+	}
+	ProfileChangeOut struct {
+		resp.Response
+		Res ProfileChangeRes
+	}
+)
+
+func newProfileChangeRes(m MemberProfileChangeModel) ProfileChangeRes {
+	return ProfileChangeRes{
+		Id:           int64(m.Id),
+		MemberId:     m.MemberId,
+		MemberName:   m.MemberName,
+		Field:        m.Field.String,
+		OldValue:     m.OldValue,
+		NewValue:     m.NewValue,
+		Status:       m.Status.String,
+		ReviewReason: m.ReviewReason,
+		ReviewedAt:   null.NewTime(m.ReviewedAt.Time, m.ReviewedAt.Valid),
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+type (
+	QueryMemberProfileChangesRes struct {
+		Changes []ProfileChangeRes `json:"changes"`
+	}
+	QueryMemberProfileChangesOut struct {
+		resp.Response
+		Res QueryMemberProfileChangesRes
+	}
+)
+
+// The latest changes of the member, so the member know which edits are
+// still waiting and why one was rejected
+func (d *UserDeps) QueryMemberProfileChanges(ctx context.Context, uid string) (out QueryMemberProfileChangesOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	_, err = uuid.FromString(uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	changes, err := d.MemberProfileChangeRepository.QueryByMemberId(ctx, uid, 25)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query profile changes by member id"))
+		return
+	}
+
+	res := make([]ProfileChangeRes, len(changes))
+	for i, m := range changes {
+		res[i] = newProfileChangeRes(m)
+	}
+
+	out.Res.Changes = res
+
+	return
+}
+
+type (
+	QueryProfileChangesRes struct {
+		Cursor  int64              `json:"cursor"`
+		Total   int64              `json:"total"`
+		Changes []ProfileChangeRes `json:"changes"`
+	}
+	QueryProfileChangesOut struct {
+		resp.Response
+		Res QueryProfileChangesRes
+	}
+)
+
+// Review queue of the admin, empty status query the changes waiting for
+// the review
+func (d *UserDeps) QueryProfileChanges(ctx context.Context, status, cursor, limit string) (out QueryProfileChangesOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if status == "" {
+		status = ProfileChangePending.String
+	}
+	if _, err = profileChangeStatusFromString(status); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidProfileChangeStatus)
+		return
+	}
+
+	fromCursor, _ := strconv.ParseInt(cursor, 10, 64)
+	nlimit, _ := strconv.ParseInt(limit, 10, 64)
+	if nlimit == 0 {
+		nlimit = 25
+	}
+
+	total, err := d.MemberProfileChangeRepository.CountByStatus(ctx, status)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "count profile changes by status"))
+		return
+	}
+
+	changes, err := d.MemberProfileChangeRepository.Query(ctx, status, fromCursor, nlimit)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query profile changes"))
+		return
+	}
+
+	var nextCursor int64
+	if len(changes) != 0 {
+		nextCursor = int64(changes[len(changes)-1].Id)
+	}
+
+	res := make([]ProfileChangeRes, len(changes))
+	for i, m := range changes {
+		res[i] = newProfileChangeRes(m)
+	}
+
+	out.Res = QueryProfileChangesRes{
+		Cursor:  nextCursor,
+		Total:   total,
+		Changes: res,
+	}
+
+	return
+}
+
+type ReviewProfileChangeIn struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// An approved change is applied to the member, the id card of a rejected
+// change is removed since nobody use it
+func (d *UserDeps) ReviewProfileChange(ctx context.Context, cid, actorUid string, in ReviewProfileChangeIn) (out ProfileChangeOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateReviewProfileChangeIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	id, err := strconv.ParseUint(cid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrProfileChangeNotFound)
+		return
+	}
+
+	change, err := d.MemberProfileChangeRepository.FindById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrProfileChangeNotFound)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find profile change by id"))
+		return
+	}
+
+	if change.Status != ProfileChangePending {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrProfileChangeReviewed)
+		return
+	}
+
+	member, err := d.MemberRepository.FindById(ctx, change.MemberId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by id"))
+		return
+	}
+
+	status, _ := profileChangeStatusFromString(in.Status)
+	if status == ProfileChangeApproved {
+		switch change.Field {
+		case ProfileChangeFieldName:
+			member.Name = change.NewValue
+		case ProfileChangeFieldIdCard:
+			member.IdCardUrl = change.NewValue
+		}
+
+		if err = d.MemberRepository.Update(ctx, change.MemberId, member); err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update member"))
+			return
+		}
+
+		change.MemberName = member.Name
+	}
+
+	change.Status = status
+	change.ReviewReason = in.Reason
+	change.ReviewerId = actorUid
+	if err = d.MemberProfileChangeRepository.UpdateStatusById(ctx, id, change); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update profile change status"))
+		return
+	}
+
+	if status == ProfileChangeRejected && change.Field == ProfileChangeFieldIdCard {
+		if err = d.removeFile(ctx, change.NewValue); err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "remove rejected id card"))
+			return
+		}
+	}
+
+	change.ReviewedAt.Scan(time.Now())
+	out.Res = newProfileChangeRes(change)
+
+	return
+}
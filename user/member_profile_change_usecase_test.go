@@ -0,0 +1,248 @@
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateProfileStageChange(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := userDeps.UpdateProfile(context.Background(), uid, user.UpdateProfileIn{
+		Name:       "Nama Baru",
+		Username:   memberNormal.Username,
+		WaPhone:    memberNormal.WaPhone,
+		OtherPhone: memberNormal.OtherPhone,
+	})
+	if out.Error != nil {
+		t.Fatal(out.Error)
+	}
+
+	assert.Equal(t, []string{user.ProfileChangeFieldName.String}, out.Res.PendingChanges)
+
+	m, err := memberRepository.FindById(context.Background(), uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, memberNormal.Name, m.Name)
+
+	// Editing the name again replace the staged value
+	out = userDeps.UpdateProfile(context.Background(), uid, user.UpdateProfileIn{
+		Name:       "Nama Lain",
+		Username:   memberNormal.Username,
+		WaPhone:    memberNormal.WaPhone,
+		OtherPhone: memberNormal.OtherPhone,
+	})
+	if out.Error != nil {
+		t.Fatal(out.Error)
+	}
+
+	changes := userDeps.QueryMemberProfileChanges(context.Background(), uid)
+	if changes.Error != nil {
+		t.Fatal(changes.Error)
+	}
+
+	assert.Len(t, changes.Res.Changes, 1)
+	assert.Equal(t, "Nama Lain", changes.Res.Changes[0].NewValue)
+	assert.Equal(t, user.ProfileChangePending.String, changes.Res.Changes[0].Status)
+}
+
+func TestReviewProfileChange(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actorUid, err := createUser(memberRepository, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updateName := func(name string) string {
+		out := userDeps.UpdateProfile(context.Background(), uid, user.UpdateProfileIn{
+			Name:       name,
+			Username:   memberNormal.Username,
+			WaPhone:    memberNormal.WaPhone,
+			OtherPhone: memberNormal.OtherPhone,
+		})
+		if out.Error != nil {
+			t.Fatal(out.Error)
+		}
+
+		changes := userDeps.QueryProfileChanges(context.Background(), "", "", "")
+		if changes.Error != nil {
+			t.Fatal(changes.Error)
+		}
+
+		return strconv.FormatInt(changes.Res.Changes[0].Id, 10)
+	}
+
+	rejected := updateName("Nama Ditolak")
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		ExpectedStatus     string
+		Init               func() string
+		In                 user.ReviewProfileChangeIn
+	}{
+		{
+			Name:               "Review Profile Change Fail, Reason Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Init: func() string {
+				return rejected
+			},
+			In: user.ReviewProfileChangeIn{
+				Status: user.ProfileChangeRejected.String,
+			},
+		},
+		{
+			Name:               "Review Profile Change Reject Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedStatus:     user.ProfileChangeRejected.String,
+			Init: func() string {
+				return rejected
+			},
+			In: user.ReviewProfileChangeIn{
+				Status: user.ProfileChangeRejected.String,
+				Reason: "Nama tidak sesuai dengan KTP",
+			},
+		},
+		{
+			Name:               "Review Profile Change Fail, Already Reviewed",
+			ExpectedStatusCode: http.StatusBadRequest,
+			Init: func() string {
+				return rejected
+			},
+			In: user.ReviewProfileChangeIn{
+				Status: user.ProfileChangeApproved.String,
+			},
+		},
+		{
+			Name:               "Review Profile Change Approve Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedStatus:     user.ProfileChangeApproved.String,
+			Init: func() string {
+				return updateName("Nama Disetujui")
+			},
+			In: user.ReviewProfileChangeIn{
+				Status: user.ProfileChangeApproved.String,
+			},
+		},
+		{
+			Name:               "Review Profile Change Fail, Change Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Init: func() string {
+				return "999"
+			},
+			In: user.ReviewProfileChangeIn{
+				Status: user.ProfileChangeApproved.String,
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			cid := c.Init()
+
+			tx, err := db.Begin(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.WithValue(context.Background(), arbitary.TrxX{}, tx)
+			res := userDeps.ReviewProfileChange(ctx, cid, actorUid, c.In)
+			tx.Commit(context.Background())
+			tx.Rollback(context.Background())
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if c.ExpectedStatus == "" {
+				return
+			}
+
+			assert.Equal(t, c.ExpectedStatus, res.Res.Status)
+		})
+	}
+
+	m, err := memberRepository.FindById(context.Background(), uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "Nama Disetujui", m.Name)
+}
+
+func TestReviewProfileChangeIdCardRemoveFail(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actorUid, err := createUser(memberRepository, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idCardUrl := "https://res.cloudinary.com/demo/image/upload/v1/uhomestay/profile/ktp.jpg"
+	change, err := memberProfileChangeRepository.Save(context.Background(), user.MemberProfileChangeModel{
+		MemberId: uid,
+		Field:    user.ProfileChangeFieldIdCard,
+		NewValue: idCardUrl,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userDeps.Remove = func(url string) error {
+		return errors.New("destroy file: unavailable")
+	}
+	defer func() { userDeps.Remove = remove }()
+
+	// The review is kept even though the rejected id card is still
+	// uploaded, the removal is retried later
+	res := userDeps.ReviewProfileChange(context.Background(), strconv.FormatUint(change.Id, 10), actorUid, user.ReviewProfileChangeIn{
+		Status: user.ProfileChangeRejected.String,
+		Reason: "KTP tidak terbaca",
+	})
+	if res.StatusCode != http.StatusOK {
+		t.Logf("%#v", res)
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusOK, res.StatusCode)
+	}
+
+	assert.Equal(t, user.ProfileChangeRejected.String, res.Res.Status)
+
+	removals := userDeps.QueryFileRemovals(context.Background(), "")
+	if assert.Len(t, removals.Res.FileRemovals, 1) {
+		assert.Equal(t, idCardUrl, removals.Res.FileRemovals[0].Url)
+	}
+}
@@ -0,0 +1,52 @@
+package user
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrProfileChangeStatusRequired = errors.New("status perubahan profil tidak boleh kosong")
+	ErrInvalidProfileChangeStatus  = errors.New("status perubahan profil harus berupa approved atau rejected")
+	ErrProfileChangeReasonRequired = errors.New("alasan penolakan perubahan profil tidak boleh kosong")
+	ErrMaxProfileChangeReason      = errors.New("alasan penolakan perubahan profil tidak dapat lebih dari 500 karakter")
+)
+
+func ValidateReviewProfileChangeIn(i ReviewProfileChangeIn) error {
+	g := new(errgroup.Group)
+
+	g.Go(func() error {
+		if strings.Trim(i.Status, " ") == "" {
+			return ErrProfileChangeStatusRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		switch i.Status {
+		case "", ProfileChangeApproved.String, ProfileChangeRejected.String:
+			return nil
+		}
+		return ErrInvalidProfileChangeStatus
+	})
+	g.Go(func() error {
+		if i.Status == ProfileChangeRejected.String && strings.Trim(i.Reason, " ") == "" {
+			return ErrProfileChangeReasonRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Reason) > 500 {
+			return ErrMaxProfileChangeReason
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,327 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/httpdecode"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/fikryfahrezy/crypt/agron2"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrRejectedMember      = errors.New("pendaftaran anggota ditolak pengelola")
+	ErrInfoRequestedMember = errors.New("pengelola membutuhkan informasi tambahan untuk pendaftaran anggota")
+	ErrNotInfoRequested    = errors.New("pendaftaran anggota tidak sedang menunggu informasi tambahan")
+)
+
+// Members saved before the review existed have no status, an approved
+// one is always shown as approved
+func memberApprovalStatus(m MemberModel) MemberApprovalStatus {
+	if m.IsApproved {
+		return MemberApprovalApproved
+	}
+
+	if m.ApprovalStatus == MemberApprovalUnknown {
+		return MemberApprovalPending
+	}
+
+	return m.ApprovalStatus
+}
+
+// The reason of the review is part of the message, so the applicant know
+// why the account can not be used yet
+func notApprovedResponse(m MemberModel) resp.Response {
+	var err error
+	switch m.ApprovalStatus {
+	case MemberApprovalRejected:
+		err = ErrRejectedMember
+	case MemberApprovalInfoRequested:
+		err = ErrInfoRequestedMember
+	default:
+		return resp.NewResponse(http.StatusBadRequest, "", ErrNotApprovedMember)
+	}
+
+	message := err.Error()
+	if m.ApprovalReason != "" {
+		message = message + ": " + m.ApprovalReason
+	}
+
+	return resp.NewResponse(http.StatusBadRequest, message, err)
+}
+
+type ReviewMemberRegistrationIn struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+func (d *UserDeps) ReviewMemberRegistration(ctx context.Context, uid string, in ReviewMemberRegistrationIn) (out MemberApprovalOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateReviewMemberRegistrationIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	_, err = uuid.FromString(uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	member, err := d.MemberRepository.FindById(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find unapproved member by id"))
+		return
+	}
+
+	if member.IsApproved {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	status, _ := memberApprovalStatusFromString(in.Status)
+	member.IsApproved = status == MemberApprovalApproved
+	member.ApprovalStatus = status
+	member.ApprovalReason = in.Reason
+	if member.IsApproved {
+		member.ApprovalReason = ""
+		member.Status = MemberStatusActive
+	}
+	member.ReviewedAt.Scan(time.Now())
+
+	if err = d.MemberRepository.Update(ctx, uid, member); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update member"))
+		return
+	}
+
+	out.Res.Id = uid
+
+	return
+}
+
+type (
+	RegistrationStatusRes struct {
+		Status     string    `json:"status"`
+		Reason     string    `json:"reason"`
+		ReviewedAt null.Time `json:"reviewed_at"`
+	}
+	RegistrationStatusOut struct {
+		resp.Response
+		Res RegistrationStatusRes
+	}
+)
+
+// The applicant check the registration with the same credential used to
+// log in, so the review reason is not exposed to anyone knowing the username.
+// The failures count toward the login throttle like a failed login.
+func (d *UserDeps) FindRegistrationStatus(ctx context.Context, in LoginIn) (out RegistrationStatusOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateLoginIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	if res := d.loginThrottleResponse(ctx, in.Identifier, in.Ip); res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	member, err := d.MemberRepository.FindByUsername(in.Identifier)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = d.loginFailedResponse(ctx, in.Identifier, in.Ip, resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound))
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by username"))
+		return
+	}
+
+	if err = agron2.Argon2Verify(member.Password, in.Password, agron2.Argon2Id); err != nil {
+		out.Response = d.loginFailedResponse(ctx, in.Identifier, in.Ip, resp.NewResponse(http.StatusBadRequest, "", ErrPasswordNotMatch))
+		return
+	}
+
+	out.Res = RegistrationStatusRes{
+		Status:     memberApprovalStatus(member).String,
+		Reason:     member.ApprovalReason,
+		ReviewedAt: null.NewTime(member.ReviewedAt.Time, member.ReviewedAt.Valid),
+	}
+
+	return
+}
+
+type ResubmitRegistrationIn struct {
+	Identifier string                `mapstructure:"identifier"`
+	Password   string                `mapstructure:"password"`
+	Name       string                `mapstructure:"name"`
+	WaPhone    string                `mapstructure:"wa_phone"`
+	OtherPhone string                `mapstructure:"other_phone"`
+	IdCard     httpdecode.FileHeader `mapstructure:"id_card"`
+	Ip         string                `mapstructure:"-"`
+}
+
+// The applicant answer a request for more information by correcting the
+// registration or uploading another id card, the registration then wait
+// for a review again. The credential is checked the same way as the
+// registration status.
+func (d *UserDeps) ResubmitRegistration(ctx context.Context, in ResubmitRegistrationIn) (out RegistrationStatusOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateResubmitRegistrationIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	if res := d.loginThrottleResponse(ctx, in.Identifier, in.Ip); res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	member, err := d.MemberRepository.FindByUsername(in.Identifier)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = d.loginFailedResponse(ctx, in.Identifier, in.Ip, resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound))
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by username"))
+		return
+	}
+
+	if err = agron2.Argon2Verify(member.Password, in.Password, agron2.Argon2Id); err != nil {
+		out.Response = d.loginFailedResponse(ctx, in.Identifier, in.Ip, resp.NewResponse(http.StatusBadRequest, "", ErrPasswordNotMatch))
+		return
+	}
+
+	if memberApprovalStatus(member) != MemberApprovalInfoRequested {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrNotInfoRequested)
+		return
+	}
+
+	uid := member.Id.UUID.String()
+	member.Name = in.Name
+	member.WaPhone = in.WaPhone
+	member.OtherPhone = in.OtherPhone
+
+	existingMember, err := d.MemberRepository.CheckOtherUniqueField(ctx, uid, member)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "check other unique field"))
+		return
+	}
+
+	if !existingMember.Id.UUID.IsNil() {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrDuplicateUniqueProperty)
+		return
+	}
+
+	idCard := in.IdCard.File
+	defer func() {
+		if idCard != nil {
+			idCard.Close()
+		}
+	}()
+
+	idCardFileUrlCh := make(chan string)
+	idCardUploadResCh := make(chan resp.Response)
+	go UploadFile(in.IdCard.Filename, idCard, d.Upload, idCardFileUrlCh, idCardUploadResCh)
+
+	newIdCardUrl := <-idCardFileUrlCh
+	if res := <-idCardUploadResCh; res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	oldIdCardUrl := ""
+	if newIdCardUrl != "" {
+		oldIdCardUrl = member.IdCardUrl
+		member.IdCardUrl = newIdCardUrl
+	}
+
+	member.ApprovalStatus = MemberApprovalPending
+	member.ApprovalReason = ""
+	if err = d.MemberRepository.Update(ctx, uid, member); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update member"))
+		return
+	}
+
+	// The id card asked to be replaced is not used anymore
+	if err = d.removeFile(ctx, oldIdCardUrl); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "remove replaced id card"))
+		return
+	}
+
+	out.Res = RegistrationStatusRes{
+		Status:     MemberApprovalPending.String,
+		ReviewedAt: null.NewTime(member.ReviewedAt.Time, member.ReviewedAt.Valid),
+	}
+
+	return
+}
+
+// Purge the registrations rejected before the retention the same way as an
+// erasure, along with the uploaded files and the homestay images. A file
+// that can not be removed is retried by the file removal retry.
+func (d *UserDeps) PurgeRejectedMembers(ctx context.Context, retention time.Duration) (purged int, err error) {
+	members, err := d.MemberRepository.QueryRejectedBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, errors.Wrap(err, "query rejected member")
+	}
+
+	for _, m := range members {
+		uid := m.Id.UUID.String()
+
+		urls, err := d.MemberRepository.QueryFileUrls(ctx, uid)
+		if err != nil {
+			return purged, errors.Wrap(err, "query member file urls")
+		}
+
+		for _, url := range urls {
+			if err = d.removeFile(ctx, url); err != nil {
+				return purged, err
+			}
+		}
+
+		if err = d.MemberRepository.EraseById(ctx, uid); err != nil {
+			return purged, errors.Wrap(err, "erase member by id")
+		}
+
+		purged++
+	}
+
+	return purged, nil
+}
+
+// Purge the rejected registrations on each interval until the context is
+// done, meant to be run in its own goroutine. A registration that could not
+// be purged stays rejected and is purged on a later interval, the files that
+// could not be removed are shown to the admins with their last error.
+func (d *UserDeps) RunRejectedMemberPurge(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = d.PurgeRejectedMembers(ctx, retention)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
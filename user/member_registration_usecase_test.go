@@ -0,0 +1,400 @@
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewMemberRegistration(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid1, err := createUser(memberRepository, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid2, err := createUser(memberRepository, pendingMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		ExpectedStatus     string
+		Id                 string
+		In                 user.ReviewMemberRegistrationIn
+	}{
+		{
+			Name:               "Review Member Registration Fail, Reason Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Id:                 uid2,
+			In: user.ReviewMemberRegistrationIn{
+				Status: user.MemberApprovalRejected.String,
+			},
+		},
+		{
+			Name:               "Review Member Registration Fail, Invalid Status",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Id:                 uid2,
+			In: user.ReviewMemberRegistrationIn{
+				Status: "pending",
+			},
+		},
+		{
+			Name:               "Review Member Registration Request Info Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedStatus:     user.MemberApprovalInfoRequested.String,
+			Id:                 uid2,
+			In: user.ReviewMemberRegistrationIn{
+				Status: user.MemberApprovalInfoRequested.String,
+				Reason: "Foto ktp tidak terbaca",
+			},
+		},
+		{
+			Name:               "Review Member Registration Reject Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedStatus:     user.MemberApprovalRejected.String,
+			Id:                 uid2,
+			In: user.ReviewMemberRegistrationIn{
+				Status: user.MemberApprovalRejected.String,
+				Reason: "Bukan pemilik homestay",
+			},
+		},
+		{
+			Name:               "Review Member Registration Fail, Member Already Approved",
+			ExpectedStatusCode: http.StatusNotFound,
+			Id:                 uid1,
+			In: user.ReviewMemberRegistrationIn{
+				Status: user.MemberApprovalRejected.String,
+				Reason: "Bukan pemilik homestay",
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			tx, err := db.Begin(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.WithValue(context.Background(), arbitary.TrxX{}, tx)
+			res := userDeps.ReviewMemberRegistration(ctx, c.Id, c.In)
+			tx.Commit(context.Background())
+			tx.Rollback(context.Background())
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if c.ExpectedStatus == "" {
+				return
+			}
+
+			m, err := memberRepository.FindById(context.Background(), c.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, c.ExpectedStatus, m.ApprovalStatus.String)
+			assert.Equal(t, c.In.Reason, m.ApprovalReason)
+			assert.False(t, m.IsApproved)
+		})
+	}
+}
+
+func TestFindRegistrationStatus(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, pendingMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reason := "Bukan pemilik homestay"
+	res := userDeps.ReviewMemberRegistration(context.Background(), uid, user.ReviewMemberRegistrationIn{
+		Status: user.MemberApprovalRejected.String,
+		Reason: reason,
+	})
+	if res.Error != nil {
+		t.Fatal(res.Error)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		In                 user.LoginIn
+	}{
+		{
+			Name:               "Find Registration Status Success",
+			ExpectedStatusCode: http.StatusOK,
+			In: user.LoginIn{
+				Identifier: pendingMember.Username,
+				Password:   pendingMember.Password,
+			},
+		},
+		{
+			Name:               "Find Registration Status Fail, Wrong Password",
+			ExpectedStatusCode: http.StatusBadRequest,
+			In: user.LoginIn{
+				Identifier: pendingMember.Username,
+				Password:   "wrong-password",
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.FindRegistrationStatus(context.Background(), c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if res.Error != nil {
+				return
+			}
+
+			assert.Equal(t, user.MemberApprovalRejected.String, res.Res.Status)
+			assert.Equal(t, reason, res.Res.Reason)
+			assert.True(t, res.Res.ReviewedAt.Valid)
+		})
+	}
+
+	login := userDeps.MemberLogin(context.Background(), user.LoginIn{
+		Identifier: pendingMember.Username,
+		Password:   pendingMember.Password,
+	})
+	assert.Equal(t, http.StatusBadRequest, login.StatusCode)
+	assert.ErrorIs(t, login.Error, user.ErrRejectedMember)
+	assert.Contains(t, login.Message, reason)
+}
+
+func TestResubmitRegistration(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, pendingMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := userDeps.ReviewMemberRegistration(context.Background(), uid, user.ReviewMemberRegistrationIn{
+		Status: user.MemberApprovalInfoRequested.String,
+		Reason: "Foto ktp tidak terbaca",
+	})
+	if res.Error != nil {
+		t.Fatal(res.Error)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		In                 user.ResubmitRegistrationIn
+	}{
+		{
+			Name:               "Resubmit Registration Fail, Name Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: user.ResubmitRegistrationIn{
+				Identifier: pendingMember.Username,
+				Password:   pendingMember.Password,
+				WaPhone:    pendingMember.WaPhone,
+				OtherPhone: pendingMember.OtherPhone,
+			},
+		},
+		{
+			Name:               "Resubmit Registration Fail, Wrong Password",
+			ExpectedStatusCode: http.StatusBadRequest,
+			In: user.ResubmitRegistrationIn{
+				Identifier: pendingMember.Username,
+				Password:   "wrong-password",
+				Name:       "Nama Baru",
+				WaPhone:    pendingMember.WaPhone,
+				OtherPhone: pendingMember.OtherPhone,
+			},
+		},
+		{
+			Name:               "Resubmit Registration Success",
+			ExpectedStatusCode: http.StatusOK,
+			In: user.ResubmitRegistrationIn{
+				Identifier: pendingMember.Username,
+				Password:   pendingMember.Password,
+				Name:       "Nama Baru",
+				WaPhone:    pendingMember.WaPhone,
+				OtherPhone: pendingMember.OtherPhone,
+				IdCard:     generateFile(fileDir, fileName),
+			},
+		},
+		{
+			Name:               "Resubmit Registration Fail, Not Waiting for Information",
+			ExpectedStatusCode: http.StatusBadRequest,
+			In: user.ResubmitRegistrationIn{
+				Identifier: pendingMember.Username,
+				Password:   pendingMember.Password,
+				Name:       "Nama Lain",
+				WaPhone:    pendingMember.WaPhone,
+				OtherPhone: pendingMember.OtherPhone,
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			tx, err := db.Begin(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.WithValue(context.Background(), arbitary.TrxX{}, tx)
+			res := userDeps.ResubmitRegistration(ctx, c.In)
+			tx.Commit(context.Background())
+			tx.Rollback(context.Background())
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if res.Error != nil {
+				return
+			}
+
+			assert.Equal(t, user.MemberApprovalPending.String, res.Res.Status)
+		})
+	}
+
+	m, err := memberRepository.FindById(context.Background(), uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, user.MemberApprovalPending.String, m.ApprovalStatus.String)
+	assert.Equal(t, "", m.ApprovalReason)
+	assert.Equal(t, "Nama Baru", m.Name)
+	assert.Contains(t, m.IdCardUrl, fileName)
+	assert.False(t, m.IsApproved)
+}
+
+func TestPurgeRejectedMembers(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = createUser(memberRepository, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, pendingMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := userDeps.ReviewMemberRegistration(context.Background(), uid, user.ReviewMemberRegistrationIn{
+		Status: user.MemberApprovalRejected.String,
+		Reason: "Bukan pemilik homestay",
+	})
+	if res.Error != nil {
+		t.Fatal(res.Error)
+	}
+
+	imageUrl := "https://example.com/homestay.png"
+	_, err = db.Exec(
+		context.Background(),
+		`WITH h AS (
+			INSERT INTO member_homestays (name, address, member_id)
+			VALUES ('Homestay', 'Jalan', $1)
+			RETURNING id
+		)
+		INSERT INTO homestay_images (name, url, member_homestay_id)
+		SELECT 'homestay.png', $2, id FROM h`,
+		uid,
+		imageUrl,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The homestay image can not be removed, the registration is still
+	// purged and the removal is retried later
+	removed := []string{}
+	userDeps.Remove = func(url string) error {
+		removed = append(removed, url)
+		if url == imageUrl {
+			return errors.New("destroy file: unavailable")
+		}
+		return nil
+	}
+	defer func() { userDeps.Remove = remove }()
+
+	purged, err := userDeps.PurgeRejectedMembers(context.Background(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, purged)
+
+	purged, err = userDeps.PurgeRejectedMembers(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, purged)
+	assert.Contains(t, removed, imageUrl)
+
+	_, err = memberRepository.FindById(context.Background(), uid)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	var name, url string
+	err = db.QueryRow(
+		context.Background(),
+		`SELECT m.name, hi.url
+		FROM members m
+			JOIN member_homestays mh ON mh.member_id = m.id
+			JOIN homestay_images hi ON hi.member_homestay_id = mh.id
+		WHERE m.id = $1`,
+		uid,
+	).Scan(&name, &url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, user.ErasedMemberName, name)
+	assert.Equal(t, "", url)
+
+	_, err = memberRepository.FindByUsername(member.Username)
+	assert.NoError(t, err)
+
+	removals := userDeps.QueryFileRemovals(context.Background(), "")
+	if assert.Len(t, removals.Res.FileRemovals, 1) {
+		assert.Equal(t, imageUrl, removals.Res.FileRemovals[0].Url)
+		assert.Equal(t, "destroy file: unavailable", removals.Res.FileRemovals[0].LastError)
+	}
+
+	userDeps.Remove = remove
+	failed, err := userDeps.RetryFileRemovals(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, failed)
+
+	removals = userDeps.QueryFileRemovals(context.Background(), "")
+	assert.Len(t, removals.Res.FileRemovals, 0)
+}
@@ -0,0 +1,110 @@
+package user
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrApprovalStatusRequired = errors.New("status persetujuan anggota tidak boleh kosong")
+	ErrInvalidApprovalStatus  = errors.New("status persetujuan anggota harus berupa approved, rejected, atau info_requested")
+	ErrApprovalReasonRequired = errors.New("alasan penolakan atau permintaan informasi pendaftaran tidak boleh kosong")
+	ErrMaxApprovalReason      = errors.New("alasan penolakan atau permintaan informasi pendaftaran tidak dapat lebih dari 500 karakter")
+)
+
+func ValidateReviewMemberRegistrationIn(i ReviewMemberRegistrationIn) error {
+	g := new(errgroup.Group)
+
+	g.Go(func() error {
+		if strings.Trim(i.Status, " ") == "" {
+			return ErrApprovalStatusRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		switch i.Status {
+		case "", MemberApprovalApproved.String, MemberApprovalRejected.String, MemberApprovalInfoRequested.String:
+			return nil
+		}
+		return ErrInvalidApprovalStatus
+	})
+	g.Go(func() error {
+		isReasonRequired := i.Status == MemberApprovalRejected.String || i.Status == MemberApprovalInfoRequested.String
+		if isReasonRequired && strings.Trim(i.Reason, " ") == "" {
+			return ErrApprovalReasonRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Reason) > 500 {
+			return ErrMaxApprovalReason
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ValidateResubmitRegistrationIn(i ResubmitRegistrationIn) error {
+	if err := ValidateLoginIn(LoginIn{Identifier: i.Identifier, Password: i.Password}); err != nil {
+		return err
+	}
+
+	g := new(errgroup.Group)
+
+	g.Go(func() error {
+		if strings.Trim(i.Name, " ") == "" {
+			return ErrMemberNameRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.WaPhone, " ") == "" {
+			return ErrWaPhoneRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.OtherPhone, " ") == "" {
+			return ErrOtherPhoneRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Name) > 100 {
+			return ErrMaxName
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.WaPhone) > 50 {
+			return ErrMaxWaPhone
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.OtherPhone) > 50 {
+			return ErrMaxOtherPhone
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.IdCard.Filename) > 200 {
+			return ErrIdCardFileName
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	return nil
+}
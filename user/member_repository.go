@@ -41,11 +41,19 @@ func (r *MemberRepository) Save(ctx context.Context, m MemberModel) error {
 			password,
 			is_admin,
 			is_approved,
+			approval_status,
+			approval_reason,
+			reviewed_at,
+			status,
+			suspended_until,
+			username_visibility,
+			wa_phone_visibility,
+			other_phone_visibility,
 			created_at,
 			updated_at,
 			deleted_at
 		)
-		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
 	`
 
 	var exec MemberExecutor
@@ -56,6 +64,16 @@ func (r *MemberRepository) Save(ctx context.Context, m MemberModel) error {
 		exec = r.PostgreDb.Exec
 	}
 
+	// A member added already approved by an admin skip the review
+	approvalStatus := m.ApprovalStatus
+	status := m.Status
+	if m.IsApproved {
+		approvalStatus = MemberApprovalApproved
+		if status == MemberStatusUnknown {
+			status = MemberStatusActive
+		}
+	}
+
 	var err error
 	t := time.Now()
 
@@ -72,6 +90,14 @@ func (r *MemberRepository) Save(ctx context.Context, m MemberModel) error {
 		m.Password,
 		m.IsAdmin,
 		m.IsApproved,
+		approvalStatus,
+		m.ApprovalReason,
+		m.ReviewedAt,
+		status,
+		m.SuspendedUntil,
+		m.UsernameVisibility,
+		m.WaPhoneVisibility,
+		m.OtherPhoneVisibility,
 		t,
 		t,
 		nil,
@@ -132,6 +158,14 @@ func (r *MemberRepository) FindByUsername(username string) (m MemberModel, err e
 			password,
 			is_admin,
 			is_approved,
+			approval_status,
+			approval_reason,
+			reviewed_at,
+			status,
+			suspended_until,
+			username_visibility,
+			wa_phone_visibility,
+			other_phone_visibility,
 			created_at,
 			updated_at,
 			deleted_at
@@ -206,9 +240,17 @@ func (r *MemberRepository) Update(ctx context.Context, id string, m MemberModel)
 			password,
 			is_admin,
 			is_approved,
+			approval_status,
+			approval_reason,
+			reviewed_at,
+			status,
+			suspended_until,
+			username_visibility,
+			wa_phone_visibility,
+			other_phone_visibility,
 			updated_at
-		) = ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		WHERE id = $11
+		) = ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		WHERE id = $19
 	`
 
 	var exec MemberExecutor
@@ -234,6 +276,14 @@ func (r *MemberRepository) Update(ctx context.Context, id string, m MemberModel)
 		m.Password,
 		m.IsAdmin,
 		m.IsApproved,
+		m.ApprovalStatus,
+		m.ApprovalReason,
+		m.ReviewedAt,
+		m.Status,
+		m.SuspendedUntil,
+		m.UsernameVisibility,
+		m.WaPhoneVisibility,
+		m.OtherPhoneVisibility,
 		t,
 		id,
 	)
@@ -258,6 +308,14 @@ func (r *MemberRepository) FindById(ctx context.Context, uid string) (m MemberMo
 			password,
 			is_admin,
 			is_approved,
+			approval_status,
+			approval_reason,
+			reviewed_at,
+			status,
+			suspended_until,
+			username_visibility,
+			wa_phone_visibility,
+			other_phone_visibility,
 			created_at,
 			updated_at,
 			deleted_at
@@ -346,6 +404,14 @@ func (r *MemberRepository) QueryInId(ctx context.Context, uids []string) (ms []M
 			password,
 			is_admin,
 			is_approved,
+			approval_status,
+			approval_reason,
+			reviewed_at,
+			status,
+			suspended_until,
+			username_visibility,
+			wa_phone_visibility,
+			other_phone_visibility,
 			created_at,
 			updated_at,
 			deleted_at
@@ -420,6 +486,14 @@ func (r *MemberRepository) Query(ctx context.Context, uid pgtypeuuid.UUID, q str
 			password,
 			is_admin,
 			is_approved,
+			approval_status,
+			approval_reason,
+			reviewed_at,
+			status,
+			suspended_until,
+			username_visibility,
+			wa_phone_visibility,
+			other_phone_visibility,
 			created_at,
 			updated_at,
 			deleted_at
@@ -455,6 +529,121 @@ func (r *MemberRepository) Query(ctx context.Context, uid pgtypeuuid.UUID, q str
 	return ms, nil
 }
 
+// Filters are combined with the full-text search, the total of the matching
+// members is returned on each row so the page and the count agree
+func (r *MemberRepository) Search(ctx context.Context, f MemberFilter) ([]MemberSearchModel, error) {
+	search := "$1::text = ''"
+	rank := "0"
+	if f.Q != "" {
+		search = `(
+			m.textsearchable_index_col @@ websearch_to_tsquery('english', $1)
+			OR m.name ILIKE '%' || $1 || '%'
+			OR m.username ILIKE '%' || $1 || '%'
+		)`
+		rank = "ts_rank(m.textrank_index_col, websearch_to_tsquery('english', $1))"
+	}
+
+	order := "m.created_at DESC, m.id DESC"
+	switch f.SortBy {
+	case "oldest":
+		order = "m.created_at ASC, m.id ASC"
+	case "name":
+		order = "m.name ASC, m.id ASC"
+	case "arrears":
+		order = "a.dues_arrears DESC, m.created_at DESC, m.id DESC"
+	case "relevance":
+		order = rank + " DESC, m.created_at DESC, m.id DESC"
+	}
+
+	sqlQuery := `
+		SELECT
+			m.id,
+			m.name,
+			m.other_phone,
+			m.wa_phone,
+			m.profile_pic_url,
+			m.id_card_url,
+			m.username,
+			m.password,
+			m.is_admin,
+			m.is_approved,
+			m.approval_status,
+			m.approval_reason,
+			m.reviewed_at,
+			m.status,
+			m.suspended_until,
+			m.username_visibility,
+			m.wa_phone_visibility,
+			m.other_phone_visibility,
+			m.created_at,
+			m.updated_at,
+			m.deleted_at,
+			a.dues_arrears,
+			COUNT(*) OVER() AS total
+		FROM members m,
+			LATERAL (
+				SELECT COUNT(md.id) AS dues_arrears
+				FROM member_dues md
+					JOIN dues d ON d.id = md.dues_id
+				WHERE md.member_id = m.id
+					AND md.status = 'unpaid'
+					AND md.deleted_at IS NULL
+					AND d.deleted_at IS NULL
+					AND ($6::timestamp IS NULL OR d.date >= $6::timestamp)
+			) a
+		WHERE m.deleted_at IS NULL
+			AND ` + search + `
+			AND ($2::text = '' OR (CASE WHEN m.is_approved THEN 'approved' ELSE m.approval_status::text END) = $2::text)
+			AND ($3::boolean IS NULL OR m.is_admin = $3::boolean)
+			AND (
+				($4::bigint = 0 AND $5::bigint = 0)
+				OR EXISTS (
+					SELECT 1
+					FROM org_structures os
+					WHERE os.member_id = m.id
+						AND os.deleted_at IS NULL
+						AND ($4::bigint = 0 OR os.position_id = $4::bigint)
+						AND ($5::bigint = 0 OR os.org_period_id = $5::bigint)
+				)
+			)
+			AND a.dues_arrears >= $7::bigint
+			AND ($8::timestamp IS NULL OR m.created_at >= $8::timestamp)
+			AND ($9::timestamp IS NULL OR m.created_at < $9::timestamp)
+		ORDER BY ` + order + `
+		OFFSET $10
+		LIMIT $11
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		f.Q,
+		f.ApprovalStatus,
+		f.IsAdmin,
+		f.PositionId,
+		f.OrgPeriodId,
+		f.ArrearsSince,
+		f.MinArrears,
+		f.CreatedFrom,
+		f.CreatedUntil,
+		f.Offset,
+		f.Limit,
+	)
+	defer rows.Close()
+
+	var mps []*MemberSearchModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []MemberSearchModel{}, err
+	}
+
+	ms := make([]MemberSearchModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
 func (r *MemberRepository) CountMember(ctx context.Context) (n int64, err error) {
 	sqlQuery := `
 		SELECT COUNT(id) AS n
@@ -482,6 +671,299 @@ func (r *MemberRepository) CountMember(ctx context.Context) (n int64, err error)
 	return n, nil
 }
 
+func (r *MemberRepository) QueryRejectedBefore(ctx context.Context, t time.Time) (ms []MemberModel, err error) {
+	sqlQuery := `
+		SELECT
+			id,
+			name,
+			other_phone,
+			wa_phone,
+			profile_pic_url,
+			id_card_url,
+			username,
+			password,
+			is_admin,
+			is_approved,
+			approval_status,
+			approval_reason,
+			reviewed_at,
+			status,
+			suspended_until,
+			username_visibility,
+			wa_phone_visibility,
+			other_phone_visibility,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM members
+		WHERE deleted_at IS NULL
+			AND is_approved = false
+			AND approval_status = 'rejected'
+			AND reviewed_at < $1
+		ORDER BY reviewed_at
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		t,
+	)
+	defer rows.Close()
+
+	var mps []*MemberModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []MemberModel{}, err
+	}
+
+	ms = make([]MemberModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+// Urls of the files uploaded by a member, the id cards including the
+// staged ones, the profile picture, and the homestay images. Dues proves
+// are left out since they belong to the books.
+func (r *MemberRepository) QueryFileUrls(ctx context.Context, uid string) (urls []string, err error) {
+	sqlQuery := `
+		SELECT url
+		FROM (
+			SELECT profile_pic_url AS url
+			FROM members
+			WHERE id = $1
+			UNION
+			SELECT id_card_url
+			FROM members
+			WHERE id = $1
+			UNION
+			SELECT mh.thumbnail_url
+			FROM member_homestays mh
+			WHERE mh.member_id = $1
+			UNION
+			SELECT hi.url
+			FROM homestay_images hi
+				JOIN member_homestays mh ON mh.id = hi.member_homestay_id
+			WHERE mh.member_id = $1
+			UNION
+			SELECT UNNEST(ARRAY[old_value, new_value])
+			FROM member_profile_changes
+			WHERE member_id = $1
+				AND field = 'id_card'
+		) files
+		WHERE url <> ''
+		ORDER BY url
+	`
+
+	var query MemberQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	rows, err := query(
+		context.Background(),
+		sqlQuery,
+		uid,
+	)
+	if err != nil {
+		return []string{}, err
+	}
+	defer rows.Close()
+
+	urls = []string{}
+	for rows.Next() {
+		var url string
+		if err = rows.Scan(&url); err != nil {
+			return []string{}, err
+		}
+
+		urls = append(urls, url)
+	}
+
+	return urls, rows.Err()
+}
+
+// Anonymise the personal fields of a member and its homestays, the
+// member is deleted so it can not login anymore. The member row is kept
+// since the dues and the org structures refer to it.
+func (r *MemberRepository) EraseById(ctx context.Context, uid string) error {
+	sqlQuery := `
+		WITH homestays AS (
+			UPDATE member_homestays
+			SET
+				address = '',
+				latitude = 0,
+				longitude = 0,
+				thumbnail_url = '',
+				updated_at = $2,
+				deleted_at = COALESCE(deleted_at, $2)
+			WHERE member_id = $1
+			RETURNING id
+		), images AS (
+			UPDATE homestay_images
+			SET
+				name = '',
+				alphnum_name = '',
+				url = '',
+				caption = '',
+				deleted_at = COALESCE(deleted_at, $2)
+			WHERE member_homestay_id IN (SELECT id FROM homestays)
+		), profile_changes AS (
+			UPDATE member_profile_changes
+			SET
+				old_value = '',
+				new_value = '',
+				updated_at = $2
+			WHERE member_id = $1
+		), invitations AS (
+			UPDATE member_invitations
+			SET
+				name = '',
+				wa_phone = '',
+				other_phone = ''
+			WHERE member_id = $1
+		), recovery_codes AS (
+			DELETE FROM member_recovery_codes
+			WHERE member_id = $1
+		), totps AS (
+			DELETE FROM member_totps
+			WHERE member_id = $1
+		), sessions AS (
+			DELETE FROM member_sessions
+			WHERE member_id = $1
+		), login_throttles AS (
+			DELETE FROM login_throttles
+			WHERE kind = 'username'
+			AND identifier = (SELECT LOWER(username) FROM members WHERE id = $1)
+		)
+		UPDATE members
+		SET
+			name = $3,
+			username = $4,
+			wa_phone = $4,
+			other_phone = $4,
+			profile_pic_url = '',
+			id_card_url = '',
+			password = '',
+			approval_reason = '',
+			updated_at = $2,
+			deleted_at = COALESCE(deleted_at, $2)
+		WHERE id = $1
+	`
+
+	var exec MemberExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	// The unique fields take the member id so the erased members do not
+	// collide with each other
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		uid,
+		time.Now(),
+		ErasedMemberName,
+		"erased-"+uid,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *MemberRepository) SaveStatusHistory(ctx context.Context, m MemberStatusHistoryModel) (nm MemberStatusHistoryModel, err error) {
+	sqlQuery := `
+		INSERT INTO member_status_histories (
+			member_id,
+			from_status,
+			to_status,
+			reason,
+			suspended_until,
+			actor_id,
+			created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::uuid, $7)
+		RETURNING id
+	`
+
+	var queryRow MemberQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	var lastInsertId uint64
+	t := time.Now()
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		m.MemberId,
+		m.FromStatus,
+		m.ToStatus,
+		m.Reason,
+		m.SuspendedUntil,
+		m.ActorId,
+		t,
+	).Scan(&lastInsertId)
+
+	if err != nil {
+		return MemberStatusHistoryModel{}, err
+	}
+
+	m.Id = lastInsertId
+	m.CreatedAt = t
+
+	return m, nil
+}
+
+func (r *MemberRepository) QueryStatusHistories(ctx context.Context, uid string) (ms []MemberStatusHistoryModel, err error) {
+	sqlQuery := `
+		SELECT
+			id,
+			member_id,
+			from_status,
+			to_status,
+			reason,
+			suspended_until,
+			COALESCE(actor_id::text, '') AS actor_id,
+			created_at
+		FROM member_status_histories
+		WHERE member_id = $1
+		ORDER BY id DESC
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		uid,
+	)
+	defer rows.Close()
+
+	var mps []*MemberStatusHistoryModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []MemberStatusHistoryModel{}, err
+	}
+
+	ms = make([]MemberStatusHistoryModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
 func (r *MemberRepository) SaveMemberHomestay(ctx context.Context, m MemberHomestayModel) (nm MemberHomestayModel, err error) {
 	sqlQuery := `
 		INSERT INTO member_homestays (
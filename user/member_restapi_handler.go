@@ -8,10 +8,12 @@ import (
 
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/httpdecode"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
+	mw "github.com/PA-D3RPLA/d3if43-htt-uhomestay/middleware"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
 	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
 	"github.com/auth0/go-jwt-middleware/v2/validator"
 	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
 )
 
 func (d *UserDeps) PostRegisterMember(w http.ResponseWriter, r *http.Request) {
@@ -36,6 +38,17 @@ func (d *UserDeps) PostMember(w http.ResponseWriter, r *http.Request) {
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
 
+func (d *UserDeps) PostMembersImport(w http.ResponseWriter, r *http.Request) {
+	var in ImportMembersIn
+	if err := httpdecode.Multipart(r, &in, 1024*1024, httpdecode.MultipartToFileHookFunc); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.ImportMembers(r.Context(), in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
 func (d *UserDeps) PostLoginMember(w http.ResponseWriter, r *http.Request) {
 	decoder := json.NewDecoder(r.Body)
 
@@ -46,6 +59,8 @@ func (d *UserDeps) PostLoginMember(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	in.Ip = mw.ClientIp(r)
+	in.UserAgent = r.UserAgent()
 	out := d.MemberLogin(r.Context(), in)
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
@@ -60,6 +75,8 @@ func (d *UserDeps) PostLoginAdmin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	in.Ip = mw.ClientIp(r)
+	in.UserAgent = r.UserAgent()
 	out := d.AdminLogin(r.Context(), in)
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
@@ -86,19 +103,96 @@ func (d *UserDeps) GetMembers(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query().Get("q")
 	cursor := r.URL.Query().Get("cursor")
 	limit := r.URL.Query().Get("limit")
-	out := d.QueryMember(r.Context(), q, cursor, limit)
+
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil && !errors.Is(err, jwt.ErrClaimsNotFound) {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.QueryMember(r.Context(), jwtPayload.Uid, q, cursor, limit)
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
 
 func (d *UserDeps) GetMember(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	out := d.FindMemberDetail(r.Context(), id)
+
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil && !errors.Is(err, jwt.ErrClaimsNotFound) {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.FindMemberDetail(r.Context(), id, jwtPayload.Uid)
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
 
 func (d *UserDeps) PatchMemberApproval(w http.ResponseWriter, r *http.Request) {
+	// An empty body approve the member as before the review had a status
+	in := ReviewMemberRegistrationIn{
+		Status: MemberApprovalApproved.String,
+	}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&in); err != nil && !errors.Is(err, io.EOF) {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	out := d.ReviewMemberRegistration(r.Context(), id, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) PatchMemberStatus(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateAdminClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+
+	var in ChangeMemberStatusIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	out := d.ChangeMemberStatus(r.Context(), id, jwtPayload.Uid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) GetMemberStatusHistory(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	out := d.ApproveMember(r.Context(), id)
+	out := d.QueryMemberStatusHistory(r.Context(), id)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) PostRegistrationStatus(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+
+	var in LoginIn
+	err := decoder.Decode(&in)
+	if err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	in.Ip = mw.ClientIp(r)
+	out := d.FindRegistrationStatus(r.Context(), in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) PostRegistrationResubmission(w http.ResponseWriter, r *http.Request) {
+	var in ResubmitRegistrationIn
+	if err := httpdecode.Multipart(r, &in, 10*1024, httpdecode.MultipartToFileHookFunc); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	in.Ip = mw.ClientIp(r)
+	out := d.ResubmitRegistration(r.Context(), in)
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
 
@@ -119,6 +213,207 @@ func (d *UserDeps) PutMemberProfile(w http.ResponseWriter, r *http.Request) {
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
 
+func (d *UserDeps) GetMemberPrivacy(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.FindMemberPrivacy(r.Context(), jwtPayload.Uid)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) PutMemberPrivacy(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+
+	var in MemberPrivacyIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.UpdateMemberPrivacy(r.Context(), jwtPayload.Uid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) GetMemberErasure(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.FindMemberErasure(r.Context(), jwtPayload.Uid)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) PostMemberErasure(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	// The reason is optional, so is the body
+	var in MemberErasureIn
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&in); err != nil && !errors.Is(err, io.EOF) {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.RequestMemberErasure(r.Context(), jwtPayload.Uid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) GetMemberErasures(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	cursor := r.URL.Query().Get("cursor")
+	limit := r.URL.Query().Get("limit")
+	out := d.QueryMemberErasures(r.Context(), status, cursor, limit)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) PatchMemberErasure(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateAdminClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+
+	var in ReviewMemberErasureIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	out := d.ReviewMemberErasure(r.Context(), id, jwtPayload.Uid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) GetMemberProfileChanges(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.QueryMemberProfileChanges(r.Context(), jwtPayload.Uid)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) GetProfileChanges(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	cursor := r.URL.Query().Get("cursor")
+	limit := r.URL.Query().Get("limit")
+	out := d.QueryProfileChanges(r.Context(), status, cursor, limit)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) PatchProfileChange(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateAdminClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+
+	var in ReviewProfileChangeIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	out := d.ReviewProfileChange(r.Context(), id, jwtPayload.Uid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) GetMembersSearch(w http.ResponseWriter, r *http.Request) {
+	out := d.SearchMember(r.Context(), SearchMemberQIn{
+		Q:              r.URL.Query().Get("q"),
+		ApprovalStatus: r.URL.Query().Get("approval_status"),
+		IsAdmin:        r.URL.Query().Get("is_admin"),
+		PositionId:     r.URL.Query().Get("position_id"),
+		PeriodId:       r.URL.Query().Get("period_id"),
+		MinArrears:     r.URL.Query().Get("min_arrears"),
+		ArrearsMonths:  r.URL.Query().Get("arrears_months"),
+		CreatedFrom:    r.URL.Query().Get("created_from"),
+		CreatedUntil:   r.URL.Query().Get("created_until"),
+		Sort:           r.URL.Query().Get("sort"),
+		Cursor:         r.URL.Query().Get("cursor"),
+		Limit:          r.URL.Query().Get("limit"),
+	})
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) GetMemberCardVerification(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	out := d.VerifyMemberCard(r.Context(), token)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) PostMemberInvitation(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateAdminClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+
+	var in InviteMemberIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.InviteMember(r.Context(), jwtPayload.Uid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) GetMemberInvitations(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	cursor := r.URL.Query().Get("cursor")
+	limit := r.URL.Query().Get("limit")
+	out := d.QueryMemberInvitations(r.Context(), status, cursor, limit)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) DeleteMemberInvitation(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	out := d.RemoveMemberInvitation(r.Context(), id)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) GetRegisterInvitation(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	out := d.FindMemberInvitation(r.Context(), token)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) PostRegisterInvitation(w http.ResponseWriter, r *http.Request) {
+	var in AcceptMemberInvitationIn
+	if err := httpdecode.Multipart(r, &in, 10*1024, httpdecode.MultipartToFileHookFunc); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.AcceptMemberInvitation(r.Context(), in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
 func (d *UserDeps) GetProfileMember(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Content-Type", "application/json")
 
@@ -185,3 +480,171 @@ func (d *UserDeps) GetUserJwt(w http.ResponseWriter, r *http.Request) {
 	out := d.UserLoginWithUsername(r.Context(), username)
 	out.HttpJSON(w, resp.NewHttpBody(out.Res))
 }
+
+func (d *UserDeps) PostLoginTotp(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+
+	var in LoginTotpIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	in.Ip = mw.ClientIp(r)
+	in.UserAgent = r.UserAgent()
+	out := d.LoginTotp(r.Context(), in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) PostLoginTotpEnrollment(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+
+	var in LoginTotpEnrollmentIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.StartLoginTotpEnrollment(r.Context(), in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) GetMemberTotp(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.FindMemberTotp(r.Context(), jwtPayload.Uid)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) PostMemberTotp(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.StartMemberTotpEnrollment(r.Context(), jwtPayload.Uid)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) PostMemberTotpConfirm(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+
+	var in TotpCodeIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.ConfirmMemberTotp(r.Context(), jwtPayload.Uid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) PostMemberTotpDisable(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+
+	var in TotpCodeIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.DisableMemberTotp(r.Context(), jwtPayload.Uid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) PostMemberRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+
+	var in TotpCodeIn
+	if err := decoder.Decode(&in); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	out := d.RegenerateRecoveryCodes(r.Context(), jwtPayload.Uid, in)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) DeleteMemberTotp(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	out := d.ResetMemberTotp(r.Context(), id)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) GetLoginLocks(w http.ResponseWriter, r *http.Request) {
+	limit := r.URL.Query().Get("limit")
+	out := d.QueryLoginLocks(r.Context(), limit)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) DeleteMemberLoginLock(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateAdminClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	out := d.UnlockMember(r.Context(), id, jwtPayload.Uid)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) GetMemberSessions(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	sessionId, err := jwt.SessionId(r)
+	if err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	cursor := r.URL.Query().Get("cursor")
+	limit := r.URL.Query().Get("limit")
+	out := d.QueryMemberSessions(r.Context(), jwtPayload.Uid, sessionId, cursor, limit)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) DeleteMemberSession(w http.ResponseWriter, r *http.Request) {
+	var jwtPayload jwt.JwtPrivateClaim
+	if err := jwt.DecodeCustomClaims(r, &jwtPayload); err != nil {
+		resp.NewResponse(http.StatusInternalServerError, "", err).HttpJSON(w, nil)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	out := d.RevokeMemberSession(r.Context(), jwtPayload.Uid, id)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
+
+func (d *UserDeps) DeleteMemberSessions(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	out := d.RevokeMemberSessions(r.Context(), id)
+	out.HttpJSON(w, resp.NewHttpBody(out.Res))
+}
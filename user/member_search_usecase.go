@@ -0,0 +1,228 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidApprovalStatusFilter = errors.New("status persetujuan anggota harus berupa pending, approved, rejected, atau info_requested")
+	ErrInvalidIsAdminFilter        = errors.New("status admin harus berupa true atau false")
+	ErrInvalidPositionFilter       = errors.New("jabatan harus berupa id jabatan")
+	ErrInvalidOrgPeriodFilter      = errors.New("periode organisasi harus berupa id periode")
+	ErrInvalidMinArrears           = errors.New("jumlah tunggakan iuran harus berupa angka tidak negatif")
+	ErrInvalidArrearsMonths        = errors.New("rentang bulan tunggakan iuran harus berupa angka tidak negatif")
+	ErrInvalidCreatedFrom          = errors.New("tanggal awal pendaftaran harus berformat YYYY-MM-DD")
+	ErrInvalidCreatedUntil         = errors.New("tanggal akhir pendaftaran harus berformat YYYY-MM-DD")
+	ErrInvalidMemberSort           = errors.New("urutan anggota harus berupa newest, oldest, name, arrears, atau relevance")
+)
+
+type (
+	SearchMemberQIn struct {
+		Q              string
+		ApprovalStatus string
+		IsAdmin        string
+		PositionId     string
+		PeriodId       string
+		MinArrears     string
+		ArrearsMonths  string
+		CreatedFrom    string
+		CreatedUntil   string
+		Sort           string
+		Cursor         string
+		Limit          string
+	}
+	SearchMemberItem struct {
+		MemberOut
+		DuesArrears int64     `json:"dues_arrears"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+	SearchMemberRes struct {
+		Total   int64              `json:"total"`
+		Cursor  int64              `json:"cursor"`
+		Members []SearchMemberItem `json:"members"`
+	}
+	SearchMemberOut struct {
+		resp.Response
+		Res SearchMemberRes
+	}
+)
+
+func parseFilterId(s string, errInvalid error) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || id == 0 {
+		return 0, errInvalid
+	}
+
+	return id, nil
+}
+
+func parseFilterCount(s string, errInvalid error) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, errInvalid
+	}
+
+	return n, nil
+}
+
+func parseFilterDate(s string, errInvalid error) (sql.NullTime, error) {
+	if s == "" {
+		return sql.NullTime{}, nil
+	}
+
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return sql.NullTime{}, errInvalid
+	}
+
+	return sql.NullTime{Time: t, Valid: true}, nil
+}
+
+func parseMemberFilter(qin SearchMemberQIn) (f MemberFilter, err error) {
+	f.Q = strings.Trim(qin.Q, " ")
+
+	if qin.ApprovalStatus != "" {
+		if _, err = memberApprovalStatusFromString(qin.ApprovalStatus); err != nil {
+			return MemberFilter{}, ErrInvalidApprovalStatusFilter
+		}
+		f.ApprovalStatus = qin.ApprovalStatus
+	}
+
+	if qin.IsAdmin != "" {
+		isAdmin, err := strconv.ParseBool(qin.IsAdmin)
+		if err != nil {
+			return MemberFilter{}, ErrInvalidIsAdminFilter
+		}
+		f.IsAdmin = sql.NullBool{Bool: isAdmin, Valid: true}
+	}
+
+	if f.PositionId, err = parseFilterId(qin.PositionId, ErrInvalidPositionFilter); err != nil {
+		return MemberFilter{}, err
+	}
+	if f.OrgPeriodId, err = parseFilterId(qin.PeriodId, ErrInvalidOrgPeriodFilter); err != nil {
+		return MemberFilter{}, err
+	}
+	if f.MinArrears, err = parseFilterCount(qin.MinArrears, ErrInvalidMinArrears); err != nil {
+		return MemberFilter{}, err
+	}
+
+	months, err := parseFilterCount(qin.ArrearsMonths, ErrInvalidArrearsMonths)
+	if err != nil {
+		return MemberFilter{}, err
+	}
+	// The last n months include the current one, so the dues are counted
+	// from the first day of the month n - 1 months ago
+	if months > 0 {
+		now := time.Now()
+		since := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -int(months-1), 0)
+		f.ArrearsSince = sql.NullTime{Time: since, Valid: true}
+	}
+
+	if f.CreatedFrom, err = parseFilterDate(qin.CreatedFrom, ErrInvalidCreatedFrom); err != nil {
+		return MemberFilter{}, err
+	}
+	if f.CreatedUntil, err = parseFilterDate(qin.CreatedUntil, ErrInvalidCreatedUntil); err != nil {
+		return MemberFilter{}, err
+	}
+	// The until date is inclusive
+	if f.CreatedUntil.Valid {
+		f.CreatedUntil.Time = f.CreatedUntil.Time.AddDate(0, 0, 1)
+	}
+
+	f.SortBy = qin.Sort
+	switch f.SortBy {
+	case "":
+		f.SortBy = "newest"
+		if f.Q != "" {
+			f.SortBy = "relevance"
+		}
+	case "newest", "oldest", "name", "arrears", "relevance":
+	default:
+		return MemberFilter{}, ErrInvalidMemberSort
+	}
+
+	f.Offset, _ = strconv.ParseInt(qin.Cursor, 10, 64)
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+
+	f.Limit, _ = strconv.ParseInt(qin.Limit, 10, 64)
+	if f.Limit <= 0 || f.Limit > 100 {
+		f.Limit = 25
+	}
+
+	return f, nil
+}
+
+// Admin list of the members by the structured filters combined with the
+// full-text search, the cursor is the offset of the next page
+func (d *UserDeps) SearchMember(ctx context.Context, qin SearchMemberQIn) (out SearchMemberOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	f, err := parseMemberFilter(qin)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	members, err := d.MemberRepository.Search(ctx, f)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "search member"))
+		return
+	}
+
+	mLen := len(members)
+
+	var total, nextCursor int64
+	if mLen != 0 {
+		total = members[0].Total
+	}
+	if f.Offset+int64(mLen) < total {
+		nextCursor = f.Offset + int64(mLen)
+	}
+
+	outMembers := make([]SearchMemberItem, mLen)
+	for i, m := range members {
+		outMembers[i] = SearchMemberItem{
+			MemberOut: MemberOut{
+				Id:             m.Id.UUID.String(),
+				Username:       m.Username,
+				Name:           m.Name,
+				WaPhone:        m.WaPhone,
+				OtherPhone:     m.OtherPhone,
+				ProfilePicUrl:  m.ProfilePicUrl,
+				IsAdmin:        m.IsAdmin,
+				IsApproved:     m.IsApproved,
+				ApprovalStatus: memberApprovalStatus(m.MemberModel).String,
+				Status:         EffectiveMemberStatus(m.MemberModel).String,
+			},
+			DuesArrears: m.DuesArrears,
+			CreatedAt:   m.CreatedAt,
+		}
+	}
+
+	out.Res = SearchMemberRes{
+		Total:   total,
+		Cursor:  nextCursor,
+		Members: outMembers,
+	}
+
+	return
+}
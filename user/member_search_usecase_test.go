@@ -0,0 +1,190 @@
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
+)
+
+func createUnpaidDues(uid string, date time.Time) error {
+	var duesId uint64
+	err := db.QueryRow(
+		context.Background(),
+		`INSERT INTO dues (date, idr_amount) VALUES ($1, '10000') RETURNING id`,
+		date,
+	).Scan(&duesId)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(
+		context.Background(),
+		`INSERT INTO member_dues (member_id, dues_id, status) VALUES ($1, $2, 'unpaid')`,
+		uid,
+		duesId,
+	)
+
+	return err
+}
+
+func TestSearchMember(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = createUser(memberRepository, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, periodId, positionId, err := createFullUser(userDeps, memberNormal, period, position)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = createUser(memberRepository, pendingMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if err = createUnpaidDues(uid, thisMonth); err != nil {
+		t.Fatal(err)
+	}
+	if err = createUnpaidDues(uid, thisMonth.AddDate(0, -6, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		ExpectedTotal      int64
+		ExpectedFirstId    string
+		In                 user.SearchMemberQIn
+	}{
+		{
+			Name:               "Search Member without Filter Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      3,
+			In:                 user.SearchMemberQIn{},
+		},
+		{
+			Name:               "Search Member by Approval Status Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      1,
+			In: user.SearchMemberQIn{
+				ApprovalStatus: user.MemberApprovalPending.String,
+			},
+		},
+		{
+			Name:               "Search Member by Admin Flag Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      2,
+			In: user.SearchMemberQIn{
+				IsAdmin: "true",
+			},
+		},
+		{
+			Name:               "Search Member by Position and Period Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      1,
+			ExpectedFirstId:    uid,
+			In: user.SearchMemberQIn{
+				PositionId: strconv.FormatUint(positionId, 10),
+				PeriodId:   strconv.FormatUint(periodId, 10),
+			},
+		},
+		{
+			Name:               "Search Member by Dues Arrears Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      1,
+			ExpectedFirstId:    uid,
+			In: user.SearchMemberQIn{
+				ApprovalStatus: user.MemberApprovalApproved.String,
+				MinArrears:     "2",
+			},
+		},
+		{
+			Name:               "Search Member by Dues Arrears of the Last 3 Months Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      0,
+			In: user.SearchMemberQIn{
+				MinArrears:    "2",
+				ArrearsMonths: "3",
+			},
+		},
+		{
+			Name:               "Search Member by Full-text and Filter Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      1,
+			ExpectedFirstId:    uid,
+			In: user.SearchMemberQIn{
+				Q:       memberNormal.Username,
+				IsAdmin: "false",
+			},
+		},
+		{
+			Name:               "Search Member Sorted by Arrears Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      3,
+			ExpectedFirstId:    uid,
+			In: user.SearchMemberQIn{
+				Sort: "arrears",
+			},
+		},
+		{
+			Name:               "Search Member by Creation Date Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedTotal:      0,
+			In: user.SearchMemberQIn{
+				CreatedFrom: now.AddDate(0, 0, 2).Format("2006-01-02"),
+			},
+		},
+		{
+			Name:               "Search Member Fail, Invalid Admin Flag",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: user.SearchMemberQIn{
+				IsAdmin: "maybe",
+			},
+		},
+		{
+			Name:               "Search Member Fail, Invalid Creation Date",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: user.SearchMemberQIn{
+				CreatedUntil: "19-10-2022",
+			},
+		},
+		{
+			Name:               "Search Member Fail, Invalid Sort",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: user.SearchMemberQIn{
+				Sort: "random",
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.SearchMember(context.Background(), c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if res.Res.Total != c.ExpectedTotal {
+				t.Fatalf("Expected total %d. Got %d\n", c.ExpectedTotal, res.Res.Total)
+			}
+
+			if c.ExpectedFirstId != "" && res.Res.Members[0].Id != c.ExpectedFirstId {
+				t.Fatalf("Expected first member %s. Got %s\n", c.ExpectedFirstId, res.Res.Members[0].Id)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package user
+
+import (
+	"database/sql"
+	"time"
+)
+
+// A login, the token carry the token id so it can be revoked
+type MemberSessionModel struct {
+	Id         uint64
+	TokenId    string
+	MemberId   string
+	IsAdmin    bool
+	Device     string
+	Ip         string
+	UserAgent  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  sql.NullTime
+}
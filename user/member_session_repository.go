@@ -0,0 +1,299 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type MemberSessionRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewMemberSessionRepository(postgreDb *pgxpool.Pool) *MemberSessionRepository {
+	return &MemberSessionRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+type (
+	MemberSessionExecutor   func(ctx context.Context, sql string, arguments ...interface{}) (commandTag pgconn.CommandTag, err error)
+	MemberSessionQuerierRow func(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	MemberSessionQuerier    func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+)
+
+func (r *MemberSessionRepository) Save(ctx context.Context, m MemberSessionModel) (nm MemberSessionModel, err error) {
+	sqlQuery := `
+		INSERT INTO member_sessions (
+			token_id,
+			member_id,
+			is_admin,
+			device,
+			ip,
+			user_agent,
+			created_at,
+			last_seen_at,
+			expires_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $8)
+		RETURNING id
+	`
+
+	var queryRow MemberSessionQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	var lastInsertId uint64
+	t := time.Now()
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		m.TokenId,
+		m.MemberId,
+		m.IsAdmin,
+		m.Device,
+		m.Ip,
+		m.UserAgent,
+		t,
+		m.ExpiresAt,
+	).Scan(&lastInsertId)
+
+	if err != nil {
+		return MemberSessionModel{}, err
+	}
+
+	m.Id = lastInsertId
+	m.CreatedAt = t
+	m.LastSeenAt = t
+
+	return m, nil
+}
+
+// Report whether the session of the token is not revoked, not expired and
+// was seen after idleBefore. The last seen time is only written when it is
+// before staleBefore, so a busy session does not write on every request.
+func (r *MemberSessionRepository) Touch(ctx context.Context, tokenId string, t, staleBefore, idleBefore time.Time) (isAlive bool, err error) {
+	sqlQuery := `
+		WITH touched AS (
+			UPDATE member_sessions
+			SET last_seen_at = $2
+			WHERE token_id = $1
+			AND revoked_at IS NULL
+			AND expires_at > $2
+			AND last_seen_at > $4
+			AND last_seen_at < $3
+			RETURNING id
+		)
+		SELECT EXISTS (SELECT 1 FROM touched)
+			OR EXISTS (
+				SELECT 1
+				FROM member_sessions
+				WHERE token_id = $1
+				AND revoked_at IS NULL
+				AND expires_at > $2
+				AND last_seen_at > $4
+			)
+	`
+
+	var queryRow MemberSessionQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		tokenId,
+		t,
+		staleBefore,
+		idleBefore,
+	).Scan(&isAlive)
+
+	if err != nil {
+		return false, err
+	}
+
+	return isAlive, nil
+}
+
+func (r *MemberSessionRepository) QueryByMemberId(ctx context.Context, uid string, id, limit int64) ([]MemberSessionModel, error) {
+	fromId := "id > $2"
+	if id != 0 {
+		fromId = "id < $2"
+	}
+
+	sqlQuery := `
+		SELECT
+			id,
+			token_id::text AS token_id,
+			member_id::text AS member_id,
+			is_admin,
+			device,
+			ip,
+			user_agent,
+			created_at,
+			last_seen_at,
+			expires_at,
+			revoked_at
+		FROM member_sessions
+		WHERE member_id = $1
+		AND ` + fromId + `
+		ORDER BY id DESC
+		LIMIT $3
+	`
+
+	rows, _ := r.PostgreDb.Query(
+		context.Background(),
+		sqlQuery,
+		uid,
+		id,
+		limit,
+	)
+	defer rows.Close()
+
+	var mps []*MemberSessionModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []MemberSessionModel{}, err
+	}
+
+	ms := make([]MemberSessionModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+func (r *MemberSessionRepository) CountByMemberId(ctx context.Context, uid string) (n int64, err error) {
+	sqlQuery := `
+		SELECT COUNT(id) AS n
+		FROM member_sessions
+		WHERE member_id = $1
+	`
+
+	var queryRow MemberSessionQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		uid,
+	).Scan(&n)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Revoke a session of the member, nothing is affected when the session
+// belongs to another member or is already revoked
+func (r *MemberSessionRepository) RevokeById(ctx context.Context, uid string, id uint64) (n int64, err error) {
+	sqlQuery := `
+		UPDATE member_sessions
+		SET revoked_at = $3
+		WHERE id = $1
+		AND member_id = $2
+		AND revoked_at IS NULL
+	`
+
+	var exec MemberSessionExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	cmd, err := exec(
+		context.Background(),
+		sqlQuery,
+		id,
+		uid,
+		time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return cmd.RowsAffected(), nil
+}
+
+func (r *MemberSessionRepository) RevokeByMemberId(ctx context.Context, uid string) (n int64, err error) {
+	sqlQuery := `
+		UPDATE member_sessions
+		SET revoked_at = $2
+		WHERE member_id = $1
+		AND revoked_at IS NULL
+	`
+
+	var exec MemberSessionExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	cmd, err := exec(
+		context.Background(),
+		sqlQuery,
+		uid,
+		time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return cmd.RowsAffected(), nil
+}
+
+// Revoke the admin sessions of the member, the member sessions are kept
+func (r *MemberSessionRepository) RevokeAdminByMemberId(ctx context.Context, uid string) (n int64, err error) {
+	sqlQuery := `
+		UPDATE member_sessions
+		SET revoked_at = $2
+		WHERE member_id = $1
+		AND is_admin = true
+		AND revoked_at IS NULL
+	`
+
+	var exec MemberSessionExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	cmd, err := exec(
+		context.Background(),
+		sqlQuery,
+		uid,
+		time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return cmd.RowsAffected(), nil
+}
@@ -0,0 +1,299 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	// How often the last seen time of a session is written
+	SessionSeenInterval = time.Minute
+	// A session ends after the lifetime, or earlier when it is not used for
+	// the idle timeout. The lifetime is the expiry of the token as well.
+	SessionLifetime    = 30 * 24 * time.Hour
+	SessionIdleTimeout = 7 * 24 * time.Hour
+	// Most sessions listed in one page of the login history
+	MemberSessionsMaxLimit = 100
+)
+
+var ErrMemberSessionNotFound = errors.New("sesi login tidak ditemukan")
+
+// A short name of the browser and the system from the user agent, enough
+// for a member to recognise the device
+func sessionDevice(userAgent string) string {
+	var browser, system string
+
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	}
+
+	switch {
+	case strings.Contains(userAgent, "Android"):
+		system = "Android"
+	case strings.Contains(userAgent, "iPhone"):
+		system = "iPhone"
+	case strings.Contains(userAgent, "iPad"):
+		system = "iPad"
+	case strings.Contains(userAgent, "Windows"):
+		system = "Windows"
+	case strings.Contains(userAgent, "Mac OS X"):
+		system = "macOS"
+	case strings.Contains(userAgent, "Linux"):
+		system = "Linux"
+	}
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " (" + system + ")"
+	case browser != "":
+		return browser
+	}
+
+	return system
+}
+
+// Every token issued belong to a new session, the token id is the one the
+// jwt middleware check
+func (d *UserDeps) signLoginJwt(ctx context.Context, uid string, isAdmin bool, ip, userAgent string) (string, error) {
+	tokenId, err := uuid.NewV4()
+	if err != nil {
+		return "", errors.Wrap(err, "generate session token id")
+	}
+
+	t := time.Now()
+	expiresAt := t.Add(SessionLifetime)
+
+	if _, err = d.MemberSessionRepository.Save(ctx, MemberSessionModel{
+		TokenId:   tokenId.String(),
+		MemberId:  uid,
+		IsAdmin:   isAdmin,
+		Device:    sessionDevice(userAgent),
+		Ip:        ip,
+		UserAgent: userAgent,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return "", errors.Wrap(err, "save member session")
+	}
+
+	var privateClaim interface{} = jwt.JwtPrivateClaim{
+		Uid: uid,
+	}
+	if isAdmin {
+		privateClaim = jwt.JwtPrivateAdminClaim{
+			Uid:     uid,
+			IsAdmin: true,
+		}
+	}
+
+	return jwt.Sign(
+		tokenId.String(),
+		"token",
+		d.JwtIssuerUrl,
+		d.JwtKey,
+		d.JwtAudiences,
+		time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC),
+		expiresAt,
+		t,
+		privateClaim,
+	)
+}
+
+// The session checker of the jwt middlewares
+func (d *UserDeps) IsSessionAlive(ctx context.Context, tokenId string) (bool, error) {
+	if _, err := uuid.FromString(tokenId); err != nil {
+		return false, nil
+	}
+
+	t := time.Now()
+	isAlive, err := d.MemberSessionRepository.Touch(ctx, tokenId, t, t.Add(-SessionSeenInterval), t.Add(-SessionIdleTimeout))
+	if err != nil {
+		return false, errors.Wrap(err, "touch member session")
+	}
+
+	return isAlive, nil
+}
+
+type (
+	MemberSessionRes struct {
+		Id         uint64    `json:"id"`
+		Device     string    `json:"device"`
+		Ip         string    `json:"ip"`
+		UserAgent  string    `json:"user_agent"`
+		IsAdmin    bool      `json:"is_admin"`
+		IsActive   bool      `json:"is_active"`
+		IsCurrent  bool      `json:"is_current"`
+		CreatedAt  time.Time `json:"created_at"`
+		LastSeenAt time.Time `json:"last_seen_at"`
+		ExpiresAt  time.Time `json:"expires_at"`
+		RevokedAt  null.Time `json:"revoked_at"`
+	}
+	QueryMemberSessionsRes struct {
+		Cursor   int64              `json:"cursor"`
+		Total    int64              `json:"total"`
+		Sessions []MemberSessionRes `json:"sessions"`
+	}
+	QueryMemberSessionsOut struct {
+		resp.Response
+		Res QueryMemberSessionsRes
+	}
+)
+
+// The login history of the member, the revoked sessions included
+func (d *UserDeps) QueryMemberSessions(ctx context.Context, uid, currentTokenId, cursor, limit string) (out QueryMemberSessionsOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	fromCursor, _ := strconv.ParseInt(cursor, 10, 64)
+	nlimit, _ := strconv.ParseInt(limit, 10, 64)
+	if nlimit <= 0 {
+		nlimit = 25
+	}
+	if nlimit > MemberSessionsMaxLimit {
+		nlimit = MemberSessionsMaxLimit
+	}
+
+	total, err := d.MemberSessionRepository.CountByMemberId(ctx, uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "count member sessions by member id"))
+		return
+	}
+
+	sessions, err := d.MemberSessionRepository.QueryByMemberId(ctx, uid, fromCursor, nlimit)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query member sessions by member id"))
+		return
+	}
+
+	var nextCursor int64
+	if len(sessions) != 0 {
+		nextCursor = int64(sessions[len(sessions)-1].Id)
+	}
+
+	t := time.Now()
+	res := make([]MemberSessionRes, len(sessions))
+	for i, s := range sessions {
+		res[i] = MemberSessionRes{
+			Id:         s.Id,
+			Device:     s.Device,
+			Ip:         s.Ip,
+			UserAgent:  s.UserAgent,
+			IsAdmin:    s.IsAdmin,
+			IsActive:   !s.RevokedAt.Valid && s.ExpiresAt.After(t) && s.LastSeenAt.After(t.Add(-SessionIdleTimeout)),
+			IsCurrent:  s.TokenId == currentTokenId,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+			ExpiresAt:  s.ExpiresAt,
+			RevokedAt:  null.NewTime(s.RevokedAt.Time, s.RevokedAt.Valid),
+		}
+	}
+
+	out.Res = QueryMemberSessionsRes{
+		Cursor:   nextCursor,
+		Total:    total,
+		Sessions: res,
+	}
+
+	return
+}
+
+type (
+	MemberSessionIdRes struct {
+		Id uint64 `json:"id"`
+	}
+	MemberSessionIdOut struct {
+		resp.Response
+		Res MemberSessionIdRes
+	}
+)
+
+// Log out a session of the member, the current one included
+func (d *UserDeps) RevokeMemberSession(ctx context.Context, uid, rid string) (out MemberSessionIdOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	id, err := strconv.ParseUint(rid, 10, 64)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberSessionNotFound)
+		return
+	}
+
+	n, err := d.MemberSessionRepository.RevokeById(ctx, uid, id)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "revoke member session by id"))
+		return
+	}
+
+	if n == 0 {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberSessionNotFound)
+		return
+	}
+
+	out.Res.Id = id
+
+	return
+}
+
+type (
+	RevokeMemberSessionsRes struct {
+		Id      string `json:"id"`
+		Revoked int64  `json:"revoked"`
+	}
+	RevokeMemberSessionsOut struct {
+		resp.Response
+		Res RevokeMemberSessionsRes
+	}
+)
+
+// Force logout every session of a member, for an account taken over
+func (d *UserDeps) RevokeMemberSessions(ctx context.Context, uid string) (out RevokeMemberSessionsOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if _, err = uuid.FromString(uid); err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	_, err = d.MemberRepository.FindById(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by id"))
+		return
+	}
+
+	n, err := d.MemberSessionRepository.RevokeByMemberId(ctx, uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "revoke member sessions by member id"))
+		return
+	}
+
+	out.Res = RevokeMemberSessionsRes{
+		Id:      uid,
+		Revoked: n,
+	}
+
+	return
+}
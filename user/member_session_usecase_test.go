@@ -0,0 +1,265 @@
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func loginMemberSession(m user.MemberModel) (tokenId string, id uint64, err error) {
+	res := userDeps.MemberLogin(context.Background(), user.LoginIn{
+		Identifier: m.Username,
+		Password:   m.Password,
+		Ip:         "10.0.0.1",
+		UserAgent:  "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/106.0.0.0 Safari/537.36",
+	})
+	if res.Error != nil {
+		return "", 0, res.Error
+	}
+
+	err = db.QueryRow(
+		context.Background(),
+		`SELECT token_id::text, id FROM member_sessions ORDER BY id DESC LIMIT 1`,
+	).Scan(&tokenId, &id)
+
+	return tokenId, id, err
+}
+
+func TestQueryMemberSessions(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = loginMemberSession(memberNormal); err != nil {
+		t.Fatal(err)
+	}
+
+	tokenId, _, err := loginMemberSession(memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := userDeps.QueryMemberSessions(context.Background(), uid, tokenId, "", "")
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusOK, res.StatusCode)
+	}
+
+	assert.Equal(t, int64(2), res.Res.Total)
+	if assert.Len(t, res.Res.Sessions, 2) {
+		assert.True(t, res.Res.Sessions[0].IsCurrent)
+		assert.False(t, res.Res.Sessions[1].IsCurrent)
+		assert.Equal(t, "Chrome (Windows)", res.Res.Sessions[0].Device)
+		assert.Equal(t, "10.0.0.1", res.Res.Sessions[0].Ip)
+	}
+
+	next := userDeps.QueryMemberSessions(context.Background(), uid, tokenId, strconv.FormatInt(res.Res.Cursor, 10), "")
+	assert.Len(t, next.Res.Sessions, 0)
+
+	for _, limit := range []string{"-1", "1000000"} {
+		res := userDeps.QueryMemberSessions(context.Background(), uid, tokenId, "", limit)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Len(t, res.Res.Sessions, 2)
+	}
+}
+
+func TestIsSessionAlive(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name          string
+		ExpectedAlive bool
+		Update        string
+	}{
+		{
+			Name:          "Session Alive",
+			ExpectedAlive: true,
+		},
+		{
+			Name:          "Session Not Alive, Idle",
+			ExpectedAlive: false,
+			Update:        `UPDATE member_sessions SET last_seen_at = NOW() - INTERVAL '8 days' WHERE token_id = $1`,
+		},
+		{
+			Name:          "Session Not Alive, Expired",
+			ExpectedAlive: false,
+			Update:        `UPDATE member_sessions SET expires_at = NOW() - INTERVAL '1 second' WHERE token_id = $1`,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			tokenId, _, err := loginMemberSession(memberNormal)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if c.Update != "" {
+				if _, err = db.Exec(context.Background(), c.Update, tokenId); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			isAlive, err := userDeps.IsSessionAlive(context.Background(), tokenId)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, c.ExpectedAlive, isAlive)
+		})
+	}
+}
+
+func TestRevokeMemberSession(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherUid, err := createUser(memberRepository, member2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenId, id, err := loginMemberSession(memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rid := strconv.FormatUint(id, 10)
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Uid                string
+		Id                 string
+	}{
+		{
+			Name:               "Revoke Member Session Fail, Session of Other Member",
+			ExpectedStatusCode: http.StatusNotFound,
+			Uid:                otherUid,
+			Id:                 rid,
+		},
+		{
+			Name:               "Revoke Member Session Success",
+			ExpectedStatusCode: http.StatusOK,
+			Uid:                uid,
+			Id:                 rid,
+		},
+		{
+			Name:               "Revoke Member Session Fail, Already Revoked",
+			ExpectedStatusCode: http.StatusNotFound,
+			Uid:                uid,
+			Id:                 rid,
+		},
+		{
+			Name:               "Revoke Member Session Fail, Session Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Uid:                uid,
+			Id:                 "x",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.RevokeMemberSession(context.Background(), c.Uid, c.Id)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+
+	isAlive, err := userDeps.IsSessionAlive(context.Background(), tokenId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.False(t, isAlive)
+}
+
+func TestRevokeMemberSessions(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenIds := make([]string, 2)
+	for i := range tokenIds {
+		if tokenIds[i], _, err = loginMemberSession(memberNormal); err != nil {
+			t.Fatal(err)
+		}
+
+		isAlive, err := userDeps.IsSessionAlive(context.Background(), tokenIds[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, isAlive)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Uid                string
+	}{
+		{
+			Name:               "Revoke Member Sessions Success",
+			ExpectedStatusCode: http.StatusOK,
+			Uid:                uid,
+		},
+		{
+			Name:               "Revoke Member Sessions Fail, Member Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Uid:                "1ed4a9d6-0000-6000-8000-000000000000",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.RevokeMemberSessions(context.Background(), c.Uid)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+
+	for _, tokenId := range tokenIds {
+		isAlive, err := userDeps.IsSessionAlive(context.Background(), tokenId)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.False(t, isAlive)
+	}
+}
@@ -0,0 +1,222 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	ErrSuspendedMember         = errors.New("akun anggota sedang ditangguhkan")
+	ErrResignedMember          = errors.New("anggota sudah mengundurkan diri")
+	ErrDeceasedMember          = errors.New("anggota sudah meninggal dunia")
+	ErrMemberStatusTransition  = errors.New("status anggota tidak dapat diubah ke status tersebut")
+	ErrSuspendedUntilLowerThan = errors.New("tanggal akhir penangguhan anggota harus setelah hari ini")
+)
+
+// Status a member can be moved to by an admin, a pending member is
+// activated through the registration review
+var memberStatusTransitions = map[MemberStatus][]MemberStatus{
+	MemberStatusActive:    {MemberStatusSuspended, MemberStatusResigned, MemberStatusDeceased},
+	MemberStatusSuspended: {MemberStatusActive, MemberStatusSuspended, MemberStatusResigned, MemberStatusDeceased},
+	MemberStatusResigned:  {MemberStatusActive, MemberStatusDeceased},
+}
+
+// A suspension is over once the end date passed, the member is active
+// again without anyone lifting it
+func EffectiveMemberStatus(m MemberModel) MemberStatus {
+	if m.Status == MemberStatusSuspended && m.SuspendedUntil.Valid && !m.SuspendedUntil.Time.After(time.Now()) {
+		return MemberStatusActive
+	}
+
+	return m.Status
+}
+
+func inactiveResponse(m MemberModel) resp.Response {
+	switch EffectiveMemberStatus(m) {
+	case MemberStatusSuspended:
+		message := ErrSuspendedMember.Error() + " sampai " + m.SuspendedUntil.Time.Format("2006-01-02")
+		return resp.NewResponse(http.StatusBadRequest, message, ErrSuspendedMember)
+	case MemberStatusResigned:
+		return resp.NewResponse(http.StatusBadRequest, "", ErrResignedMember)
+	case MemberStatusDeceased:
+		return resp.NewResponse(http.StatusBadRequest, "", ErrDeceasedMember)
+	}
+
+	return resp.NewResponse(http.StatusOK, "", nil)
+}
+
+type (
+	ChangeMemberStatusIn struct {
+		Status         string `json:"status"`
+		Reason         string `json:"reason"`
+		SuspendedUntil string `json:"suspended_until"`
+	}
+	ChangeMemberStatusRes struct {
+		Id string `json:"id"`
+	}
+	ChangeMemberStatusOut struct {
+		resp.Response
+		Res ChangeMemberStatusRes
+	}
+)
+
+// Every change is recorded with the reason and the admin doing it
+func (d *UserDeps) ChangeMemberStatus(ctx context.Context, uid, actorUid string, in ChangeMemberStatusIn) (out ChangeMemberStatusOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateChangeMemberStatusIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	_, err = uuid.FromString(uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	member, err := d.MemberRepository.FindById(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by id"))
+		return
+	}
+
+	fromStatus := EffectiveMemberStatus(member)
+	toStatus, _ := memberStatusFromString(in.Status)
+
+	var isAllowed bool
+	for _, s := range memberStatusTransitions[fromStatus] {
+		if isAllowed = s == toStatus; isAllowed {
+			break
+		}
+	}
+	if !isAllowed {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrMemberStatusTransition)
+		return
+	}
+
+	member.Status = toStatus
+	member.SuspendedUntil = sql.NullTime{}
+	if toStatus == MemberStatusSuspended {
+		suspendedUntil, err := time.Parse("2006-01-02", in.SuspendedUntil)
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidSuspendedUntil)
+			return
+		}
+
+		if !suspendedUntil.After(time.Now()) {
+			out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrSuspendedUntilLowerThan)
+			return
+		}
+
+		member.SuspendedUntil.Scan(suspendedUntil)
+	}
+
+	if err = d.MemberRepository.Update(ctx, uid, member); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update member"))
+		return
+	}
+
+	_, err = d.MemberRepository.SaveStatusHistory(ctx, MemberStatusHistoryModel{
+		MemberId:       uid,
+		FromStatus:     fromStatus,
+		ToStatus:       toStatus,
+		Reason:         in.Reason,
+		SuspendedUntil: member.SuspendedUntil,
+		ActorId:        actorUid,
+	})
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save member status history"))
+		return
+	}
+
+	// A member who can no longer log in is logged out of every session
+	if toStatus != MemberStatusActive {
+		if _, err = d.MemberSessionRepository.RevokeByMemberId(ctx, uid); err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "revoke member sessions by member id"))
+			return
+		}
+	}
+
+	out.Res.Id = uid
+
+	return
+}
+
+type (
+	MemberStatusHistoryOut struct {
+		Id             uint64    `json:"id"`
+		FromStatus     string    `json:"from_status"`
+		ToStatus       string    `json:"to_status"`
+		Reason         string    `json:"reason"`
+		SuspendedUntil null.Time `json:"suspended_until"`
+		ActorId        string    `json:"actor_id"`
+		CreatedAt      time.Time `json:"created_at"`
+	}
+	QueryMemberStatusHistoryRes struct {
+		Histories []MemberStatusHistoryOut `json:"histories"`
+	}
+	QueryMemberStatusHistoryOut struct {
+		resp.Response
+		Res QueryMemberStatusHistoryRes
+	}
+)
+
+func (d *UserDeps) QueryMemberStatusHistory(ctx context.Context, uid string) (out QueryMemberStatusHistoryOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	_, err = uuid.FromString(uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	_, err = d.MemberRepository.FindById(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by id"))
+		return
+	}
+
+	histories, err := d.MemberRepository.QueryStatusHistories(ctx, uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "query member status histories"))
+		return
+	}
+
+	outHistories := make([]MemberStatusHistoryOut, len(histories))
+	for i, h := range histories {
+		outHistories[i] = MemberStatusHistoryOut{
+			Id:             h.Id,
+			FromStatus:     h.FromStatus.String,
+			ToStatus:       h.ToStatus.String,
+			Reason:         h.Reason,
+			SuspendedUntil: null.NewTime(h.SuspendedUntil.Time, h.SuspendedUntil.Valid),
+			ActorId:        h.ActorId,
+			CreatedAt:      h.CreatedAt,
+		}
+	}
+
+	out.Res.Histories = outHistories
+
+	return
+}
@@ -0,0 +1,205 @@
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeMemberStatus(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actorUid, err := createUser(memberRepository, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pendingUid, err := createUser(memberRepository, pendingMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	suspendedUntil := time.Now().AddDate(0, 1, 0).Format("2006-01-02")
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		ExpectedStatus     string
+		Id                 string
+		In                 user.ChangeMemberStatusIn
+	}{
+		{
+			Name:               "Change Member Status Fail, Reason Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Id:                 uid,
+			In: user.ChangeMemberStatusIn{
+				Status:         user.MemberStatusSuspended.String,
+				SuspendedUntil: suspendedUntil,
+			},
+		},
+		{
+			Name:               "Change Member Status Fail, Suspended Until Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Id:                 uid,
+			In: user.ChangeMemberStatusIn{
+				Status: user.MemberStatusSuspended.String,
+				Reason: "Iuran belum dibayar",
+			},
+		},
+		{
+			Name:               "Change Member Status Fail, Suspended Until Passed",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			Id:                 uid,
+			In: user.ChangeMemberStatusIn{
+				Status:         user.MemberStatusSuspended.String,
+				Reason:         "Iuran belum dibayar",
+				SuspendedUntil: "2020-01-01",
+			},
+		},
+		{
+			Name:               "Change Member Status Fail, Pending Member",
+			ExpectedStatusCode: http.StatusBadRequest,
+			Id:                 pendingUid,
+			In: user.ChangeMemberStatusIn{
+				Status: user.MemberStatusResigned.String,
+				Reason: "Pindah domisili",
+			},
+		},
+		{
+			Name:               "Change Member Status Suspend Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedStatus:     user.MemberStatusSuspended.String,
+			Id:                 uid,
+			In: user.ChangeMemberStatusIn{
+				Status:         user.MemberStatusSuspended.String,
+				Reason:         "Iuran belum dibayar",
+				SuspendedUntil: suspendedUntil,
+			},
+		},
+		{
+			Name:               "Change Member Status Activate Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedStatus:     user.MemberStatusActive.String,
+			Id:                 uid,
+			In: user.ChangeMemberStatusIn{
+				Status: user.MemberStatusActive.String,
+			},
+		},
+		{
+			Name:               "Change Member Status Deceased Success",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedStatus:     user.MemberStatusDeceased.String,
+			Id:                 uid,
+			In: user.ChangeMemberStatusIn{
+				Status: user.MemberStatusDeceased.String,
+				Reason: "Meninggal dunia",
+			},
+		},
+		{
+			Name:               "Change Member Status Fail, Deceased Member",
+			ExpectedStatusCode: http.StatusBadRequest,
+			Id:                 uid,
+			In: user.ChangeMemberStatusIn{
+				Status: user.MemberStatusActive.String,
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			tx, err := db.Begin(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.WithValue(context.Background(), arbitary.TrxX{}, tx)
+			res := userDeps.ChangeMemberStatus(ctx, c.Id, actorUid, c.In)
+			tx.Commit(context.Background())
+			tx.Rollback(context.Background())
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if c.ExpectedStatus == "" {
+				return
+			}
+
+			m, err := memberRepository.FindById(context.Background(), c.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, c.ExpectedStatus, m.Status.String)
+		})
+	}
+
+	histories := userDeps.QueryMemberStatusHistory(context.Background(), uid)
+	if histories.Error != nil {
+		t.Fatal(histories.Error)
+	}
+
+	assert.Len(t, histories.Res.Histories, 3)
+	assert.Equal(t, user.MemberStatusDeceased.String, histories.Res.Histories[0].ToStatus)
+	assert.Equal(t, actorUid, histories.Res.Histories[0].ActorId)
+	assert.Equal(t, user.MemberStatusActive.String, histories.Res.Histories[2].FromStatus)
+	assert.True(t, histories.Res.Histories[2].SuspendedUntil.Valid)
+}
+
+func TestMemberLoginSuspended(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actorUid, err := createUser(memberRepository, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenId, _, err := loginMemberSession(memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := userDeps.ChangeMemberStatus(context.Background(), uid, actorUid, user.ChangeMemberStatusIn{
+		Status:         user.MemberStatusSuspended.String,
+		Reason:         "Iuran belum dibayar",
+		SuspendedUntil: time.Now().AddDate(0, 1, 0).Format("2006-01-02"),
+	})
+	if res.Error != nil {
+		t.Fatal(res.Error)
+	}
+
+	login := userDeps.MemberLogin(context.Background(), user.LoginIn{
+		Identifier: memberNormal.Username,
+		Password:   memberNormal.Password,
+	})
+	assert.Equal(t, http.StatusBadRequest, login.StatusCode)
+	assert.ErrorIs(t, login.Error, user.ErrSuspendedMember)
+
+	isAlive, err := userDeps.IsSessionAlive(context.Background(), tokenId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, isAlive)
+}
@@ -0,0 +1,70 @@
+package user
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrMemberStatusRequired       = errors.New("status anggota tidak boleh kosong")
+	ErrInvalidMemberStatus        = errors.New("status anggota harus berupa active, suspended, resigned, atau deceased")
+	ErrMemberStatusReasonRequired = errors.New("alasan perubahan status anggota tidak boleh kosong")
+	ErrMaxMemberStatusReason      = errors.New("alasan perubahan status anggota tidak dapat lebih dari 500 karakter")
+	ErrSuspendedUntilRequired     = errors.New("tanggal akhir penangguhan anggota tidak boleh kosong")
+	ErrInvalidSuspendedUntil      = errors.New("tanggal akhir penangguhan anggota harus berformat YYYY-MM-DD")
+)
+
+func ValidateChangeMemberStatusIn(i ChangeMemberStatusIn) error {
+	g := new(errgroup.Group)
+
+	g.Go(func() error {
+		if strings.Trim(i.Status, " ") == "" {
+			return ErrMemberStatusRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		switch i.Status {
+		case "", MemberStatusActive.String, MemberStatusSuspended.String, MemberStatusResigned.String, MemberStatusDeceased.String:
+			return nil
+		}
+		return ErrInvalidMemberStatus
+	})
+	g.Go(func() error {
+		if i.Status != "" && i.Status != MemberStatusActive.String && strings.Trim(i.Reason, " ") == "" {
+			return ErrMemberStatusReasonRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if utf8.RuneCountInString(i.Reason) > 500 {
+			return ErrMaxMemberStatusReason
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if i.Status == MemberStatusSuspended.String && strings.Trim(i.SuspendedUntil, " ") == "" {
+			return ErrSuspendedUntilRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if i.SuspendedUntil == "" {
+			return nil
+		}
+		if _, err := time.Parse("2006-01-02", i.SuspendedUntil); err != nil {
+			return ErrInvalidSuspendedUntil
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	return nil
+}
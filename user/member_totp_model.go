@@ -0,0 +1,16 @@
+package user
+
+import (
+	"database/sql"
+	"time"
+)
+
+type MemberTotpModel struct {
+	MemberId     string
+	Secret       string
+	IsEnabled    bool
+	LastUsedStep int64
+	EnabledAt    sql.NullTime
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
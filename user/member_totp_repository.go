@@ -0,0 +1,319 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type MemberTotpRepository struct {
+	PostgreDb *pgxpool.Pool
+}
+
+func NewMemberTotpRepository(postgreDb *pgxpool.Pool) *MemberTotpRepository {
+	return &MemberTotpRepository{
+		PostgreDb: postgreDb,
+	}
+}
+
+type (
+	MemberTotpExecutor   func(ctx context.Context, sql string, arguments ...interface{}) (commandTag pgconn.CommandTag, err error)
+	MemberTotpQuerierRow func(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	MemberTotpQuerier    func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+)
+
+// Save the secret of a new enrolment, an enrolment not confirmed yet is
+// replaced by the new one
+func (r *MemberTotpRepository) Save(ctx context.Context, m MemberTotpModel) (nm MemberTotpModel, err error) {
+	sqlQuery := `
+		INSERT INTO member_totps (
+			member_id,
+			secret,
+			is_enabled,
+			last_used_step,
+			enabled_at,
+			created_at,
+			updated_at
+		)
+		VALUES ($1, $2, false, 0, NULL, $3, $3)
+		ON CONFLICT (member_id) DO UPDATE
+		SET
+			secret = EXCLUDED.secret,
+			is_enabled = false,
+			last_used_step = 0,
+			enabled_at = NULL,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	var exec MemberTotpExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	t := time.Now()
+
+	_, err = exec(
+		context.Background(),
+		sqlQuery,
+		m.MemberId,
+		m.Secret,
+		t,
+	)
+	if err != nil {
+		return MemberTotpModel{}, err
+	}
+
+	m.IsEnabled = false
+	m.LastUsedStep = 0
+	m.CreatedAt = t
+	m.UpdatedAt = t
+
+	return m, nil
+}
+
+func (r *MemberTotpRepository) FindByMemberId(ctx context.Context, uid string) (m MemberTotpModel, err error) {
+	sqlQuery := `
+		SELECT
+			member_id::text AS member_id,
+			secret,
+			is_enabled,
+			last_used_step,
+			enabled_at,
+			created_at,
+			updated_at
+		FROM member_totps
+		WHERE member_id = $1
+	`
+
+	var query MemberTotpQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	var rows pgx.Rows
+
+	rows, err = query(
+		context.Background(),
+		sqlQuery,
+		uid,
+	)
+
+	if err != nil {
+		return MemberTotpModel{}, err
+	}
+
+	if err = pgxscan.ScanOne(&m, rows); err != nil {
+		return MemberTotpModel{}, err
+	}
+
+	return m, nil
+}
+
+// Confirm the enrolment with the step of the first valid code
+func (r *MemberTotpRepository) EnableByMemberId(ctx context.Context, uid string, step int64) error {
+	sqlQuery := `
+		UPDATE member_totps
+		SET
+			is_enabled = true,
+			last_used_step = $2,
+			enabled_at = $3,
+			updated_at = $3
+		WHERE member_id = $1
+	`
+
+	var exec MemberTotpExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		uid,
+		step,
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Record the step of a used code, nothing is affected when the step is
+// not after the last used one so a code can not be replayed
+func (r *MemberTotpRepository) UseStep(ctx context.Context, uid string, step int64) (n int64, err error) {
+	sqlQuery := `
+		UPDATE member_totps
+		SET
+			last_used_step = $2,
+			updated_at = $3
+		WHERE member_id = $1
+		AND last_used_step < $2
+	`
+
+	var exec MemberTotpExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	cmd, err := exec(
+		context.Background(),
+		sqlQuery,
+		uid,
+		step,
+		time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return cmd.RowsAffected(), nil
+}
+
+// Remove the enrolment along with the recovery codes
+func (r *MemberTotpRepository) DeleteByMemberId(ctx context.Context, uid string) error {
+	sqlQuery := `
+		WITH recovery_codes AS (
+			DELETE FROM member_recovery_codes
+			WHERE member_id = $1
+		)
+		DELETE FROM member_totps
+		WHERE member_id = $1
+	`
+
+	var exec MemberTotpExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		uid,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Replace every recovery code of the member, the used ones included
+func (r *MemberTotpRepository) ReplaceRecoveryCodes(ctx context.Context, uid string, codeHashes []string) error {
+	sqlQuery := `
+		WITH old_codes AS (
+			DELETE FROM member_recovery_codes
+			WHERE member_id = $1
+		)
+		INSERT INTO member_recovery_codes (
+			member_id,
+			code_hash,
+			created_at
+		)
+		SELECT $1::uuid, UNNEST($2::text[]), $3
+	`
+
+	var exec MemberTotpExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	_, err := exec(
+		context.Background(),
+		sqlQuery,
+		uid,
+		codeHashes,
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Mark the recovery code as used, nothing is affected when the code does
+// not exist or is already used
+func (r *MemberTotpRepository) UseRecoveryCode(ctx context.Context, uid, codeHash string) (n int64, err error) {
+	sqlQuery := `
+		UPDATE member_recovery_codes
+		SET used_at = $3
+		WHERE member_id = $1
+		AND code_hash = $2
+		AND used_at IS NULL
+	`
+
+	var exec MemberTotpExecutor
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		exec = tx.Exec
+	} else {
+		exec = r.PostgreDb.Exec
+	}
+
+	cmd, err := exec(
+		context.Background(),
+		sqlQuery,
+		uid,
+		codeHash,
+		time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return cmd.RowsAffected(), nil
+}
+
+func (r *MemberTotpRepository) CountUnusedRecoveryCodes(ctx context.Context, uid string) (n int64, err error) {
+	sqlQuery := `
+		SELECT COUNT(id) AS n
+		FROM member_recovery_codes
+		WHERE member_id = $1
+		AND used_at IS NULL
+	`
+
+	var queryRow MemberTotpQuerierRow
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		queryRow = tx.QueryRow
+	} else {
+		queryRow = r.PostgreDb.QueryRow
+	}
+
+	err = queryRow(
+		context.Background(),
+		sqlQuery,
+		uid,
+	).Scan(&n)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
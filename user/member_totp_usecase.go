@@ -0,0 +1,675 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"image/png"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/qrcode"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/totp"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	TotpIssuer = "U-Homestay"
+	// Time given to enter the code after the password is checked
+	LoginChallengeValidity = 5 * time.Minute
+	RecoveryCodeCount      = 10
+)
+
+const (
+	TotpStatusRequired           = "required"
+	TotpStatusEnrollmentRequired = "enrollment_required"
+)
+
+var (
+	ErrTotpNotEnabled        = errors.New("autentikasi dua langkah belum diaktifkan")
+	ErrTotpAlreadyEnabled    = errors.New("autentikasi dua langkah sudah aktif")
+	ErrTotpNotEnrolled       = errors.New("pendaftaran autentikasi dua langkah belum dimulai")
+	ErrInvalidTotpCode       = errors.New("kode autentikasi dua langkah tidak valid")
+	ErrInvalidLoginChallenge = errors.New("login dua langkah tidak valid atau kedaluwarsa, silakan login ulang")
+	ErrTotpRequiredForAdmin  = errors.New("autentikasi dua langkah wajib untuk akun admin")
+)
+
+// The challenge carry the member id, the expiry, and whether the password
+// was checked by the admin login, it only prove the first step is passed
+// so it is not a jwt the middlewares would accept
+func (d *UserDeps) signLoginChallenge(id uuid.UUID, isAdmin bool, expiry time.Time) string {
+	payload := make([]byte, uuid.Size+9)
+	copy(payload, id.Bytes())
+	binary.BigEndian.PutUint64(payload[uuid.Size:], uint64(expiry.Unix()))
+	if isAdmin {
+		payload[uuid.Size+8] = 1
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(d.tokenSignature("login-challenge", payload))
+}
+
+func (d *UserDeps) parseLoginChallenge(token string) (id uuid.UUID, isAdmin bool, err error) {
+	p := strings.Split(strings.Trim(token, " "), ".")
+	if len(p) != 2 {
+		return uuid.Nil, false, ErrInvalidLoginChallenge
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(p[0])
+	if err != nil || len(payload) != uuid.Size+9 {
+		return uuid.Nil, false, ErrInvalidLoginChallenge
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(p[1])
+	if err != nil || !hmac.Equal(signature, d.tokenSignature("login-challenge", payload)) {
+		return uuid.Nil, false, ErrInvalidLoginChallenge
+	}
+
+	expiry := time.Unix(int64(binary.BigEndian.Uint64(payload[uuid.Size:])), 0)
+	if !expiry.After(time.Now()) {
+		return uuid.Nil, false, ErrInvalidLoginChallenge
+	}
+
+	id, err = uuid.FromBytes(payload[:uuid.Size])
+	if err != nil {
+		return uuid.Nil, false, ErrInvalidLoginChallenge
+	}
+
+	return id, payload[uuid.Size+8] == 1, nil
+}
+
+// Decide whether the login need the second step. A member enrolled has to
+// enter the code, while an admin not enrolled yet has to enrol before
+// getting the token.
+func (d *UserDeps) loginTotpChallenge(ctx context.Context, member MemberModel, isAdmin bool) (LoginRes, error) {
+	t, err := d.MemberTotpRepository.FindByMemberId(ctx, member.Id.UUID.String())
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return LoginRes{}, errors.Wrap(err, "find member totp by member id")
+	}
+
+	var status string
+	switch {
+	case err == nil && t.IsEnabled:
+		status = TotpStatusRequired
+	case isAdmin:
+		status = TotpStatusEnrollmentRequired
+	default:
+		return LoginRes{}, nil
+	}
+
+	return LoginRes{
+		TotpToken:  d.signLoginChallenge(member.Id.UUID, isAdmin, time.Now().Add(LoginChallengeValidity)),
+		TotpStatus: status,
+	}, nil
+}
+
+// Recovery codes are random enough that a plain hash is enough to keep
+// them from being read from the database
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func newRecoveryCodes() (codes, hashes []string, err error) {
+	codes = make([]string, RecoveryCodeCount)
+	hashes = make([]string, RecoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		h := hex.EncodeToString(b)
+		codes[i] = h[:5] + "-" + h[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	return codes, hashes, nil
+}
+
+func (d *UserDeps) replaceRecoveryCodes(ctx context.Context, uid string) ([]string, error) {
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, errors.Wrap(err, "generate recovery codes")
+	}
+
+	if err = d.MemberTotpRepository.ReplaceRecoveryCodes(ctx, uid, hashes); err != nil {
+		return nil, errors.Wrap(err, "replace recovery codes")
+	}
+
+	return codes, nil
+}
+
+// A valid code is only accepted once, the step of the code must be after
+// the last one used
+func (d *UserDeps) useTotpCode(ctx context.Context, t MemberTotpModel, code string) (bool, error) {
+	step, ok := totp.Verify(t.Secret, strings.Trim(code, " "), time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	n, err := d.MemberTotpRepository.UseStep(ctx, t.MemberId, step)
+	if err != nil {
+		return false, errors.Wrap(err, "use member totp step")
+	}
+
+	return n != 0, nil
+}
+
+func (d *UserDeps) findMemberTotp(ctx context.Context, uid string) (MemberTotpModel, resp.Response) {
+	t, err := d.MemberTotpRepository.FindByMemberId(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return MemberTotpModel{}, resp.NewResponse(http.StatusBadRequest, "", ErrTotpNotEnabled)
+	}
+
+	if err != nil {
+		return MemberTotpModel{}, resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member totp by member id"))
+	}
+
+	return t, resp.NewResponse(http.StatusOK, "", nil)
+}
+
+func (d *UserDeps) findTotpMember(ctx context.Context, uid string) (MemberModel, resp.Response) {
+	_, err := uuid.FromString(uid)
+	if err != nil {
+		return MemberModel{}, resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+	}
+
+	member, err := d.MemberRepository.FindById(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return MemberModel{}, resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+	}
+
+	if err != nil {
+		return MemberModel{}, resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by id"))
+	}
+
+	return member, resp.NewResponse(http.StatusOK, "", nil)
+}
+
+type (
+	TotpEnrollmentRes struct {
+		Secret string `json:"secret"`
+		Uri    string `json:"uri"`
+		QrCode string `json:"qr_code"`
+	}
+	TotpEnrollmentOut struct {
+		resp.Response
+		Res TotpEnrollmentRes
+	}
+)
+
+// A new secret waiting for the first code, the qr code is a png data url
+// of the otpauth uri ready to be scanned by an authenticator app
+func (d *UserDeps) startTotpEnrollment(ctx context.Context, member MemberModel) (out TotpEnrollmentOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusCreated, "", nil)
+
+	uid := member.Id.UUID.String()
+	t, err := d.MemberTotpRepository.FindByMemberId(ctx, uid)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member totp by member id"))
+		return
+	}
+
+	if err == nil && t.IsEnabled {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrTotpAlreadyEnabled)
+		return
+	}
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "generate totp secret"))
+		return
+	}
+
+	if _, err = d.MemberTotpRepository.Save(ctx, MemberTotpModel{
+		MemberId: uid,
+		Secret:   secret,
+	}); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "save member totp"))
+		return
+	}
+
+	uri := totp.URI(TotpIssuer, member.Username, secret)
+	qr, err := qrcode.Encode([]byte(uri))
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "encode totp qr code"))
+		return
+	}
+
+	var b bytes.Buffer
+	if err = png.Encode(&b, qr.Image(6, 4)); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "encode totp qr code png"))
+		return
+	}
+
+	out.Res = TotpEnrollmentRes{
+		Secret: secret,
+		Uri:    uri,
+		QrCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(b.Bytes()),
+	}
+
+	return
+}
+
+type (
+	TotpStatusRes struct {
+		IsEnabled         bool      `json:"is_enabled"`
+		EnabledAt         null.Time `json:"enabled_at"`
+		RecoveryCodesLeft int64     `json:"recovery_codes_left"`
+	}
+	TotpStatusOut struct {
+		resp.Response
+		Res TotpStatusRes
+	}
+)
+
+func (d *UserDeps) FindMemberTotp(ctx context.Context, uid string) (out TotpStatusOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	t, err := d.MemberTotpRepository.FindByMemberId(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member totp by member id"))
+		return
+	}
+
+	if !t.IsEnabled {
+		return
+	}
+
+	n, err := d.MemberTotpRepository.CountUnusedRecoveryCodes(ctx, uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "count unused recovery codes"))
+		return
+	}
+
+	out.Res = TotpStatusRes{
+		IsEnabled:         true,
+		EnabledAt:         null.NewTime(t.EnabledAt.Time, t.EnabledAt.Valid),
+		RecoveryCodesLeft: n,
+	}
+
+	return
+}
+
+func (d *UserDeps) StartMemberTotpEnrollment(ctx context.Context, uid string) (out TotpEnrollmentOut) {
+	member, res := d.findTotpMember(ctx, uid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	return d.startTotpEnrollment(ctx, member)
+}
+
+type (
+	TotpCodeIn struct {
+		Code string `json:"code"`
+	}
+	TotpRecoveryCodesRes struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	TotpRecoveryCodesOut struct {
+		resp.Response
+		Res TotpRecoveryCodesRes
+	}
+)
+
+// The first valid code enable the enrolment, the recovery codes are only
+// shown this once
+func (d *UserDeps) ConfirmMemberTotp(ctx context.Context, uid string, in TotpCodeIn) (out TotpRecoveryCodesOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateTotpCodeIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	t, err := d.MemberTotpRepository.FindByMemberId(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrTotpNotEnrolled)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member totp by member id"))
+		return
+	}
+
+	if t.IsEnabled {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrTotpAlreadyEnabled)
+		return
+	}
+
+	codes, res := d.enableTotp(ctx, t, in.Code)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	out.Res.RecoveryCodes = codes
+
+	return
+}
+
+func (d *UserDeps) enableTotp(ctx context.Context, t MemberTotpModel, code string) ([]string, resp.Response) {
+	step, ok := totp.Verify(t.Secret, strings.Trim(code, " "), time.Now())
+	if !ok {
+		return nil, resp.NewResponse(http.StatusBadRequest, "", ErrInvalidTotpCode)
+	}
+
+	if err := d.MemberTotpRepository.EnableByMemberId(ctx, t.MemberId, step); err != nil {
+		return nil, resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "enable member totp by member id"))
+	}
+
+	codes, err := d.replaceRecoveryCodes(ctx, t.MemberId)
+	if err != nil {
+		return nil, resp.NewResponse(http.StatusInternalServerError, "", err)
+	}
+
+	return codes, resp.NewResponse(http.StatusOK, "", nil)
+}
+
+type (
+	MemberTotpIdRes struct {
+		Id string `json:"id"`
+	}
+	MemberTotpIdOut struct {
+		resp.Response
+		Res MemberTotpIdRes
+	}
+)
+
+// Only a member can turn it off, it is mandatory for an admin
+func (d *UserDeps) DisableMemberTotp(ctx context.Context, uid string, in TotpCodeIn) (out MemberTotpIdOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateTotpCodeIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	member, res := d.findTotpMember(ctx, uid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	if member.IsAdmin {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrTotpRequiredForAdmin)
+		return
+	}
+
+	t, res := d.findMemberTotp(ctx, uid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	if !t.IsEnabled {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrTotpNotEnabled)
+		return
+	}
+
+	ok, err := d.useTotpCode(ctx, t, in.Code)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", err)
+		return
+	}
+
+	if !ok {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrInvalidTotpCode)
+		return
+	}
+
+	if err = d.MemberTotpRepository.DeleteByMemberId(ctx, uid); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "delete member totp by member id"))
+		return
+	}
+
+	out.Res.Id = uid
+
+	return
+}
+
+// New recovery codes replace the old ones, used or not
+func (d *UserDeps) RegenerateRecoveryCodes(ctx context.Context, uid string, in TotpCodeIn) (out TotpRecoveryCodesOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateTotpCodeIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	t, res := d.findMemberTotp(ctx, uid)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	if !t.IsEnabled {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrTotpNotEnabled)
+		return
+	}
+
+	ok, err := d.useTotpCode(ctx, t, in.Code)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", err)
+		return
+	}
+
+	if !ok {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrInvalidTotpCode)
+		return
+	}
+
+	codes, err := d.replaceRecoveryCodes(ctx, uid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", err)
+		return
+	}
+
+	out.Res.RecoveryCodes = codes
+
+	return
+}
+
+// For a member who lost both the authenticator and the recovery codes, an
+// admin reset has to enrol again on the next login
+func (d *UserDeps) ResetMemberTotp(ctx context.Context, uid string) (out MemberTotpIdOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if _, res := d.findTotpMember(ctx, uid); res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	if err = d.MemberTotpRepository.DeleteByMemberId(ctx, uid); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "delete member totp by member id"))
+		return
+	}
+
+	out.Res.Id = uid
+
+	return
+}
+
+// The member of the challenge must still be allowed to log in, the state
+// may change between the two steps
+func (d *UserDeps) findChallengeMember(ctx context.Context, token string) (MemberModel, bool, resp.Response) {
+	id, isAdmin, err := d.parseLoginChallenge(token)
+	if err != nil {
+		return MemberModel{}, false, resp.NewResponse(http.StatusBadRequest, "", err)
+	}
+
+	member, err := d.MemberRepository.FindById(ctx, id.String())
+	if errors.Is(err, pgx.ErrNoRows) {
+		return MemberModel{}, false, resp.NewResponse(http.StatusBadRequest, "", ErrInvalidLoginChallenge)
+	}
+
+	if err != nil {
+		return MemberModel{}, false, resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member by id"))
+	}
+
+	if isAdmin && !member.IsAdmin {
+		return MemberModel{}, false, resp.NewResponse(http.StatusBadRequest, "", ErrInvalidLoginChallenge)
+	}
+
+	if !member.IsApproved {
+		return MemberModel{}, false, notApprovedResponse(member)
+	}
+
+	if res := inactiveResponse(member); res.Error != nil {
+		return MemberModel{}, false, res
+	}
+
+	return member, isAdmin, resp.NewResponse(http.StatusOK, "", nil)
+}
+
+type LoginTotpEnrollmentIn struct {
+	TotpToken string `json:"totp_token"`
+}
+
+// Enrolment of an admin logging in without it
+func (d *UserDeps) StartLoginTotpEnrollment(ctx context.Context, in LoginTotpEnrollmentIn) (out TotpEnrollmentOut) {
+	if strings.Trim(in.TotpToken, " ") == "" {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrLoginChallengeRequired)
+		return
+	}
+
+	member, _, res := d.findChallengeMember(ctx, in.TotpToken)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	return d.startTotpEnrollment(ctx, member)
+}
+
+type (
+	LoginTotpIn struct {
+		TotpToken    string `json:"totp_token"`
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+		Ip           string `json:"-"`
+		UserAgent    string `json:"-"`
+	}
+	LoginTotpRes struct {
+		Token         string   `json:"token"`
+		RecoveryCodes []string `json:"recovery_codes,omitempty"`
+	}
+	LoginTotpOut struct {
+		resp.Response
+		Res LoginTotpRes
+	}
+)
+
+// The second step of the login, the jwt is only issued once the code or a
+// recovery code checks out. The code of an admin still enrolling confirm
+// the enrolment, so the recovery codes come along with the token.
+func (d *UserDeps) LoginTotp(ctx context.Context, in LoginTotpIn) (out LoginTotpOut) {
+	var err error
+	out.Response = resp.NewResponse(http.StatusOK, "", nil)
+
+	if err = ValidateLoginTotpIn(in); err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	member, isAdmin, res := d.findChallengeMember(ctx, in.TotpToken)
+	if res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	// A wrong code counts as a failed login of the username so the code can
+	// not be guessed with a password already known
+	if res := d.loginThrottleResponse(ctx, member.Username, in.Ip); res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	uid := member.Id.UUID.String()
+	t, err := d.MemberTotpRepository.FindByMemberId(ctx, uid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrTotpNotEnrolled)
+		return
+	}
+
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member totp by member id"))
+		return
+	}
+
+	switch {
+	case !t.IsEnabled && isAdmin:
+		codes, res := d.enableTotp(ctx, t, in.Code)
+		if errors.Is(res.Error, ErrInvalidTotpCode) {
+			out.Response = d.loginFailedResponse(ctx, member.Username, in.Ip, res)
+			return
+		}
+		if res.Error != nil {
+			out.Response = res
+			return
+		}
+		out.Res.RecoveryCodes = codes
+	case !t.IsEnabled:
+		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrTotpNotEnabled)
+		return
+	case strings.Trim(in.RecoveryCode, " ") != "":
+		n, err := d.MemberTotpRepository.UseRecoveryCode(ctx, uid, hashRecoveryCode(in.RecoveryCode))
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "use recovery code"))
+			return
+		}
+		if n == 0 {
+			out.Response = d.loginFailedResponse(ctx, member.Username, in.Ip, resp.NewResponse(http.StatusBadRequest, "", ErrInvalidTotpCode))
+			return
+		}
+	default:
+		ok, err := d.useTotpCode(ctx, t, in.Code)
+		if err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", err)
+			return
+		}
+		if !ok {
+			out.Response = d.loginFailedResponse(ctx, member.Username, in.Ip, resp.NewResponse(http.StatusBadRequest, "", ErrInvalidTotpCode))
+			return
+		}
+	}
+
+	jwtToken, err := d.signLoginJwt(ctx, uid, isAdmin, in.Ip, in.UserAgent)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "jwt signer"))
+		return
+	}
+
+	if err = d.resetLoginFailures(ctx, member.Username); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", err)
+		return
+	}
+
+	out.Res.Token = jwtToken
+
+	return
+}
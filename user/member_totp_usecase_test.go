@@ -0,0 +1,358 @@
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/totp"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginTotp(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = createUser(memberRepository, memberAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	login := userDeps.AdminLogin(context.Background(), user.LoginIn{
+		Identifier: memberAdmin.Username,
+		Password:   memberAdmin.Password,
+	})
+	if login.StatusCode != http.StatusOK {
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusOK, login.StatusCode)
+	}
+
+	assert.Empty(t, login.Res.Token)
+	assert.Equal(t, user.TotpStatusEnrollmentRequired, login.Res.TotpStatus)
+
+	enrollment := userDeps.StartLoginTotpEnrollment(context.Background(), user.LoginTotpEnrollmentIn{
+		TotpToken: login.Res.TotpToken,
+	})
+	if enrollment.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusCreated, enrollment.StatusCode)
+	}
+
+	assert.NotEmpty(t, enrollment.Res.QrCode)
+
+	code, err := totp.Code(enrollment.Res.Secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enrolled := userDeps.LoginTotp(context.Background(), user.LoginTotpIn{
+		TotpToken: login.Res.TotpToken,
+		Code:      code,
+	})
+	if enrolled.StatusCode != http.StatusOK {
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusOK, enrolled.StatusCode)
+	}
+
+	assert.NotEmpty(t, enrolled.Res.Token)
+	assert.Len(t, enrolled.Res.RecoveryCodes, user.RecoveryCodeCount)
+
+	login = userDeps.AdminLogin(context.Background(), user.LoginIn{
+		Identifier: memberAdmin.Username,
+		Password:   memberAdmin.Password,
+	})
+	assert.Equal(t, user.TotpStatusRequired, login.Res.TotpStatus)
+
+	nextCode, err := totp.Code(enrollment.Res.Secret, time.Now().Add(totp.Period*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		In                 user.LoginTotpIn
+	}{
+		{
+			Name:               "Login Totp Fail, Code Already Used",
+			ExpectedStatusCode: http.StatusBadRequest,
+			In: user.LoginTotpIn{
+				TotpToken: login.Res.TotpToken,
+				Code:      code,
+			},
+		},
+		{
+			Name:               "Login Totp Fail, Wrong Code",
+			ExpectedStatusCode: http.StatusBadRequest,
+			In: user.LoginTotpIn{
+				TotpToken: login.Res.TotpToken,
+				Code:      "000000",
+			},
+		},
+		{
+			Name:               "Login Totp Fail, Invalid Token",
+			ExpectedStatusCode: http.StatusBadRequest,
+			In: user.LoginTotpIn{
+				TotpToken: login.Res.TotpToken + "x",
+				Code:      nextCode,
+			},
+		},
+		{
+			Name:               "Login Totp Fail, Code Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In: user.LoginTotpIn{
+				TotpToken: login.Res.TotpToken,
+			},
+		},
+		{
+			Name:               "Login Totp Success",
+			ExpectedStatusCode: http.StatusOK,
+			In: user.LoginTotpIn{
+				TotpToken: login.Res.TotpToken,
+				Code:      nextCode,
+			},
+		},
+		{
+			Name:               "Login Totp with Recovery Code Success",
+			ExpectedStatusCode: http.StatusOK,
+			In: user.LoginTotpIn{
+				TotpToken:    login.Res.TotpToken,
+				RecoveryCode: enrolled.Res.RecoveryCodes[0],
+			},
+		},
+		{
+			Name:               "Login Totp Fail, Recovery Code Already Used",
+			ExpectedStatusCode: http.StatusBadRequest,
+			In: user.LoginTotpIn{
+				TotpToken:    login.Res.TotpToken,
+				RecoveryCode: enrolled.Res.RecoveryCodes[0],
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.LoginTotp(context.Background(), c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+
+			if res.Error == nil {
+				assert.NotEmpty(t, res.Res.Token)
+			}
+		})
+	}
+}
+
+func TestConfirmMemberTotp(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notConfirmed := userDeps.ConfirmMemberTotp(context.Background(), uid, user.TotpCodeIn{Code: "000000"})
+	assert.Equal(t, http.StatusBadRequest, notConfirmed.StatusCode)
+
+	enrollment := userDeps.StartMemberTotpEnrollment(context.Background(), uid)
+	if enrollment.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusCreated, enrollment.StatusCode)
+	}
+
+	code, err := totp.Code(enrollment.Res.Secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		In                 user.TotpCodeIn
+	}{
+		{
+			Name:               "Confirm Member Totp Fail, Code Required",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+			In:                 user.TotpCodeIn{},
+		},
+		{
+			Name:               "Confirm Member Totp Fail, Wrong Code",
+			ExpectedStatusCode: http.StatusBadRequest,
+			In:                 user.TotpCodeIn{Code: "000000"},
+		},
+		{
+			Name:               "Confirm Member Totp Success",
+			ExpectedStatusCode: http.StatusOK,
+			In:                 user.TotpCodeIn{Code: code},
+		},
+		{
+			Name:               "Confirm Member Totp Fail, Already Enabled",
+			ExpectedStatusCode: http.StatusBadRequest,
+			In:                 user.TotpCodeIn{Code: code},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.ConfirmMemberTotp(context.Background(), uid, c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+
+	status := userDeps.FindMemberTotp(context.Background(), uid)
+	assert.True(t, status.Res.IsEnabled)
+	assert.Equal(t, int64(user.RecoveryCodeCount), status.Res.RecoveryCodesLeft)
+
+	login := userDeps.MemberLogin(context.Background(), user.LoginIn{
+		Identifier: memberNormal.Username,
+		Password:   memberNormal.Password,
+	})
+	assert.Empty(t, login.Res.Token)
+	assert.Equal(t, user.TotpStatusRequired, login.Res.TotpStatus)
+}
+
+func TestDisableMemberTotp(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adminUid, err := createUser(memberRepository, memberAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Enrol both with the code of the previous step so the current one is
+	// still unused
+	var secrets []string
+	for _, v := range []string{adminUid, uid} {
+		enrollment := userDeps.StartMemberTotpEnrollment(context.Background(), v)
+		code, err := totp.Code(enrollment.Res.Secret, time.Now().Add(-totp.Period*time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res := userDeps.ConfirmMemberTotp(context.Background(), v, user.TotpCodeIn{Code: code}); res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		secrets = append(secrets, enrollment.Res.Secret)
+	}
+
+	adminCode, _ := totp.Code(secrets[0], time.Now())
+	code, _ := totp.Code(secrets[1], time.Now())
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Uid                string
+		In                 user.TotpCodeIn
+	}{
+		{
+			Name:               "Disable Member Totp Fail, Admin",
+			ExpectedStatusCode: http.StatusBadRequest,
+			Uid:                adminUid,
+			In:                 user.TotpCodeIn{Code: adminCode},
+		},
+		{
+			Name:               "Disable Member Totp Fail, Wrong Code",
+			ExpectedStatusCode: http.StatusBadRequest,
+			Uid:                uid,
+			In:                 user.TotpCodeIn{Code: "000000"},
+		},
+		{
+			Name:               "Disable Member Totp Success",
+			ExpectedStatusCode: http.StatusOK,
+			Uid:                uid,
+			In:                 user.TotpCodeIn{Code: code},
+		},
+		{
+			Name:               "Disable Member Totp Fail, Not Enabled",
+			ExpectedStatusCode: http.StatusBadRequest,
+			Uid:                uid,
+			In:                 user.TotpCodeIn{Code: code},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.DisableMemberTotp(context.Background(), c.Uid, c.In)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestResetMemberTotp(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := createUser(memberRepository, memberAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enrollment := userDeps.StartMemberTotpEnrollment(context.Background(), uid)
+	code, err := totp.Code(enrollment.Res.Secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res := userDeps.ConfirmMemberTotp(context.Background(), uid, user.TotpCodeIn{Code: code}); res.Error != nil {
+		t.Fatal(res.Error)
+	}
+
+	testCases := []struct {
+		Name               string
+		ExpectedStatusCode int
+		Uid                string
+	}{
+		{
+			Name:               "Reset Member Totp Success",
+			ExpectedStatusCode: http.StatusOK,
+			Uid:                uid,
+		},
+		{
+			Name:               "Reset Member Totp Fail, Member Not Found",
+			ExpectedStatusCode: http.StatusNotFound,
+			Uid:                "1ed4a9d6-0000-6000-8000-000000000000",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := userDeps.ResetMemberTotp(context.Background(), c.Uid)
+
+			if res.StatusCode != c.ExpectedStatusCode {
+				t.Logf("%#v", res)
+				t.Fatalf("Expected response code %d. Got %d\n", c.ExpectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+
+	login := userDeps.AdminLogin(context.Background(), user.LoginIn{
+		Identifier: memberAdmin.Username,
+		Password:   memberAdmin.Password,
+	})
+	assert.Equal(t, user.TotpStatusEnrollmentRequired, login.Res.TotpStatus)
+}
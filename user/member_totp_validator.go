@@ -0,0 +1,43 @@
+package user
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrTotpCodeRequired       = errors.New("kode autentikasi dua langkah tidak boleh kosong")
+	ErrLoginChallengeRequired = errors.New("token login dua langkah tidak boleh kosong")
+)
+
+func ValidateTotpCodeIn(i TotpCodeIn) error {
+	if strings.Trim(i.Code, " ") == "" {
+		return ErrTotpCodeRequired
+	}
+
+	return nil
+}
+
+func ValidateLoginTotpIn(i LoginTotpIn) error {
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		if strings.Trim(i.TotpToken, " ") == "" {
+			return ErrLoginChallengeRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if strings.Trim(i.Code, " ") == "" && strings.Trim(i.RecoveryCode, " ") == "" {
+			return ErrTotpCodeRequired
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -12,13 +12,13 @@ import (
 
 	arbitary "github.com/PA-D3RPLA/d3if43-htt-uhomestay/arbitrary"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/filetype"
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/geo"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/pagination"
 
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/httpdecode"
 	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/resp"
 	"github.com/fikryfahrezy/crypt/agron2"
 
-	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/jwt"
 	"github.com/gofrs/uuid"
 	pgtypeuuid "github.com/jackc/pgtype/ext/gofrs-uuid"
 	"github.com/jackc/pgx/v4"
@@ -333,11 +333,23 @@ func (d *UserDeps) MemberRegister(ctx context.Context, in RegisterIn) (out Regis
 		return
 	}
 
+	latitude, err := geo.ParseLatitude(in.HomestayLatitude)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidHomestayLat)
+		return
+	}
+
+	longitude, err := geo.ParseLongitude(in.HomestayLongitude)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusUnprocessableEntity, "", ErrInvalidHomestayLng)
+		return
+	}
+
 	memberHomestay := MemberHomestayModel{
 		Name:         in.HomestayName,
 		Address:      in.HomestayAddress,
-		Latitude:     in.HomestayLatitude,
-		Longitude:    in.HomestayLongitude,
+		Latitude:     latitude,
+		Longitude:    longitude,
 		ThumbnailUrl: homestayPhotoUrl,
 		MemberId:     saverOut.Res.Id,
 	}
@@ -372,9 +384,13 @@ type (
 	LoginIn struct {
 		Identifier string `json:"identifier"`
 		Password   string `json:"password"`
+		Ip         string `json:"-"`
+		UserAgent  string `json:"-"`
 	}
 	LoginRes struct {
-		Token string `json:"token"`
+		Token      string `json:"token"`
+		TotpToken  string `json:"totp_token,omitempty"`
+		TotpStatus string `json:"totp_status,omitempty"`
 	}
 	LoginOut struct {
 		resp.Response
@@ -391,9 +407,14 @@ func (d *UserDeps) MemberLogin(ctx context.Context, in LoginIn) (out LoginOut) {
 		return
 	}
 
+	if res := d.loginThrottleResponse(ctx, in.Identifier, in.Ip); res.Error != nil {
+		out.Response = res
+		return
+	}
+
 	member, err := d.MemberRepository.FindByUsername(in.Identifier)
 	if errors.Is(err, pgx.ErrNoRows) {
-		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		out.Response = d.loginFailedResponse(ctx, in.Identifier, in.Ip, resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound))
 		return
 	}
 
@@ -402,33 +423,43 @@ func (d *UserDeps) MemberLogin(ctx context.Context, in LoginIn) (out LoginOut) {
 		return
 	}
 
+	if err = agron2.Argon2Verify(member.Password, in.Password, agron2.Argon2Id); err != nil {
+		out.Response = d.loginFailedResponse(ctx, in.Identifier, in.Ip, resp.NewResponse(http.StatusBadRequest, "", ErrPasswordNotMatch))
+		return
+	}
+
 	if !member.IsApproved {
-		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrNotApprovedMember)
+		out.Response = notApprovedResponse(member)
 		return
 	}
 
-	if err = agron2.Argon2Verify(member.Password, in.Password, agron2.Argon2Id); err != nil {
-		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrPasswordNotMatch)
+	if res := inactiveResponse(member); res.Error != nil {
+		out.Response = res
 		return
 	}
 
-	jwtToken, err := jwt.Sign(
-		"",
-		"token",
-		d.JwtIssuerUrl,
-		d.JwtKey,
-		d.JwtAudiences,
-		time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC),
-		time.Time{},
-		time.Time{},
-		jwt.JwtPrivateClaim{
-			Uid: member.Id.UUID.String(),
-		})
+	challenge, err := d.loginTotpChallenge(ctx, member, false)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", err)
+		return
+	}
+
+	if challenge.TotpToken != "" {
+		out.Res = challenge
+		return
+	}
+
+	jwtToken, err := d.signLoginJwt(ctx, member.Id.UUID.String(), false, in.Ip, in.UserAgent)
 	if err != nil {
 		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "jwt signer"))
 		return
 	}
 
+	if err = d.resetLoginFailures(ctx, in.Identifier); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", err)
+		return
+	}
+
 	out.Res.Token = jwtToken
 
 	return
@@ -443,9 +474,14 @@ func (d *UserDeps) AdminLogin(ctx context.Context, in LoginIn) (out LoginOut) {
 		return
 	}
 
+	if res := d.loginThrottleResponse(ctx, in.Identifier, in.Ip); res.Error != nil {
+		out.Response = res
+		return
+	}
+
 	member, err := d.MemberRepository.FindByUsername(in.Identifier)
 	if errors.Is(err, pgx.ErrNoRows) {
-		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		out.Response = d.loginFailedResponse(ctx, in.Identifier, in.Ip, resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound))
 		return
 	}
 
@@ -455,38 +491,47 @@ func (d *UserDeps) AdminLogin(ctx context.Context, in LoginIn) (out LoginOut) {
 	}
 
 	if !member.IsAdmin {
-		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
+		out.Response = d.loginFailedResponse(ctx, in.Identifier, in.Ip, resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound))
+		return
+	}
+
+	if err = agron2.Argon2Verify(member.Password, in.Password, agron2.Argon2Id); err != nil {
+		out.Response = d.loginFailedResponse(ctx, in.Identifier, in.Ip, resp.NewResponse(http.StatusBadRequest, "", ErrPasswordNotMatch))
 		return
 	}
 
 	if !member.IsApproved {
-		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrNotApprovedMember)
+		out.Response = notApprovedResponse(member)
 		return
 	}
 
-	if err = agron2.Argon2Verify(member.Password, in.Password, agron2.Argon2Id); err != nil {
-		out.Response = resp.NewResponse(http.StatusBadRequest, "", ErrPasswordNotMatch)
-		return
-	}
-
-	jwtToken, err := jwt.Sign(
-		"",
-		"token",
-		d.JwtIssuerUrl,
-		d.JwtKey,
-		d.JwtAudiences,
-		time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC),
-		time.Time{},
-		time.Time{},
-		jwt.JwtPrivateAdminClaim{
-			Uid:     member.Id.UUID.String(),
-			IsAdmin: true,
-		})
+	if res := inactiveResponse(member); res.Error != nil {
+		out.Response = res
+		return
+	}
+
+	challenge, err := d.loginTotpChallenge(ctx, member, true)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", err)
+		return
+	}
+
+	if challenge.TotpToken != "" {
+		out.Res = challenge
+		return
+	}
+
+	jwtToken, err := d.signLoginJwt(ctx, member.Id.UUID.String(), true, in.Ip, in.UserAgent)
 	if err != nil {
 		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "jwt signer"))
 		return
 	}
 
+	if err = d.resetLoginFailures(ctx, in.Identifier); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", err)
+		return
+	}
+
 	out.Res.Token = jwtToken
 
 	return
@@ -599,6 +644,7 @@ func (d *UserDeps) EditMember(ctx context.Context, uid string, in EditMemberIn)
 	member.OtherPhone = in.OtherPhone
 	member.WaPhone = in.WaPhone
 	member.Username = in.Username
+	isAdminRevoked := member.IsAdmin && !in.IsAdmin.Bool
 	member.IsAdmin = in.IsAdmin.Bool
 
 	existingMember, err := d.MemberRepository.CheckOtherUniqueField(ctx, uid, member)
@@ -669,6 +715,14 @@ func (d *UserDeps) EditMember(ctx context.Context, uid string, in EditMemberIn)
 		return
 	}
 
+	// The admin token is not valid anymore once the member is not an admin
+	if isAdminRevoked {
+		if _, err = d.MemberSessionRepository.RevokeAdminByMemberId(ctx, uid); err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "revoke admin sessions"))
+			return
+		}
+	}
+
 	if periodId != orgStructure.OrgPeriodId || len(positions) != 0 {
 		memId := member.Id.UUID.String()
 		structures := make([]OrgStructureModel, len(positions))
@@ -733,6 +787,11 @@ func (d *UserDeps) RemoveMember(ctx context.Context, uid string) (out RemoveMemb
 		return
 	}
 
+	if _, err = d.MemberSessionRepository.RevokeByMemberId(ctx, uid); err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "revoke member sessions by member id"))
+		return
+	}
+
 	out.Res.Id = uid
 
 	return
@@ -740,14 +799,16 @@ func (d *UserDeps) RemoveMember(ctx context.Context, uid string) (out RemoveMemb
 
 type (
 	MemberOut struct {
-		Id            string `json:"id"`
-		Username      string `json:"username"`
-		Name          string `json:"name"`
-		WaPhone       string `json:"wa_phone"`
-		OtherPhone    string `json:"other_phone"`
-		ProfilePicUrl string `json:"profile_pic_url"`
-		IsAdmin       bool   `json:"is_admin"`
-		IsApproved    bool   `json:"is_approved"`
+		Id             string `json:"id"`
+		Username       string `json:"username"`
+		Name           string `json:"name"`
+		WaPhone        string `json:"wa_phone"`
+		OtherPhone     string `json:"other_phone"`
+		ProfilePicUrl  string `json:"profile_pic_url"`
+		IsAdmin        bool   `json:"is_admin"`
+		IsApproved     bool   `json:"is_approved"`
+		ApprovalStatus string `json:"approval_status"`
+		Status         string `json:"status"`
 	}
 	QueryMemberRes struct {
 		Total   int64       `json:"total"`
@@ -760,10 +821,17 @@ type (
 	}
 )
 
-func (d *UserDeps) QueryMember(ctx context.Context, q, cursor, limit string) (out QueryMemberOut) {
+// The fields are projected by the visibility each member set for the viewer
+func (d *UserDeps) QueryMember(ctx context.Context, viewerUid, q, cursor, limit string) (out QueryMemberOut) {
 	var err error
 	out.Response = resp.NewResponse(http.StatusOK, "", nil)
 
+	viewer, err := d.findMemberViewer(ctx, viewerUid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member viewer"))
+		return
+	}
+
 	s, t, err := pagination.DecodeSIDCursor(cursor)
 	if err != nil {
 		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "decode sid cursor"))
@@ -800,15 +868,24 @@ func (d *UserDeps) QueryMember(ctx context.Context, q, cursor, limit string) (ou
 
 	outMembers := make([]MemberOut, mLen)
 	for i, m := range members {
+		mid := m.Id.UUID.String()
 		outMembers[i] = MemberOut{
-			Id:            m.Id.UUID.String(),
-			Name:          m.Name,
-			WaPhone:       m.WaPhone,
-			OtherPhone:    m.OtherPhone,
-			ProfilePicUrl: m.ProfilePicUrl,
-			Username:      m.Username,
-			IsAdmin:       m.IsAdmin,
-			IsApproved:    m.IsApproved,
+			Id:             mid,
+			Name:           m.Name,
+			ProfilePicUrl:  m.ProfilePicUrl,
+			IsAdmin:        m.IsAdmin,
+			IsApproved:     m.IsApproved,
+			ApprovalStatus: memberApprovalStatus(m).String,
+			Status:         EffectiveMemberStatus(m).String,
+		}
+		if viewer.canView(mid, m.UsernameVisibility) {
+			outMembers[i].Username = m.Username
+		}
+		if viewer.canView(mid, m.WaPhoneVisibility) {
+			outMembers[i].WaPhone = m.WaPhone
+		}
+		if viewer.canView(mid, m.OtherPhoneVisibility) {
+			outMembers[i].OtherPhone = m.OtherPhone
 		}
 	}
 
@@ -828,18 +905,22 @@ type (
 		Name  string `json:"name"`
 	}
 	MemberDetailRes struct {
-		Id            string           `json:"id"`
-		Name          string           `json:"name"`
-		Username      string           `json:"username"`
-		WaPhone       string           `json:"wa_phone"`
-		OtherPhone    string           `json:"other_phone"`
-		ProfilePicUrl string           `json:"profile_pic_url"`
-		IdCardUrl     string           `json:"id_card_url"`
-		IsAdmin       bool             `json:"is_admin"`
-		IsApproved    bool             `json:"is_approved"`
-		PeriodId      uint64           `json:"period_id"`
-		Period        string           `json:"period"`
-		Positions     []MemberPosition `json:"positions"`
+		Id             string           `json:"id"`
+		Name           string           `json:"name"`
+		Username       string           `json:"username"`
+		WaPhone        string           `json:"wa_phone"`
+		OtherPhone     string           `json:"other_phone"`
+		ProfilePicUrl  string           `json:"profile_pic_url"`
+		IdCardUrl      string           `json:"id_card_url"`
+		IsAdmin        bool             `json:"is_admin"`
+		IsApproved     bool             `json:"is_approved"`
+		ApprovalStatus string           `json:"approval_status"`
+		ApprovalReason string           `json:"approval_reason"`
+		Status         string           `json:"status"`
+		SuspendedUntil null.Time        `json:"suspended_until"`
+		PeriodId       uint64           `json:"period_id"`
+		Period         string           `json:"period"`
+		Positions      []MemberPosition `json:"positions"`
 	}
 	FindMemberDetailOut struct {
 		resp.Response
@@ -847,7 +928,9 @@ type (
 	}
 )
 
-func (d *UserDeps) FindMemberDetail(ctx context.Context, uid string) (out FindMemberDetailOut) {
+// The id card and the review reason are only shown to admins and the
+// member itself, the other fields follow the member visibility setting
+func (d *UserDeps) FindMemberDetail(ctx context.Context, uid, viewerUid string) (out FindMemberDetailOut) {
 	var err error
 	out.Response = resp.NewResponse(http.StatusOK, "", nil)
 
@@ -857,6 +940,12 @@ func (d *UserDeps) FindMemberDetail(ctx context.Context, uid string) (out FindMe
 		return
 	}
 
+	viewer, err := d.findMemberViewer(ctx, viewerUid)
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find member viewer"))
+		return
+	}
+
 	mc := make(chan MemberModel)
 	mR := make(chan resp.Response)
 	go func(ctx context.Context, uid string, m chan MemberModel, res chan resp.Response) {
@@ -969,18 +1058,32 @@ func (d *UserDeps) FindMemberDetail(ctx context.Context, uid string) (out FindMe
 	}
 
 	out.Res = MemberDetailRes{
-		Id:            member.Id.UUID.String(),
-		Name:          member.Name,
-		WaPhone:       member.WaPhone,
-		OtherPhone:    member.OtherPhone,
-		ProfilePicUrl: member.ProfilePicUrl,
-		IdCardUrl:     member.IdCardUrl,
-		Username:      member.Username,
-		IsAdmin:       member.IsAdmin,
-		IsApproved:    member.IsApproved,
-		PeriodId:      period.Id,
-		Period:        periodStart + periodEnd,
-		Positions:     positionRes,
+		Id:             uid,
+		Name:           member.Name,
+		ProfilePicUrl:  member.ProfilePicUrl,
+		IsAdmin:        member.IsAdmin,
+		IsApproved:     member.IsApproved,
+		ApprovalStatus: memberApprovalStatus(member).String,
+		Status:         EffectiveMemberStatus(member).String,
+		PeriodId:       period.Id,
+		Period:         periodStart + periodEnd,
+		Positions:      positionRes,
+	}
+	if out.Res.Status == MemberStatusSuspended.String {
+		out.Res.SuspendedUntil = null.NewTime(member.SuspendedUntil.Time, member.SuspendedUntil.Valid)
+	}
+	if viewer.canView(uid, member.UsernameVisibility) {
+		out.Res.Username = member.Username
+	}
+	if viewer.canView(uid, member.WaPhoneVisibility) {
+		out.Res.WaPhone = member.WaPhone
+	}
+	if viewer.canView(uid, member.OtherPhoneVisibility) {
+		out.Res.OtherPhone = member.OtherPhone
+	}
+	if viewer.canView(uid, VisibilityAdmins) {
+		out.Res.IdCardUrl = member.IdCardUrl
+		out.Res.ApprovalReason = member.ApprovalReason
 	}
 
 	return
@@ -997,40 +1100,9 @@ type (
 )
 
 func (d *UserDeps) ApproveMember(ctx context.Context, uid string) (out MemberApprovalOut) {
-	var err error
-	out.Response = resp.NewResponse(http.StatusOK, "", nil)
-
-	_, err = uuid.FromString(uid)
-	if err != nil {
-		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
-		return
-	}
-
-	member, err := d.MemberRepository.FindById(ctx, uid)
-	if errors.Is(err, pgx.ErrNoRows) {
-		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
-		return
-	}
-
-	if err != nil {
-		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "find unapproved member by id"))
-		return
-	}
-
-	if member.IsApproved {
-		out.Response = resp.NewResponse(http.StatusNotFound, "", ErrMemberNotFound)
-		return
-	}
-
-	member.IsApproved = true
-	if err = d.MemberRepository.Update(ctx, uid, member); err != nil {
-		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update member"))
-		return
-	}
-
-	out.Res.Id = uid
-
-	return
+	return d.ReviewMemberRegistration(ctx, uid, ReviewMemberRegistrationIn{
+		Status: MemberApprovalApproved.String,
+	})
 }
 
 type (
@@ -1044,7 +1116,8 @@ type (
 		IdCard     httpdecode.FileHeader `mapstructure:"id_card"`
 	}
 	UpdateProfileRes struct {
-		Id string `json:"id"`
+		Id             string   `json:"id"`
+		PendingChanges []string `json:"pending_changes"`
 	}
 	UpdateProfileOut struct {
 		resp.Response
@@ -1078,7 +1151,8 @@ func (d *UserDeps) UpdateProfile(ctx context.Context, uid string, in UpdateProfi
 		return
 	}
 
-	member.Name = in.Name
+	// The name and the id card are checked by an admin first, the other
+	// fields are applied right away
 	member.OtherPhone = in.OtherPhone
 	member.WaPhone = in.WaPhone
 	member.Username = in.Username
@@ -1142,16 +1216,30 @@ func (d *UserDeps) UpdateProfile(ctx context.Context, uid string, in UpdateProfi
 		return
 	}
 
-	if newIdCardUrl != "" {
-		member.IdCardUrl = newIdCardUrl
-	}
-
 	if err = d.MemberRepository.Update(ctx, uid, member); err != nil {
 		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "update member"))
 		return
 	}
 
+	pendingChanges := []string{}
+	if in.Name != member.Name {
+		if err = d.stageProfileChange(ctx, uid, ProfileChangeFieldName, member.Name, in.Name); err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", err)
+			return
+		}
+		pendingChanges = append(pendingChanges, ProfileChangeFieldName.String)
+	}
+
+	if newIdCardUrl != "" {
+		if err = d.stageProfileChange(ctx, uid, ProfileChangeFieldIdCard, member.IdCardUrl, newIdCardUrl); err != nil {
+			out.Response = resp.NewResponse(http.StatusInternalServerError, "", err)
+			return
+		}
+		pendingChanges = append(pendingChanges, ProfileChangeFieldIdCard.String)
+	}
+
 	out.Res.Id = uid
+	out.Res.PendingChanges = pendingChanges
 
 	return
 }
@@ -1176,18 +1264,7 @@ func (d *UserDeps) MemberLoginWithUsername(ctx context.Context, username string)
 		return
 	}
 
-	jwtToken, err := jwt.Sign(
-		"",
-		"token",
-		d.JwtIssuerUrl,
-		d.JwtKey,
-		d.JwtAudiences,
-		time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC),
-		time.Time{},
-		time.Time{},
-		jwt.JwtPrivateClaim{
-			Uid: member.Id.UUID.String(),
-		})
+	jwtToken, err := d.signLoginJwt(ctx, member.Id.UUID.String(), false, "", "")
 	if err != nil {
 		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "jwt signer"))
 		return
@@ -1223,19 +1300,7 @@ func (d *UserDeps) AdminLoginWithUsername(ctx context.Context, username string)
 		return
 	}
 
-	jwtToken, err := jwt.Sign(
-		"",
-		"token",
-		d.JwtIssuerUrl,
-		d.JwtKey,
-		d.JwtAudiences,
-		time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC),
-		time.Time{},
-		time.Time{},
-		jwt.JwtPrivateAdminClaim{
-			Uid:     member.Id.UUID.String(),
-			IsAdmin: true,
-		})
+	jwtToken, err := d.signLoginJwt(ctx, member.Id.UUID.String(), true, "", "")
 	if err != nil {
 		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "jwt signer"))
 		return
@@ -1266,45 +1331,10 @@ func (d *UserDeps) UserLoginWithUsername(ctx context.Context, username string) (
 		return
 	}
 
-	var jwtToken string
-
-	if member.IsAdmin {
-		jwtToken, err = jwt.Sign(
-			"",
-			"token",
-			d.JwtIssuerUrl,
-			d.JwtKey,
-			d.JwtAudiences,
-			time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC),
-			time.Time{},
-			time.Time{},
-			jwt.JwtPrivateAdminClaim{
-				Uid:     member.Id.UUID.String(),
-				IsAdmin: true,
-			})
-		if err != nil {
-			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "jwt signer"))
-			return
-		}
-	}
-
-	if jwtToken == "" {
-		jwtToken, err = jwt.Sign(
-			"",
-			"token",
-			d.JwtIssuerUrl,
-			d.JwtKey,
-			d.JwtAudiences,
-			time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC),
-			time.Time{},
-			time.Time{},
-			jwt.JwtPrivateClaim{
-				Uid: member.Id.UUID.String(),
-			})
-		if err != nil {
-			out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "jwt signer"))
-			return
-		}
+	jwtToken, err := d.signLoginJwt(ctx, member.Id.UUID.String(), member.IsAdmin, "", "")
+	if err != nil {
+		out.Response = resp.NewResponse(http.StatusInternalServerError, "", errors.Wrap(err, "jwt signer"))
+		return
 	}
 
 	out.Res.Token = jwtToken
@@ -51,7 +51,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0001",
 				OtherPhone:        "+62 821-1111-0001",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -69,7 +69,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0002",
 				OtherPhone:        "+62 821-1111-0002",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -87,7 +87,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           member.WaPhone,
 				OtherPhone:        "+62 821-1111-0003",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -105,7 +105,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0004",
 				OtherPhone:        member.OtherPhone,
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -123,7 +123,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -141,7 +141,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -159,7 +159,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -177,7 +177,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           strings.Repeat("0", 51),
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -195,7 +195,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        strings.Repeat("0", 51),
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -213,7 +213,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        strings.Repeat("0", 51),
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -231,7 +231,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -249,7 +249,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -267,7 +267,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -285,7 +285,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   strings.Repeat("0", 201),
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -339,7 +339,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -357,7 +357,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: strings.Repeat("0", 51),
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -375,7 +375,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -393,7 +393,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -411,7 +411,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "",
 				Profile:           generateFile(fileDir, fileName),
@@ -429,7 +429,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          strings.Repeat("a", 201),
 				Profile:           generateFile(fileDir, fileName),
@@ -447,7 +447,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				IdCard:            generateFile(fileDir, fileName),
@@ -464,7 +464,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, strings.Repeat("a", 201)),
@@ -482,7 +482,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0066",
 				OtherPhone:        "+62 821-1111-0066",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile("./fixture/pdf.pdf", fileName),
@@ -500,7 +500,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -517,7 +517,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -535,7 +535,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -553,7 +553,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0066",
 				OtherPhone:        "+62 821-1111-0066",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -570,7 +570,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0005",
 				OtherPhone:        "+62 821-1111-0005",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -588,7 +588,7 @@ func TestRegisterMember(t *testing.T) {
 				WaPhone:           "+62 821-1111-0066",
 				OtherPhone:        "+62 821-1111-0066",
 				HomestayAddress:   "Homestay Address",
-				HomestayLatitude:  "120.12312312",
+				HomestayLatitude:  "-6.12312312",
 				HomestayLongitude: "90.1212321",
 				Password:          "password",
 				Profile:           generateFile(fileDir, fileName),
@@ -1469,6 +1469,69 @@ func TestEditMember(t *testing.T) {
 	}
 }
 
+func TestEditMemberRevokeAdmin(t *testing.T) {
+	err := ClearTables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, prid, psid, err := createFullUser(userDeps, member, period, position)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adminTokenId, _, err := loginMemberSession(member)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = db.Exec(context.Background(), `UPDATE member_sessions SET is_admin = true WHERE token_id = $1`, adminTokenId); err != nil {
+		t.Fatal(err)
+	}
+
+	tokenId, _, err := loginMemberSession(member)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), arbitary.TrxX{}, tx)
+	res := userDeps.EditMember(ctx, uid, user.EditMemberIn{
+		Name:        member.Name,
+		Username:    member.Username,
+		PositionIds: []int64{int64(psid)},
+		PeriodId:    int64(prid),
+		WaPhone:     member.WaPhone,
+		OtherPhone:  member.OtherPhone,
+		IsAdmin:     null.BoolFrom(false),
+	})
+	tx.Commit(context.Background())
+	tx.Rollback(context.Background())
+
+	if res.StatusCode != http.StatusOK {
+		t.Logf("%#v", res)
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusOK, res.StatusCode)
+	}
+
+	isAlive, err := userDeps.IsSessionAlive(context.Background(), adminTokenId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.False(t, isAlive)
+
+	isAlive, err = userDeps.IsSessionAlive(context.Background(), tokenId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, isAlive)
+}
+
 func TestRemoveMember(t *testing.T) {
 	err := ClearTables(db)
 	if err != nil {
@@ -1547,7 +1610,7 @@ func TestQueryMember(t *testing.T) {
 			}
 
 			ctx := context.WithValue(context.Background(), arbitary.TrxX{}, tx)
-			res := userDeps.QueryMember(ctx, "", "", "0")
+			res := userDeps.QueryMember(ctx, "", "", "", "0")
 			tx.Commit(context.Background())
 			tx.Rollback(context.Background())
 
@@ -1595,7 +1658,7 @@ func TestFindMemberDetail(t *testing.T) {
 
 	for _, c := range testCases {
 		t.Run(c.Name, func(t *testing.T) {
-			res := userDeps.FindMemberDetail(context.Background(), c.Id)
+			res := userDeps.FindMemberDetail(context.Background(), c.Id, "")
 
 			if res.StatusCode != c.ExpectedStatusCode {
 				t.Logf("%#v", res)
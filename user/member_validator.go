@@ -4,8 +4,10 @@ import (
 	"strings"
 	"unicode/utf8"
 
+	"github.com/PA-D3RPLA/d3if43-htt-uhomestay/geo"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
+	"gopkg.in/guregu/null.v4"
 )
 
 var (
@@ -28,6 +30,8 @@ var (
 	ErrMaxHomestayAddress     = errors.New("alamat homestay anggota tidak dapat lebih dari 200 karakter")
 	ErrMaxHomestayLat         = errors.New("titik garis bujur map homestay tidak dapat lebih dari 50 karakter")
 	ErrMaxHomestayLng         = errors.New("titik garis lintang map homestay tidak dapat lebih dari 50 karakter")
+	ErrInvalidHomestayLat     = errors.New("titik garis lintang map homestay harus berupa angka antara -90 dan 90")
+	ErrInvalidHomestayLng     = errors.New("titik garis bujur map homestay harus berupa angka antara -180 dan 180")
 	ErrMaxUsername            = errors.New("username anggota tidak dapat lebih dari 50 karakter")
 	ErrMaxPassword            = errors.New("password anggota tidak dapat lebih dari 200 karakter")
 	ErrProfileRequired        = errors.New("foto profile tidak boleh kosong")
@@ -36,10 +40,13 @@ var (
 	ErrIdCardFileName         = errors.New("nama file ktp tidak dapat lebih dari 200 karakter")
 	ErrHomestayPhotoRequired  = errors.New("foto homestay tidak boleh kosong")
 	ErrHomestayPhotoFileName  = errors.New("nama file foto homestay tidak dapat lebih dari 200 karakter")
+	ErrVisibilityRequired     = errors.New("visibilitas data anggota tidak boleh kosong")
+	ErrInvalidVisibility      = errors.New("visibilitas data anggota harus berupa public, members, atau admins")
 )
 
-func ValidateAddMemberIn(i AddMemberIn) error {
-	g := new(errgroup.Group)
+// Checks of the member account fields shared by adding a member one by one
+// and importing members in bulk
+func goValidateMemberAccount(g *errgroup.Group, i AddMemberIn) {
 	g.Go(func() error {
 		if strings.Trim(i.Name, " ") == "" {
 			return ErrMemberNameRequired
@@ -52,18 +59,6 @@ func ValidateAddMemberIn(i AddMemberIn) error {
 		}
 		return nil
 	})
-	g.Go(func() error {
-		if len(i.PositionIds) == 0 {
-			return ErrPositionRequired
-		}
-		return nil
-	})
-	g.Go(func() error {
-		if i.PeriodId == 0 {
-			return ErrOrgPeriodRequired
-		}
-		return nil
-	})
 	g.Go(func() error {
 		if strings.Trim(i.WaPhone, " ") == "" {
 			return ErrWaPhoneRequired
@@ -118,6 +113,23 @@ func ValidateAddMemberIn(i AddMemberIn) error {
 		}
 		return nil
 	})
+}
+
+func ValidateAddMemberIn(i AddMemberIn) error {
+	g := new(errgroup.Group)
+	goValidateMemberAccount(g, i)
+	g.Go(func() error {
+		if len(i.PositionIds) == 0 {
+			return ErrPositionRequired
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if i.PeriodId == 0 {
+			return ErrOrgPeriodRequired
+		}
+		return nil
+	})
 
 	if err := g.Wait(); err != nil {
 		return err
@@ -126,6 +138,85 @@ func ValidateAddMemberIn(i AddMemberIn) error {
 	return nil
 }
 
+// Position and homestay are optional for an imported member, but the
+// homestay fields are all required once one of them is filled
+func ValidateImportMemberRowIn(i ImportMemberRowIn) error {
+	g := new(errgroup.Group)
+	goValidateMemberAccount(g, AddMemberIn{
+		Name:       i.Name,
+		Username:   i.Username,
+		Password:   i.Password,
+		WaPhone:    i.WaPhone,
+		OtherPhone: i.OtherPhone,
+		IsAdmin:    null.BoolFrom(false),
+	})
+
+	hasHomestay := strings.Trim(i.HomestayName+i.HomestayAddress+i.HomestayLatitude+i.HomestayLongitude, " ") != ""
+	if hasHomestay {
+		g.Go(func() error {
+			if strings.Trim(i.HomestayName, " ") == "" {
+				return ErrHomestayNameRequired
+			}
+			return nil
+		})
+		g.Go(func() error {
+			if strings.Trim(i.HomestayAddress, " ") == "" {
+				return ErrHomestayAddresRequired
+			}
+			return nil
+		})
+		g.Go(func() error {
+			if strings.Trim(i.HomestayLatitude, " ") == "" {
+				return ErrLatitudeRequired
+			}
+			return nil
+		})
+		g.Go(func() error {
+			if strings.Trim(i.HomestayLongitude, " ") == "" {
+				return ErrLongitudeRequired
+			}
+			return nil
+		})
+		g.Go(func() error {
+			if utf8.RuneCountInString(i.HomestayName) > 100 {
+				return ErrMaxHomestayName
+			}
+			return nil
+		})
+		g.Go(func() error {
+			if utf8.RuneCountInString(i.HomestayAddress) > 200 {
+				return ErrMaxHomestayAddress
+			}
+			return nil
+		})
+		g.Go(func() error {
+			lat := i.HomestayLatitude
+			if strings.Trim(lat, " ") == "" {
+				return nil
+			}
+			if _, err := geo.ParseLatitude(lat); err != nil {
+				return ErrInvalidHomestayLat
+			}
+			return nil
+		})
+		g.Go(func() error {
+			lng := i.HomestayLongitude
+			if strings.Trim(lng, " ") == "" {
+				return nil
+			}
+			if _, err := geo.ParseLongitude(lng); err != nil {
+				return ErrInvalidHomestayLng
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return nil
+}
+
 func ValidateRegisterIn(i RegisterIn) error {
 	g := new(errgroup.Group)
 	g.Go(func() error {
@@ -224,6 +315,26 @@ func ValidateRegisterIn(i RegisterIn) error {
 		}
 		return nil
 	})
+	g.Go(func() error {
+		lat := i.HomestayLatitude
+		if strings.Trim(lat, " ") == "" || utf8.RuneCountInString(lat) > 50 {
+			return nil
+		}
+		if _, err := geo.ParseLatitude(lat); err != nil {
+			return ErrInvalidHomestayLat
+		}
+		return nil
+	})
+	g.Go(func() error {
+		lng := i.HomestayLongitude
+		if strings.Trim(lng, " ") == "" || utf8.RuneCountInString(lng) > 50 {
+			return nil
+		}
+		if _, err := geo.ParseLongitude(lng); err != nil {
+			return ErrInvalidHomestayLng
+		}
+		return nil
+	})
 	g.Go(func() error {
 		if utf8.RuneCountInString(i.Username) > 50 {
 			return ErrMaxUsername
@@ -468,3 +579,28 @@ func ValidateUpdateProfileIn(i UpdateProfileIn) error {
 	}
 	return nil
 }
+
+func ValidateMemberPrivacyIn(i MemberPrivacyIn) error {
+	g := new(errgroup.Group)
+	for _, v := range []string{i.UsernameVisibility, i.WaPhoneVisibility, i.OtherPhoneVisibility} {
+		v := v
+		g.Go(func() error {
+			if strings.Trim(v, " ") == "" {
+				return ErrVisibilityRequired
+			}
+			return nil
+		})
+		g.Go(func() error {
+			if _, err := fieldVisibilityFromString(v); v != "" && err != nil {
+				return ErrInvalidVisibility
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -285,6 +285,52 @@ func (r *OrgStructureRepository) FindByOrgIdAndMemberId(ctx context.Context, org
 	return ms, nil
 }
 
+// Positions of a member in every period, including the removed ones, the
+// oldest period first
+func (r *OrgStructureRepository) FindByMemberId(ctx context.Context, uid string) (m []OrgStructureModel, err error) {
+	sqlQuery := `
+		SELECT
+			id,
+			position_name,
+			position_level,
+			member_id,
+			position_id,
+			org_period_id,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM org_structures
+		WHERE member_id = $1
+		ORDER BY org_period_id, position_level, id
+	`
+
+	var query OrgStructureQuerier
+	tx, ok := ctx.Value(arbitary.TrxX{}).(pgx.Tx)
+	if ok {
+		query = tx.Query
+	} else {
+		query = r.PostgreDb.Query
+	}
+
+	rows, _ := query(
+		context.Background(),
+		sqlQuery,
+		uid,
+	)
+
+	var mps []*OrgStructureModel
+	if err := pgxscan.ScanAll(&mps, rows); err != nil {
+		return []OrgStructureModel{}, err
+	}
+
+	ms := make([]OrgStructureModel, len(mps))
+	for i, m := range mps {
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
 func (r *OrgStructureRepository) DeleteByOrgIdAndMemberId(ctx context.Context, orgId uint64, uid string) error {
 	sqlQuery := `
 	DELETE FROM org_structures